@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/openapi"
+)
+
+// CreateQuote adds a new quote to the local quote store. Only admins can reach this handler, see securityHandler
+func (app *application) CreateQuote(ctx context.Context, req *openapi.QuoteEdit) (openapi.CreateQuoteRes, error) {
+	quote, err := app.catalogueService.CreateQuote(ctx, quoteEditFromRequest(req))
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling catalogueService.CreateQuote")
+		return app.internalServerError()
+	}
+
+	return curatedQuoteResponse(quote), nil
+}
+
+// UpdateQuote edits or hides a quote in the local quote store. Only admins can reach this handler, see securityHandler
+func (app *application) UpdateQuote(ctx context.Context, req *openapi.QuoteEdit, params openapi.UpdateQuoteParams) (openapi.UpdateQuoteRes, error) {
+	quote, err := app.catalogueService.UpdateQuote(ctx, params.ID, quoteEditFromRequest(req))
+	if err == models.ErrQuoteNotFound {
+		return app.notFound()
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling catalogueService.UpdateQuote")
+		return app.internalServerError()
+	}
+
+	return curatedQuoteResponse(quote), nil
+}
+
+// DeleteQuote soft-deletes a quote, which blocks it permanently. Only admins can reach this handler, see securityHandler
+func (app *application) DeleteQuote(ctx context.Context, params openapi.DeleteQuoteParams) (openapi.DeleteQuoteRes, error) {
+	err := app.catalogueService.DeleteQuote(ctx, params.ID)
+	if err == models.ErrQuoteNotFound {
+		return app.notFound()
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling catalogueService.DeleteQuote")
+		return app.internalServerError()
+	}
+
+	return &openapi.DeleteQuoteNoContent{}, nil
+}
+
+// CreateApiKey creates a new API key for the given subject and role. The key is only returned in this response
+func (app *application) CreateApiKey(ctx context.Context, req *openapi.ApiKeyRequest) (openapi.CreateApiKeyRes, error) {
+	apiKey, key, err := app.authService.CreateApiKey(ctx, req.Subject, models.Role(req.Role))
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling authService.CreateApiKey")
+		return app.internalServerError()
+	}
+
+	return &openapi.CreatedApiKey{
+		ID:      openapi.UUID(apiKey.ID.String()),
+		Key:     key,
+		Subject: apiKey.Subject,
+		Role:    openapi.Role(apiKey.Role),
+	}, nil
+}
+
+// RevokeApiKey revokes an API key created with CreateApiKey
+func (app *application) RevokeApiKey(ctx context.Context, params openapi.RevokeApiKeyParams) (openapi.RevokeApiKeyRes, error) {
+	id, err := uuid.Parse(string(params.ID))
+	if err != nil {
+		return app.notFound()
+	}
+
+	err = app.authService.RevokeApiKey(ctx, id)
+	if err == models.ErrApiKeyNotFound {
+		return app.notFound()
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling authService.RevokeApiKey")
+		return app.internalServerError()
+	}
+
+	return &openapi.RevokeApiKeyNoContent{}, nil
+}
+
+// CreateBackup writes a snapshot of the database to the backup directory, while the api keeps serving requests
+func (app *application) CreateBackup(ctx context.Context) (openapi.CreateBackupRes, error) {
+	backup, err := app.backupService.Backup(ctx)
+	if errors.Is(err, models.ErrBackupsDisabled) {
+		return &openapi.R503{Message: err.Error()}, nil
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling backupService.Backup")
+		return app.internalServerError()
+	}
+
+	return backupResponse(backup), nil
+}
+
+// ListBackups returns the backups in the backup directory, newest first
+func (app *application) ListBackups(ctx context.Context) (openapi.ListBackupsRes, error) {
+	backups, err := app.backupService.ListBackups(ctx)
+	if errors.Is(err, models.ErrBackupsDisabled) {
+		return &openapi.R503{Message: err.Error()}, nil
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling backupService.ListBackups")
+		return app.internalServerError()
+	}
+
+	res := make(openapi.ListBackupsOKApplicationJSON, len(backups))
+	for i, backup := range backups {
+		res[i] = *backupResponse(backup)
+	}
+	return &res, nil
+}
+
+// ExportGames streams the games as CSV or NDJSON. The export is written to a pipe while ogen copies it to the response,
+// so the games are never all in memory. Until the first bytes are written the response can still become a 500,
+// after that an error can only end the response early.
+func (app *application) ExportGames(ctx context.Context, params openapi.ExportGamesParams) (openapi.ExportGamesRes, error) {
+	format := models.ExportFormat(params.Format)
+	pr, pw := io.Pipe()
+	// The request context ends when the client goes away, the reader is closed then so the export stops writing
+	stop := context.AfterFunc(ctx, func() { pr.CloseWithError(ctx.Err()) })
+	go func() {
+		defer stop()
+		pw.CloseWithError(app.exportService.ExportGames(ctx, pw, format, params.From.Or(time.Time{}), params.To.Or(time.Time{})))
+	}()
+
+	// The export buffers its first rows, so an error while starting the export arrives before any bytes do
+	reader := bufio.NewReader(pr)
+	_, err := reader.Peek(1)
+	if err != nil && !errors.Is(err, io.EOF) {
+		app.logger.Error().Err(err).Msg("unexpected error when calling exportService.ExportGames")
+		return app.internalServerError()
+	}
+
+	if format == models.ExportFormatNDJSON {
+		return &openapi.ExportGamesOKApplicationXNdjson{Data: reader}, nil
+	}
+	return &openapi.ExportGamesOKTextCsv{Data: reader}, nil
+}
+
+func backupResponse(backup *models.Backup) *openapi.Backup {
+	return &openapi.Backup{
+		Name:      backup.Name,
+		Size:      backup.Size,
+		CreatedAt: backup.CreatedAt,
+	}
+}
+
+func quoteEditFromRequest(req *openapi.QuoteEdit) models.QuoteEdit {
+	return models.QuoteEdit{
+		Quote:    req.Quote,
+		Author:   req.Author,
+		Language: models.Language(req.Language.Or(openapi.LanguageEn)),
+		Hidden:   req.Hidden.Or(false),
+	}
+}
+
+func curatedQuoteResponse(quote *models.CuratedQuote) *openapi.CuratedQuote {
+	return &openapi.CuratedQuote{
+		ID:       quote.ID,
+		Quote:    quote.Quote.Quote,
+		Author:   quote.Author,
+		Language: openapi.Language(quote.Language),
+		Source:   openapi.CuratedQuoteSource(quote.Source),
+		Hidden:   quote.Hidden,
+	}
+}
@@ -0,0 +1,503 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/openapi"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplication_CreateQuote(t *testing.T) {
+	type Test struct {
+		req                *openapi.QuoteEdit
+		expectedEdit       models.QuoteEdit
+		mockedServiceQuote *models.CuratedQuote
+		mockedServiceError error
+		expectedResult     openapi.CreateQuoteRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			// first we bootstrap a minimal version of the application, needed for the handler
+			mockedCatalogueService := new(MockedCatalogueService)
+			mockedCatalogueService.On("CreateQuote", tt.expectedEdit).Once().Return(tt.mockedServiceQuote, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:           &logger,
+				catalogueService: mockedCatalogueService,
+			}
+
+			// We now run the handler and validate the result
+			res, err := app.CreateQuote(context.TODO(), tt.req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("creates a quote", run(Test{
+		req:          &openapi.QuoteEdit{Quote: "A quote added by an admin.", Author: "An admin", Hidden: openapi.NewOptBool(true)},
+		expectedEdit: models.QuoteEdit{Quote: "A quote added by an admin.", Author: "An admin", Language: models.LanguageEnglish, Hidden: true},
+		mockedServiceQuote: &models.CuratedQuote{
+			Quote:  models.Quote{ID: 1000000, Quote: "A quote added by an admin.", Author: "An admin"},
+			Source: models.QuoteSourceAdmin,
+			Hidden: true,
+		},
+		expectedResult: &openapi.CuratedQuote{
+			ID:     1000000,
+			Quote:  "A quote added by an admin.",
+			Author: "An admin",
+			Source: openapi.CuratedQuoteSourceAdmin,
+			Hidden: true,
+		},
+	}))
+
+	t.Run("creates a quote in another language", run(Test{
+		req:          &openapi.QuoteEdit{Quote: "Een citaat van een beheerder.", Author: "Een beheerder", Language: openapi.NewOptLanguage(openapi.LanguageNl)},
+		expectedEdit: models.QuoteEdit{Quote: "Een citaat van een beheerder.", Author: "Een beheerder", Language: models.LanguageDutch},
+		mockedServiceQuote: &models.CuratedQuote{
+			Quote:  models.Quote{ID: 1000001, Quote: "Een citaat van een beheerder.", Author: "Een beheerder", Language: models.LanguageDutch},
+			Source: models.QuoteSourceAdmin,
+		},
+		expectedResult: &openapi.CuratedQuote{
+			ID:       1000001,
+			Quote:    "Een citaat van een beheerder.",
+			Author:   "Een beheerder",
+			Language: openapi.LanguageNl,
+			Source:   openapi.CuratedQuoteSourceAdmin,
+		},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		req:                &openapi.QuoteEdit{Quote: "A quote added by an admin.", Author: "An admin"},
+		expectedEdit:       models.QuoteEdit{Quote: "A quote added by an admin.", Author: "An admin", Language: models.LanguageEnglish},
+		mockedServiceError: errors.New("something went wrong"),
+		expectedResult: &openapi.R500{
+			Message: "unknown_error",
+		},
+	}))
+}
+
+func TestApplication_UpdateQuote(t *testing.T) {
+	type Test struct {
+		id                 int
+		req                *openapi.QuoteEdit
+		expectedEdit       models.QuoteEdit
+		mockedServiceQuote *models.CuratedQuote
+		mockedServiceError error
+		expectedResult     openapi.UpdateQuoteRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			// first we bootstrap a minimal version of the application, needed for the handler
+			mockedCatalogueService := new(MockedCatalogueService)
+			mockedCatalogueService.On("UpdateQuote", tt.id, tt.expectedEdit).Once().Return(tt.mockedServiceQuote, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:           &logger,
+				catalogueService: mockedCatalogueService,
+			}
+
+			// We now run the handler and validate the result
+			res, err := app.UpdateQuote(context.TODO(), tt.req, openapi.UpdateQuoteParams{ID: tt.id})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("hides a quote from dummyjson", run(Test{
+		id:           70,
+		req:          &openapi.QuoteEdit{Quote: "The cure for pain is in the pain.", Author: "Rumi", Hidden: openapi.NewOptBool(true)},
+		expectedEdit: models.QuoteEdit{Quote: "The cure for pain is in the pain.", Author: "Rumi", Language: models.LanguageEnglish, Hidden: true},
+		mockedServiceQuote: &models.CuratedQuote{
+			Quote:  models.Quote{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi"},
+			Source: models.QuoteSourceDummyJson,
+			Hidden: true,
+		},
+		expectedResult: &openapi.CuratedQuote{
+			ID:     70,
+			Quote:  "The cure for pain is in the pain.",
+			Author: "Rumi",
+			Source: openapi.CuratedQuoteSourceDummyjson,
+			Hidden: true,
+		},
+	}))
+
+	t.Run("returns a 404 if the quote doesn't exist", run(Test{
+		id:                 99999,
+		req:                &openapi.QuoteEdit{Quote: "A quote", Author: "A name"},
+		expectedEdit:       models.QuoteEdit{Quote: "A quote", Author: "A name", Language: models.LanguageEnglish},
+		mockedServiceError: models.ErrQuoteNotFound,
+		expectedResult: &openapi.R404{
+			Message: "not_found",
+		},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		id:                 70,
+		req:                &openapi.QuoteEdit{Quote: "A quote", Author: "A name"},
+		expectedEdit:       models.QuoteEdit{Quote: "A quote", Author: "A name", Language: models.LanguageEnglish},
+		mockedServiceError: errors.New("something went wrong"),
+		expectedResult: &openapi.R500{
+			Message: "unknown_error",
+		},
+	}))
+}
+
+func TestApplication_DeleteQuote(t *testing.T) {
+	type Test struct {
+		id                 int
+		mockedServiceError error
+		expectedResult     openapi.DeleteQuoteRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			// first we bootstrap a minimal version of the application, needed for the handler
+			mockedCatalogueService := new(MockedCatalogueService)
+			mockedCatalogueService.On("DeleteQuote", tt.id).Once().Return(tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:           &logger,
+				catalogueService: mockedCatalogueService,
+			}
+
+			// We now run the handler and validate the result
+			res, err := app.DeleteQuote(context.TODO(), openapi.DeleteQuoteParams{ID: tt.id})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("deletes a quote", run(Test{
+		id:             70,
+		expectedResult: &openapi.DeleteQuoteNoContent{},
+	}))
+
+	t.Run("returns a 404 if the quote doesn't exist", run(Test{
+		id:                 99999,
+		mockedServiceError: models.ErrQuoteNotFound,
+		expectedResult: &openapi.R404{
+			Message: "not_found",
+		},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		id:                 70,
+		mockedServiceError: errors.New("something went wrong"),
+		expectedResult: &openapi.R500{
+			Message: "unknown_error",
+		},
+	}))
+}
+
+func TestApplication_CreateApiKey(t *testing.T) {
+	type Test struct {
+		req                 *openapi.ApiKeyRequest
+		mockedServiceApiKey *models.ApiKey
+		mockedServiceKey    string
+		mockedServiceError  error
+		expectedResult      openapi.CreateApiKeyRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			// first we bootstrap a minimal version of the application, needed for the handler
+			mockedAuthService := new(MockedAuthService)
+			mockedAuthService.On("CreateApiKey", tt.req.Subject, models.Role(tt.req.Role)).Once().Return(tt.mockedServiceApiKey, tt.mockedServiceKey, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:      &logger,
+				authService: mockedAuthService,
+			}
+
+			// We now run the handler and validate the result
+			res, err := app.CreateApiKey(context.TODO(), tt.req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("creates an api key", run(Test{
+		req: &openapi.ApiKeyRequest{Subject: "player-42", Role: openapi.RolePlayer},
+		mockedServiceApiKey: &models.ApiKey{
+			ID:      uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+			Subject: "player-42",
+			Role:    models.RolePlayer,
+		},
+		mockedServiceKey: "kq_a-random-key",
+		expectedResult: &openapi.CreatedApiKey{
+			ID:      "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+			Key:     "kq_a-random-key",
+			Subject: "player-42",
+			Role:    openapi.RolePlayer,
+		},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		req:                &openapi.ApiKeyRequest{Subject: "player-42", Role: openapi.RolePlayer},
+		mockedServiceError: errors.New("something went wrong"),
+		expectedResult: &openapi.R500{
+			Message: "unknown_error",
+		},
+	}))
+}
+
+func TestApplication_RevokeApiKey(t *testing.T) {
+	type Test struct {
+		id                 openapi.UUID
+		mockedServiceError error
+		expectedResult     openapi.RevokeApiKeyRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			// first we bootstrap a minimal version of the application, needed for the handler
+			mockedAuthService := new(MockedAuthService)
+			if id, err := uuid.Parse(string(tt.id)); err == nil {
+				mockedAuthService.On("RevokeApiKey", id).Once().Return(tt.mockedServiceError)
+			}
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:      &logger,
+				authService: mockedAuthService,
+			}
+
+			// We now run the handler and validate the result
+			res, err := app.RevokeApiKey(context.TODO(), openapi.RevokeApiKeyParams{ID: tt.id})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("revokes an api key", run(Test{
+		id:             "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+		expectedResult: &openapi.RevokeApiKeyNoContent{},
+	}))
+
+	t.Run("returns a 404 if the api key doesn't exist", run(Test{
+		id:                 "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+		mockedServiceError: models.ErrApiKeyNotFound,
+		expectedResult: &openapi.R404{
+			Message: "not_found",
+		},
+	}))
+
+	t.Run("returns a 404 if the id is not a valid uuid", run(Test{
+		id: "not-a-uuid",
+		expectedResult: &openapi.R404{
+			Message: "not_found",
+		},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		id:                 "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+		mockedServiceError: errors.New("something went wrong"),
+		expectedResult: &openapi.R500{
+			Message: "unknown_error",
+		},
+	}))
+}
+
+func TestApplication_CreateBackup(t *testing.T) {
+	createdAt := time.Date(2025, 2, 1, 12, 1, 13, 0, time.UTC)
+
+	type Test struct {
+		mockedBackup   *models.Backup
+		mockedError    error
+		expectedResult openapi.CreateBackupRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedBackupService := new(MockedBackupService)
+			mockedBackupService.On("Backup").Once().Return(tt.mockedBackup, tt.mockedError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:        &logger,
+				backupService: mockedBackupService,
+			}
+
+			res, err := app.CreateBackup(context.TODO())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("creates a backup", run(Test{
+		mockedBackup:   &models.Backup{Name: "kabisa-20250201-120113.000000000.db", Size: 98304, CreatedAt: createdAt},
+		expectedResult: &openapi.Backup{Name: "kabisa-20250201-120113.000000000.db", Size: 98304, CreatedAt: createdAt},
+	}))
+
+	t.Run("returns service unavailable when backups are disabled", run(Test{
+		mockedBackup:   (*models.Backup)(nil),
+		mockedError:    models.ErrBackupsDisabled,
+		expectedResult: &openapi.R503{Message: "backups_disabled"},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		mockedBackup:   (*models.Backup)(nil),
+		mockedError:    errors.New("disk is full"),
+		expectedResult: &openapi.R500{Message: "unknown_error"},
+	}))
+}
+
+func TestApplication_ListBackups(t *testing.T) {
+	createdAt := time.Date(2025, 2, 1, 12, 1, 13, 0, time.UTC)
+
+	type Test struct {
+		mockedBackups  []*models.Backup
+		mockedError    error
+		expectedResult openapi.ListBackupsRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedBackupService := new(MockedBackupService)
+			mockedBackupService.On("ListBackups").Once().Return(tt.mockedBackups, tt.mockedError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:        &logger,
+				backupService: mockedBackupService,
+			}
+
+			res, err := app.ListBackups(context.TODO())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("lists the backups", run(Test{
+		mockedBackups: []*models.Backup{
+			{Name: "kabisa-20250201-120113.000000000.db", Size: 98304, CreatedAt: createdAt},
+			{Name: "kabisa-20250131-120113.000000000.db", Size: 65536, CreatedAt: createdAt.AddDate(0, 0, -1)},
+		},
+		expectedResult: &openapi.ListBackupsOKApplicationJSON{
+			{Name: "kabisa-20250201-120113.000000000.db", Size: 98304, CreatedAt: createdAt},
+			{Name: "kabisa-20250131-120113.000000000.db", Size: 65536, CreatedAt: createdAt.AddDate(0, 0, -1)},
+		},
+	}))
+
+	t.Run("lists no backups", run(Test{
+		mockedBackups:  []*models.Backup{},
+		expectedResult: &openapi.ListBackupsOKApplicationJSON{},
+	}))
+
+	t.Run("returns service unavailable when backups are disabled", run(Test{
+		mockedBackups:  ([]*models.Backup)(nil),
+		mockedError:    models.ErrBackupsDisabled,
+		expectedResult: &openapi.R503{Message: "backups_disabled"},
+	}))
+}
+
+func TestApplication_ExportGames(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	type Test struct {
+		params         openapi.ExportGamesParams
+		expectedFormat models.ExportFormat
+		expectedFrom   time.Time
+		mockedOutput   string
+		mockedError    error
+		expectedResult openapi.ExportGamesRes
+		expectedBody   string
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedExportService := new(MockedExportService)
+			mockedExportService.On("ExportGames", tt.expectedFormat, tt.expectedFrom, time.Time{}).Once().Return(tt.mockedOutput, tt.mockedError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:        &logger,
+				exportService: mockedExportService,
+			}
+
+			res, err := app.ExportGames(context.TODO(), tt.params)
+			require.NoError(t, err)
+			if tt.expectedResult != nil {
+				assert.Equal(t, tt.expectedResult, res)
+				return
+			}
+			reader, ok := res.(io.Reader)
+			require.True(t, ok)
+			body, err := io.ReadAll(reader)
+			assert.Equal(t, tt.expectedBody, string(body))
+			if tt.mockedError != nil {
+				// The error after the first bytes ends the stream instead
+				assert.Equal(t, tt.mockedError, err)
+				return
+			}
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("streams a CSV export", run(Test{
+		params:         openapi.ExportGamesParams{Format: openapi.ExportGamesFormatCsv, From: openapi.NewOptDateTime(from)},
+		expectedFormat: models.ExportFormatCSV,
+		expectedFrom:   from,
+		mockedOutput:   "id,mode\n",
+		expectedBody:   "id,mode\n",
+	}))
+
+	t.Run("streams an NDJSON export", run(Test{
+		params:         openapi.ExportGamesParams{Format: openapi.ExportGamesFormatNdjson},
+		expectedFormat: models.ExportFormatNDJSON,
+		mockedOutput:   `{"id":"a"}` + "\n",
+		expectedBody:   `{"id":"a"}` + "\n",
+	}))
+
+	t.Run("streams an empty export", run(Test{
+		params:         openapi.ExportGamesParams{Format: openapi.ExportGamesFormatNdjson},
+		expectedFormat: models.ExportFormatNDJSON,
+		expectedBody:   "",
+	}))
+
+	t.Run("returns internal server error when the export fails before the first bytes", run(Test{
+		params:         openapi.ExportGamesParams{Format: openapi.ExportGamesFormatCsv},
+		expectedFormat: models.ExportFormatCSV,
+		mockedError:    errors.New("database is gone"),
+		expectedResult: &openapi.R500{Message: "unknown_error"},
+	}))
+
+	t.Run("ends the stream when the export fails after the first bytes", run(Test{
+		params:         openapi.ExportGamesParams{Format: openapi.ExportGamesFormatCsv},
+		expectedFormat: models.ExportFormatCSV,
+		mockedOutput:   "id,mode\n",
+		mockedError:    errors.New("database is gone"),
+		expectedBody:   "id,mode\n",
+	}))
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/database"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/repositories"
+	"github.com/pietdevries94/Kabisa/services"
+	"github.com/rs/zerolog"
+)
+
+// initBackupService creates the backup service with the backup directory and retention from the config
+func initBackupService(logger *zerolog.Logger, conf *config, db *database.DB) *services.BackupService {
+	backupRetention, err := strconv.Atoi(conf.backupRetention)
+	if err != nil || backupRetention < 0 {
+		logger.Fatal().Err(err).Str("value", conf.backupRetention).Msg("could not parse set backupRetention as non negative int")
+	}
+	return services.NewBackupService(logger, repositories.NewBackupRepo(logger, db), conf.backupDir, backupRetention)
+}
+
+// startBackupSchedule makes a backup every interval in the background. The first backup is made after the first interval,
+// so restarts don't create a backup each time. With an interval of 0, no backups are scheduled.
+func startBackupSchedule(logger *zerolog.Logger, backupService backupService, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			_, err := backupService.Backup(context.Background())
+			if err != nil {
+				logger.Error().Err(err).Msg("could not make scheduled backup")
+			}
+		}
+	}()
+}
+
+// runBackupCommand makes a single backup of the database from the config and prints its path. It's safe to use while the api is running.
+// The backup is written to the backup directory, or to the file given with -o. The retention only applies to the backup directory.
+func runBackupCommand(logger *zerolog.Logger, conf *config, args []string) {
+	flags := flag.NewFlagSet("backup", flag.ExitOnError)
+	output := flags.String("o", "", "the file to write the backup to, instead of a new file in the backup directory")
+	flags.Parse(args) //nolint:errcheck // the flag set exits on errors
+
+	if database.IsMemory(conf.sqliteDSN) {
+		logger.Fatal().Str("DSN", conf.sqliteDSN).Msg("the database is in memory, so only the api itself can back it up")
+	}
+	db := initDatabase(logger, conf)
+	defer db.Close()
+	backupService := initBackupService(logger, conf, db)
+
+	var backup *models.Backup
+	var err error
+	path := *output
+	if path != "" {
+		backup, err = backupService.BackupTo(context.Background(), path)
+	} else {
+		backup, err = backupService.Backup(context.Background())
+		if backup != nil {
+			path = filepath.Join(conf.backupDir, backup.Name)
+		}
+	}
+	if errors.Is(err, models.ErrBackupsDisabled) {
+		logger.Fatal().Msg("set KABISAQUOTE_BACKUP_DIR, or give the file to write the backup to with -o")
+	}
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not make backup")
+	}
+	fmt.Println(path)
+}
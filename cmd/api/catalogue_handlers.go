@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/openapi"
+)
+
+// defaultPageLimit is used when no limit is given. ogen already applies the default from the spec, but this keeps the handlers safe when called directly
+const defaultPageLimit = 20
+
+// ListQuotes returns a page of quotes from the local catalogue, optionally filtered by author and a full-text search
+func (app *application) ListQuotes(ctx context.Context, params openapi.ListQuotesParams) (openapi.ListQuotesRes, error) {
+	page, err := app.catalogueService.ListQuotes(ctx, models.QuoteFilter{
+		Author: params.Author.Or(""),
+		Search: params.Q.Or(""),
+		Limit:  params.Limit.Or(defaultPageLimit),
+		Offset: params.Offset.Or(0),
+	})
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling catalogueService.ListQuotes")
+		return app.internalServerError()
+	}
+
+	result := &openapi.QuotePage{
+		Items:  make([]openapi.Quote, len(page.Items)),
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	for i, q := range page.Items {
+		result.Items[i] = quoteResponse(q)
+	}
+
+	return result, nil
+}
+
+// GetQuote returns a single quote from the local catalogue
+func (app *application) GetQuote(ctx context.Context, params openapi.GetQuoteParams) (openapi.GetQuoteRes, error) {
+	quote, err := app.catalogueService.GetQuote(ctx, params.ID)
+	if err == models.ErrQuoteNotFound {
+		return app.notFound()
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling catalogueService.GetQuote")
+		return app.internalServerError()
+	}
+
+	result := quoteResponse(quote)
+	return &result, nil
+}
+
+// ListAuthors returns a page of the authors in the local catalogue, together with their number of quotes
+func (app *application) ListAuthors(ctx context.Context, params openapi.ListAuthorsParams) (openapi.ListAuthorsRes, error) {
+	page, err := app.catalogueService.ListAuthors(ctx, params.Limit.Or(defaultPageLimit), params.Offset.Or(0))
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling catalogueService.ListAuthors")
+		return app.internalServerError()
+	}
+
+	result := &openapi.AuthorPage{
+		Items:  make([]openapi.Author, len(page.Items)),
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	for i, a := range page.Items {
+		result.Items[i] = openapi.Author{
+			Name:       a.Author,
+			QuoteCount: a.QuoteCount,
+		}
+	}
+
+	return result, nil
+}
+
+// GetDailyQuote returns the quote of the day, together with caching headers. The day defaults to today (UTC).
+// When the client already has the quote, as indicated by If-None-Match, a 304 is returned instead.
+func (app *application) GetDailyQuote(ctx context.Context, params openapi.GetDailyQuoteParams) (openapi.GetDailyQuoteRes, error) {
+	now := time.Now().UTC()
+
+	dailyQuote, err := app.catalogueService.GetDailyQuote(ctx, params.Date.Or(now))
+	if err == models.ErrDailyQuoteNotFound {
+		return app.notFound()
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling catalogueService.GetDailyQuote")
+		return app.internalServerError()
+	}
+
+	etag := fmt.Sprintf(`"%s-%d"`, dailyQuote.Date, dailyQuote.Quote.ID)
+	cacheControl := dailyQuoteCacheControl(dailyQuote.Date, now)
+
+	if etagMatches(params.IfNoneMatch.Or(""), etag) {
+		return &openapi.GetDailyQuoteNotModified{
+			ETag:         etag,
+			CacheControl: cacheControl,
+		}, nil
+	}
+
+	date, err := time.Parse(time.DateOnly, dailyQuote.Date)
+	if err != nil {
+		app.logger.Error().Err(err).Str("date", dailyQuote.Date).Msg("could not parse date of daily quote")
+		return app.internalServerError()
+	}
+
+	return &openapi.DailyQuoteHeaders{
+		ETag:         etag,
+		CacheControl: cacheControl,
+		Response: openapi.DailyQuote{
+			Date:  date,
+			Quote: quoteResponse(&dailyQuote.Quote),
+		},
+	}, nil
+}
+
+// dailyQuoteCacheControl returns the Cache-Control header for the quote of the given day (YYYY-MM-DD).
+// The quote of today may be cached until midnight UTC, quotes of earlier days never change anymore.
+func dailyQuoteCacheControl(date string, now time.Time) string {
+	if date < now.Format(time.DateOnly) {
+		return "public, max-age=31536000, immutable"
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	return fmt.Sprintf("public, max-age=%d", int(midnight.Sub(now).Seconds()))
+}
+
+// etagMatches checks if the If-None-Match header contains the given etag. Weak etags are compared as strong ones
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
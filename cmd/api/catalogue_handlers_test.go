@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/openapi"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplication_ListQuotes(t *testing.T) {
+	type Test struct {
+		params              openapi.ListQuotesParams
+		expectedFilter      models.QuoteFilter
+		mockedServiceResult *models.Page[*models.Quote]
+		mockedServiceError  error
+		expectedResult      openapi.ListQuotesRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			// first we bootstrap a minimal version of the application, needed for the handler
+			mockedCatalogueService := new(MockedCatalogueService)
+			mockedCatalogueService.On("ListQuotes", tt.expectedFilter).Once().Return(tt.mockedServiceResult, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:           &logger,
+				catalogueService: mockedCatalogueService,
+			}
+
+			// We now run the handler and validate the result
+			res, err := app.ListQuotes(context.TODO(), tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("returns a page of quotes", run(Test{
+		params: openapi.ListQuotesParams{
+			Limit:  openapi.NewOptInt(1),
+			Offset: openapi.NewOptInt(1),
+			Author: openapi.NewOptString("Rumi"),
+			Q:      openapi.NewOptString("beauty"),
+		},
+		expectedFilter: models.QuoteFilter{Author: "Rumi", Search: "beauty", Limit: 1, Offset: 1},
+		mockedServiceResult: &models.Page[*models.Quote]{
+			Items: []*models.Quote{
+				{ID: 172, Quote: "The only lasting beauty is the beauty of the heart.", Author: "Rumi"},
+			},
+			Total:  2,
+			Limit:  1,
+			Offset: 1,
+		},
+		expectedResult: &openapi.QuotePage{
+			Items: []openapi.Quote{
+				{ID: 172, Quote: "The only lasting beauty is the beauty of the heart.", Author: "Rumi"},
+			},
+			Total:  2,
+			Limit:  1,
+			Offset: 1,
+		},
+	}))
+
+	t.Run("uses the default pagination when not given", run(Test{
+		expectedFilter:      models.QuoteFilter{Limit: 20},
+		mockedServiceResult: &models.Page[*models.Quote]{Items: []*models.Quote{}, Limit: 20},
+		expectedResult:      &openapi.QuotePage{Items: []openapi.Quote{}, Limit: 20},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		expectedFilter:     models.QuoteFilter{Limit: 20},
+		mockedServiceError: errors.New("something went wrong"),
+		expectedResult: &openapi.R500{
+			Message: "unknown_error",
+		},
+	}))
+}
+
+func TestApplication_GetQuote(t *testing.T) {
+	type Test struct {
+		id                 int
+		mockedServiceQuote *models.Quote
+		mockedServiceError error
+		expectedResult     openapi.GetQuoteRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			// first we bootstrap a minimal version of the application, needed for the handler
+			mockedCatalogueService := new(MockedCatalogueService)
+			mockedCatalogueService.On("GetQuote", tt.id).Once().Return(tt.mockedServiceQuote, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:           &logger,
+				catalogueService: mockedCatalogueService,
+			}
+
+			// We now run the handler and validate the result
+			res, err := app.GetQuote(context.TODO(), openapi.GetQuoteParams{ID: tt.id})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("returns a quote", run(Test{
+		id: 1207,
+		mockedServiceQuote: &models.Quote{
+			ID:     1207,
+			Quote:  "Everything Has Its Limit - Iron Ore Cannot Be Educated Into Gold.",
+			Author: "Mark Twain",
+		},
+		expectedResult: &openapi.Quote{
+			ID:     1207,
+			Quote:  "Everything Has Its Limit - Iron Ore Cannot Be Educated Into Gold.",
+			Author: "Mark Twain",
+		},
+	}))
+
+	t.Run("returns a 404 if the quote doesn't exist", run(Test{
+		id:                 99999,
+		mockedServiceError: models.ErrQuoteNotFound,
+		expectedResult: &openapi.R404{
+			Message: "not_found",
+		},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		id:                 1207,
+		mockedServiceError: errors.New("something went wrong"),
+		expectedResult: &openapi.R500{
+			Message: "unknown_error",
+		},
+	}))
+}
+
+func TestApplication_ListAuthors(t *testing.T) {
+	type Test struct {
+		params              openapi.ListAuthorsParams
+		expectedLimit       int
+		expectedOffset      int
+		mockedServiceResult *models.Page[*models.AuthorQuoteCount]
+		mockedServiceError  error
+		expectedResult      openapi.ListAuthorsRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			// first we bootstrap a minimal version of the application, needed for the handler
+			mockedCatalogueService := new(MockedCatalogueService)
+			mockedCatalogueService.On("ListAuthors", tt.expectedLimit, tt.expectedOffset).Once().Return(tt.mockedServiceResult, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:           &logger,
+				catalogueService: mockedCatalogueService,
+			}
+
+			// We now run the handler and validate the result
+			res, err := app.ListAuthors(context.TODO(), tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("returns a page of authors", run(Test{
+		params:         openapi.ListAuthorsParams{Limit: openapi.NewOptInt(2), Offset: openapi.NewOptInt(4)},
+		expectedLimit:  2,
+		expectedOffset: 4,
+		mockedServiceResult: &models.Page[*models.AuthorQuoteCount]{
+			Items: []*models.AuthorQuoteCount{
+				{Author: "Mark Twain", QuoteCount: 12},
+				{Author: "Rumi", QuoteCount: 40},
+			},
+			Total:  100,
+			Limit:  2,
+			Offset: 4,
+		},
+		expectedResult: &openapi.AuthorPage{
+			Items: []openapi.Author{
+				{Name: "Mark Twain", QuoteCount: 12},
+				{Name: "Rumi", QuoteCount: 40},
+			},
+			Total:  100,
+			Limit:  2,
+			Offset: 4,
+		},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		expectedLimit:      20,
+		mockedServiceError: errors.New("something went wrong"),
+		expectedResult: &openapi.R500{
+			Message: "unknown_error",
+		},
+	}))
+}
+
+func TestApplication_GetDailyQuote(t *testing.T) {
+	type Test struct {
+		params             openapi.GetDailyQuoteParams
+		mockedServiceQuote *models.DailyQuote
+		mockedServiceError error
+		expectedResult     openapi.GetDailyQuoteRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			// first we bootstrap a minimal version of the application, needed for the handler
+			mockedCatalogueService := new(MockedCatalogueService)
+			mockedCatalogueService.On("GetDailyQuote", tt.params.Date.Value).Once().Return(tt.mockedServiceQuote, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:           &logger,
+				catalogueService: mockedCatalogueService,
+			}
+
+			// We now run the handler and validate the result
+			res, err := app.GetDailyQuote(context.TODO(), tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	// We use a day in the past, so the caching headers are predictable
+	day := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("returns the quote of the day with caching headers", run(Test{
+		params: openapi.GetDailyQuoteParams{Date: openapi.NewOptDate(day)},
+		mockedServiceQuote: &models.DailyQuote{
+			Date:  "2025-02-01",
+			Quote: models.Quote{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi"},
+		},
+		expectedResult: &openapi.DailyQuoteHeaders{
+			ETag:         `"2025-02-01-70"`,
+			CacheControl: "public, max-age=31536000, immutable",
+			Response: openapi.DailyQuote{
+				Date:  day,
+				Quote: openapi.Quote{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi"},
+			},
+		},
+	}))
+
+	t.Run("returns not modified when the etag matches", run(Test{
+		params: openapi.GetDailyQuoteParams{
+			Date:        openapi.NewOptDate(day),
+			IfNoneMatch: openapi.NewOptString(`"2025-01-31-12", W/"2025-02-01-70"`),
+		},
+		mockedServiceQuote: &models.DailyQuote{
+			Date:  "2025-02-01",
+			Quote: models.Quote{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi"},
+		},
+		expectedResult: &openapi.GetDailyQuoteNotModified{
+			ETag:         `"2025-02-01-70"`,
+			CacheControl: "public, max-age=31536000, immutable",
+		},
+	}))
+
+	t.Run("returns a 404 when there is no quote for the day", run(Test{
+		params:             openapi.GetDailyQuoteParams{Date: openapi.NewOptDate(day)},
+		mockedServiceError: models.ErrDailyQuoteNotFound,
+		expectedResult: &openapi.R404{
+			Message: "not_found",
+		},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		params:             openapi.GetDailyQuoteParams{Date: openapi.NewOptDate(day)},
+		mockedServiceError: errors.New("something went wrong"),
+		expectedResult: &openapi.R500{
+			Message: "unknown_error",
+		},
+	}))
+}
+
+func TestDailyQuoteCacheControl(t *testing.T) {
+	now := time.Date(2025, 2, 1, 23, 0, 0, 0, time.UTC)
+
+	// Today's quote can be cached until midnight
+	assert.Equal(t, "public, max-age=3600", dailyQuoteCacheControl("2025-02-01", now))
+	// Earlier quotes never change
+	assert.Equal(t, "public, max-age=31536000, immutable", dailyQuoteCacheControl("2025-01-31", now))
+}
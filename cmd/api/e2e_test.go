@@ -0,0 +1,1067 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ogen-go/ogen/ogenerrors"
+	"github.com/pietdevries94/Kabisa/fakequotes"
+	"github.com/pietdevries94/Kabisa/openapi"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// e2eAdminKey is the static admin API key of the server started by startE2E
+const e2eAdminKey = "e2e-admin-key"
+
+// e2eHarness is a running api with a fake quote upstream and its own in-memory database
+type e2eHarness struct {
+	// url is the base url of the api
+	url string
+	// client is an anonymous client of the api
+	client *openapi.Client
+	// adminClient is a client using the static admin API key
+	adminClient *openapi.Client
+	// db is a second connection to the database of the api, to change state that can't be changed through the api, like the time
+	db *sql.DB
+	// backupDir is the directory the api writes its backups to
+	backupDir string
+}
+
+// e2eSecurity provides the API key of the client. Without a key, the client doesn't send credentials
+type e2eSecurity struct {
+	apiKey string
+}
+
+func (sec e2eSecurity) ApiKey(context.Context, openapi.OperationName) (openapi.ApiKey, error) {
+	if sec.apiKey == "" {
+		return openapi.ApiKey{}, ogenerrors.ErrSkipClientSecurity
+	}
+	return openapi.ApiKey{APIKey: sec.apiKey}, nil
+}
+
+func (sec e2eSecurity) BearerAuth(context.Context, openapi.OperationName) (openapi.BearerAuth, error) {
+	return openapi.BearerAuth{}, ogenerrors.ErrSkipClientSecurity
+}
+
+// startE2E boots the api like main does, on a random port. The quotes come from fakequotes, so no network is needed.
+// Everything is stopped when the test is done.
+func startE2E(t *testing.T) *e2eHarness {
+	t.Helper()
+
+	// Every test gets its own named in-memory database, so they don't see each other's games
+	return startE2EWithDSN(t, fmt.Sprintf("file:e2e-%s?mode=memory&cache=shared", uuid.NewString()))
+}
+
+// startE2EWithDSN is startE2E with the given database, for tests that need a database file like in production
+func startE2EWithDSN(t *testing.T, dsn string) *e2eHarness {
+	t.Helper()
+
+	upstream := fakequotes.NewTestServer()
+	t.Cleanup(upstream.Close)
+
+	conf := initConfig()
+	conf.sqliteDSN = dsn
+	conf.dummyJsonBaseURL = upstream.URL
+	conf.apiKeys = "e2e-admin:admin:" + e2eAdminKey
+	conf.rateLimits = ""
+	conf.backupDir = t.TempDir()
+
+	logger := zerolog.New(os.Stderr).Level(zerolog.WarnLevel)
+	app := initApplication(&logger, conf)
+	srv := httptest.NewServer(initHttpHandler(&logger, conf, app))
+	t.Cleanup(srv.Close)
+
+	client, err := openapi.NewClient(srv.URL, e2eSecurity{}, openapi.WithClient(srv.Client()))
+	require.NoError(t, err)
+	adminClient, err := openapi.NewClient(srv.URL, e2eSecurity{apiKey: e2eAdminKey}, openapi.WithClient(srv.Client()))
+	require.NoError(t, err)
+
+	db, err := sql.Open("sqlite", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	h := &e2eHarness{
+		url:         srv.URL,
+		client:      client,
+		adminClient: adminClient,
+		db:          db,
+		backupDir:   conf.backupDir,
+	}
+
+	// The catalogue is synced in the background on startup, we wait for it so the tests are deterministic
+	require.Eventually(t, func() bool {
+		res, err := client.ListQuotes(context.TODO(), openapi.ListQuotesParams{})
+		page, ok := res.(*openapi.QuotePage)
+		return err == nil && ok && page.Total == len(fakequotes.DefaultQuotes())
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return h
+}
+
+// createGame starts a new game and fails the test if that's not possible
+func (h *e2eHarness) createGame(t *testing.T, playerID string) *openapi.CreateNewQuoteGameOK {
+	t.Helper()
+
+	params := openapi.CreateNewQuoteGameParams{}
+	if playerID != "" {
+		params.XPlayerID = openapi.NewOptString(playerID)
+	}
+	res, err := h.client.CreateNewQuoteGame(context.TODO(), params)
+	require.NoError(t, err)
+	require.IsType(t, &openapi.CreateNewQuoteGameOK{}, res)
+	return res.(*openapi.CreateNewQuoteGameOK)
+}
+
+// submit submits the answers of a game
+func (h *e2eHarness) submit(t *testing.T, id openapi.UUID, answers []openapi.QuoteGameAnswer) openapi.SubmitAnswerForQuoteGameRes {
+	t.Helper()
+
+	res, err := h.client.SubmitAnswerForQuoteGame(context.TODO(), answers, openapi.SubmitAnswerForQuoteGameParams{ID: id})
+	require.NoError(t, err)
+	return res
+}
+
+// correctAnswers looks up the actual authors of the quotes of a game in the dataset of fakequotes
+func correctAnswers(game *openapi.CreateNewQuoteGameOK) []openapi.QuoteGameAnswer {
+	authors := map[int]string{}
+	for _, q := range fakequotes.DefaultQuotes() {
+		authors[q.ID] = q.Author
+	}
+
+	answers := make([]openapi.QuoteGameAnswer, len(game.Quotes))
+	for i, q := range game.Quotes {
+		answers[i] = openapi.QuoteGameAnswer{ID: q.ID, Author: authors[q.ID]}
+	}
+	return answers
+}
+
+// missingWords looks up the words that were blanked in the quotes of a fill in the blank game. The missing word is what the full quote
+// in the dataset of fakequotes has in place of the first blank. A repeated word has more blanks, so the word ends where the text up to
+// the next blank starts
+func missingWords(t *testing.T, game *openapi.CreateNewQuoteGameOK) map[int]string {
+	t.Helper()
+	texts := map[int]string{}
+	for _, q := range fakequotes.DefaultQuotes() {
+		texts[q.ID] = q.Quote
+	}
+	missing := map[int]string{}
+	for _, q := range game.Quotes {
+		before, after, found := strings.Cut(q.Quote, "_____")
+		require.True(t, found, "quote %d has no blank", q.ID)
+		rest := strings.TrimPrefix(texts[q.ID], before)
+		next, _, _ := strings.Cut(after, "_____")
+		if next == "" {
+			missing[q.ID] = rest
+			continue
+		}
+		missing[q.ID] = rest[:strings.Index(rest, next)]
+	}
+	return missing
+}
+
+func TestE2E_QuoteGame(t *testing.T) {
+	t.Run("plays a game with all answers correct", func(t *testing.T) {
+		h := startE2E(t)
+		game := h.createGame(t, "")
+		require.Len(t, game.Quotes, 3)
+		require.Len(t, game.Authors, 3)
+
+		answers := correctAnswers(game)
+		res := h.submit(t, game.ID, answers)
+
+		require.IsType(t, &openapi.QuoteGameResult{}, res)
+		result := res.(*openapi.QuoteGameResult)
+		assert.Equal(t, game.ID, result.ID)
+		require.Len(t, result.Answers, 3)
+		for i, a := range result.Answers {
+			assert.True(t, a.Correct)
+			assert.Equal(t, answers[i].Author, a.ActualAuthor)
+		}
+	})
+
+	t.Run("plays a game with wrong answers", func(t *testing.T) {
+		h := startE2E(t)
+		game := h.createGame(t, "")
+
+		// We rotate the authors, so every quote gets the author of another quote. The authors of a game are always distinct
+		answers := correctAnswers(game)
+		rotated := make([]openapi.QuoteGameAnswer, len(answers))
+		for i := range answers {
+			rotated[i] = openapi.QuoteGameAnswer{ID: answers[i].ID, Author: answers[(i+1)%len(answers)].Author}
+		}
+		res := h.submit(t, game.ID, rotated)
+
+		require.IsType(t, &openapi.QuoteGameResult{}, res)
+		for _, a := range res.(*openapi.QuoteGameResult).Answers {
+			assert.False(t, a.Correct)
+		}
+	})
+
+	t.Run("plays a multiple choice game", func(t *testing.T) {
+		h := startE2E(t)
+		res, err := h.client.CreateNewQuoteGame(context.TODO(), openapi.CreateNewQuoteGameParams{Mode: openapi.NewOptGameMode(openapi.GameModeMultipleChoice)})
+		require.NoError(t, err)
+		require.IsType(t, &openapi.CreateNewQuoteGameOK{}, res)
+		game := res.(*openapi.CreateNewQuoteGameOK)
+		assert.Equal(t, openapi.GameModeMultipleChoice, game.Mode)
+		assert.Empty(t, game.Authors)
+		require.Len(t, game.Quotes, 3)
+
+		// Every quote comes with four choices, including the right author. We answer the first quote wrong
+		answers := correctAnswers(game)
+		for i, q := range game.Quotes {
+			assert.Len(t, q.Choices, 4)
+			assert.Contains(t, q.Choices, answers[i].Author)
+		}
+		for _, choice := range game.Quotes[0].Choices {
+			if choice != answers[0].Author {
+				answers[0].Author = choice
+				break
+			}
+		}
+
+		submitted := h.submit(t, game.ID, answers)
+		require.IsType(t, &openapi.QuoteGameResult{}, submitted)
+		result := submitted.(*openapi.QuoteGameResult)
+		require.Len(t, result.Answers, 3)
+		correct := map[int]bool{}
+		for _, a := range result.Answers {
+			correct[a.ID] = a.Correct
+		}
+		assert.Equal(t, map[int]bool{game.Quotes[0].ID: false, game.Quotes[1].ID: true, game.Quotes[2].ID: true}, correct)
+	})
+
+	t.Run("plays a fill in the blank game", func(t *testing.T) {
+		h := startE2E(t)
+		res, err := h.client.CreateNewQuoteGame(context.TODO(), openapi.CreateNewQuoteGameParams{Mode: openapi.NewOptGameMode(openapi.GameModeFillInTheBlank)})
+		require.NoError(t, err)
+		require.IsType(t, &openapi.CreateNewQuoteGameOK{}, res)
+		game := res.(*openapi.CreateNewQuoteGameOK)
+		assert.Equal(t, openapi.GameModeFillInTheBlank, game.Mode)
+		require.Len(t, game.Quotes, 3)
+
+		texts := map[int]string{}
+		for _, q := range fakequotes.DefaultQuotes() {
+			texts[q.ID] = q.Quote
+		}
+		missing := missingWords(t, game)
+
+		// The case of an answer doesn't matter, but the last answer is wrong
+		answers := []openapi.BlankAnswer{
+			{ID: game.Quotes[0].ID, Answer: strings.ToUpper(missing[game.Quotes[0].ID])},
+			{ID: game.Quotes[1].ID, Answer: missing[game.Quotes[1].ID]},
+			{ID: game.Quotes[2].ID, Answer: "xyzzy xyzzy"},
+		}
+		submitted, err := h.client.SubmitBlanksForQuoteGame(context.TODO(), answers, openapi.SubmitBlanksForQuoteGameParams{ID: game.ID})
+		require.NoError(t, err)
+		require.IsType(t, &openapi.BlankResult{}, submitted)
+		result := submitted.(*openapi.BlankResult)
+		require.Len(t, result.Blanks, 3)
+		for _, b := range result.Blanks {
+			assert.Equal(t, missing[b.ID], b.Expected)
+			assert.Equal(t, texts[b.ID], b.Quote)
+			assert.Equal(t, b.ID != game.Quotes[2].ID, b.Correct, "quote %d", b.ID)
+		}
+
+		// A game in another mode can't be answered with blanks
+		other := h.createGame(t, "")
+		submitted, err = h.client.SubmitBlanksForQuoteGame(context.TODO(), []openapi.BlankAnswer{
+			{ID: other.Quotes[0].ID, Answer: "a"},
+			{ID: other.Quotes[1].ID, Answer: "b"},
+			{ID: other.Quotes[2].ID, Answer: "c"},
+		}, openapi.SubmitBlanksForQuoteGameParams{ID: other.ID})
+		require.NoError(t, err)
+		require.IsType(t, &openapi.R422{}, submitted)
+		assert.Equal(t, "wrong_game_mode", submitted.(*openapi.R422).Message)
+	})
+
+	t.Run("scores a fill in the blank game against the quotes as they were when the game was created", func(t *testing.T) {
+		h := startE2E(t)
+		res, err := h.client.CreateNewQuoteGame(context.TODO(), openapi.CreateNewQuoteGameParams{Mode: openapi.NewOptGameMode(openapi.GameModeFillInTheBlank)})
+		require.NoError(t, err)
+		require.IsType(t, &openapi.CreateNewQuoteGameOK{}, res)
+		game := res.(*openapi.CreateNewQuoteGameOK)
+		missing := missingWords(t, game)
+
+		// An admin rewrites the quotes during the game, so other words would be blanked now
+		authors := map[int]string{}
+		for _, q := range fakequotes.DefaultQuotes() {
+			authors[q.ID] = q.Author
+		}
+		for _, q := range game.Quotes {
+			edited, err := h.adminClient.UpdateQuote(context.TODO(), &openapi.QuoteEdit{
+				Quote:  "Completely rewritten by the admin.",
+				Author: authors[q.ID],
+			}, openapi.UpdateQuoteParams{ID: q.ID})
+			require.NoError(t, err)
+			require.IsType(t, &openapi.CuratedQuote{}, edited)
+		}
+
+		answers := make([]openapi.BlankAnswer, len(game.Quotes))
+		for i, q := range game.Quotes {
+			answers[i] = openapi.BlankAnswer{ID: q.ID, Answer: missing[q.ID]}
+		}
+		submitted, err := h.client.SubmitBlanksForQuoteGame(context.TODO(), answers, openapi.SubmitBlanksForQuoteGameParams{ID: game.ID})
+		require.NoError(t, err)
+		require.IsType(t, &openapi.BlankResult{}, submitted)
+		result := submitted.(*openapi.BlankResult)
+		require.Len(t, result.Blanks, 3)
+		for _, b := range result.Blanks {
+			assert.Equal(t, missing[b.ID], b.Expected)
+			assert.True(t, b.Correct, "quote %d", b.ID)
+		}
+	})
+
+	t.Run("deducts the costs of hints from the score", func(t *testing.T) {
+		h := startE2E(t)
+		game := h.createGame(t, "")
+		answers := correctAnswers(game)
+
+		res, err := h.client.RequestHintForQuoteGame(context.TODO(), &openapi.HintRequest{Type: openapi.HintTypeRevealPair, QuoteId: openapi.NewOptInt(answers[0].ID)}, openapi.RequestHintForQuoteGameParams{ID: game.ID})
+		require.NoError(t, err)
+		assert.Equal(t, &openapi.Hint{Type: openapi.HintTypeRevealPair, QuoteId: answers[0].ID, Author: answers[0].Author, Cost: 2}, res)
+
+		// A revealed quote gets no more hints
+		res, err = h.client.RequestHintForQuoteGame(context.TODO(), &openapi.HintRequest{Type: openapi.HintTypeEliminateAuthor, QuoteId: openapi.NewOptInt(answers[0].ID)}, openapi.RequestHintForQuoteGameParams{ID: game.ID})
+		require.NoError(t, err)
+		require.IsType(t, &openapi.R422{}, res)
+		assert.Equal(t, "no_hint_available", res.(*openapi.R422).Message)
+
+		res, err = h.client.RequestHintForQuoteGame(context.TODO(), &openapi.HintRequest{Type: openapi.HintTypeEliminateAuthor}, openapi.RequestHintForQuoteGameParams{ID: game.ID})
+		require.NoError(t, err)
+		require.IsType(t, &openapi.Hint{}, res)
+		eliminated := res.(*openapi.Hint)
+		assert.NotEqual(t, answers[0].ID, eliminated.QuoteId)
+		assert.Contains(t, game.Authors, eliminated.Author)
+
+		// All answers are correct, but the hints cost three points
+		submitted := h.submit(t, game.ID, answers)
+		require.IsType(t, &openapi.QuoteGameResult{}, submitted)
+		result := submitted.(*openapi.QuoteGameResult)
+		assert.Equal(t, 0, result.Score)
+		assert.Len(t, result.Hints, 2)
+
+		// The game is over, so there are no more hints
+		res, err = h.client.RequestHintForQuoteGame(context.TODO(), &openapi.HintRequest{Type: openapi.HintTypeRevealPair}, openapi.RequestHintForQuoteGameParams{ID: game.ID})
+		require.NoError(t, err)
+		assert.Equal(t, &openapi.R404{Message: "not_found", Detail: openapi.NewOptString("We could not find what you are looking for.")}, res)
+	})
+
+	t.Run("avoids the quotes of recent games of a player", func(t *testing.T) {
+		h := startE2E(t)
+
+		// With 30 quotes and 10 excluded games, five games in a row never repeat a quote and still have enough distinct authors left
+		seen := map[int]bool{}
+		for range 5 {
+			game := h.createGame(t, "player-42")
+			for _, q := range game.Quotes {
+				assert.False(t, seen[q.ID], "quote %d is repeated", q.ID)
+				seen[q.ID] = true
+			}
+		}
+	})
+
+	t.Run("rejects answers after the deadline", func(t *testing.T) {
+		h := startE2E(t)
+		game := h.createGame(t, "")
+
+		_, err := h.db.Exec("update quote_game set created_at = ? where id = ?", time.Now().Add(-6*time.Minute), string(game.ID))
+		require.NoError(t, err)
+
+		res := h.submit(t, game.ID, correctAnswers(game))
+		assert.Equal(t, &openapi.R404{Message: "not_found", Detail: openapi.NewOptString("We could not find what you are looking for.")}, res)
+	})
+
+	t.Run("returns the stored result for a repeated submission", func(t *testing.T) {
+		h := startE2E(t)
+		game := h.createGame(t, "")
+
+		first := h.submit(t, game.ID, correctAnswers(game))
+		require.IsType(t, &openapi.QuoteGameResult{}, first)
+
+		res := h.submit(t, game.ID, correctAnswers(game))
+		assert.Equal(t, first, res)
+
+		// Also after the deadline, the result is still in the database
+		_, err := h.db.Exec("update quote_game set created_at = ? where id = ?", time.Now().Add(-6*time.Minute), string(game.ID))
+		require.NoError(t, err)
+		res = h.submit(t, game.ID, correctAnswers(game))
+		assert.Equal(t, first, res)
+	})
+
+	t.Run("rejects a second submission with other answers", func(t *testing.T) {
+		h := startE2E(t)
+		game := h.createGame(t, "")
+
+		res := h.submit(t, game.ID, correctAnswers(game))
+		require.IsType(t, &openapi.QuoteGameResult{}, res)
+
+		answers := correctAnswers(game)
+		answers[0].Author, answers[1].Author = answers[1].Author, answers[0].Author
+		res = h.submit(t, game.ID, answers)
+		assert.Equal(t, &openapi.R409{
+			Message: "quote_game_already_answered",
+			Detail:  openapi.NewOptString("You already answered this game with other answers."),
+		}, res)
+	})
+
+	t.Run("rejects an unknown game", func(t *testing.T) {
+		h := startE2E(t)
+
+		res := h.submit(t, "03f17f15-5d0a-49ea-aa05-039f2f18373e", []openapi.QuoteGameAnswer{
+			{ID: 1, Author: "Rumi"},
+			{ID: 2, Author: "Abdul Kalam"},
+			{ID: 4, Author: "Bill Gates"},
+		})
+		assert.Equal(t, &openapi.R404{Message: "not_found", Detail: openapi.NewOptString("We could not find what you are looking for.")}, res)
+	})
+
+	t.Run("rejects answers for other quotes", func(t *testing.T) {
+		h := startE2E(t)
+		game := h.createGame(t, "")
+
+		answers := correctAnswers(game)
+		answers[0].ID = 9999
+		res := h.submit(t, game.ID, answers)
+		require.IsType(t, &openapi.R422{}, res)
+		assert.Equal(t, "invalid_quote_id", res.(*openapi.R422).Message)
+
+		// A rejected answer doesn't complete the game
+		res = h.submit(t, game.ID, correctAnswers(game))
+		assert.IsType(t, &openapi.QuoteGameResult{}, res)
+	})
+
+	t.Run("rejects an incomplete answer", func(t *testing.T) {
+		h := startE2E(t)
+		game := h.createGame(t, "")
+
+		res := h.submit(t, game.ID, correctAnswers(game)[:2])
+		require.IsType(t, &openapi.R422{}, res)
+		assert.Equal(t, "invalid_quote_id", res.(*openapi.R422).Message)
+	})
+}
+
+func TestE2E_ConcurrentSubmissions(t *testing.T) {
+	// Connections to a database file don't share a cache like the in-memory databases, so this is how production handles parallel requests
+	h := startE2EWithDSN(t, "file:"+filepath.Join(t.TempDir(), "kabisa.db"))
+	game := h.createGame(t, "")
+
+	// Half of the submissions have all answers correct, the other half has two authors swapped
+	correct := correctAnswers(game)
+	swapped := correctAnswers(game)
+	swapped[0].Author, swapped[1].Author = swapped[1].Author, swapped[0].Author
+
+	const submissions = 10
+	responses := make([]openapi.SubmitAnswerForQuoteGameRes, submissions)
+	errs := make([]error, submissions)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range submissions {
+		answers := correct
+		if i%2 == 1 {
+			answers = swapped
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			responses[i], errs[i] = h.client.SubmitAnswerForQuoteGame(context.TODO(), answers, openapi.SubmitAnswerForQuoteGameParams{ID: game.ID})
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	// Only one submission completes the game. The others with the same answers get its result, the others get a conflict
+	var results []*openapi.QuoteGameResult
+	winners := map[bool]bool{}
+	for i, res := range responses {
+		require.NoError(t, errs[i])
+		if result, ok := res.(*openapi.QuoteGameResult); ok {
+			results = append(results, result)
+			winners[i%2 == 0] = true
+			continue
+		}
+		assert.IsType(t, &openapi.R409{}, res)
+	}
+	require.Len(t, winners, 1, "submissions with different answers both completed the game")
+	assert.Len(t, results, submissions/2)
+	for _, result := range results {
+		assert.Equal(t, results[0], result)
+	}
+}
+
+func TestE2E_IdempotencyKeys(t *testing.T) {
+	t.Run("replays a retried game creation", func(t *testing.T) {
+		h := startE2E(t)
+		params := openapi.CreateNewQuoteGameParams{
+			XPlayerID:      openapi.NewOptString("player-42"),
+			IdempotencyKey: openapi.NewOptString("create-1"),
+		}
+
+		first, err := h.client.CreateNewQuoteGame(context.TODO(), params)
+		require.NoError(t, err)
+		require.IsType(t, &openapi.CreateNewQuoteGameOK{}, first)
+		retry, err := h.client.CreateNewQuoteGame(context.TODO(), params)
+		require.NoError(t, err)
+		assert.Equal(t, first, retry)
+
+		var games int
+		require.NoError(t, h.db.QueryRow("select count(*) from quote_game").Scan(&games))
+		assert.Equal(t, 1, games)
+
+		// Another key creates another game
+		params.IdempotencyKey = openapi.NewOptString("create-2")
+		other, err := h.client.CreateNewQuoteGame(context.TODO(), params)
+		require.NoError(t, err)
+		require.IsType(t, &openapi.CreateNewQuoteGameOK{}, other)
+		assert.NotEqual(t, first.(*openapi.CreateNewQuoteGameOK).ID, other.(*openapi.CreateNewQuoteGameOK).ID)
+	})
+
+	t.Run("ignores the key of an anonymous caller without a player id", func(t *testing.T) {
+		h := startE2E(t)
+		params := openapi.CreateNewQuoteGameParams{IdempotencyKey: openapi.NewOptString("create-1")}
+
+		for range 2 {
+			res, err := h.client.CreateNewQuoteGame(context.TODO(), params)
+			require.NoError(t, err)
+			require.IsType(t, &openapi.CreateNewQuoteGameOK{}, res)
+		}
+
+		var games int
+		require.NoError(t, h.db.QueryRow("select count(*) from quote_game").Scan(&games))
+		assert.Equal(t, 2, games)
+	})
+
+	t.Run("rejects invalid credentials before replaying", func(t *testing.T) {
+		h := startE2E(t)
+		params := openapi.CreateNewQuoteGameParams{
+			XPlayerID:      openapi.NewOptString("player-42"),
+			IdempotencyKey: openapi.NewOptString("create-1"),
+		}
+		res, err := h.client.CreateNewQuoteGame(context.TODO(), params)
+		require.NoError(t, err)
+		require.IsType(t, &openapi.CreateNewQuoteGameOK{}, res)
+
+		// The generated client doesn't expect a 401 for this operation, so we send the retry ourselves
+		req, err := http.NewRequest(http.MethodPost, h.url+"/quote-game", nil)
+		require.NoError(t, err)
+		req.Header.Set("X-Player-Id", "player-42")
+		req.Header.Set("Idempotency-Key", "create-1")
+		req.Header.Set("X-Api-Key", "not-a-key")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+	})
+
+	// The answers of a game can't be sent with a player id, so the submissions are made by an authenticated caller
+	t.Run("replays a retried submission", func(t *testing.T) {
+		h := startE2E(t)
+		game := h.createGame(t, "")
+		params := openapi.SubmitAnswerForQuoteGameParams{ID: game.ID, IdempotencyKey: openapi.NewOptString("answer-1")}
+
+		first, err := h.adminClient.SubmitAnswerForQuoteGame(context.TODO(), correctAnswers(game), params)
+		require.NoError(t, err)
+		require.IsType(t, &openapi.QuoteGameResult{}, first)
+		retry, err := h.adminClient.SubmitAnswerForQuoteGame(context.TODO(), correctAnswers(game), params)
+		require.NoError(t, err)
+		assert.Equal(t, first, retry)
+	})
+
+	t.Run("rejects a key reused for another payload", func(t *testing.T) {
+		h := startE2E(t)
+		game := h.createGame(t, "")
+		params := openapi.SubmitAnswerForQuoteGameParams{ID: game.ID, IdempotencyKey: openapi.NewOptString("answer-1")}
+
+		res, err := h.adminClient.SubmitAnswerForQuoteGame(context.TODO(), correctAnswers(game), params)
+		require.NoError(t, err)
+		require.IsType(t, &openapi.QuoteGameResult{}, res)
+
+		answers := correctAnswers(game)
+		answers[0].Author, answers[1].Author = answers[1].Author, answers[0].Author
+		res, err = h.adminClient.SubmitAnswerForQuoteGame(context.TODO(), answers, params)
+		require.NoError(t, err)
+		assert.Equal(t, &openapi.R409{
+			Message: "idempotency_key_reused",
+			Detail:  openapi.NewOptString("This idempotency key was already used for another request."),
+		}, res)
+	})
+}
+
+// Malformed requests can't be made with the generated client, as it validates the requests itself
+func TestE2E_MalformedRequests(t *testing.T) {
+	h := startE2E(t)
+	game := h.createGame(t, "")
+
+	type Test struct {
+		path               string
+		body               string
+		expectedStatusCode int
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			resp, err := http.Post(h.url+tt.path, "application/json", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.expectedStatusCode, resp.StatusCode)
+		}
+	}
+
+	t.Run("rejects invalid json", run(Test{
+		path:               fmt.Sprintf("/quote-game/%s/answer", game.ID),
+		body:               `[{"id": 1, "author": `,
+		expectedStatusCode: http.StatusBadRequest,
+	}))
+
+	t.Run("rejects answers of the wrong type", run(Test{
+		path:               fmt.Sprintf("/quote-game/%s/answer", game.ID),
+		body:               `[{"id": "one", "author": "Rumi"}]`,
+		expectedStatusCode: http.StatusBadRequest,
+	}))
+
+	t.Run("rejects a game id that is not a uuid", run(Test{
+		path:               "/quote-game/not-a-uuid/answer",
+		body:               `[]`,
+		expectedStatusCode: http.StatusBadRequest,
+	}))
+
+	t.Run("rejects an unknown path", run(Test{
+		path:               "/quote-games",
+		body:               `{}`,
+		expectedStatusCode: http.StatusNotFound,
+	}))
+
+	// None of the malformed requests touched the game
+	res := h.submit(t, game.ID, correctAnswers(game))
+	assert.IsType(t, &openapi.QuoteGameResult{}, res)
+}
+
+func TestE2E_Curation(t *testing.T) {
+	h := startE2E(t)
+
+	// Hiding all but three quotes leaves exactly one possible game
+	visible := map[int]bool{1: true, 4: true, 7: true}
+	for _, q := range fakequotes.DefaultQuotes() {
+		if visible[q.ID] {
+			continue
+		}
+		res, err := h.adminClient.UpdateQuote(context.TODO(), &openapi.QuoteEdit{
+			Quote:  q.Quote,
+			Author: q.Author,
+			Hidden: openapi.NewOptBool(true),
+		}, openapi.UpdateQuoteParams{ID: q.ID})
+		require.NoError(t, err)
+		require.IsType(t, &openapi.CuratedQuote{}, res)
+	}
+
+	game := h.createGame(t, "")
+	ids := []int{}
+	for _, q := range game.Quotes {
+		ids = append(ids, q.ID)
+	}
+	assert.ElementsMatch(t, []int{1, 4, 7}, ids)
+
+	// Anonymous clients can't curate. The generated client refuses to send this request without credentials, so we send it ourselves
+	req, err := http.NewRequest(http.MethodDelete, h.url+"/admin/quotes/1", http.NoBody)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestE2E_Backups(t *testing.T) {
+	h := startE2EWithDSN(t, "file:"+filepath.Join(t.TempDir(), "kabisa.db"))
+	game := h.createGame(t, "player-42")
+
+	res, err := h.adminClient.CreateBackup(context.TODO())
+	require.NoError(t, err)
+	require.IsType(t, &openapi.Backup{}, res)
+	backup := res.(*openapi.Backup)
+	assert.Positive(t, backup.Size)
+
+	list, err := h.adminClient.ListBackups(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, &openapi.ListBackupsOKApplicationJSON{*backup}, list)
+
+	// The backup is a database with the game in it, made while the api was running
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(h.backupDir, backup.Name))
+	require.NoError(t, err)
+	defer db.Close()
+	var playerID string
+	err = db.QueryRow("select player_id from quote_game where id = ?", game.ID).Scan(&playerID)
+	require.NoError(t, err)
+	assert.Equal(t, "player-42", playerID)
+
+	// Anonymous clients can't make backups. The generated client refuses to send this request without credentials, so we send it ourselves
+	resp, err := http.Post(h.url+"/admin/backups", "application/json", http.NoBody)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestE2E_ExportGames(t *testing.T) {
+	h := startE2E(t)
+	completed := h.createGame(t, "player-42")
+	require.IsType(t, &openapi.QuoteGameResult{}, h.submit(t, completed.ID, correctAnswers(completed)))
+	open := h.createGame(t, "")
+
+	export := func(t *testing.T, query string) (*http.Response, string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, h.url+"/admin/exports/games?"+query, http.NoBody)
+		require.NoError(t, err)
+		req.Header.Set("X-Api-Key", e2eAdminKey)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	t.Run("exports the games as CSV", func(t *testing.T) {
+		resp, body := export(t, "format=csv")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+
+		rows, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, "id", rows[0][0])
+		assert.Equal(t, []string{string(completed.ID), "match", "player-42", ""}, rows[1][:4])
+		assert.Equal(t, []string{"true", "true", "true"}, rows[1][7:10])
+		assert.NotEmpty(t, rows[1][11])
+		assert.Equal(t, []string{string(open.ID), "match", ""}, rows[2][:3])
+		// An open game has no results yet
+		assert.Equal(t, []string{"", "", ""}, rows[2][7:10])
+		assert.Equal(t, []string{"", ""}, rows[2][11:])
+	})
+
+	t.Run("exports the games as NDJSON", func(t *testing.T) {
+		resp, body := export(t, "format=ndjson")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+		lines := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+		require.Len(t, lines, 2)
+		var game struct {
+			ID      string `json:"id"`
+			Correct []bool `json:"correct"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &game))
+		assert.Equal(t, string(completed.ID), game.ID)
+		assert.Equal(t, []bool{true, true, true}, game.Correct)
+	})
+
+	t.Run("exports only the games in the range", func(t *testing.T) {
+		resp, body := export(t, "format=ndjson&to="+url.QueryEscape(time.Now().Add(-time.Hour).Format(time.RFC3339)))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, body)
+	})
+
+	t.Run("rejects an unknown format", func(t *testing.T) {
+		resp, _ := export(t, "format=xml")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("rejects anonymous clients", func(t *testing.T) {
+		resp, err := http.Get(h.url + "/admin/exports/games?format=csv")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestE2E_Stats(t *testing.T) {
+	h := startE2E(t)
+	game := h.createGame(t, "")
+
+	// Every quote gets the author of the next quote
+	answers := correctAnswers(game)
+	wrong := make([]openapi.QuoteGameAnswer, len(answers))
+	expectedCorrect := 0
+	for i := range answers {
+		wrong[i] = openapi.QuoteGameAnswer{ID: answers[i].ID, Author: answers[(i+1)%len(answers)].Author}
+		if wrong[i].Author == answers[i].Author {
+			expectedCorrect++
+		}
+	}
+	require.IsType(t, &openapi.QuoteGameResult{}, h.submit(t, game.ID, wrong))
+	// A game that is not completed is not counted
+	h.createGame(t, "")
+
+	res, err := h.client.ListQuoteStats(context.TODO(), openapi.ListQuoteStatsParams{})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.QuoteStatsPage{}, res)
+	stats := res.(*openapi.QuoteStatsPage)
+	assert.Equal(t, 3, stats.Total)
+	correct := 0
+	for _, item := range stats.Items {
+		assert.Equal(t, 1, item.Attempts)
+		assert.True(t, item.Author.Set)
+		correct += item.Correct
+	}
+	assert.Equal(t, expectedCorrect, correct)
+
+	confusionRes, err := h.client.ListAuthorConfusions(context.TODO(), openapi.ListAuthorConfusionsParams{})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.AuthorConfusionPage{}, confusionRes)
+	confusions := 0
+	for _, item := range confusionRes.(*openapi.AuthorConfusionPage).Items {
+		assert.NotEqual(t, item.Author, item.GuessedAuthor)
+		confusions += item.Count
+	}
+	assert.Equal(t, 3-expectedCorrect, confusions)
+
+	// Anything can be typed as an answer, but only the authors that were offered in the game are listed as confusions
+	spam := h.createGame(t, "")
+	typed := make([]openapi.QuoteGameAnswer, len(spam.Quotes))
+	for i, q := range spam.Quotes {
+		typed[i] = openapi.QuoteGameAnswer{ID: q.ID, Author: "Visit my site"}
+	}
+	require.IsType(t, &openapi.QuoteGameResult{}, h.submit(t, spam.ID, typed))
+	confusionRes, err = h.client.ListAuthorConfusions(context.TODO(), openapi.ListAuthorConfusionsParams{})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.AuthorConfusionPage{}, confusionRes)
+	for _, item := range confusionRes.(*openapi.AuthorConfusionPage).Items {
+		assert.NotEqual(t, "Visit my site", item.GuessedAuthor)
+	}
+}
+
+func TestE2E_Languages(t *testing.T) {
+	h := startE2E(t)
+	ctx := context.TODO()
+
+	dutchIDs := []int{}
+	for _, q := range []openapi.QuoteEdit{
+		{Quote: "Voorkomen is beter dan genezen.", Author: "Erasmus"},
+		{Quote: "Wie wat vindt, heeft wat verloren.", Author: "Jacob Cats"},
+		{Quote: "Wie kan de zon het schijnen beletten?", Author: "Vondel"},
+	} {
+		q.Language = openapi.NewOptLanguage(openapi.LanguageNl)
+		res, err := h.adminClient.CreateQuote(ctx, &q)
+		require.NoError(t, err)
+		require.IsType(t, &openapi.CuratedQuote{}, res)
+		dutchIDs = append(dutchIDs, res.(*openapi.CuratedQuote).ID)
+	}
+
+	dutch := openapi.NewOptString("nl-NL,nl;q=0.9,en;q=0.8")
+	quoteRes, err := h.client.GetRandomQuote(ctx, openapi.GetRandomQuoteParams{AcceptLanguage: dutch})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.Quote{}, quoteRes)
+	assert.Equal(t, openapi.LanguageNl, quoteRes.(*openapi.Quote).Language)
+	assert.Contains(t, dutchIDs, quoteRes.(*openapi.Quote).ID)
+
+	gameRes, err := h.client.CreateNewQuoteGame(ctx, openapi.CreateNewQuoteGameParams{AcceptLanguage: dutch})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.CreateNewQuoteGameOK{}, gameRes)
+	ids := []int{}
+	for _, q := range gameRes.(*openapi.CreateNewQuoteGameOK).Quotes {
+		ids = append(ids, q.ID)
+	}
+	assert.ElementsMatch(t, dutchIDs, ids)
+
+	// Without a preference, the quotes are English
+	quoteRes, err = h.client.GetRandomQuote(ctx, openapi.GetRandomQuoteParams{})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.Quote{}, quoteRes)
+	assert.Equal(t, openapi.LanguageEn, quoteRes.(*openapi.Quote).Language)
+
+	// Errors get a human readable detail in the preferred language
+	req, err := http.NewRequest(http.MethodGet, h.url+"/quotes/99999", http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Language", "nl")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "nl", resp.Header.Get("Content-Language"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"message": "not_found", "detail": "We konden niet vinden wat je zoekt."}`, string(body))
+}
+
+func TestE2E_Room(t *testing.T) {
+	h := startE2E(t)
+	ctx := context.TODO()
+
+	res, err := h.client.CreateRoom(ctx, openapi.CreateRoomParams{XPlayerID: openapi.NewOptString("host")})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.Room{}, res)
+	room := res.(*openapi.Room)
+	assert.Len(t, room.Code, 6)
+	assert.Equal(t, "host", room.Host)
+
+	// Codes are not case sensitive, and every participant gets the same game
+	joinRes, err := h.client.JoinRoom(ctx, openapi.JoinRoomParams{Code: strings.ToLower(room.Code), XPlayerID: openapi.NewOptString("player-1")})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.Room{}, joinRes)
+	assert.Equal(t, room, joinRes)
+	_, err = h.client.JoinRoom(ctx, openapi.JoinRoomParams{Code: room.Code, XPlayerID: openapi.NewOptString("player-2")})
+	require.NoError(t, err)
+
+	correct := correctAnswers(&openapi.CreateNewQuoteGameOK{Quotes: room.Quotes})
+	wrong := []openapi.QuoteGameAnswer{
+		{ID: correct[0].ID, Author: correct[1].Author},
+		{ID: correct[1].ID, Author: correct[0].Author},
+		correct[2],
+	}
+
+	// The game of the room can only be answered through the room. Otherwise a participant could learn the authors from
+	// the result first, and complete the game the room shares
+	gameRes := h.submit(t, room.ID, wrong)
+	assert.IsType(t, &openapi.R404{}, gameRes)
+	blanks := make([]openapi.BlankAnswer, len(correct))
+	for i, answer := range correct {
+		blanks[i] = openapi.BlankAnswer{ID: answer.ID, Answer: "word"}
+	}
+	blanksRes, err := h.client.SubmitBlanksForQuoteGame(ctx, blanks, openapi.SubmitBlanksForQuoteGameParams{ID: room.ID})
+	require.NoError(t, err)
+	assert.IsType(t, &openapi.R404{}, blanksRes)
+
+	answerRes, err := h.client.SubmitAnswerForRoom(ctx, wrong, openapi.SubmitAnswerForRoomParams{Code: room.Code, XPlayerID: openapi.NewOptString("player-1")})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.QuoteGameResult{}, answerRes)
+	answerRes, err = h.client.SubmitAnswerForRoom(ctx, correct, openapi.SubmitAnswerForRoomParams{Code: room.Code, XPlayerID: openapi.NewOptString("host")})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.QuoteGameResult{}, answerRes)
+
+	// Every participant can answer only once, and only after joining
+	answerRes, err = h.client.SubmitAnswerForRoom(ctx, correct, openapi.SubmitAnswerForRoomParams{Code: room.Code, XPlayerID: openapi.NewOptString("player-1")})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.R422{}, answerRes)
+	assert.Equal(t, "room_already_answered", answerRes.(*openapi.R422).Message)
+	answerRes, err = h.client.SubmitAnswerForRoom(ctx, correct, openapi.SubmitAnswerForRoomParams{Code: room.Code, XPlayerID: openapi.NewOptString("stranger")})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.R422{}, answerRes)
+	assert.Equal(t, "room_not_joined", answerRes.(*openapi.R422).Message)
+
+	resultRes, err := h.client.GetRoomResult(ctx, openapi.GetRoomResultParams{Code: room.Code})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.RoomResult{}, resultRes)
+	result := resultRes.(*openapi.RoomResult)
+	require.Len(t, result.Participants, 3)
+	assert.Equal(t, "host", result.Participants[0].PlayerId)
+	assert.Equal(t, openapi.NewOptInt(3), result.Participants[0].Score)
+	assert.Equal(t, "player-1", result.Participants[1].PlayerId)
+	assert.Equal(t, openapi.NewOptInt(1), result.Participants[1].Score)
+	assert.Equal(t, "player-2", result.Participants[2].PlayerId)
+	assert.False(t, result.Participants[2].Submitted)
+
+	// After the deadline the room can't be joined or answered, but the result stays available
+	_, err = h.db.Exec(`UPDATE quote_game SET created_at = ? WHERE id IN (SELECT quote_game_id FROM room WHERE code = ?)`, time.Now().Add(-time.Hour), room.Code)
+	require.NoError(t, err)
+	joinRes, err = h.client.JoinRoom(ctx, openapi.JoinRoomParams{Code: room.Code, XPlayerID: openapi.NewOptString("late")})
+	require.NoError(t, err)
+	assert.IsType(t, &openapi.R404{}, joinRes)
+	answerRes, err = h.client.SubmitAnswerForRoom(ctx, correct, openapi.SubmitAnswerForRoomParams{Code: room.Code, XPlayerID: openapi.NewOptString("player-2")})
+	require.NoError(t, err)
+	assert.IsType(t, &openapi.R404{}, answerRes)
+	resultRes, err = h.client.GetRoomResult(ctx, openapi.GetRoomResultParams{Code: room.Code})
+	require.NoError(t, err)
+	assert.IsType(t, &openapi.RoomResult{}, resultRes)
+
+	// A room needs to know who the player is
+	res, err = h.client.CreateRoom(ctx, openapi.CreateRoomParams{})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.R422{}, res)
+	assert.Equal(t, "player_id_required", res.(*openapi.R422).Message)
+}
+
+func TestE2E_RoomEvents(t *testing.T) {
+	h := startE2E(t)
+	ctx := context.TODO()
+
+	res, err := h.client.CreateRoom(ctx, openapi.CreateRoomParams{XPlayerID: openapi.NewOptString("host")})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.Room{}, res)
+	room := res.(*openapi.Room)
+
+	stream, err := http.Get(h.url + "/rooms/" + room.Code + "/events")
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+
+	// Joining through the api is pushed to the stream
+	_, err = h.client.JoinRoom(ctx, openapi.JoinRoomParams{Code: room.Code, XPlayerID: openapi.NewOptString("player-1")})
+	require.NoError(t, err)
+
+	scanner := bufio.NewScanner(stream.Body)
+	require.True(t, scanner.Scan())
+	assert.Equal(t, "event: player_joined", scanner.Text())
+	require.True(t, scanner.Scan())
+	assert.Contains(t, scanner.Text(), `"playerId":"player-1"`)
+}
+
+func TestE2E_DailyChallenge(t *testing.T) {
+	h := startE2E(t)
+	ctx := context.TODO()
+
+	play := func(playerID string) openapi.CreateDailyQuoteGameRes {
+		t.Helper()
+		res, err := h.client.CreateDailyQuoteGame(ctx, openapi.CreateDailyQuoteGameParams{XPlayerID: openapi.NewOptString(playerID)})
+		require.NoError(t, err)
+		return res
+	}
+
+	// Everyone gets the same quotes today, but every player gets their own game
+	res := play("player-1")
+	require.IsType(t, &openapi.DailyQuoteGame{}, res)
+	game1 := res.(*openapi.DailyQuoteGame)
+	assert.Equal(t, time.Now().UTC().Format(time.DateOnly), game1.Date.Format(time.DateOnly))
+	require.Len(t, game1.Quotes, 3)
+	res = play("player-2")
+	require.IsType(t, &openapi.DailyQuoteGame{}, res)
+	game2 := res.(*openapi.DailyQuoteGame)
+	assert.Equal(t, game1.Quotes, game2.Quotes)
+	assert.Equal(t, game1.Authors, game2.Authors)
+	assert.NotEqual(t, game1.ID, game2.ID)
+
+	// The challenge can be played once a day
+	res = play("player-1")
+	require.IsType(t, &openapi.R409{}, res)
+	assert.Equal(t, "daily_challenge_already_played", res.(*openapi.R409).Message)
+	res, err := h.client.CreateDailyQuoteGame(ctx, openapi.CreateDailyQuoteGameParams{})
+	require.NoError(t, err)
+	assert.IsType(t, &openapi.R422{}, res)
+
+	// The games are answered like any other game and show up in the leaderboard
+	correct := correctAnswers(&openapi.CreateNewQuoteGameOK{Quotes: game1.Quotes})
+	require.IsType(t, &openapi.QuoteGameResult{}, h.submit(t, game2.ID, correct))
+	wrong := []openapi.QuoteGameAnswer{
+		{ID: correct[0].ID, Author: correct[1].Author},
+		{ID: correct[1].ID, Author: correct[0].Author},
+		correct[2],
+	}
+	require.IsType(t, &openapi.QuoteGameResult{}, h.submit(t, game1.ID, wrong))
+
+	leaderboardRes, err := h.client.GetDailyLeaderboard(ctx, openapi.GetDailyLeaderboardParams{})
+	require.NoError(t, err)
+	require.IsType(t, &openapi.DailyLeaderboard{}, leaderboardRes)
+	leaderboard := leaderboardRes.(*openapi.DailyLeaderboard)
+	assert.Equal(t, 2, leaderboard.Total)
+	require.Len(t, leaderboard.Items, 2)
+	assert.Equal(t, openapi.DailyLeaderboardEntry{Rank: 1, PlayerId: "player-2", Score: 3, CompletedAt: leaderboard.Items[0].CompletedAt}, leaderboard.Items[0])
+	assert.Equal(t, "player-1", leaderboard.Items[1].PlayerId)
+	assert.Equal(t, 1, leaderboard.Items[1].Score)
+}
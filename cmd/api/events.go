@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/openapi"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// sseHeartbeatInterval keeps idle streams open through proxies that close silent connections
+	sseHeartbeatInterval = 15 * time.Second
+	// eventBufferSize is the number of events a slow client can lag behind before it misses events
+	eventBufferSize = 16
+)
+
+// The event streams are not operations of the generated server, so they have operation ids of their own for their rate limits
+const (
+	streamQuoteGameEventsOperation = "streamQuoteGameEvents"
+	streamRoomEventsOperation      = "streamRoomEvents"
+)
+
+var eventOperationIDs = []string{streamQuoteGameEventsOperation, streamRoomEventsOperation}
+
+// sseEvent is the data of a Server-Sent Event. The name of the event is the type as well, so clients can listen per type
+type sseEvent struct {
+	Type     models.EventType `json:"type"`
+	PlayerID string           `json:"playerId,omitempty"`
+	Score    *int             `json:"score,omitempty"`
+	Deadline *time.Time       `json:"deadline,omitempty"`
+	Time     time.Time        `json:"time"`
+}
+
+// initEventHandler mounts the event streams next to the api. The streams are not part of openapi.yaml, as ogen doesn't support Server-Sent Events.
+// Opening a stream is rate limited like the operations of the api, and streams limits the number of streams a client has open at once
+func initEventHandler(app *application, api http.Handler, rateLimiter *rateLimiter, streams *streamLimiter) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /quote-game/{id}/events", rateLimiter.operation(streamQuoteGameEventsOperation, streams.middleware(http.HandlerFunc(app.streamQuoteGameEvents))))
+	mux.Handle("GET /rooms/{code}/events", rateLimiter.operation(streamRoomEventsOperation, streams.middleware(http.HandlerFunc(app.streamRoomEvents))))
+	mux.Handle("/", api)
+	return mux
+}
+
+// streamLimiter limits the number of event streams a single client has open at once. A stream holds its connection and subscription
+// until the game ends, so the rate limit alone doesn't bound them. Clients are identified in the same way as by the rate limiter.
+type streamLimiter struct {
+	logger    *zerolog.Logger
+	clientKey func(r *http.Request) string
+	// max is the number of streams a client can have open at once. 0 doesn't limit the streams
+	max int
+
+	mu   sync.Mutex
+	open map[string]int
+}
+
+func newStreamLimiter(logger *zerolog.Logger, clientKey func(r *http.Request) string, max int) *streamLimiter {
+	return &streamLimiter{
+		logger:    logger,
+		clientKey: clientKey,
+		max:       max,
+		open:      map[string]int{},
+	}
+}
+
+// middleware rejects a stream with a 429 when the client already has the maximum number of streams open
+func (sl *streamLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sl.max == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		client := sl.clientKey(r)
+		if !sl.acquire(client) {
+			sl.logger.Debug().Str("client", client).Msg("client has too many open event streams")
+			writeJSON(w, http.StatusTooManyRequests, &openapi.R429{Message: "too_many_streams"})
+			return
+		}
+		defer sl.release(client)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (sl *streamLimiter) acquire(client string) bool {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	if sl.open[client] >= sl.max {
+		return false
+	}
+	sl.open[client]++
+	return true
+}
+
+// release closes a stream of the client. Clients without open streams are removed, so memory doesn't grow with every client ever seen
+func (sl *streamLimiter) release(client string) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.open[client]--
+	if sl.open[client] <= 0 {
+		delete(sl.open, client)
+	}
+}
+
+// streamQuoteGameEvents streams the events of a single player game, until the result is available or the game expired
+func (app *application) streamQuoteGameEvents(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, &openapi.R404{Message: "not_found"})
+		return
+	}
+
+	// We subscribe before looking up the game, so no event can be missed in between
+	events, unsubscribe := app.events.Subscribe(models.QuoteGameTopic(id))
+	defer unsubscribe()
+
+	status, err := app.quoteService.GetQuoteGameStatus(r.Context(), id)
+	if err == models.ErrQuoteGameIdNotFound {
+		writeJSON(w, http.StatusNotFound, &openapi.R404{Message: "not_found"})
+		return
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling quoteService.GetQuoteGameStatus")
+		writeJSON(w, http.StatusInternalServerError, &openapi.R500{Message: "unknown_error"})
+		return
+	}
+
+	var ended *models.Event
+	switch {
+	case status.Completed:
+		ended = &models.Event{Type: models.EventResultsAvailable, Deadline: status.Deadline, Time: time.Now()}
+	case time.Now().After(status.Deadline):
+		ended = &models.Event{Type: models.EventGameExpired, Deadline: status.Deadline, Time: time.Now()}
+	}
+	app.streamEvents(w, r, events, ended, func(event models.Event) bool {
+		return event.Type == models.EventResultsAvailable || event.Type == models.EventGameExpired
+	})
+}
+
+// streamRoomEvents streams the events of a room, until the game of the room expired
+func (app *application) streamRoomEvents(w http.ResponseWriter, r *http.Request) {
+	// Room codes are not case sensitive, the topic always uses the normalized code like the room service does
+	code := models.NormalizeRoomCode(r.PathValue("code"))
+
+	// We subscribe before looking up the room, so no event can be missed in between
+	events, unsubscribe := app.events.Subscribe(models.RoomTopic(code))
+	defer unsubscribe()
+
+	room, err := app.roomService.GetRoomResult(r.Context(), code)
+	if err == models.ErrRoomNotFound {
+		writeJSON(w, http.StatusNotFound, &openapi.R404{Message: "not_found"})
+		return
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling roomService.GetRoomResult")
+		writeJSON(w, http.StatusInternalServerError, &openapi.R500{Message: "unknown_error"})
+		return
+	}
+
+	var ended *models.Event
+	if time.Now().After(room.Deadline) {
+		ended = &models.Event{Type: models.EventGameExpired, Deadline: room.Deadline, Time: time.Now()}
+	}
+	app.streamEvents(w, r, events, ended, func(event models.Event) bool {
+		return event.Type == models.EventGameExpired
+	})
+}
+
+// streamEvents writes the events as Server-Sent Events until a final event is written or the client disconnects.
+// When the game already ended, only the ended event is written, so the client knows it can stop listening.
+func (app *application) streamEvents(w http.ResponseWriter, r *http.Request, events <-chan models.Event, ended *models.Event, final func(models.Event) bool) {
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if ended != nil {
+		_ = writeEvent(w, *ended)
+		_ = rc.Flush()
+		return
+	}
+	// The headers are flushed right away, so the client knows the stream is open
+	if err := rc.Flush(); err != nil {
+		app.logger.Error().Err(err).Msg("could not flush event stream")
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			_, err := fmt.Fprint(w, ": heartbeat\n\n")
+			if err == nil {
+				err = rc.Flush()
+			}
+			if err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			err := writeEvent(w, event)
+			if err == nil {
+				err = rc.Flush()
+			}
+			if err != nil || final(event) {
+				return
+			}
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event models.Event) error {
+	data := sseEvent{
+		Type:     event.Type,
+		PlayerID: event.PlayerID,
+		Score:    event.Score,
+		Time:     event.Time,
+	}
+	if !event.Deadline.IsZero() {
+		data.Deadline = &event.Deadline
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, encoded)
+	return err
+}
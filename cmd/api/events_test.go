@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/pubsub"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startEventServer serves the event streams of an application with the given services and a real broker
+func startEventServer(t *testing.T, app *application) (*httptest.Server, *pubsub.Broker) {
+	t.Helper()
+
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	broker := pubsub.NewBroker(&logger, eventBufferSize)
+	app.logger = &logger
+	app.events = broker
+
+	// Without limits, the streams are neither rate limited nor limited in number
+	rl := newRateLimiter(&logger, nil, nil, map[string]rateLimit{}, nil)
+	srv := httptest.NewServer(initEventHandler(app, http.NotFoundHandler(), rl, newStreamLimiter(&logger, rl.clientKey, 0)))
+	t.Cleanup(srv.Close)
+	return srv, broker
+}
+
+// readEvents reads the stream until it's closed by the server and returns the data of every event
+func readEvents(t *testing.T, body io.Reader) []sseEvent {
+	t.Helper()
+
+	events := []sseEvent{}
+	name := ""
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event := sseEvent{}
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event))
+			assert.Equal(t, name, string(event.Type))
+			events = append(events, event)
+		}
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func TestApplication_StreamRoomEvents(t *testing.T) {
+	deadline := time.Now().Add(time.Minute).UTC()
+	mockedRoomService := new(MockedRoomService)
+	mockedRoomService.On("GetRoomResult", "ABC234").Return(&models.RoomResult{Room: models.Room{Code: "ABC234", Deadline: deadline}}, nil)
+	mockedRoomService.On("GetRoomResult", "OLD234").Return(&models.RoomResult{Room: models.Room{Code: "OLD234", Deadline: time.Now().Add(-time.Minute)}}, nil)
+	mockedRoomService.On("GetRoomResult", "ZZZ999").Return((*models.RoomResult)(nil), models.ErrRoomNotFound)
+
+	srv, broker := startEventServer(t, &application{roomService: mockedRoomService})
+
+	t.Run("streams the events of the room until the game expired", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "/rooms/abc234/events")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+		// The handler subscribed before it sent the headers, so nothing published from now on is missed
+		score := 2
+		broker.Publish(models.Event{Topic: "room:ABC234", Type: models.EventPlayerJoined, PlayerID: "player-1"})
+		broker.Publish(models.Event{Topic: "room:ZZZ999", Type: models.EventPlayerJoined, PlayerID: "someone-else"})
+		broker.Publish(models.Event{Topic: "room:ABC234", Type: models.EventAnswerSubmitted, PlayerID: "player-1", Score: &score})
+		broker.Publish(models.Event{Topic: "room:ABC234", Type: models.EventGameExpired, Deadline: deadline})
+
+		events := readEvents(t, res.Body)
+		require.Len(t, events, 3)
+		assert.Equal(t, models.EventPlayerJoined, events[0].Type)
+		assert.Equal(t, "player-1", events[0].PlayerID)
+		assert.Nil(t, events[0].Deadline)
+		assert.Equal(t, models.EventAnswerSubmitted, events[1].Type)
+		assert.Equal(t, &score, events[1].Score)
+		assert.Equal(t, models.EventGameExpired, events[2].Type)
+		require.NotNil(t, events[2].Deadline)
+		assert.True(t, deadline.Equal(*events[2].Deadline))
+	})
+
+	t.Run("follows the same room for a code with spaces around it", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "/rooms/%20abc234%20/events")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		broker.Publish(models.Event{Topic: "room:ABC234", Type: models.EventGameExpired, Deadline: deadline})
+
+		events := readEvents(t, res.Body)
+		require.Len(t, events, 1)
+		assert.Equal(t, models.EventGameExpired, events[0].Type)
+	})
+
+	t.Run("ends right away when the room expired already", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "/rooms/OLD234/events")
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		events := readEvents(t, res.Body)
+		require.Len(t, events, 1)
+		assert.Equal(t, models.EventGameExpired, events[0].Type)
+	})
+
+	t.Run("returns a 404 for an unknown room", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "/rooms/ZZZ999/events")
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"message":"not_found"}`, string(body))
+	})
+}
+
+func TestApplication_StreamQuoteGameEvents(t *testing.T) {
+	open, completed, unknown := uuid.New(), uuid.New(), uuid.New()
+	mockedQuoteService := new(MockedQuoteService)
+	mockedQuoteService.On("GetQuoteGameStatus", open).Return(&models.QuoteGameStatus{ID: open, Deadline: time.Now().Add(time.Minute)}, nil)
+	mockedQuoteService.On("GetQuoteGameStatus", completed).Return(&models.QuoteGameStatus{ID: completed, Deadline: time.Now().Add(time.Minute), Completed: true}, nil)
+	mockedQuoteService.On("GetQuoteGameStatus", unknown).Return((*models.QuoteGameStatus)(nil), models.ErrQuoteGameIdNotFound)
+
+	srv, broker := startEventServer(t, &application{quoteService: mockedQuoteService})
+
+	t.Run("streams the events of the game until the result is available", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "/quote-game/" + open.String() + "/events")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		score := 3
+		broker.Publish(models.Event{Topic: models.QuoteGameTopic(open), Type: models.EventDeadlineApproaching})
+		broker.Publish(models.Event{Topic: models.QuoteGameTopic(open), Type: models.EventResultsAvailable, Score: &score})
+
+		events := readEvents(t, res.Body)
+		require.Len(t, events, 2)
+		assert.Equal(t, models.EventDeadlineApproaching, events[0].Type)
+		assert.Equal(t, models.EventResultsAvailable, events[1].Type)
+		assert.Equal(t, &score, events[1].Score)
+	})
+
+	t.Run("ends right away when the game is answered already", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "/quote-game/" + completed.String() + "/events")
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		events := readEvents(t, res.Body)
+		require.Len(t, events, 1)
+		assert.Equal(t, models.EventResultsAvailable, events[0].Type)
+	})
+
+	for _, id := range []string{unknown.String(), "not-a-uuid"} {
+		t.Run("returns a 404 for "+id, func(t *testing.T) {
+			res, err := http.Get(srv.URL + "/quote-game/" + id + "/events")
+			require.NoError(t, err)
+			defer res.Body.Close()
+			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		})
+	}
+}
+
+func TestEventStreamLimits(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	mockedRoomService := new(MockedRoomService)
+	mockedRoomService.On("GetRoomResult", "ABC234").Return(&models.RoomResult{Room: models.Room{Code: "ABC234", Deadline: deadline}}, nil)
+	mockedRoomService.On("GetRoomResult", "OLD234").Return(&models.RoomResult{Room: models.Room{Code: "OLD234", Deadline: time.Now().Add(-time.Minute)}}, nil)
+
+	start := func(t *testing.T, limits map[string]rateLimit, maxStreams int) (*httptest.Server, *pubsub.Broker) {
+		t.Helper()
+
+		logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+		broker := pubsub.NewBroker(&logger, eventBufferSize)
+		app := &application{logger: &logger, events: broker, roomService: mockedRoomService}
+		rl := newRateLimiter(&logger, nil, nil, limits, nil)
+		srv := httptest.NewServer(initEventHandler(app, http.NotFoundHandler(), rl, newStreamLimiter(&logger, rl.clientKey, maxStreams)))
+		t.Cleanup(srv.Close)
+		return srv, broker
+	}
+
+	t.Run("rate limits opening streams", func(t *testing.T) {
+		srv, _ := start(t, map[string]rateLimit{streamRoomEventsOperation: {perMinute: 1, burst: 1}}, 0)
+
+		res, err := http.Get(srv.URL + "/rooms/OLD234/events")
+		require.NoError(t, err)
+		readEvents(t, res.Body)
+		res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		res, err = http.Get(srv.URL + "/rooms/OLD234/events")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		assert.Equal(t, "60", res.Header.Get("Retry-After"))
+	})
+
+	t.Run("limits the number of open streams of a client", func(t *testing.T) {
+		srv, broker := start(t, map[string]rateLimit{}, 1)
+
+		first, err := http.Get(srv.URL + "/rooms/ABC234/events")
+		require.NoError(t, err)
+		defer first.Body.Close()
+		require.Equal(t, http.StatusOK, first.StatusCode)
+
+		// The first stream is still open
+		res, err := http.Get(srv.URL + "/rooms/ABC234/events")
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		assert.JSONEq(t, `{"message":"too_many_streams"}`, string(body))
+
+		// When the first stream ended, the client can open another one
+		broker.Publish(models.Event{Topic: "room:ABC234", Type: models.EventGameExpired, Deadline: deadline})
+		readEvents(t, first.Body)
+		res, err = http.Get(srv.URL + "/rooms/OLD234/events")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+}
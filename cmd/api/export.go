@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/database"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/repositories"
+	"github.com/pietdevries94/Kabisa/services"
+	"github.com/rs/zerolog"
+)
+
+// runExportCommand writes the games of the database from the config to a file and prints its path, like GET /admin/exports/games.
+// It's safe to use while the api is running. An existing file is never overwritten.
+func runExportCommand(logger *zerolog.Logger, conf *config, args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", string(models.ExportFormatCSV), "the file format of the export, csv or ndjson")
+	output := flags.String("o", "", "the file to write the export to")
+	from := flags.String("from", "", "only export games created at or after this RFC 3339 time")
+	to := flags.String("to", "", "only export games created before this RFC 3339 time")
+	flags.Parse(args) //nolint:errcheck // the flag set exits on errors
+
+	if !models.ExportFormat(*format).Valid() {
+		logger.Fatal().Str("value", *format).Msg("the format should be csv or ndjson")
+	}
+	if *output == "" {
+		logger.Fatal().Msg("give the file to write the export to with -o")
+	}
+	fromTime := parseExportTime(logger, "from", *from)
+	toTime := parseExportTime(logger, "to", *to)
+	if database.IsMemory(conf.sqliteDSN) {
+		logger.Fatal().Str("DSN", conf.sqliteDSN).Msg("the database is in memory, so only the api itself can export it")
+	}
+
+	db := initDatabase(logger, conf)
+	defer db.Close()
+	exportService := services.NewExportService(logger, repositories.NewQuoteGameRepo(logger, db))
+
+	file, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not create export file")
+	}
+	err = exportService.ExportGames(context.Background(), file, models.ExportFormat(*format), fromTime, toTime)
+	err = errors.Join(err, file.Close())
+	if err != nil {
+		// A partial export would look complete, so it's removed
+		os.Remove(*output) //nolint:errcheck // the export failed already
+		logger.Fatal().Err(err).Msg("could not export games")
+	}
+	fmt.Println(*output)
+}
+
+// parseExportTime parses the value of a time flag. An empty value is the zero time, which leaves that side of the range open
+func parseExportTime(logger *zerolog.Logger, name, value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		logger.Fatal().Err(err).Str("value", value).Msgf("could not parse -%s as RFC 3339 time", name)
+	}
+	return t
+}
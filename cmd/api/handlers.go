@@ -2,31 +2,55 @@ package main
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/i18n"
 	"github.com/pietdevries94/Kabisa/models"
 	"github.com/pietdevries94/Kabisa/openapi"
 )
 
-// GetRandomQuote returns a single ransom quote
-func (app *application) GetRandomQuote(ctx context.Context) (openapi.GetRandomQuoteRes, error) {
-	quote, err := app.quoteService.GetRandomQuote(ctx)
+// GetRandomQuote returns a single ransom quote, preferably in a language the caller accepts
+func (app *application) GetRandomQuote(ctx context.Context, params openapi.GetRandomQuoteParams) (openapi.GetRandomQuoteRes, error) {
+	quote, err := app.quoteService.GetRandomQuote(ctx, i18n.MatchLanguage(params.AcceptLanguage.Or("")))
+	if errors.Is(err, models.ErrUpstreamBusy) {
+		return app.serviceUnavailable()
+	}
 	if err != nil {
 		app.logger.Error().Err(err).Msg("unexpected error when calling quoteService.GetRandomQuote")
 		return app.internalServerError()
 	}
 
-	result := &openapi.Quote{
-		ID:     quote.ID,
-		Quote:  quote.Quote,
-		Author: quote.Author,
+	result := quoteResponse(quote)
+	return &result, nil
+}
+
+func quoteResponse(quote *models.Quote) openapi.Quote {
+	return openapi.Quote{
+		ID:       quote.ID,
+		Quote:    quote.Quote,
+		Author:   quote.Author,
+		Language: openapi.Language(quote.Language),
 	}
-	return result, nil
 }
 
 // CreateNewQuoteGame gets 3 random quotes, seperates the quotes from the authors, stores the game info and returns them to the user for them to match together
-func (app *application) CreateNewQuoteGame(ctx context.Context) (openapi.CreateNewQuoteGameRes, error) {
-	game, err := app.quoteService.CreateQuoteGame(ctx)
+// If the player identified themselves, quotes from their recent games are avoided. An authenticated caller is always used as the player,
+// so the X-Player-Id header can't be used to play as someone else. The quotes are in a language the caller accepts, when there are enough of them.
+func (app *application) CreateNewQuoteGame(ctx context.Context, params openapi.CreateNewQuoteGameParams) (openapi.CreateNewQuoteGameRes, error) {
+	playerID := playerID(ctx, params.XPlayerID)
+	mode := models.GameMode(params.Mode.Or(openapi.GameModeMatch))
+
+	language := i18n.MatchLanguage(params.AcceptLanguage.Or(""))
+
+	game, err := app.quoteService.CreateQuoteGame(ctx, playerID, mode, language)
+	if err == models.ErrInvalidGameMode {
+		return app.unprocessableContent(err)
+	}
+	if errors.Is(err, models.ErrUpstreamBusy) {
+		return app.serviceUnavailable()
+	}
 	if err != nil {
 		app.logger.Error().Err(err).Msg("unexpected error when calling quoteService.CreateQuoteGame")
 		return app.internalServerError()
@@ -34,13 +58,15 @@ func (app *application) CreateNewQuoteGame(ctx context.Context) (openapi.CreateN
 
 	result := &openapi.CreateNewQuoteGameOK{
 		ID:      openapi.UUID(game.ID.String()),
+		Mode:    openapi.GameMode(game.Mode),
 		Authors: game.Authors,
 	}
 	result.Quotes = make([]openapi.QuoteWithoutAuthor, len(game.Quotes))
 	for i, q := range game.Quotes {
 		result.Quotes[i] = openapi.QuoteWithoutAuthor{
-			ID:    q.ID,
-			Quote: q.Quote,
+			ID:      q.ID,
+			Quote:   q.Quote,
+			Choices: q.Choices,
 		}
 	}
 
@@ -49,6 +75,7 @@ func (app *application) CreateNewQuoteGame(ctx context.Context) (openapi.CreateN
 
 // SubmitAnswerForQuoteGame receives the answers a user has given to a quote game. The function validates if the game exists and the quote ids are correct.
 // After that the quotes will be retrieved and the result of the game determined and stored in the db. The result of the game is returned.
+// A game that is answered already returns the stored result for the same answers, and a 409 for other answers.
 func (app *application) SubmitAnswerForQuoteGame(ctx context.Context, answers []openapi.QuoteGameAnswer, params openapi.SubmitAnswerForQuoteGameParams) (openapi.SubmitAnswerForQuoteGameRes, error) {
 	id, err := uuid.Parse(string(params.ID))
 	if err != nil {
@@ -64,12 +91,49 @@ func (app *application) SubmitAnswerForQuoteGame(ctx context.Context, answers []
 	if err == models.ErrQuoteGameIdNotFound {
 		return app.notFound()
 	}
+	if err == models.ErrQuoteGameAlreadyAnswered {
+		return &openapi.R409{Message: err.Error()}, nil
+	}
+	if errors.Is(err, models.ErrUpstreamBusy) {
+		return app.serviceUnavailable()
+	}
+	if err != nil {
+		return app.unprocessableContent(err)
+	}
+
+	return quoteGameResultResponse(gameResult), nil
+}
+
+// RequestHintForQuoteGame gives the player a hint for a game in the match mode. The cost of the hint is deducted from the score when the game is answered.
+func (app *application) RequestHintForQuoteGame(ctx context.Context, req *openapi.HintRequest, params openapi.RequestHintForQuoteGameParams) (openapi.RequestHintForQuoteGameRes, error) {
+	id, err := uuid.Parse(string(params.ID))
+	if err != nil {
+		return app.notFound()
+	}
+
+	hint, err := app.quoteService.RequestHint(ctx, id, models.HintType(req.Type), req.QuoteId.Or(0))
+	if err == models.ErrQuoteGameIdNotFound {
+		return app.notFound()
+	}
+	if errors.Is(err, models.ErrUpstreamBusy) {
+		return app.serviceUnavailable()
+	}
 	if err != nil {
+		if _, ok := err.(*models.PublicError); !ok {
+			app.logger.Error().Err(err).Msg("unexpected error when calling quoteService.RequestHint")
+		}
 		return app.unprocessableContent(err)
 	}
 
+	res := hintResponse(hint)
+	return &res, nil
+}
+
+// quoteGameResultResponse converts the result of a quote game to its api representation
+func quoteGameResultResponse(gameResult *models.QuoteGameResult) *openapi.QuoteGameResult {
 	result := &openapi.QuoteGameResult{
 		ID:      openapi.UUID(gameResult.ID.String()),
+		Score:   gameResult.Score(),
 		Answers: make([]openapi.QuoteGameResultAnswersItem, len(gameResult.Answers)),
 	}
 	for i, a := range gameResult.Answers {
@@ -79,11 +143,160 @@ func (app *application) SubmitAnswerForQuoteGame(ctx context.Context, answers []
 			ActualAuthor: a.Author,
 		}
 	}
+	for _, h := range gameResult.Hints {
+		result.Hints = append(result.Hints, hintResponse(h))
+	}
+	return result
+}
+
+// hintResponse converts a hint to its api representation
+func hintResponse(hint *models.Hint) openapi.Hint {
+	return openapi.Hint{
+		Type:    openapi.HintType(hint.Type),
+		QuoteId: hint.QuoteID,
+		Author:  hint.Author,
+		Cost:    hint.Cost,
+	}
+}
+
+// SubmitBlanksForQuoteGame receives the missing words a user has filled in for a game in the fill in the blank mode.
+// Like SubmitAnswerForQuoteGame, the result of the game is determined, stored and returned, with the expected word of every blank.
+func (app *application) SubmitBlanksForQuoteGame(ctx context.Context, answers []openapi.BlankAnswer, params openapi.SubmitBlanksForQuoteGameParams) (openapi.SubmitBlanksForQuoteGameRes, error) {
+	id, err := uuid.Parse(string(params.ID))
+	if err != nil {
+		return app.notFound()
+	}
+
+	answerMap := make(models.QuoteGameAnswerMap)
+	for _, a := range answers {
+		answerMap[a.ID] = a.Answer
+	}
+
+	gameResult, err := app.quoteService.SubmitBlanksToQuoteGame(ctx, id, answerMap)
+	if err == models.ErrQuoteGameIdNotFound {
+		return app.notFound()
+	}
+	if err == models.ErrQuoteGameAlreadyAnswered {
+		return &openapi.R409{Message: err.Error()}, nil
+	}
+	if errors.Is(err, models.ErrUpstreamBusy) {
+		return app.serviceUnavailable()
+	}
+	if err != nil {
+		return app.unprocessableContent(err)
+	}
+
+	result := &openapi.BlankResult{
+		ID:     openapi.UUID(gameResult.ID.String()),
+		Blanks: make([]openapi.BlankResultBlanksItem, len(gameResult.Answers)),
+	}
+	for i, a := range gameResult.Answers {
+		result.Blanks[i] = openapi.BlankResultBlanksItem{
+			ID:      a.ID,
+			Quote:   a.Quote.Quote,
+			Author:  a.Author,
+			Correct: a.Correct,
+		}
+		if a.Blank != nil {
+			result.Blanks[i].Answer = a.Blank.Given
+			result.Blanks[i].Expected = a.Blank.Expected
+		}
+	}
 
 	return result, nil
 }
 
-func (app *application) unprocessableContent(err error) (openapi.SubmitAnswerForQuoteGameRes, error) {
+// CreateDailyQuoteGame starts a game of the daily challenge for the caller. Every player can play the challenge once a day,
+// so the player is required and a second game on the same day is a conflict.
+func (app *application) CreateDailyQuoteGame(ctx context.Context, params openapi.CreateDailyQuoteGameParams) (openapi.CreateDailyQuoteGameRes, error) {
+	game, err := app.quoteService.CreateDailyQuoteGame(ctx, playerID(ctx, params.XPlayerID))
+	if err == models.ErrDailyChallengeAlreadyPlayed {
+		return &openapi.R409{
+			Message: err.Error(),
+		}, nil
+	}
+	if errors.Is(err, models.ErrUpstreamBusy) {
+		return app.serviceUnavailable()
+	}
+	if err == models.ErrPlayerIDRequired {
+		return app.unprocessableContent(err)
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling quoteService.CreateDailyQuoteGame")
+		return app.internalServerError()
+	}
+
+	date, err := time.Parse(time.DateOnly, game.Date)
+	if err != nil {
+		app.logger.Error().Err(err).Str("date", game.Date).Msg("could not parse date of daily quote game")
+		return app.internalServerError()
+	}
+
+	result := &openapi.DailyQuoteGame{
+		Date:    date,
+		ID:      openapi.UUID(game.ID.String()),
+		Authors: game.Authors,
+		Quotes:  make([]openapi.QuoteWithoutAuthor, len(game.Quotes)),
+	}
+	for i, q := range game.Quotes {
+		result.Quotes[i] = openapi.QuoteWithoutAuthor{
+			ID:    q.ID,
+			Quote: q.Quote,
+		}
+	}
+	return result, nil
+}
+
+// GetDailyLeaderboard returns a page of the leaderboard of the daily challenge. The day defaults to today (UTC)
+func (app *application) GetDailyLeaderboard(ctx context.Context, params openapi.GetDailyLeaderboardParams) (openapi.GetDailyLeaderboardRes, error) {
+	day := params.Date.Or(time.Now()).UTC()
+
+	page, err := app.quoteService.GetDailyLeaderboard(ctx, day, params.Limit.Or(defaultPageLimit), params.Offset.Or(0))
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling quoteService.GetDailyLeaderboard")
+		return app.internalServerError()
+	}
+
+	result := &openapi.DailyLeaderboard{
+		Date:   time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC),
+		Items:  make([]openapi.DailyLeaderboardEntry, len(page.Items)),
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	for i, e := range page.Items {
+		result.Items[i] = openapi.DailyLeaderboardEntry{
+			Rank:        e.Rank,
+			PlayerId:    e.PlayerID,
+			Score:       e.Score,
+			CompletedAt: e.CompletedAt,
+		}
+	}
+	return result, nil
+}
+
+// playerID returns the id of the authenticated caller, or the X-Player-Id header for anonymous callers.
+// An authenticated caller can't use the header to play as someone else.
+func playerID(ctx context.Context, header openapi.OptString) string {
+	if caller, ok := models.CallerFromContext(ctx); ok {
+		return caller.ID
+	}
+	return header.Or("")
+}
+
+// unprocessableContentRes is implemented by the responses of every operation that can return both a 422 and a 500
+type unprocessableContentRes interface {
+	openapi.CreateNewQuoteGameRes
+	openapi.SubmitAnswerForQuoteGameRes
+	openapi.SubmitBlanksForQuoteGameRes
+	openapi.RequestHintForQuoteGameRes
+	openapi.CreateRoomRes
+	openapi.JoinRoomRes
+	openapi.SubmitAnswerForRoomRes
+	openapi.CreateDailyQuoteGameRes
+}
+
+func (app *application) unprocessableContent(err error) (unprocessableContentRes, error) {
 	pe, ok := err.(*models.PublicError)
 	if !ok {
 		return app.internalServerError()
@@ -99,6 +312,13 @@ func (app *application) internalServerError() (*openapi.R500, error) {
 	}, nil
 }
 
+// serviceUnavailable is returned when dummyjson is too busy to handle the request in time, see models.ErrUpstreamBusy
+func (app *application) serviceUnavailable() (*openapi.R503, error) {
+	return &openapi.R503{
+		Message: models.ErrUpstreamBusy.Error(),
+	}, nil
+}
+
 func (app *application) notFound() (*openapi.R404, error) {
 	return &openapi.R404{
 		Message: "not_found",
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pietdevries94/Kabisa/models"
@@ -16,6 +17,8 @@ import (
 
 func TestApplication_GetRandomQuote(t *testing.T) {
 	type Test struct {
+		params             openapi.GetRandomQuoteParams
+		expectedLanguage   models.Language
 		mockedServiceQuote *models.Quote
 		mockedServiceError error
 		expectedResult     openapi.GetRandomQuoteRes
@@ -27,7 +30,11 @@ func TestApplication_GetRandomQuote(t *testing.T) {
 
 			// first we bootstrap a minimal version of the application, needed for the handler
 			mockedQuoteService := new(MockedQuoteService)
-			mockedQuoteService.On("GetRandomQuote").Once().Return(tt.mockedServiceQuote, tt.mockedServiceError)
+			expectedLanguage := tt.expectedLanguage
+			if expectedLanguage == "" {
+				expectedLanguage = models.LanguageEnglish
+			}
+			mockedQuoteService.On("GetRandomQuote", expectedLanguage).Once().Return(tt.mockedServiceQuote, tt.mockedServiceError)
 
 			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
 			app := application{
@@ -36,7 +43,7 @@ func TestApplication_GetRandomQuote(t *testing.T) {
 			}
 
 			// We now run the handler and validate the result
-			res, err := app.GetRandomQuote(context.TODO())
+			res, err := app.GetRandomQuote(context.TODO(), tt.params)
 			require.NoError(t, err)
 			assert.Equal(t, tt.expectedResult, res)
 		}
@@ -44,14 +51,33 @@ func TestApplication_GetRandomQuote(t *testing.T) {
 
 	t.Run("returns a quote", run(Test{
 		mockedServiceQuote: &models.Quote{
-			ID:     1207,
-			Quote:  "Everything Has Its Limit - Iron Ore Cannot Be Educated Into Gold.",
-			Author: "Mark Twain",
+			ID:       1207,
+			Quote:    "Everything Has Its Limit - Iron Ore Cannot Be Educated Into Gold.",
+			Author:   "Mark Twain",
+			Language: models.LanguageEnglish,
+		},
+		expectedResult: &openapi.Quote{
+			ID:       1207,
+			Quote:    "Everything Has Its Limit - Iron Ore Cannot Be Educated Into Gold.",
+			Author:   "Mark Twain",
+			Language: openapi.LanguageEn,
+		},
+	}))
+
+	t.Run("returns a quote in the accepted language", run(Test{
+		params:           openapi.GetRandomQuoteParams{AcceptLanguage: openapi.NewOptString("nl-NL,nl;q=0.9,en;q=0.8")},
+		expectedLanguage: models.LanguageDutch,
+		mockedServiceQuote: &models.Quote{
+			ID:       1000001,
+			Quote:    "Wie het kleine niet eert, is het grote niet weerd.",
+			Author:   "Onbekend",
+			Language: models.LanguageDutch,
 		},
 		expectedResult: &openapi.Quote{
-			ID:     1207,
-			Quote:  "Everything Has Its Limit - Iron Ore Cannot Be Educated Into Gold.",
-			Author: "Mark Twain",
+			ID:       1000001,
+			Quote:    "Wie het kleine niet eert, is het grote niet weerd.",
+			Author:   "Onbekend",
+			Language: openapi.LanguageNl,
 		},
 	}))
 
@@ -61,10 +87,21 @@ func TestApplication_GetRandomQuote(t *testing.T) {
 			Message: "unknown_error",
 		},
 	}))
+
+	t.Run("returns a 503 when dummyjson is too busy", run(Test{
+		mockedServiceError: models.ErrUpstreamBusy,
+		expectedResult: &openapi.R503{
+			Message: "upstream_busy",
+		},
+	}))
 }
 
 func TestApplication_CreateQuoteGame(t *testing.T) {
 	type Test struct {
+		params             openapi.CreateNewQuoteGameParams
+		expectedPlayerID   string
+		expectedMode       models.GameMode
+		expectedLanguage   models.Language
 		mockedServiceQuote *models.QuoteGame
 		mockedServiceError error
 		expectedResult     openapi.CreateNewQuoteGameRes
@@ -76,7 +113,15 @@ func TestApplication_CreateQuoteGame(t *testing.T) {
 
 			// first we bootstrap a minimal version of the application, needed for the handler
 			mockedQuoteService := new(MockedQuoteService)
-			mockedQuoteService.On("CreateQuoteGame").Once().Return(tt.mockedServiceQuote, tt.mockedServiceError)
+			expectedMode := tt.expectedMode
+			if expectedMode == "" {
+				expectedMode = models.GameModeMatch
+			}
+			expectedLanguage := tt.expectedLanguage
+			if expectedLanguage == "" {
+				expectedLanguage = models.LanguageEnglish
+			}
+			mockedQuoteService.On("CreateQuoteGame", tt.expectedPlayerID, expectedMode, expectedLanguage).Once().Return(tt.mockedServiceQuote, tt.mockedServiceError)
 
 			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
 			app := application{
@@ -85,7 +130,7 @@ func TestApplication_CreateQuoteGame(t *testing.T) {
 			}
 
 			// We now run the handler and validate the result
-			res, err := app.CreateNewQuoteGame(context.TODO())
+			res, err := app.CreateNewQuoteGame(context.TODO(), tt.params)
 			require.NoError(t, err)
 			assert.Equal(t, tt.expectedResult, res)
 		}
@@ -93,7 +138,8 @@ func TestApplication_CreateQuoteGame(t *testing.T) {
 
 	t.Run("returns a quote game", run(Test{
 		mockedServiceQuote: &models.QuoteGame{
-			ID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+			ID:   uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+			Mode: models.GameModeMatch,
 			Quotes: []*models.QuoteWithoutAuthor{
 				{
 					ID:    70,
@@ -115,7 +161,8 @@ func TestApplication_CreateQuoteGame(t *testing.T) {
 			},
 		},
 		expectedResult: &openapi.CreateNewQuoteGameOK{
-			ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+			ID:   "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+			Mode: openapi.GameModeMatch,
 			Quotes: []openapi.QuoteWithoutAuthor{
 				{
 					ID:    70,
@@ -144,6 +191,53 @@ func TestApplication_CreateQuoteGame(t *testing.T) {
 			Message: "unknown_error",
 		},
 	}))
+
+	t.Run("returns a 503 when dummyjson is too busy", run(Test{
+		mockedServiceError: models.ErrUpstreamBusy,
+		expectedResult: &openapi.R503{
+			Message: "upstream_busy",
+		},
+	}))
+
+	t.Run("passes the player id to the service", run(Test{
+		params:             openapi.CreateNewQuoteGameParams{XPlayerID: openapi.NewOptString("player-42")},
+		expectedPlayerID:   "player-42",
+		mockedServiceQuote: &models.QuoteGame{ID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e")},
+		expectedResult: &openapi.CreateNewQuoteGameOK{
+			ID:     "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+			Quotes: []openapi.QuoteWithoutAuthor{},
+		},
+	}))
+
+	t.Run("returns a multiple choice game with the choices of every quote", run(Test{
+		params:       openapi.CreateNewQuoteGameParams{Mode: openapi.NewOptGameMode(openapi.GameModeMultipleChoice)},
+		expectedMode: models.GameModeMultipleChoice,
+		mockedServiceQuote: &models.QuoteGame{
+			ID:   uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+			Mode: models.GameModeMultipleChoice,
+			Quotes: []*models.QuoteWithoutAuthor{
+				{ID: 70, Quote: "The cure for pain is in the pain.", Choices: []string{"Abdul Kalam", "Buddha", "Rumi", "Seneca"}},
+			},
+			Authors: []string{},
+		},
+		expectedResult: &openapi.CreateNewQuoteGameOK{
+			ID:   "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+			Mode: openapi.GameModeMultipleChoice,
+			Quotes: []openapi.QuoteWithoutAuthor{
+				{ID: 70, Quote: "The cure for pain is in the pain.", Choices: []string{"Abdul Kalam", "Buddha", "Rumi", "Seneca"}},
+			},
+			Authors: []string{},
+		},
+	}))
+
+	t.Run("returns a 422 when the mode is unknown", run(Test{
+		params:             openapi.CreateNewQuoteGameParams{Mode: openapi.NewOptGameMode("unknown")},
+		expectedMode:       "unknown",
+		mockedServiceError: models.ErrInvalidGameMode,
+		expectedResult: &openapi.R422{
+			Message: "invalid_game_mode",
+		},
+	}))
 }
 
 func TestApplication_SubmitAnswerForQuoteGame(t *testing.T) {
@@ -204,7 +298,8 @@ func TestApplication_SubmitAnswerForQuoteGame(t *testing.T) {
 			},
 		},
 		expectedResult: &openapi.QuoteGameResult{
-			ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+			ID:    "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+			Score: 1,
 			Answers: []openapi.QuoteGameResultAnswersItem{
 				{ID: 54, Correct: false, ActualAuthor: "George"},
 				{ID: 43, Correct: false, ActualAuthor: "William"},
@@ -213,6 +308,42 @@ func TestApplication_SubmitAnswerForQuoteGame(t *testing.T) {
 		},
 	}))
 
+	t.Run("returns the used hints and deducts their costs from the score", run(Test{
+		answers: []openapi.QuoteGameAnswer{
+			{ID: 54, Author: "George"},
+			{ID: 43, Author: "William"},
+			{ID: 2, Author: "Bob"},
+		},
+		params: openapi.SubmitAnswerForQuoteGameParams{
+			ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+		},
+		expectedMockedServiceInputID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+		expectedMockedServiceInputAnswers: models.QuoteGameAnswerMap{
+			54: "George",
+			43: "William",
+			2:  "Bob",
+		},
+		mockedServiceResult: &models.QuoteGameResult{
+			ID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+			Answers: []*models.QuoteGameActualAnswer{
+				{Quote: models.Quote{ID: 54, Author: "George", Quote: "Hello!"}, Correct: true},
+				{Quote: models.Quote{ID: 43, Author: "William", Quote: "Hi!"}, Correct: true},
+				{Quote: models.Quote{ID: 2, Author: "Bob", Quote: "Bye!"}, Correct: true},
+			},
+			Hints: []*models.Hint{{Type: models.HintRevealPair, QuoteID: 2, Author: "Bob", Cost: 2}},
+		},
+		expectedResult: &openapi.QuoteGameResult{
+			ID:    "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+			Score: 1,
+			Hints: []openapi.Hint{{Type: openapi.HintTypeRevealPair, QuoteId: 2, Author: "Bob", Cost: 2}},
+			Answers: []openapi.QuoteGameResultAnswersItem{
+				{ID: 54, Correct: true, ActualAuthor: "George"},
+				{ID: 43, Correct: true, ActualAuthor: "William"},
+				{ID: 2, Correct: true, ActualAuthor: "Bob"},
+			},
+		},
+	}))
+
 	t.Run("returns an 500 if the service errors without a public error", run(Test{
 		answers: []openapi.QuoteGameAnswer{
 			{ID: 54, Author: "A name"},
@@ -269,6 +400,23 @@ func TestApplication_SubmitAnswerForQuoteGame(t *testing.T) {
 		},
 	}))
 
+	t.Run("returns a 409 if the game is answered already with other answers", run(Test{
+		answers: []openapi.QuoteGameAnswer{
+			{ID: 54, Author: "A name"},
+		},
+		params: openapi.SubmitAnswerForQuoteGameParams{
+			ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+		},
+		expectedMockedServiceInputID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+		expectedMockedServiceInputAnswers: models.QuoteGameAnswerMap{
+			54: "A name",
+		},
+		mockedServiceError: models.ErrQuoteGameAlreadyAnswered,
+		expectedResult: &openapi.R409{
+			Message: "quote_game_already_answered",
+		},
+	}))
+
 	t.Run("returns a 422 if any other public error is given", run(Test{
 		answers: []openapi.QuoteGameAnswer{
 			{ID: 54, Author: "A name"},
@@ -289,4 +437,287 @@ func TestApplication_SubmitAnswerForQuoteGame(t *testing.T) {
 			Message: "invalid_quote_id",
 		},
 	}))
+
+	t.Run("returns a 503 when dummyjson is too busy", run(Test{
+		answers: []openapi.QuoteGameAnswer{
+			{ID: 54, Author: "A name"},
+		},
+		params: openapi.SubmitAnswerForQuoteGameParams{
+			ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+		},
+		expectedMockedServiceInputID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+		expectedMockedServiceInputAnswers: models.QuoteGameAnswerMap{
+			54: "A name",
+		},
+		mockedServiceError: models.ErrUpstreamBusy,
+		expectedResult: &openapi.R503{
+			Message: "upstream_busy",
+		},
+	}))
+}
+
+func TestApplication_SubmitBlanksForQuoteGame(t *testing.T) {
+	id := uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e")
+	answers := []openapi.BlankAnswer{{ID: 54, Answer: "hello"}, {ID: 43, Answer: "bye"}}
+	answerMap := models.QuoteGameAnswerMap{54: "hello", 43: "bye"}
+
+	type Test struct {
+		params              openapi.SubmitBlanksForQuoteGameParams
+		mockedServiceResult *models.QuoteGameResult
+		mockedServiceError  error
+		expectedResult      openapi.SubmitBlanksForQuoteGameRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedQuoteService := new(MockedQuoteService)
+			mockedQuoteService.On("SubmitBlanksToQuoteGame", id, answerMap).
+				Once().
+				Return(tt.mockedServiceResult, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:       &logger,
+				quoteService: mockedQuoteService,
+			}
+
+			res, err := app.SubmitBlanksForQuoteGame(context.TODO(), answers, tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("returns the breakdown of every blank", run(Test{
+		params: openapi.SubmitBlanksForQuoteGameParams{ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e"},
+		mockedServiceResult: &models.QuoteGameResult{
+			ID: id,
+			Answers: []*models.QuoteGameActualAnswer{
+				{Quote: models.Quote{ID: 54, Author: "George", Quote: "Hello!"}, Correct: true, Blank: &models.BlankAnswer{Given: "hello", Expected: "Hello"}},
+				{Quote: models.Quote{ID: 43, Author: "William", Quote: "Hi!"}, Correct: false, Blank: &models.BlankAnswer{Given: "bye", Expected: "Hi"}},
+			},
+		},
+		expectedResult: &openapi.BlankResult{
+			ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+			Blanks: []openapi.BlankResultBlanksItem{
+				{ID: 54, Quote: "Hello!", Author: "George", Answer: "hello", Expected: "Hello", Correct: true},
+				{ID: 43, Quote: "Hi!", Author: "William", Answer: "bye", Expected: "Hi", Correct: false},
+			},
+		},
+	}))
+
+	t.Run("returns a 404 if the id is not parseable as a uuid v4", run(Test{
+		params:         openapi.SubmitBlanksForQuoteGameParams{ID: "nope"},
+		expectedResult: &openapi.R404{Message: "not_found"},
+	}))
+
+	t.Run("returns a 404 if the game is not found", run(Test{
+		params:             openapi.SubmitBlanksForQuoteGameParams{ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e"},
+		mockedServiceError: models.ErrQuoteGameIdNotFound,
+		expectedResult:     &openapi.R404{Message: "not_found"},
+	}))
+
+	t.Run("returns a 409 if the game is answered already with other words", run(Test{
+		params:             openapi.SubmitBlanksForQuoteGameParams{ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e"},
+		mockedServiceError: models.ErrQuoteGameAlreadyAnswered,
+		expectedResult:     &openapi.R409{Message: "quote_game_already_answered"},
+	}))
+
+	t.Run("returns a 422 if the game is in another mode", run(Test{
+		params:             openapi.SubmitBlanksForQuoteGameParams{ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e"},
+		mockedServiceError: models.ErrWrongGameMode,
+		expectedResult:     &openapi.R422{Message: "wrong_game_mode"},
+	}))
+
+	t.Run("returns a 500 if the service errors without a public error", run(Test{
+		params:             openapi.SubmitBlanksForQuoteGameParams{ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e"},
+		mockedServiceError: errors.New("a crazy error"),
+		expectedResult:     &openapi.R500{Message: "unknown_error"},
+	}))
+}
+
+func TestApplication_RequestHintForQuoteGame(t *testing.T) {
+	id := uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e")
+
+	type Test struct {
+		params              openapi.RequestHintForQuoteGameParams
+		req                 *openapi.HintRequest
+		expectedQuoteID     int
+		mockedServiceResult *models.Hint
+		mockedServiceError  error
+		expectedResult      openapi.RequestHintForQuoteGameRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedQuoteService := new(MockedQuoteService)
+			mockedQuoteService.On("RequestHint", id, models.HintType(tt.req.Type), tt.expectedQuoteID).
+				Once().
+				Return(tt.mockedServiceResult, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:       &logger,
+				quoteService: mockedQuoteService,
+			}
+
+			res, err := app.RequestHintForQuoteGame(context.TODO(), tt.req, tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("returns the hint", run(Test{
+		params:              openapi.RequestHintForQuoteGameParams{ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e"},
+		req:                 &openapi.HintRequest{Type: openapi.HintTypeEliminateAuthor, QuoteId: openapi.NewOptInt(54)},
+		expectedQuoteID:     54,
+		mockedServiceResult: &models.Hint{Type: models.HintEliminateAuthor, QuoteID: 54, Author: "Bob", Cost: 1},
+		expectedResult:      &openapi.Hint{Type: openapi.HintTypeEliminateAuthor, QuoteId: 54, Author: "Bob", Cost: 1},
+	}))
+
+	t.Run("lets the service choose the quote when none is given", run(Test{
+		params:              openapi.RequestHintForQuoteGameParams{ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e"},
+		req:                 &openapi.HintRequest{Type: openapi.HintTypeRevealPair},
+		mockedServiceResult: &models.Hint{Type: models.HintRevealPair, QuoteID: 2, Author: "Bob", Cost: 2},
+		expectedResult:      &openapi.Hint{Type: openapi.HintTypeRevealPair, QuoteId: 2, Author: "Bob", Cost: 2},
+	}))
+
+	t.Run("returns a 404 if the id is not parseable as a uuid v4", run(Test{
+		params:         openapi.RequestHintForQuoteGameParams{ID: "nope"},
+		req:            &openapi.HintRequest{Type: openapi.HintTypeRevealPair},
+		expectedResult: &openapi.R404{Message: "not_found"},
+	}))
+
+	t.Run("returns a 404 if the game is not found", run(Test{
+		params:             openapi.RequestHintForQuoteGameParams{ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e"},
+		req:                &openapi.HintRequest{Type: openapi.HintTypeRevealPair},
+		mockedServiceError: models.ErrQuoteGameIdNotFound,
+		expectedResult:     &openapi.R404{Message: "not_found"},
+	}))
+
+	t.Run("returns a 422 if no hint is available", run(Test{
+		params:             openapi.RequestHintForQuoteGameParams{ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e"},
+		req:                &openapi.HintRequest{Type: openapi.HintTypeRevealPair},
+		mockedServiceError: models.ErrNoHintAvailable,
+		expectedResult:     &openapi.R422{Message: "no_hint_available"},
+	}))
+
+	t.Run("returns a 500 if the service errors without a public error", run(Test{
+		params:             openapi.RequestHintForQuoteGameParams{ID: "03f17f15-5d0a-49ea-aa05-039f2f18373e"},
+		req:                &openapi.HintRequest{Type: openapi.HintTypeRevealPair},
+		mockedServiceError: errors.New("a crazy error"),
+		expectedResult:     &openapi.R500{Message: "unknown_error"},
+	}))
+}
+
+func TestApplication_CreateDailyQuoteGame(t *testing.T) {
+	type Test struct {
+		params             openapi.CreateDailyQuoteGameParams
+		expectedPlayerID   string
+		mockedServiceGame  *models.DailyQuoteGame
+		mockedServiceError error
+		expectedResult     openapi.CreateDailyQuoteGameRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedQuoteService := new(MockedQuoteService)
+			mockedQuoteService.On("CreateDailyQuoteGame", tt.expectedPlayerID).Once().Return(tt.mockedServiceGame, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:       &logger,
+				quoteService: mockedQuoteService,
+			}
+
+			res, err := app.CreateDailyQuoteGame(context.TODO(), tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("returns the game of the daily challenge", run(Test{
+		params:           openapi.CreateDailyQuoteGameParams{XPlayerID: openapi.NewOptString("player-42")},
+		expectedPlayerID: "player-42",
+		mockedServiceGame: &models.DailyQuoteGame{
+			QuoteGame: models.QuoteGame{
+				ID:      uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+				Quotes:  []*models.QuoteWithoutAuthor{{ID: 70, Quote: "The cure for pain is in the pain."}},
+				Authors: []string{"Rumi"},
+			},
+			Date: "2025-02-01",
+		},
+		expectedResult: &openapi.DailyQuoteGame{
+			Date:    time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			ID:      "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+			Quotes:  []openapi.QuoteWithoutAuthor{{ID: 70, Quote: "The cure for pain is in the pain."}},
+			Authors: []string{"Rumi"},
+		},
+	}))
+
+	t.Run("returns a conflict when the player played today already", run(Test{
+		params:             openapi.CreateDailyQuoteGameParams{XPlayerID: openapi.NewOptString("player-42")},
+		expectedPlayerID:   "player-42",
+		mockedServiceError: models.ErrDailyChallengeAlreadyPlayed,
+		expectedResult:     &openapi.R409{Message: "daily_challenge_already_played"},
+	}))
+
+	t.Run("returns a 422 without a player", run(Test{
+		mockedServiceError: models.ErrPlayerIDRequired,
+		expectedResult:     &openapi.R422{Message: "player_id_required"},
+	}))
+
+	t.Run("returns a 503 when dummyjson is too busy", run(Test{
+		mockedServiceError: models.ErrUpstreamBusy,
+		expectedResult:     &openapi.R503{Message: "upstream_busy"},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		mockedServiceError: models.ErrCatalogueEmpty,
+		expectedResult:     &openapi.R500{Message: "unknown_error"},
+	}))
+}
+
+func TestApplication_GetDailyLeaderboard(t *testing.T) {
+	completedAt := time.Date(2025, 2, 1, 12, 1, 13, 0, time.UTC)
+	day := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	mockedQuoteService := new(MockedQuoteService)
+	mockedQuoteService.On("GetDailyLeaderboard", day, 10, 5).Once().Return(&models.Page[*models.DailyLeaderboardEntry]{
+		Items:  []*models.DailyLeaderboardEntry{{Rank: 6, PlayerID: "player-42", Score: 3, CompletedAt: completedAt}},
+		Total:  6,
+		Limit:  10,
+		Offset: 5,
+	}, nil)
+	mockedQuoteService.On("GetDailyLeaderboard", day.AddDate(0, 0, -1), defaultPageLimit, 0).Once().
+		Return((*models.Page[*models.DailyLeaderboardEntry])(nil), errors.New("something went wrong"))
+
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	app := application{
+		logger:       &logger,
+		quoteService: mockedQuoteService,
+	}
+
+	res, err := app.GetDailyLeaderboard(context.TODO(), openapi.GetDailyLeaderboardParams{
+		Date:   openapi.NewOptDate(day),
+		Limit:  openapi.NewOptInt(10),
+		Offset: openapi.NewOptInt(5),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &openapi.DailyLeaderboard{
+		Date:   day,
+		Items:  []openapi.DailyLeaderboardEntry{{Rank: 6, PlayerId: "player-42", Score: 3, CompletedAt: completedAt}},
+		Total:  6,
+		Limit:  10,
+		Offset: 5,
+	}, res)
+
+	res, err = app.GetDailyLeaderboard(context.TODO(), openapi.GetDailyLeaderboardParams{Date: openapi.NewOptDate(day.AddDate(0, 0, -1))})
+	require.NoError(t, err)
+	assert.Equal(t, &openapi.R500{Message: "unknown_error"}, res)
 }
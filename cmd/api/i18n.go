@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pietdevries94/Kabisa/i18n"
+	"github.com/pietdevries94/Kabisa/models"
+)
+
+// localizeErrors adds a human readable detail, in the language the caller prefers, next to the machine readable message of every
+// JSON error response. This way the handlers, and everything else that writes errors, only have to deal with the codes.
+// Responses that are not errors are passed through untouched, so event streams keep working.
+func localizeErrors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lw := &localizingResponseWriter{
+			ResponseWriter: w,
+			language:       i18n.MatchLanguage(r.Header.Get("Accept-Language")),
+		}
+		next.ServeHTTP(lw, r)
+		lw.finish()
+	})
+}
+
+// localizingResponseWriter holds back the body of a JSON error response, so finish can add the detail to it
+type localizingResponseWriter struct {
+	http.ResponseWriter
+	language models.Language
+	// statusCode and body are only set when the response is held back
+	statusCode int
+	body       *bytes.Buffer
+}
+
+func (w *localizingResponseWriter) WriteHeader(statusCode int) {
+	if statusCode >= http.StatusBadRequest && strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		w.statusCode = statusCode
+		w.body = &bytes.Buffer{}
+		return
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *localizingResponseWriter) Write(b []byte) (int, error) {
+	if w.body != nil {
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *localizingResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok && w.body == nil {
+		f.Flush()
+	}
+}
+
+// Unwrap gives http.ResponseController access to the underlying writer
+func (w *localizingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish writes the held back error response, with the detail added when the message is known to the catalogs.
+// Bodies that are not an object with a message, like the errors ogen writes for invalid requests, are written as they are.
+func (w *localizingResponseWriter) finish() {
+	if w.body == nil {
+		return
+	}
+
+	body := w.body.Bytes()
+	var fields map[string]json.RawMessage
+	var message string
+	if json.Unmarshal(body, &fields) == nil && json.Unmarshal(fields["message"], &message) == nil {
+		if detail, ok := i18n.Translate(w.language, message); ok {
+			fields["detail"], _ = json.Marshal(detail)
+			if localized, err := json.Marshal(fields); err == nil {
+				body = localized
+				w.Header().Set("Content-Language", string(w.language))
+			}
+		}
+	}
+
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.statusCode)
+	_, _ = w.ResponseWriter.Write(body)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pietdevries94/Kabisa/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalizeErrors(t *testing.T) {
+	type Test struct {
+		acceptLanguage          string
+		statusCode              int
+		contentType             string
+		body                    string
+		expectedBody            string
+		expectedContentLanguage string
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/quote", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+			localizeErrors(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+			assert.Equal(t, tt.expectedContentLanguage, rec.Header().Get("Content-Language"))
+		}
+	}
+
+	t.Run("adds the detail in the preferred language", run(Test{
+		acceptLanguage:          "nl-NL,nl;q=0.9",
+		statusCode:              http.StatusNotFound,
+		contentType:             "application/json; charset=utf-8",
+		body:                    `{"message":"room_not_found"}`,
+		expectedBody:            `{"detail":"De kamer bestaat niet.","message":"room_not_found"}`,
+		expectedContentLanguage: "nl",
+	}))
+
+	t.Run("adds the detail in English without a preference", run(Test{
+		statusCode:              http.StatusUnprocessableEntity,
+		contentType:             "application/json",
+		body:                    `{"message":"invalid_game_mode","errors":[]}`,
+		expectedBody:            `{"detail":"This game mode does not exist.","errors":[],"message":"invalid_game_mode"}`,
+		expectedContentLanguage: "en",
+	}))
+
+	t.Run("leaves an unknown message alone", run(Test{
+		acceptLanguage: "nl",
+		statusCode:     http.StatusUnprocessableEntity,
+		contentType:    "application/json",
+		body:           `{"message":"unknown_quote_id: 414"}`,
+		expectedBody:   `{"message":"unknown_quote_id: 414"}`,
+	}))
+
+	t.Run("leaves errors without a message alone", run(Test{
+		acceptLanguage: "nl",
+		statusCode:     http.StatusBadRequest,
+		contentType:    "application/json",
+		body:           `{"error_message":"decode request: invalid json"}`,
+		expectedBody:   `{"error_message":"decode request: invalid json"}`,
+	}))
+
+	t.Run("leaves errors that are not json alone", run(Test{
+		acceptLanguage: "nl",
+		statusCode:     http.StatusNotFound,
+		contentType:    "text/plain; charset=utf-8",
+		body:           "404 page not found\n",
+		expectedBody:   "404 page not found\n",
+	}))
+
+	t.Run("leaves successful responses alone", run(Test{
+		acceptLanguage: "nl",
+		statusCode:     http.StatusOK,
+		contentType:    "application/json",
+		body:           `{"message":"room_not_found"}`,
+		expectedBody:   `{"message":"room_not_found"}`,
+	}))
+}
+
+func TestLocalizeErrors_WriteJSON(t *testing.T) {
+	// The errors written outside of ogen, like by the rate limiter, are localized as well
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusTooManyRequests, &openapi.R429{Message: "too_many_requests"})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/quote-game", nil)
+	req.Header.Set("Accept-Language", "nl")
+	rec := httptest.NewRecorder()
+	localizeErrors(next).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.JSONEq(t, `{"message":"too_many_requests","detail":"Je gaat te snel. Wacht even en probeer het opnieuw."}`, rec.Body.String())
+}
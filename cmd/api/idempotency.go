@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/openapi"
+	"github.com/rs/zerolog"
+)
+
+// idempotentOperations are the operations that accept an Idempotency-Key header. A retry of these would otherwise create
+// a second game, or fail because the game is answered already
+var idempotentOperations = map[string]bool{
+	"createNewQuoteGame":       true,
+	"submitAnswerForQuoteGame": true,
+	"submitBlanksForQuoteGame": true,
+}
+
+// maxIdempotencyKeyLength is the maxLength of the Idempotency-Key header in openapi.yaml. Longer keys are left for ogen to reject
+const maxIdempotencyKeyLength = 255
+
+// maxIdempotentBodySize is the largest body of a request with an idempotency key. The body is read in memory to fingerprint it,
+// while the answers of a game are only a few hundred bytes
+const maxIdempotentBodySize = 64 << 10
+
+// idempotency replays the stored response for retried requests with the same Idempotency-Key header, instead of handling them twice
+type idempotency struct {
+	logger             *zerolog.Logger
+	routes             routeFinder
+	authService        authService
+	idempotencyService idempotencyService
+}
+
+func newIdempotency(logger *zerolog.Logger, routes routeFinder, authService authService, idempotencyService idempotencyService) *idempotency {
+	return &idempotency{
+		logger:             logger,
+		routes:             routes,
+		authService:        authService,
+		idempotencyService: idempotencyService,
+	}
+}
+
+// middleware handles the first request with an idempotency key and stores its response. A retry with the same key and payload gets
+// the stored response, with an Idempotent-Replayed header. A key used for another payload, or for a request that is still handled, gets a 409.
+//
+// Server errors are not stored, so they can be fixed by a retry. Keys are scoped to the caller, so callers can't see each others responses.
+func (idem *idempotency) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" || len(key) > maxIdempotencyKeyLength {
+			next.ServeHTTP(w, r)
+			return
+		}
+		route, ok := idem.routes.FindRoute(r.Method, r.URL.Path)
+		if !ok || !idempotentOperations[route.OperationID()] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Invalid credentials would otherwise be scoped like an anonymous caller, and could get a stored response before ogen rejects them
+		if hasCredentials(r) {
+			if _, ok := authenticateRequest(r, idem.authService); !ok {
+				idem.logger.Debug().Str("path", r.URL.Path).Msg("idempotent request with invalid credentials")
+				writeJSON(w, http.StatusUnauthorized, &openapi.R401{Message: "unauthorized"})
+				return
+			}
+		}
+		scope, ok := idem.scope(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			idem.logger.Debug().Err(err).Str("operation", route.OperationID()).Msg("body of idempotent request is too large")
+			writeJSON(w, http.StatusRequestEntityTooLarge, &openapi.R413{Message: "request_too_large"})
+			return
+		}
+		if err != nil {
+			// The client is most likely gone already, so there is nobody to tell what went wrong
+			idem.logger.Debug().Err(err).Str("path", r.URL.Path).Msg("could not read body of idempotent request")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		stored, err := idem.idempotencyService.Begin(r.Context(), scope, key, requestFingerprint(r, body))
+		switch {
+		case errors.Is(err, models.ErrIdempotencyKeyReused), errors.Is(err, models.ErrIdempotencyKeyInProgress):
+			idem.logger.Debug().Err(err).Str("operation", route.OperationID()).Msg("idempotency key is not available")
+			writeJSON(w, http.StatusConflict, &openapi.R409{Message: err.Error()})
+			return
+		case err != nil:
+			idem.logger.Error().Err(err).Str("operation", route.OperationID()).Msg("could not claim idempotency key")
+			writeJSON(w, http.StatusInternalServerError, &openapi.R500{Message: "unknown_error"})
+			return
+		case stored != nil:
+			if stored.ContentType != "" {
+				w.Header().Set("Content-Type", stored.ContentType)
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(stored.StatusCode)
+			_, _ = w.Write(stored.Body)
+			return
+		}
+
+		rw := &recordingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rw, r)
+
+		// The response should be stored even when the client is gone, that is exactly when it's going to retry
+		ctx := context.WithoutCancel(r.Context())
+		if rw.statusCode >= http.StatusInternalServerError {
+			err = idem.idempotencyService.Release(ctx, scope, key)
+		} else {
+			err = idem.idempotencyService.Complete(ctx, scope, key, &models.IdempotentResponse{
+				StatusCode:  rw.statusCode,
+				ContentType: rw.Header().Get("Content-Type"),
+				Body:        rw.body.Bytes(),
+			})
+		}
+		if err != nil {
+			idem.logger.Error().Err(err).Str("operation", route.OperationID()).Msg("could not store idempotent response")
+		}
+	})
+}
+
+// scope returns the identity of the caller the idempotency key belongs to. Anonymous callers are identified by their X-Player-Id header.
+// Without it, the key can't be scoped to anyone and is ignored, as anonymous callers would otherwise see each others responses
+func (idem *idempotency) scope(r *http.Request) (string, bool) {
+	if caller, ok := authenticateRequest(r, idem.authService); ok {
+		return "caller:" + caller.ID, true
+	}
+	if playerID := r.Header.Get("X-Player-Id"); playerID != "" {
+		return "player:" + playerID, true
+	}
+	return "", false
+}
+
+// requestFingerprint identifies the payload of a request, which has to be the same for a retry. The X-Player-Id header is part of it,
+// as it determines the player of a game, and so is the Accept-Language header, as it determines the language of the quotes
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	for _, part := range []string{r.Method, r.URL.Path, r.URL.RawQuery, r.Header.Get("X-Player-Id"), r.Header.Get("Accept-Language")} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// recordingResponseWriter writes the response to the client, while keeping a copy of it to store
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *recordingResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.statusCode = statusCode
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *recordingResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap gives http.ResponseController access to the underlying writer
+func (w *recordingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
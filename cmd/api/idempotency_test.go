@@ -0,0 +1,289 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/openapi"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotency(t *testing.T) {
+	const body = `[{"id":1,"author":"Anne"}]`
+	stored := &models.IdempotentResponse{StatusCode: http.StatusOK, ContentType: "application/json", Body: []byte(`{"id":"stored"}`)}
+
+	type Test struct {
+		path                 string
+		headers              map[string]string
+		body                 string
+		handlerStatusCode    int
+		mockedStored         *models.IdempotentResponse
+		mockedBeginError     error
+		expectedScope        string
+		expectedBegin        bool
+		expectedStatusCode   int
+		expectedBody         string
+		expectedReplayed     bool
+		expectedHandlerCalls int
+		expectedComplete     bool
+		expectedRelease      bool
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedAuthService := new(MockedAuthService)
+			mockedAuthService.On("AuthenticateApiKey", "player-key").Return(&models.Caller{ID: "player-42", Role: models.RolePlayer}, nil)
+			mockedAuthService.On("AuthenticateApiKey", "wrong-key").Return((*models.Caller)(nil), models.ErrInvalidCredentials)
+			mockedIdempotencyService := new(MockedIdempotencyService)
+			mockedIdempotencyService.On("Begin", mock.Anything, mock.Anything, mock.Anything).Return(tt.mockedStored, tt.mockedBeginError)
+			mockedIdempotencyService.On("Complete", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			mockedIdempotencyService.On("Release", mock.Anything, mock.Anything).Return(nil)
+
+			// We only need the router of the server, the requests themselves are handled by a dummy handler
+			srv, err := openapi.NewServer(&openapi.UnimplementedHandler{}, &securityHandler{authService: mockedAuthService})
+			require.NoError(t, err)
+			handlerCalls := 0
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerCalls++
+				// The handler still gets the full body
+				b, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.Equal(t, body, string(b))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.handlerStatusCode)
+				_, _ = w.Write([]byte(`{"id":"new"}`))
+			})
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			handler := newIdempotency(&logger, srv, mockedAuthService, mockedIdempotencyService).middleware(next)
+
+			reqBody := body
+			if tt.body != "" {
+				reqBody = tt.body
+			}
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(reqBody))
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rec.Code)
+			assert.Equal(t, tt.expectedBody, strings.TrimSpace(rec.Body.String()))
+			assert.Equal(t, tt.expectedReplayed, rec.Header().Get("Idempotent-Replayed") == "true")
+			assert.Equal(t, tt.expectedHandlerCalls, handlerCalls)
+
+			if tt.expectedBegin {
+				mockedIdempotencyService.AssertCalled(t, "Begin", tt.expectedScope, "key-1", mock.Anything)
+			} else {
+				mockedIdempotencyService.AssertNotCalled(t, "Begin", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.expectedComplete {
+				mockedIdempotencyService.AssertCalled(t, "Complete", tt.expectedScope, "key-1", &models.IdempotentResponse{
+					StatusCode:  tt.handlerStatusCode,
+					ContentType: "application/json",
+					Body:        []byte(`{"id":"new"}`),
+				})
+			} else {
+				mockedIdempotencyService.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.expectedRelease {
+				mockedIdempotencyService.AssertCalled(t, "Release", tt.expectedScope, "key-1")
+			} else {
+				mockedIdempotencyService.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
+			}
+		}
+	}
+
+	answerPath := "/quote-game/03f17f15-5d0a-49ea-aa05-039f2f18373e/answer"
+
+	t.Run("handles the first request and stores its response", run(Test{
+		path:                 answerPath,
+		headers:              map[string]string{"Idempotency-Key": "key-1", "X-Player-Id": "player-42"},
+		handlerStatusCode:    http.StatusOK,
+		expectedScope:        "player:player-42",
+		expectedBegin:        true,
+		expectedStatusCode:   http.StatusOK,
+		expectedBody:         `{"id":"new"}`,
+		expectedHandlerCalls: 1,
+		expectedComplete:     true,
+	}))
+
+	t.Run("stores client errors as well", run(Test{
+		path:                 answerPath,
+		headers:              map[string]string{"Idempotency-Key": "key-1", "X-Player-Id": "player-42"},
+		handlerStatusCode:    http.StatusNotFound,
+		expectedScope:        "player:player-42",
+		expectedBegin:        true,
+		expectedStatusCode:   http.StatusNotFound,
+		expectedBody:         `{"id":"new"}`,
+		expectedHandlerCalls: 1,
+		expectedComplete:     true,
+	}))
+
+	t.Run("releases the key after a server error", run(Test{
+		path:                 answerPath,
+		headers:              map[string]string{"Idempotency-Key": "key-1", "X-Player-Id": "player-42"},
+		handlerStatusCode:    http.StatusInternalServerError,
+		expectedScope:        "player:player-42",
+		expectedBegin:        true,
+		expectedStatusCode:   http.StatusInternalServerError,
+		expectedBody:         `{"id":"new"}`,
+		expectedHandlerCalls: 1,
+		expectedRelease:      true,
+	}))
+
+	t.Run("scopes the key to the authenticated caller", run(Test{
+		path:                 "/quote-game",
+		headers:              map[string]string{"Idempotency-Key": "key-1", "X-Api-Key": "player-key"},
+		handlerStatusCode:    http.StatusOK,
+		expectedScope:        "caller:player-42",
+		expectedBegin:        true,
+		expectedStatusCode:   http.StatusOK,
+		expectedBody:         `{"id":"new"}`,
+		expectedHandlerCalls: 1,
+		expectedComplete:     true,
+	}))
+
+	t.Run("scopes the key to the authenticated caller instead of the player", run(Test{
+		path:                 "/quote-game",
+		headers:              map[string]string{"Idempotency-Key": "key-1", "X-Api-Key": "player-key", "X-Player-Id": "someone-else"},
+		handlerStatusCode:    http.StatusOK,
+		expectedScope:        "caller:player-42",
+		expectedBegin:        true,
+		expectedStatusCode:   http.StatusOK,
+		expectedBody:         `{"id":"new"}`,
+		expectedHandlerCalls: 1,
+		expectedComplete:     true,
+	}))
+
+	t.Run("rejects invalid credentials before looking up the key", run(Test{
+		path:               "/quote-game",
+		headers:            map[string]string{"Idempotency-Key": "key-1", "X-Api-Key": "wrong-key", "X-Player-Id": "player-42"},
+		expectedStatusCode: http.StatusUnauthorized,
+		expectedBody:       `{"message":"unauthorized"}`,
+	}))
+
+	t.Run("ignores the key of an anonymous caller without a player id", run(Test{
+		path:                 answerPath,
+		headers:              map[string]string{"Idempotency-Key": "key-1"},
+		handlerStatusCode:    http.StatusOK,
+		expectedStatusCode:   http.StatusOK,
+		expectedBody:         `{"id":"new"}`,
+		expectedHandlerCalls: 1,
+	}))
+
+	t.Run("replays the stored response", run(Test{
+		path:               answerPath,
+		headers:            map[string]string{"Idempotency-Key": "key-1", "X-Player-Id": "player-42"},
+		mockedStored:       stored,
+		expectedScope:      "player:player-42",
+		expectedBegin:      true,
+		expectedStatusCode: http.StatusOK,
+		expectedBody:       `{"id":"stored"}`,
+		expectedReplayed:   true,
+	}))
+
+	t.Run("rejects a reused key", run(Test{
+		path:               answerPath,
+		headers:            map[string]string{"Idempotency-Key": "key-1", "X-Player-Id": "player-42"},
+		mockedBeginError:   models.ErrIdempotencyKeyReused,
+		expectedScope:      "player:player-42",
+		expectedBegin:      true,
+		expectedStatusCode: http.StatusConflict,
+		expectedBody:       `{"message":"idempotency_key_reused"}`,
+	}))
+
+	t.Run("rejects a key of a request that is still being handled", run(Test{
+		path:               answerPath,
+		headers:            map[string]string{"Idempotency-Key": "key-1", "X-Player-Id": "player-42"},
+		mockedBeginError:   models.ErrIdempotencyKeyInProgress,
+		expectedScope:      "player:player-42",
+		expectedBegin:      true,
+		expectedStatusCode: http.StatusConflict,
+		expectedBody:       `{"message":"idempotency_key_in_progress"}`,
+	}))
+
+	t.Run("does not handle the request when the key can't be claimed", run(Test{
+		path:               answerPath,
+		headers:            map[string]string{"Idempotency-Key": "key-1", "X-Player-Id": "player-42"},
+		mockedBeginError:   errors.New("database is gone"),
+		expectedScope:      "player:player-42",
+		expectedBegin:      true,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedBody:       `{"message":"unknown_error"}`,
+	}))
+
+	t.Run("rejects a body that is too large to fingerprint", run(Test{
+		path:               answerPath,
+		headers:            map[string]string{"Idempotency-Key": "key-1", "X-Player-Id": "player-42"},
+		body:               `[{"id":1,"author":"` + strings.Repeat("A", maxIdempotentBodySize) + `"}]`,
+		expectedStatusCode: http.StatusRequestEntityTooLarge,
+		expectedBody:       `{"message":"request_too_large"}`,
+	}))
+
+	t.Run("passes through requests without a key", run(Test{
+		path:                 answerPath,
+		handlerStatusCode:    http.StatusOK,
+		expectedStatusCode:   http.StatusOK,
+		expectedBody:         `{"id":"new"}`,
+		expectedHandlerCalls: 1,
+	}))
+
+	t.Run("passes through keys that are too long", run(Test{
+		path:                 answerPath,
+		headers:              map[string]string{"Idempotency-Key": strings.Repeat("k", maxIdempotencyKeyLength+1)},
+		handlerStatusCode:    http.StatusOK,
+		expectedStatusCode:   http.StatusOK,
+		expectedBody:         `{"id":"new"}`,
+		expectedHandlerCalls: 1,
+	}))
+
+	t.Run("passes through other operations", run(Test{
+		path:                 "/rooms",
+		headers:              map[string]string{"Idempotency-Key": "key-1", "X-Player-Id": "player-42"},
+		handlerStatusCode:    http.StatusCreated,
+		expectedStatusCode:   http.StatusCreated,
+		expectedBody:         `{"id":"new"}`,
+		expectedHandlerCalls: 1,
+	}))
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	fingerprint := func(path, playerID, body string) string {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		if playerID != "" {
+			req.Header.Set("X-Player-Id", playerID)
+		}
+		return requestFingerprint(req, []byte(body))
+	}
+	withLanguage := func(language string) string {
+		req := httptest.NewRequest(http.MethodPost, "/quote-game", nil)
+		req.Header.Set("X-Player-Id", "player-42")
+		req.Header.Set("Accept-Language", language)
+		return requestFingerprint(req, nil)
+	}
+
+	base := fingerprint("/quote-game", "player-42", "")
+	assert.Equal(t, base, fingerprint("/quote-game", "player-42", ""))
+	assert.NotEqual(t, base, fingerprint("/quote-game?mode=multiple_choice", "player-42", ""))
+	assert.NotEqual(t, base, fingerprint("/quote-game", "player-43", ""))
+	assert.NotEqual(t, base, fingerprint("/quote-game", "player-42", "[]"))
+	// The language determines the quotes of a new game
+	assert.NotEqual(t, base, withLanguage("nl"))
+	assert.Equal(t, withLanguage("nl"), withLanguage("nl"))
+	assert.NotEqual(t, withLanguage("nl"), withLanguage("en"))
+	// The parts are separated, so they can't be shifted into each other
+	assert.NotEqual(t, fingerprint("/quote-game", "a", "b"), fingerprint("/quote-game", "", "ab"))
+}
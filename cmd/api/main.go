@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/pietdevries94/Kabisa/database"
+	"github.com/pietdevries94/Kabisa/models"
 	"github.com/pietdevries94/Kabisa/openapi"
+	"github.com/pietdevries94/Kabisa/pubsub"
 	"github.com/pietdevries94/Kabisa/repositories"
 	"github.com/pietdevries94/Kabisa/services"
 	"github.com/rs/zerolog"
@@ -21,36 +26,92 @@ type config struct {
 	listenAddress string
 	// The timeout in seconds used when making http requests to external services
 	httpClientTimeout string
+	// The base url of the dummyjson api. This can point to a mirror or a stub like cmd/fakequotes
+	dummyJsonBaseURL string
+	// The maximum number of concurrent requests to dummyjson
+	upstreamMaxConcurrentRequests string
+	// The time in milliseconds a request to dummyjson waits for a free slot, before the api responds with a 503
+	upstreamQueueTimeout string
 	// The log level that will be shown in the console and stored in the log file (if enabled)
 	logLevel string
 	// The path to the log file. If this is not set, the application will only log to console
 	logFilePath string
 	// The connection string for sqlite
 	sqliteDSN string
+	// The journal mode of sqlite. WAL lets requests read while another request writes
+	sqliteJournalMode string
+	// The synchronous setting of sqlite, which trades durability on power loss for write speed
+	sqliteSynchronous string
+	// The time in milliseconds a query waits for a lock of another connection, before it fails
+	sqliteBusyTimeout string
+	// Whether sqlite enforces foreign key constraints
+	sqliteForeignKeys string
+	// The maximum number of connections used for reading. 0 reads with the single connection used for writing
+	sqliteReadConnections string
+	// The number of recent games of a player of which the quotes are avoided in new games
+	recentGamesExcluded string
+	// The interval in minutes in which the local quote catalogue is synced with dummyjson. 0 only syncs on startup
+	catalogueSyncInterval string
+	// A comma separated list of static API keys, formatted as subject:role:key
+	apiKeys string
+	// The secret used to validate JWTs signed with HS256. If this is not set, HS256 is not accepted
+	jwtHS256Secret string
+	// The path to a PEM encoded public key used to validate JWTs signed with RS256. If this is not set, RS256 is not accepted
+	jwtRS256PublicKeyFile string
+	// The expected issuer of JWTs. If this is not set, the issuer is not checked
+	jwtIssuer string
+	// A comma separated list of rate limits, formatted as operationId:perMinute:burst. Operations without a limit are not limited
+	rateLimits string
+	// A comma separated list of IPs and CIDR ranges of proxies, of which the X-Forwarded-For header is trusted
+	trustedProxies string
+	// The number of event streams a single client can have open at once. 0 doesn't limit them
+	maxEventStreams string
+	// The time in minutes the response of a request with an Idempotency-Key header is replayed for retries
+	idempotencyKeyTTL string
+	// The directory backups of the database are written to. If this is not set, backups are disabled
+	backupDir string
+	// The interval in minutes in which a backup is made. 0 disables scheduled backups
+	backupInterval string
+	// The number of backups kept in the backup directory. 0 keeps every backup
+	backupRetention string
 }
 
 // application contains setup services, directly needed by it's httpHandler methods
 type application struct {
-	logger       *zerolog.Logger
-	quoteService quoteService
+	logger             *zerolog.Logger
+	quoteService       quoteService
+	catalogueService   catalogueService
+	authService        authService
+	roomService        roomService
+	idempotencyService idempotencyService
+	backupService      backupService
+	exportService      exportService
+	statsService       statsService
+	events             eventBroker
 }
 
 func main() {
 	config := initConfig()
 	logger := initLogger(config)
 
+	// The backup command backs up the database of the api, also while it's running, instead of starting the server
+	if len(os.Args) > 1 && os.Args[1] == "backup" {
+		runBackupCommand(logger, config, os.Args[2:])
+		return
+	}
+	// The export command writes the game data to a file, like GET /admin/exports/games
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		runExportCommand(logger, config, os.Args[2:])
+		return
+	}
+
 	// init Application sets services, repositories and their dependencies
 	app := initApplication(logger, config)
 
-	srv, err := openapi.NewServer(app)
-	if err != nil {
-		logger.Fatal().
-			Err(err).
-			Msg("failed to setup ogen api")
-	}
+	handler := initHttpHandler(logger, config, app)
 
 	logger.Info().Str("address", config.listenAddress).Msg("starting server")
-	err = http.ListenAndServe(config.listenAddress, srv)
+	err := http.ListenAndServe(config.listenAddress, handler)
 	if err != nil {
 		logger.Fatal().
 			Err(err).
@@ -66,11 +127,32 @@ func main() {
 func initConfig() *config {
 	// First, we initialize the config with default values
 	conf := &config{
-		listenAddress:     "127.0.0.1:3333",
-		httpClientTimeout: "10",
-		logLevel:          "info",
-		logFilePath:       "",
-		sqliteDSN:         "file::memory:?cache=shared",
+		listenAddress:                 "127.0.0.1:3333",
+		httpClientTimeout:             "10",
+		dummyJsonBaseURL:              "https://dummyjson.com",
+		upstreamMaxConcurrentRequests: "10",
+		upstreamQueueTimeout:          "2000",
+		logLevel:                      "info",
+		logFilePath:                   "",
+		sqliteDSN:                     "file::memory:?cache=shared",
+		sqliteJournalMode:             "WAL",
+		sqliteSynchronous:             "NORMAL",
+		sqliteBusyTimeout:             "5000",
+		sqliteForeignKeys:             "true",
+		sqliteReadConnections:         "4",
+		recentGamesExcluded:           "10",
+		catalogueSyncInterval:         "60",
+		apiKeys:                       "",
+		jwtHS256Secret:                "",
+		jwtRS256PublicKeyFile:         "",
+		jwtIssuer:                     "",
+		rateLimits:                    "createNewQuoteGame:30:10,createDailyQuoteGame:30:10,createRoom:30:10,streamQuoteGameEvents:30:10,streamRoomEvents:30:10",
+		trustedProxies:                "",
+		maxEventStreams:               "10",
+		idempotencyKeyTTL:             "1440",
+		backupDir:                     "",
+		backupInterval:                "0",
+		backupRetention:               "7",
 	}
 
 	// Next, we manually lookup the environment variables
@@ -80,6 +162,15 @@ func initConfig() *config {
 	if val, found := os.LookupEnv("KABISAQUOTE_HTTP_CLIENT_TIMEOUT"); found {
 		conf.httpClientTimeout = val
 	}
+	if val, found := os.LookupEnv("KABISAQUOTE_DUMMYJSON_BASE_URL"); found {
+		conf.dummyJsonBaseURL = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_UPSTREAM_MAX_CONCURRENT_REQUESTS"); found {
+		conf.upstreamMaxConcurrentRequests = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_UPSTREAM_QUEUE_TIMEOUT"); found {
+		conf.upstreamQueueTimeout = val
+	}
 	if val, found := os.LookupEnv("KABISAQUOTE_LOG_LEVEL"); found {
 		conf.logLevel = val
 	}
@@ -89,6 +180,60 @@ func initConfig() *config {
 	if val, found := os.LookupEnv("KABISAQUOTE_SQLITE_DSN"); found {
 		conf.sqliteDSN = val
 	}
+	if val, found := os.LookupEnv("KABISAQUOTE_SQLITE_JOURNAL_MODE"); found {
+		conf.sqliteJournalMode = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_SQLITE_SYNCHRONOUS"); found {
+		conf.sqliteSynchronous = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_SQLITE_BUSY_TIMEOUT"); found {
+		conf.sqliteBusyTimeout = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_SQLITE_FOREIGN_KEYS"); found {
+		conf.sqliteForeignKeys = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_SQLITE_READ_CONNECTIONS"); found {
+		conf.sqliteReadConnections = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_RECENT_GAMES_EXCLUDED"); found {
+		conf.recentGamesExcluded = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_CATALOGUE_SYNC_INTERVAL"); found {
+		conf.catalogueSyncInterval = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_API_KEYS"); found {
+		conf.apiKeys = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_JWT_HS256_SECRET"); found {
+		conf.jwtHS256Secret = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_JWT_RS256_PUBLIC_KEY_FILE"); found {
+		conf.jwtRS256PublicKeyFile = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_JWT_ISSUER"); found {
+		conf.jwtIssuer = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_RATE_LIMITS"); found {
+		conf.rateLimits = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_TRUSTED_PROXIES"); found {
+		conf.trustedProxies = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_MAX_EVENT_STREAMS"); found {
+		conf.maxEventStreams = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_IDEMPOTENCY_KEY_TTL"); found {
+		conf.idempotencyKeyTTL = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_BACKUP_DIR"); found {
+		conf.backupDir = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_BACKUP_INTERVAL"); found {
+		conf.backupInterval = val
+	}
+	if val, found := os.LookupEnv("KABISAQUOTE_BACKUP_RETENTION"); found {
+		conf.backupRetention = val
+	}
 
 	return conf
 }
@@ -135,17 +280,194 @@ func initLogger(conf *config) *zerolog.Logger {
 // initApplication sets up the services, repositories and their dependencies
 // It returns a struct which contains the logger and services to be used by it's httpHandler methods
 func initApplication(logger *zerolog.Logger, conf *config) *application {
-	db := database.Init(logger, conf.sqliteDSN)
+	db := initDatabase(logger, conf)
 	httpClient := initHttpClient(logger, conf)
 
-	dummyJsonRepo := repositories.NewDummyJsonRepo(logger, httpClient)
+	upstreamMaxConcurrentRequests, err := strconv.Atoi(conf.upstreamMaxConcurrentRequests)
+	if err != nil || upstreamMaxConcurrentRequests < 1 {
+		logger.Fatal().Err(err).Str("value", conf.upstreamMaxConcurrentRequests).Msg("could not parse set upstreamMaxConcurrentRequests as positive int")
+	}
+	upstreamQueueTimeout, err := strconv.Atoi(conf.upstreamQueueTimeout)
+	if err != nil {
+		logger.Fatal().Err(err).Str("value", conf.upstreamQueueTimeout).Msg("could not parse set upstreamQueueTimeout as int")
+	}
+	dummyJsonRepo := repositories.NewDummyJsonRepo(logger, httpClient, conf.dummyJsonBaseURL, upstreamMaxConcurrentRequests, time.Duration(upstreamQueueTimeout)*time.Millisecond)
 	quoteGameRepo := repositories.NewQuoteGameRepo(logger, db)
-	quoteService := services.NewQuoteService(logger, dummyJsonRepo, quoteGameRepo)
+	quoteRepo := repositories.NewQuoteRepo(logger, db)
+	recentGamesExcluded, err := strconv.Atoi(conf.recentGamesExcluded)
+	if err != nil {
+		logger.Fatal().Err(err).Str("value", conf.recentGamesExcluded).Msg("could not parse set recentGamesExcluded as int")
+	}
+	broker := pubsub.NewBroker(logger, eventBufferSize)
+	quoteService := services.NewQuoteService(logger, dummyJsonRepo, quoteGameRepo, quoteRepo, recentGamesExcluded, broker)
+	catalogueService := services.NewCatalogueService(logger, dummyJsonRepo, quoteRepo)
+
+	catalogueSyncInterval, err := strconv.Atoi(conf.catalogueSyncInterval)
+	if err != nil {
+		logger.Fatal().Err(err).Str("value", conf.catalogueSyncInterval).Msg("could not parse set catalogueSyncInterval as int")
+	}
+	startCatalogueSync(logger, catalogueService, time.Duration(catalogueSyncInterval)*time.Minute)
+
+	roomRepo := repositories.NewRoomRepo(logger, db)
+	roomService := services.NewRoomService(logger, roomRepo, quoteService, broker)
+
+	apiKeyRepo := repositories.NewApiKeyRepo(logger, db)
+	authService := services.NewAuthService(logger, apiKeyRepo, parseStaticApiKeys(logger, conf.apiKeys), initJWTConfig(logger, conf))
+
+	idempotencyKeyTTL, err := strconv.Atoi(conf.idempotencyKeyTTL)
+	if err != nil || idempotencyKeyTTL < 1 {
+		logger.Fatal().Err(err).Str("value", conf.idempotencyKeyTTL).Msg("could not parse set idempotencyKeyTTL as positive int")
+	}
+	idempotencyRepo := repositories.NewIdempotencyRepo(logger, db)
+	idempotencyService := services.NewIdempotencyService(logger, idempotencyRepo, time.Duration(idempotencyKeyTTL)*time.Minute)
+
+	backupService := initBackupService(logger, conf, db)
+	backupInterval, err := strconv.Atoi(conf.backupInterval)
+	if err != nil || backupInterval < 0 {
+		logger.Fatal().Err(err).Str("value", conf.backupInterval).Msg("could not parse set backupInterval as non negative int")
+	}
+	if backupInterval > 0 && conf.backupDir == "" {
+		logger.Fatal().Str("value", conf.backupInterval).Msg("backupInterval is set, but there is no backupDir to write the backups to")
+	}
+	startBackupSchedule(logger, backupService, time.Duration(backupInterval)*time.Minute)
 
 	return &application{
-		logger:       logger,
-		quoteService: quoteService,
+		logger:             logger,
+		quoteService:       quoteService,
+		catalogueService:   catalogueService,
+		authService:        authService,
+		roomService:        roomService,
+		idempotencyService: idempotencyService,
+		backupService:      backupService,
+		exportService:      services.NewExportService(logger, quoteGameRepo),
+		statsService:       services.NewStatsService(logger, repositories.NewStatsRepo(logger, db)),
+		events:             broker,
+	}
+}
+
+// initDatabase opens the sqlite database with the pragmas and pool size from the config, and runs the migrations
+func initDatabase(logger *zerolog.Logger, conf *config) *database.DB {
+	busyTimeout, err := strconv.Atoi(conf.sqliteBusyTimeout)
+	if err != nil || busyTimeout < 0 {
+		logger.Fatal().Err(err).Str("value", conf.sqliteBusyTimeout).Msg("could not parse set sqliteBusyTimeout as non negative int")
+	}
+	foreignKeys, err := strconv.ParseBool(conf.sqliteForeignKeys)
+	if err != nil {
+		logger.Fatal().Err(err).Str("value", conf.sqliteForeignKeys).Msg("could not parse set sqliteForeignKeys as bool")
+	}
+	readConnections, err := strconv.Atoi(conf.sqliteReadConnections)
+	if err != nil || readConnections < 0 {
+		logger.Fatal().Err(err).Str("value", conf.sqliteReadConnections).Msg("could not parse set sqliteReadConnections as non negative int")
+	}
+
+	return database.InitWithConfig(logger, conf.sqliteDSN, database.Config{
+		JournalMode:     conf.sqliteJournalMode,
+		Synchronous:     conf.sqliteSynchronous,
+		BusyTimeout:     time.Duration(busyTimeout) * time.Millisecond,
+		ForeignKeys:     foreignKeys,
+		ReadConnections: readConnections,
+	})
+}
+
+// parseStaticApiKeys parses the comma separated list of static API keys from the config. Every entry is formatted as subject:role:key
+func parseStaticApiKeys(logger *zerolog.Logger, apiKeys string) map[string]*models.Caller {
+	res := map[string]*models.Caller{}
+	for _, entry := range strings.Split(apiKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		// The key is last, so it may contain colons itself
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" || !models.Role(parts[1]).Valid() {
+			logger.Fatal().Str("subject", parts[0]).Msg("could not parse set apiKeys, entries should be formatted as subject:role:key")
+		}
+		res[parts[2]] = &models.Caller{
+			ID:   parts[0],
+			Role: models.Role(parts[1]),
+		}
+	}
+	return res
+}
+
+// initJWTConfig creates the config used to validate JWTs. The RS256 public key is read from the file in the config
+func initJWTConfig(logger *zerolog.Logger, conf *config) services.JWTConfig {
+	jwtConfig := services.JWTConfig{
+		HS256Secret: []byte(conf.jwtHS256Secret),
+		Issuer:      conf.jwtIssuer,
+	}
+
+	if conf.jwtRS256PublicKeyFile != "" {
+		pem, err := os.ReadFile(conf.jwtRS256PublicKeyFile)
+		if err != nil {
+			logger.Fatal().Err(err).Str("path", conf.jwtRS256PublicKeyFile).Msg("can't read set jwtRS256PublicKeyFile")
+		}
+		jwtConfig.RS256PublicKey, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			logger.Fatal().Err(err).Str("path", conf.jwtRS256PublicKeyFile).Msg("could not parse set jwtRS256PublicKeyFile as rsa public key")
+		}
+	}
+
+	return jwtConfig
+}
+
+// initHttpHandler creates the ogen server for the application, wrapped in the middleware. The event streams are mounted next to it.
+// Every error response, also from the rate limiter and the event streams, gets a translated detail from localizeErrors.
+// Idempotent responses are stored before they are localized, so a retry gets the detail in the language it asks for
+func initHttpHandler(logger *zerolog.Logger, conf *config, app *application) http.Handler {
+	srv, err := openapi.NewServer(app, &securityHandler{authService: app.authService}, openapi.WithErrorHandler(app.handleError))
+	if err != nil {
+		logger.Fatal().
+			Err(err).
+			Msg("failed to setup ogen api")
+	}
+
+	rateLimiter := initRateLimiter(logger, conf, srv, app.authService)
+	maxEventStreams, err := strconv.Atoi(conf.maxEventStreams)
+	if err != nil || maxEventStreams < 0 {
+		logger.Fatal().Err(err).Str("value", conf.maxEventStreams).Msg("could not parse set maxEventStreams as non negative int")
+	}
+	streams := newStreamLimiter(logger, rateLimiter.clientKey, maxEventStreams)
+	idempotency := newIdempotency(logger, srv, app.authService, app.idempotencyService)
+	return localizeErrors(initEventHandler(app, rateLimiter.middleware(idempotency.middleware(srv)), rateLimiter, streams))
+}
+
+// initRateLimiter creates the rate limiter with the limits and trusted proxies from the config
+func initRateLimiter(logger *zerolog.Logger, conf *config, routes routeFinder, authService authService) *rateLimiter {
+	limits, err := parseRateLimits(conf.rateLimits)
+	if err != nil {
+		logger.Fatal().Err(err).Str("value", conf.rateLimits).Msg("could not parse set rateLimits")
+	}
+	trustedProxies, err := parseTrustedProxies(conf.trustedProxies)
+	if err != nil {
+		logger.Fatal().Err(err).Str("value", conf.trustedProxies).Msg("could not parse set trustedProxies")
 	}
+	return newRateLimiter(logger, routes, authService, limits, trustedProxies)
+}
+
+// startCatalogueSync syncs the local quote catalogue in the background, so the server can start while dummyjson is slow or unreachable.
+// After the first sync, the catalogue is synced again every interval. An interval of 0 disables the periodic sync.
+func startCatalogueSync(logger *zerolog.Logger, catalogueService catalogueService, interval time.Duration) {
+	sync := func() {
+		err := catalogueService.SyncCatalogue(context.Background())
+		if err != nil {
+			logger.Error().Err(err).Msg("could not sync quote catalogue")
+		}
+	}
+
+	go func() {
+		sync()
+		if interval <= 0 {
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			sync()
+		}
+	}()
 }
 
 // initHttpClient creates a http client to be used for http requests to external services
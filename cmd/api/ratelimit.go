@@ -0,0 +1,278 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/openapi"
+	"github.com/rs/zerolog"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitCleanupInterval is how often buckets that are full again are removed, so memory doesn't grow with every client ever seen
+const rateLimitCleanupInterval = time.Minute
+
+// rateLimit is the token bucket configuration of a single operation
+type rateLimit struct {
+	// perMinute is the number of requests a client can do per minute on average
+	perMinute int
+	// burst is the number of requests a client can do at once
+	burst int
+}
+
+// routeFinder finds the operation of a request. It's implemented by the ogen server
+type routeFinder interface {
+	FindRoute(method, path string) (openapi.Route, bool)
+}
+
+// bucketKey identifies the token bucket of a single client for a single operation
+type bucketKey struct {
+	operationID string
+	client      string
+}
+
+// rateLimiter limits the number of requests per client using a token bucket per client and operation.
+// Authenticated clients are identified by their identity, all other clients by their IP.
+type rateLimiter struct {
+	logger      *zerolog.Logger
+	routes      routeFinder
+	authService authService
+	// limits contains the rate limit per operation id. Operations without a limit are not limited
+	limits map[string]rateLimit
+	// trustedProxies are the proxies of which the X-Forwarded-For header is trusted
+	trustedProxies []netip.Prefix
+
+	mu          sync.Mutex
+	buckets     map[bucketKey]*rate.Limiter
+	lastCleanup time.Time
+	// now returns the current time and can be replaced in tests
+	now func() time.Time
+}
+
+func newRateLimiter(logger *zerolog.Logger, routes routeFinder, authService authService, limits map[string]rateLimit, trustedProxies []netip.Prefix) *rateLimiter {
+	return &rateLimiter{
+		logger:         logger,
+		routes:         routes,
+		authService:    authService,
+		limits:         limits,
+		trustedProxies: trustedProxies,
+		buckets:        map[bucketKey]*rate.Limiter{},
+		lastCleanup:    time.Now(),
+		now:            time.Now,
+	}
+}
+
+// middleware rejects requests of clients that exceeded the limit of the operation with a 429.
+// The Retry-After header tells the client how many seconds to wait before the next request will be accepted.
+func (rl *rateLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, ok := rl.routes.FindRoute(r.Method, r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rl.operation(route.OperationID(), next).ServeHTTP(w, r)
+	})
+}
+
+// operation limits the requests to next by the limit of the operation id, like middleware does for the operations of the server.
+// It's used directly for the routes that are not part of the server, like the event streams.
+func (rl *rateLimiter) operation(operationID string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, ok := rl.limits[operationID]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := bucketKey{
+			operationID: operationID,
+			client:      rl.clientKey(r),
+		}
+		retryAfter, allowed := rl.take(key, limit)
+		if !allowed {
+			rl.logger.Debug().Str("operation", key.operationID).Str("client", key.client).Msg("request is rate limited")
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			writeJSON(w, http.StatusTooManyRequests, &openapi.R429{Message: "too_many_requests"})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// take takes a token from the bucket of the key. If the bucket is empty, it returns the number of seconds until a token is available
+func (rl *rateLimiter) take(key bucketKey, limit rateLimit) (retryAfter int, allowed bool) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.cleanup(now)
+
+	bucket, ok := rl.buckets[key]
+	if !ok {
+		bucket = rate.NewLimiter(rate.Limit(float64(limit.perMinute)/60), limit.burst)
+		rl.buckets[key] = bucket
+	}
+
+	reservation := bucket.ReserveN(now, 1)
+	if !reservation.OK() {
+		// The burst is 0, so no request will ever be allowed
+		return math.MaxInt32, false
+	}
+	delay := reservation.DelayFrom(now)
+	if delay == 0 {
+		return 0, true
+	}
+
+	// We don't wait for the token, so we give it back
+	reservation.CancelAt(now)
+	return int(math.Ceil(delay.Seconds())), false
+}
+
+// cleanup removes buckets that are full again. A new bucket is full as well, so removing them doesn't change the limits.
+// It should be called while holding the lock.
+func (rl *rateLimiter) cleanup(now time.Time) {
+	if now.Sub(rl.lastCleanup) < rateLimitCleanupInterval {
+		return
+	}
+	rl.lastCleanup = now
+
+	for key, bucket := range rl.buckets {
+		if bucket.TokensAt(now) >= float64(bucket.Burst()) {
+			delete(rl.buckets, key)
+		}
+	}
+}
+
+// clientKey identifies the client of the request. Clients with valid credentials are identified by their identity,
+// so they can't escape the limit by changing their IP. All other clients are identified by their IP.
+// Invalid credentials are ignored here, otherwise a client could get a new bucket with every made up API key.
+func (rl *rateLimiter) clientKey(r *http.Request) string {
+	if caller, ok := authenticateRequest(r, rl.authService); ok {
+		return "caller:" + caller.ID
+	}
+	return "ip:" + rl.clientIP(r)
+}
+
+// clientIP returns the IP of the client. When the request comes from a trusted proxy, the X-Forwarded-For header is
+// walked from right to left and the first address that is not a trusted proxy is used. Addresses to the left of it
+// can be set by the client itself, so they are never trusted.
+func (rl *rateLimiter) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !rl.isTrustedProxy(addr) {
+		return host
+	}
+
+	forwardedFor := []string{}
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		forwardedFor = append(forwardedFor, strings.Split(header, ",")...)
+	}
+	for i := len(forwardedFor) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(forwardedFor[i]))
+		if err != nil {
+			// The header is malformed from here on, so the last valid hop is the best we know
+			break
+		}
+		addr = hop.Unmap()
+		if !rl.isTrustedProxy(addr) {
+			break
+		}
+	}
+	return addr.String()
+}
+
+func (rl *rateLimiter) isTrustedProxy(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range rl.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// operationIDs returns the operation ids of the generated server. ogen names a method of the handler after every operation,
+// which is the operation id starting with an upper case letter
+func operationIDs() map[string]bool {
+	handler := reflect.TypeFor[openapi.Handler]()
+	res := make(map[string]bool, handler.NumMethod())
+	for i := range handler.NumMethod() {
+		name := handler.Method(i).Name
+		res[strings.ToLower(name[:1])+name[1:]] = true
+	}
+	return res
+}
+
+// parseRateLimits parses a comma separated list of rate limits, formatted as operationId:perMinute:burst.
+// An operation id the server doesn't have is an error, as its limit would never apply. The event streams have operation ids as well
+func parseRateLimits(rateLimits string) (map[string]rateLimit, error) {
+	known := operationIDs()
+	for _, id := range eventOperationIDs {
+		known[id] = true
+	}
+	res := map[string]rateLimit{}
+	for _, entry := range strings.Split(rateLimits, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("rate limit %q should be formatted as operationId:perMinute:burst", entry)
+		}
+		if !known[parts[0]] {
+			return nil, fmt.Errorf("rate limit %q is for an unknown operation id", entry)
+		}
+		perMinute, err := strconv.Atoi(parts[1])
+		if err != nil || perMinute <= 0 {
+			return nil, fmt.Errorf("rate limit %q should have a positive number of requests per minute", entry)
+		}
+		burst, err := strconv.Atoi(parts[2])
+		if err != nil || burst <= 0 {
+			return nil, fmt.Errorf("rate limit %q should have a positive burst", entry)
+		}
+		res[parts[0]] = rateLimit{perMinute: perMinute, burst: burst}
+	}
+	return res, nil
+}
+
+// parseTrustedProxies parses a comma separated list of IPs and CIDR ranges
+func parseTrustedProxies(trustedProxies string) ([]netip.Prefix, error) {
+	res := []netip.Prefix{}
+	for _, entry := range strings.Split(trustedProxies, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, prefix.Masked())
+	}
+	return res, nil
+}
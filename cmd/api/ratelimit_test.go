@@ -0,0 +1,242 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/openapi"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	type request struct {
+		method     string
+		path       string
+		remoteAddr string
+		headers    map[string]string
+		// after is the time since the first request
+		after              time.Duration
+		expectedStatusCode int
+		expectedRetryAfter string
+	}
+	type Test struct {
+		limits         map[string]rateLimit
+		trustedProxies []netip.Prefix
+		requests       []request
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedAuthService := new(MockedAuthService)
+			mockedAuthService.On("AuthenticateApiKey", "player-key").Return(&models.Caller{ID: "player-42", Role: models.RolePlayer}, nil)
+			mockedAuthService.On("AuthenticateApiKey", mock.Anything).Return((*models.Caller)(nil), models.ErrInvalidCredentials)
+			mockedAuthService.On("AuthenticateJWT", "player-jwt").Return(&models.Caller{ID: "player-42", Role: models.RolePlayer}, nil)
+			mockedAuthService.On("AuthenticateJWT", mock.Anything).Return((*models.Caller)(nil), models.ErrInvalidCredentials)
+
+			// We only need the router of the server, the requests themselves are handled by a dummy handler
+			srv, err := openapi.NewServer(&openapi.UnimplementedHandler{}, &securityHandler{authService: mockedAuthService})
+			require.NoError(t, err)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			rl := newRateLimiter(&logger, srv, mockedAuthService, tt.limits, tt.trustedProxies)
+			start := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+			handler := rl.middleware(next)
+
+			for i, r := range tt.requests {
+				rl.now = func() time.Time { return start.Add(r.after) }
+
+				req := httptest.NewRequest(r.method, r.path, nil)
+				req.RemoteAddr = r.remoteAddr
+				for k, v := range r.headers {
+					req.Header.Set(k, v)
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+
+				assert.Equal(t, r.expectedStatusCode, rec.Code, "request %d", i)
+				assert.Equal(t, r.expectedRetryAfter, rec.Header().Get("Retry-After"), "request %d", i)
+				if r.expectedStatusCode == http.StatusTooManyRequests {
+					assert.Equal(t, `{"message":"too_many_requests"}`, strings.TrimSpace(rec.Body.String()), "request %d", i)
+				}
+			}
+		}
+	}
+
+	createGame := func(remoteAddr string, after time.Duration, expectedStatusCode int, expectedRetryAfter string) request {
+		return request{
+			method:             http.MethodPost,
+			path:               "/quote-game",
+			remoteAddr:         remoteAddr,
+			after:              after,
+			expectedStatusCode: expectedStatusCode,
+			expectedRetryAfter: expectedRetryAfter,
+		}
+	}
+	withHeaders := func(r request, headers map[string]string) request {
+		r.headers = headers
+		return r
+	}
+
+	gameLimit := map[string]rateLimit{"createNewQuoteGame": {perMinute: 6, burst: 2}}
+
+	t.Run("rejects requests after the burst until a token is available", run(Test{
+		limits: gameLimit,
+		requests: []request{
+			createGame("192.0.2.1:1234", 0, http.StatusOK, ""),
+			createGame("192.0.2.1:1234", 0, http.StatusOK, ""),
+			createGame("192.0.2.1:1234", 0, http.StatusTooManyRequests, "10"),
+			createGame("192.0.2.1:1234", 4*time.Second, http.StatusTooManyRequests, "6"),
+			createGame("192.0.2.1:1234", 10*time.Second, http.StatusOK, ""),
+			createGame("192.0.2.1:1234", 10*time.Second, http.StatusTooManyRequests, "10"),
+		},
+	}))
+
+	t.Run("limits clients separately", run(Test{
+		limits: gameLimit,
+		requests: []request{
+			createGame("192.0.2.1:1234", 0, http.StatusOK, ""),
+			createGame("192.0.2.1:1234", 0, http.StatusOK, ""),
+			createGame("192.0.2.1:1234", 0, http.StatusTooManyRequests, "10"),
+			createGame("192.0.2.2:1234", 0, http.StatusOK, ""),
+		},
+	}))
+
+	t.Run("does not limit operations without a limit", run(Test{
+		limits: gameLimit,
+		requests: []request{
+			createGame("192.0.2.1:1234", 0, http.StatusOK, ""),
+			createGame("192.0.2.1:1234", 0, http.StatusOK, ""),
+			{method: http.MethodGet, path: "/quote", remoteAddr: "192.0.2.1:1234", expectedStatusCode: http.StatusOK},
+			{method: http.MethodGet, path: "/quote", remoteAddr: "192.0.2.1:1234", expectedStatusCode: http.StatusOK},
+			{method: http.MethodGet, path: "/quote", remoteAddr: "192.0.2.1:1234", expectedStatusCode: http.StatusOK},
+		},
+	}))
+
+	t.Run("uses separate buckets per operation", run(Test{
+		limits: map[string]rateLimit{
+			"createNewQuoteGame":       {perMinute: 6, burst: 1},
+			"submitAnswerForQuoteGame": {perMinute: 60, burst: 1},
+		},
+		requests: []request{
+			createGame("192.0.2.1:1234", 0, http.StatusOK, ""),
+			{method: http.MethodPost, path: "/quote-game/03f17f15-5d0a-49ea-aa05-039f2f18373e/answer", remoteAddr: "192.0.2.1:1234", expectedStatusCode: http.StatusOK},
+			{method: http.MethodPost, path: "/quote-game/03f17f15-5d0a-49ea-aa05-039f2f18373e/answer", remoteAddr: "192.0.2.1:1234", expectedStatusCode: http.StatusTooManyRequests, expectedRetryAfter: "1"},
+			createGame("192.0.2.1:1234", 0, http.StatusTooManyRequests, "10"),
+		},
+	}))
+
+	t.Run("identifies authenticated clients by their identity instead of their ip", run(Test{
+		limits: gameLimit,
+		requests: []request{
+			withHeaders(createGame("192.0.2.1:1234", 0, http.StatusOK, ""), map[string]string{"X-Api-Key": "player-key"}),
+			withHeaders(createGame("192.0.2.2:1234", 0, http.StatusOK, ""), map[string]string{"Authorization": "Bearer player-jwt"}),
+			withHeaders(createGame("192.0.2.3:1234", 0, http.StatusTooManyRequests, "10"), map[string]string{"X-Api-Key": "player-key"}),
+			createGame("192.0.2.1:1234", 0, http.StatusOK, ""),
+		},
+	}))
+
+	t.Run("falls back to the ip for invalid credentials", run(Test{
+		limits: gameLimit,
+		requests: []request{
+			withHeaders(createGame("192.0.2.1:1234", 0, http.StatusOK, ""), map[string]string{"X-Api-Key": "made-up-key-1"}),
+			withHeaders(createGame("192.0.2.1:1234", 0, http.StatusOK, ""), map[string]string{"X-Api-Key": "made-up-key-2"}),
+			withHeaders(createGame("192.0.2.1:1234", 0, http.StatusTooManyRequests, "10"), map[string]string{"X-Api-Key": "made-up-key-3"}),
+		},
+	}))
+
+	t.Run("uses X-Forwarded-For of trusted proxies", run(Test{
+		limits:         gameLimit,
+		trustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+		requests: []request{
+			withHeaders(createGame("10.0.0.1:1234", 0, http.StatusOK, ""), map[string]string{"X-Forwarded-For": "192.0.2.1"}),
+			withHeaders(createGame("10.0.0.2:1234", 0, http.StatusOK, ""), map[string]string{"X-Forwarded-For": "192.0.2.1, 10.0.0.3"}),
+			withHeaders(createGame("10.0.0.1:1234", 0, http.StatusTooManyRequests, "10"), map[string]string{"X-Forwarded-For": "192.0.2.1"}),
+			withHeaders(createGame("10.0.0.1:1234", 0, http.StatusOK, ""), map[string]string{"X-Forwarded-For": "192.0.2.2"}),
+		},
+	}))
+
+	t.Run("ignores addresses in X-Forwarded-For set by the client", run(Test{
+		limits:         gameLimit,
+		trustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+		requests: []request{
+			withHeaders(createGame("10.0.0.1:1234", 0, http.StatusOK, ""), map[string]string{"X-Forwarded-For": "198.51.100.1, 192.0.2.1"}),
+			withHeaders(createGame("10.0.0.1:1234", 0, http.StatusOK, ""), map[string]string{"X-Forwarded-For": "198.51.100.2, 192.0.2.1"}),
+			withHeaders(createGame("10.0.0.1:1234", 0, http.StatusTooManyRequests, "10"), map[string]string{"X-Forwarded-For": "198.51.100.3, 192.0.2.1"}),
+		},
+	}))
+
+	t.Run("ignores X-Forwarded-For of untrusted clients", run(Test{
+		limits: gameLimit,
+		requests: []request{
+			withHeaders(createGame("192.0.2.1:1234", 0, http.StatusOK, ""), map[string]string{"X-Forwarded-For": "198.51.100.1"}),
+			withHeaders(createGame("192.0.2.1:1234", 0, http.StatusOK, ""), map[string]string{"X-Forwarded-For": "198.51.100.2"}),
+			withHeaders(createGame("192.0.2.1:1234", 0, http.StatusTooManyRequests, "10"), map[string]string{"X-Forwarded-For": "198.51.100.3"}),
+		},
+	}))
+}
+
+func TestParseRateLimits(t *testing.T) {
+	res, err := parseRateLimits("createNewQuoteGame:30:10, submitAnswerForQuoteGame:120:20")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]rateLimit{
+		"createNewQuoteGame":       {perMinute: 30, burst: 10},
+		"submitAnswerForQuoteGame": {perMinute: 120, burst: 20},
+	}, res)
+
+	// The event streams are not in the spec, but can be limited as well
+	res, err = parseRateLimits("streamRoomEvents:10:5")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]rateLimit{"streamRoomEvents": {perMinute: 10, burst: 5}}, res)
+
+	res, err = parseRateLimits("")
+	require.NoError(t, err)
+	assert.Empty(t, res)
+
+	for _, invalid := range []string{
+		"createNewQuoteGame", "createNewQuoteGame:30", ":30:10", "createNewQuoteGame:0:10", "createNewQuoteGame:30:many",
+		"createQuoteGame:30:10", "CreateNewQuoteGame:30:10", "createRoom:30:10,joinRooms:30:10",
+	} {
+		_, err = parseRateLimits(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestOperationIDs(t *testing.T) {
+	// The operation ids derived from the handler are the ones in the spec
+	spec, err := os.ReadFile("../../openapi.yaml")
+	require.NoError(t, err)
+	expected := map[string]bool{}
+	for _, match := range regexp.MustCompile(`(?m)^\s+operationId: (\w+)$`).FindAllStringSubmatch(string(spec), -1) {
+		expected[match[1]] = true
+	}
+	require.NotEmpty(t, expected)
+	assert.Equal(t, expected, operationIDs())
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	res, err := parseTrustedProxies("10.0.0.1, 172.16.0.0/12, ::1")
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.1/32"),
+		netip.MustParsePrefix("172.16.0.0/12"),
+		netip.MustParsePrefix("::1/128"),
+	}, res)
+
+	_, err = parseTrustedProxies("not-an-ip")
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/openapi"
+)
+
+// CreateRoom creates a quote game in a new room. The caller becomes the host and can share the code of the room with other players
+func (app *application) CreateRoom(ctx context.Context, params openapi.CreateRoomParams) (openapi.CreateRoomRes, error) {
+	room, err := app.roomService.CreateRoom(ctx, playerID(ctx, params.XPlayerID))
+	if errors.Is(err, models.ErrUpstreamBusy) {
+		return app.serviceUnavailable()
+	}
+	if err == models.ErrPlayerIDRequired {
+		return app.unprocessableContent(err)
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling roomService.CreateRoom")
+		return app.internalServerError()
+	}
+
+	return roomResponse(room), nil
+}
+
+// JoinRoom adds the caller to the room and returns the quote game of the room
+func (app *application) JoinRoom(ctx context.Context, params openapi.JoinRoomParams) (openapi.JoinRoomRes, error) {
+	room, err := app.roomService.JoinRoom(ctx, params.Code, playerID(ctx, params.XPlayerID))
+	if err == models.ErrRoomNotFound {
+		return app.notFound()
+	}
+	if errors.Is(err, models.ErrUpstreamBusy) {
+		return app.serviceUnavailable()
+	}
+	if err == models.ErrPlayerIDRequired {
+		return app.unprocessableContent(err)
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling roomService.JoinRoom")
+		return app.internalServerError()
+	}
+
+	return roomResponse(room), nil
+}
+
+// SubmitAnswerForRoom receives the answers of a participant of the room and returns the result of that participant
+func (app *application) SubmitAnswerForRoom(ctx context.Context, answers []openapi.QuoteGameAnswer, params openapi.SubmitAnswerForRoomParams) (openapi.SubmitAnswerForRoomRes, error) {
+	answerMap := make(models.QuoteGameAnswerMap)
+	for _, a := range answers {
+		answerMap[a.ID] = a.Author
+	}
+
+	gameResult, err := app.roomService.SubmitRoomAnswer(ctx, params.Code, playerID(ctx, params.XPlayerID), answerMap)
+	if err == models.ErrRoomNotFound {
+		return app.notFound()
+	}
+	if errors.Is(err, models.ErrUpstreamBusy) {
+		return app.serviceUnavailable()
+	}
+	if err != nil {
+		if _, ok := err.(*models.PublicError); !ok {
+			app.logger.Error().Err(err).Msg("unexpected error when calling roomService.SubmitRoomAnswer")
+		}
+		return app.unprocessableContent(err)
+	}
+
+	return quoteGameResultResponse(gameResult), nil
+}
+
+// GetRoomResult returns the score of every participant of the room
+func (app *application) GetRoomResult(ctx context.Context, params openapi.GetRoomResultParams) (openapi.GetRoomResultRes, error) {
+	room, err := app.roomService.GetRoomResult(ctx, params.Code)
+	if err == models.ErrRoomNotFound {
+		return app.notFound()
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling roomService.GetRoomResult")
+		return app.internalServerError()
+	}
+
+	result := &openapi.RoomResult{
+		Code:         room.Code,
+		Host:         room.HostID,
+		Deadline:     room.Deadline,
+		Participants: make([]openapi.RoomParticipant, len(room.Participants)),
+	}
+	for i, p := range room.Participants {
+		participant := openapi.RoomParticipant{
+			PlayerId:  p.PlayerID,
+			Submitted: p.SubmittedAt != nil,
+		}
+		if p.Score != nil {
+			participant.Score = openapi.NewOptInt(*p.Score)
+		}
+		if p.SubmittedAt != nil {
+			participant.SubmittedAt = openapi.NewOptDateTime(*p.SubmittedAt)
+		}
+		result.Participants[i] = participant
+	}
+
+	return result, nil
+}
+
+func roomResponse(room *models.RoomGame) *openapi.Room {
+	result := &openapi.Room{
+		Code:     room.Code,
+		Host:     room.HostID,
+		Deadline: room.Deadline,
+		ID:       openapi.UUID(room.Game.ID.String()),
+		Authors:  room.Game.Authors,
+		Quotes:   make([]openapi.QuoteWithoutAuthor, len(room.Game.Quotes)),
+	}
+	for i, q := range room.Game.Quotes {
+		result.Quotes[i] = openapi.QuoteWithoutAuthor{
+			ID:    q.ID,
+			Quote: q.Quote,
+		}
+	}
+	return result
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/openapi"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	roomDeadline = time.Date(2025, 2, 1, 12, 5, 0, 0, time.UTC)
+	roomGame     = &models.RoomGame{
+		Room: models.Room{
+			Code:     "ABC234",
+			HostID:   "host",
+			GameID:   uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+			QuoteIDs: []int{1, 2, 3},
+			Deadline: roomDeadline,
+		},
+		Game: &models.QuoteGame{
+			ID:      uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+			Quotes:  []*models.QuoteWithoutAuthor{{ID: 1, Quote: "a"}, {ID: 2, Quote: "b"}, {ID: 3, Quote: "c"}},
+			Authors: []string{"Bob", "Jan", "Max"},
+		},
+	}
+	expectedRoom = &openapi.Room{
+		Code:     "ABC234",
+		Host:     "host",
+		Deadline: roomDeadline,
+		ID:       "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+		Quotes:   []openapi.QuoteWithoutAuthor{{ID: 1, Quote: "a"}, {ID: 2, Quote: "b"}, {ID: 3, Quote: "c"}},
+		Authors:  []string{"Bob", "Jan", "Max"},
+	}
+)
+
+func TestApplication_CreateRoom(t *testing.T) {
+	type Test struct {
+		ctx                context.Context
+		params             openapi.CreateRoomParams
+		expectedHostID     string
+		mockedServiceRoom  *models.RoomGame
+		mockedServiceError error
+		expectedResult     openapi.CreateRoomRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedRoomService := new(MockedRoomService)
+			mockedRoomService.On("CreateRoom", tt.expectedHostID).Once().Return(tt.mockedServiceRoom, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:      &logger,
+				roomService: mockedRoomService,
+			}
+
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.TODO()
+			}
+			res, err := app.CreateRoom(ctx, tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+			mockedRoomService.AssertExpectations(t)
+		}
+	}
+
+	t.Run("creates a room for the player", run(Test{
+		params:            openapi.CreateRoomParams{XPlayerID: openapi.NewOptString("host")},
+		expectedHostID:    "host",
+		mockedServiceRoom: roomGame,
+		expectedResult:    expectedRoom,
+	}))
+
+	t.Run("uses the authenticated caller as host", run(Test{
+		ctx:               models.ContextWithCaller(context.TODO(), &models.Caller{ID: "host", Role: models.RolePlayer}),
+		params:            openapi.CreateRoomParams{XPlayerID: openapi.NewOptString("someone-else")},
+		expectedHostID:    "host",
+		mockedServiceRoom: roomGame,
+		expectedResult:    expectedRoom,
+	}))
+
+	t.Run("returns a 422 without a player", run(Test{
+		mockedServiceError: models.ErrPlayerIDRequired,
+		expectedResult:     &openapi.R422{Message: "player_id_required"},
+	}))
+
+	t.Run("returns a 503 when dummyjson is too busy", run(Test{
+		mockedServiceError: models.ErrUpstreamBusy,
+		expectedResult:     &openapi.R503{Message: "upstream_busy"},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		mockedServiceError: errors.New("something went wrong"),
+		expectedResult:     &openapi.R500{Message: "unknown_error"},
+	}))
+}
+
+func TestApplication_JoinRoom(t *testing.T) {
+	type Test struct {
+		mockedServiceRoom  *models.RoomGame
+		mockedServiceError error
+		expectedResult     openapi.JoinRoomRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedRoomService := new(MockedRoomService)
+			mockedRoomService.On("JoinRoom", "abc234", "player-1").Once().Return(tt.mockedServiceRoom, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:      &logger,
+				roomService: mockedRoomService,
+			}
+
+			res, err := app.JoinRoom(context.TODO(), openapi.JoinRoomParams{
+				Code:      "abc234",
+				XPlayerID: openapi.NewOptString("player-1"),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("returns the game of the room", run(Test{
+		mockedServiceRoom: roomGame,
+		expectedResult:    expectedRoom,
+	}))
+
+	t.Run("returns a 404 for an unknown or closed room", run(Test{
+		mockedServiceError: models.ErrRoomNotFound,
+		expectedResult:     &openapi.R404{Message: "not_found"},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		mockedServiceError: errors.New("something went wrong"),
+		expectedResult:     &openapi.R500{Message: "unknown_error"},
+	}))
+}
+
+func TestApplication_SubmitAnswerForRoom(t *testing.T) {
+	type Test struct {
+		mockedServiceResult *models.QuoteGameResult
+		mockedServiceError  error
+		expectedResult      openapi.SubmitAnswerForRoomRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			answers := models.QuoteGameAnswerMap{1: "Jan", 2: "Bob", 3: "Max"}
+			mockedRoomService := new(MockedRoomService)
+			mockedRoomService.On("SubmitRoomAnswer", "ABC234", "player-1", answers).Once().Return(tt.mockedServiceResult, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:      &logger,
+				roomService: mockedRoomService,
+			}
+
+			res, err := app.SubmitAnswerForRoom(context.TODO(), []openapi.QuoteGameAnswer{
+				{ID: 1, Author: "Jan"},
+				{ID: 2, Author: "Bob"},
+				{ID: 3, Author: "Max"},
+			}, openapi.SubmitAnswerForRoomParams{
+				Code:      "ABC234",
+				XPlayerID: openapi.NewOptString("player-1"),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("returns the result of the participant", run(Test{
+		mockedServiceResult: &models.QuoteGameResult{
+			ID: roomGame.GameID,
+			Answers: []*models.QuoteGameActualAnswer{
+				{Quote: models.Quote{ID: 1, Quote: "a", Author: "Jan"}, Correct: true},
+				{Quote: models.Quote{ID: 2, Quote: "b", Author: "Max"}, Correct: false},
+				{Quote: models.Quote{ID: 3, Quote: "c", Author: "Bob"}, Correct: false},
+			},
+		},
+		expectedResult: &openapi.QuoteGameResult{
+			ID:    "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+			Score: 1,
+			Answers: []openapi.QuoteGameResultAnswersItem{
+				{ID: 1, Correct: true, ActualAuthor: "Jan"},
+				{ID: 2, Correct: false, ActualAuthor: "Max"},
+				{ID: 3, Correct: false, ActualAuthor: "Bob"},
+			},
+		},
+	}))
+
+	t.Run("returns a 404 for an unknown or closed room", run(Test{
+		mockedServiceError: models.ErrRoomNotFound,
+		expectedResult:     &openapi.R404{Message: "not_found"},
+	}))
+
+	t.Run("returns a 422 for a second answer", run(Test{
+		mockedServiceError: models.ErrRoomAlreadyAnswered,
+		expectedResult:     &openapi.R422{Message: "room_already_answered"},
+	}))
+
+	t.Run("returns a 422 when the player didn't join", run(Test{
+		mockedServiceError: models.ErrRoomNotJoined,
+		expectedResult:     &openapi.R422{Message: "room_not_joined"},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		mockedServiceError: errors.New("something went wrong"),
+		expectedResult:     &openapi.R500{Message: "unknown_error"},
+	}))
+}
+
+func TestApplication_GetRoomResult(t *testing.T) {
+	score := 2
+	submittedAt := roomDeadline.Add(-time.Minute)
+
+	mockedRoomService := new(MockedRoomService)
+	mockedRoomService.On("GetRoomResult", "ABC234").Return(&models.RoomResult{
+		Room: roomGame.Room,
+		Participants: []*models.RoomParticipant{
+			{PlayerID: "host", Score: &score, SubmittedAt: &submittedAt},
+			{PlayerID: "player-1"},
+		},
+	}, nil)
+	mockedRoomService.On("GetRoomResult", "ZZZ999").Return((*models.RoomResult)(nil), models.ErrRoomNotFound)
+
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	app := application{
+		logger:      &logger,
+		roomService: mockedRoomService,
+	}
+
+	res, err := app.GetRoomResult(context.TODO(), openapi.GetRoomResultParams{Code: "ABC234"})
+	require.NoError(t, err)
+	assert.Equal(t, &openapi.RoomResult{
+		Code:     "ABC234",
+		Host:     "host",
+		Deadline: roomDeadline,
+		Participants: []openapi.RoomParticipant{
+			{PlayerId: "host", Submitted: true, Score: openapi.NewOptInt(2), SubmittedAt: openapi.NewOptDateTime(submittedAt)},
+			{PlayerId: "player-1", Submitted: false},
+		},
+	}, res)
+
+	res, err = app.GetRoomResult(context.TODO(), openapi.GetRoomResultParams{Code: "ZZZ999"})
+	require.NoError(t, err)
+	assert.Equal(t, &openapi.R404{Message: "not_found"}, res)
+}
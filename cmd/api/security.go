@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ogen-go/ogen/ogenerrors"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/openapi"
+)
+
+// errForbidden is returned when a caller is authenticated, but doesn't have the role needed for the operation
+var errForbidden = errors.New("caller does not have the required role")
+
+// adminOperations are the operations that can only be used by callers with the admin role
+var adminOperations = map[openapi.OperationName]bool{
+	openapi.CreateQuoteOperation:  true,
+	openapi.UpdateQuoteOperation:  true,
+	openapi.DeleteQuoteOperation:  true,
+	openapi.CreateApiKeyOperation: true,
+	openapi.RevokeApiKeyOperation: true,
+	openapi.CreateBackupOperation: true,
+	openapi.ListBackupsOperation:  true,
+	openapi.ExportGamesOperation:  true,
+}
+
+// securityHandler authenticates requests with an API key or JWT. Authentication is optional for most operations,
+// but when credentials are given they have to be valid. The caller is injected into the context for the handlers.
+type securityHandler struct {
+	authService authService
+}
+
+// HandleApiKey authenticates the caller using the X-Api-Key header
+func (sec *securityHandler) HandleApiKey(ctx context.Context, operationName openapi.OperationName, t openapi.ApiKey) (context.Context, error) {
+	caller, err := sec.authService.AuthenticateApiKey(ctx, t.APIKey)
+	if err != nil {
+		return ctx, err
+	}
+	return authorize(ctx, operationName, caller)
+}
+
+// HandleBearerAuth authenticates the caller using a JWT in the Authorization header
+func (sec *securityHandler) HandleBearerAuth(ctx context.Context, operationName openapi.OperationName, t openapi.BearerAuth) (context.Context, error) {
+	caller, err := sec.authService.AuthenticateJWT(ctx, t.Token)
+	if err != nil {
+		return ctx, err
+	}
+	return authorize(ctx, operationName, caller)
+}
+
+// authorize checks if the caller has the role needed for the operation and returns a context containing the caller
+func authorize(ctx context.Context, operationName openapi.OperationName, caller *models.Caller) (context.Context, error) {
+	if adminOperations[operationName] && caller.Role != models.RoleAdmin {
+		return ctx, errForbidden
+	}
+	return models.ContextWithCaller(ctx, caller), nil
+}
+
+// authenticateRequest returns the caller of a request that didn't reach the security handler yet, for middleware that needs to know
+// who is calling. Invalid credentials are ignored here, ogen rejects them once the request reaches the security handler.
+func authenticateRequest(r *http.Request, authService authService) (*models.Caller, bool) {
+	if key := r.Header.Get("X-Api-Key"); key != "" {
+		if caller, err := authService.AuthenticateApiKey(r.Context(), key); err == nil {
+			return caller, true
+		}
+	}
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		if caller, err := authService.AuthenticateJWT(r.Context(), token); err == nil {
+			return caller, true
+		}
+	}
+	return nil, false
+}
+
+// hasCredentials reports if a request has credentials that authenticateRequest would check
+func hasCredentials(r *http.Request) bool {
+	if r.Header.Get("X-Api-Key") != "" {
+		return true
+	}
+	scheme, _, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	return ok && strings.EqualFold(scheme, "Bearer")
+}
+
+// handleError writes the errors that occur before a request reaches a handler. Security errors are written in the same format
+// as the other error responses of the api, all other errors are handled by ogen.
+func (app *application) handleError(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) {
+	var securityError *ogenerrors.SecurityError
+	if !errors.As(err, &securityError) {
+		ogenerrors.DefaultErrorHandler(ctx, w, r, err)
+		return
+	}
+
+	switch {
+	case errors.Is(err, errForbidden):
+		app.logger.Debug().Err(err).Str("path", r.URL.Path).Msg("request with insufficient role")
+		writeJSON(w, http.StatusForbidden, &openapi.R403{Message: "forbidden"})
+	case errors.Is(err, models.ErrInvalidCredentials), errors.Is(err, ogenerrors.ErrSecurityRequirementIsNotSatisfied):
+		app.logger.Debug().Err(err).Str("path", r.URL.Path).Msg("request with invalid credentials")
+		writeJSON(w, http.StatusUnauthorized, &openapi.R401{Message: "unauthorized"})
+	default:
+		app.logger.Error().Err(err).Str("path", r.URL.Path).Msg("unexpected error when authenticating request")
+		writeJSON(w, http.StatusInternalServerError, &openapi.R500{Message: "unknown_error"})
+	}
+}
+
+// writeJSON writes the body with the given status code. The body is encoded by its own MarshalJSON, so the ogen types are encoded
+// exactly like ogen does, without their unset optional fields
+func writeJSON(w http.ResponseWriter, statusCode int, body json.Marshaler) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/openapi"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityHandler(t *testing.T) {
+	type Test struct {
+		method             string
+		path               string
+		headers            map[string]string
+		expectedStatusCode int
+		expectedBody       string
+		expectedPlayerID   string
+	}
+
+	player := &models.Caller{ID: "player-42", Role: models.RolePlayer}
+	admin := &models.Caller{ID: "admin", Role: models.RoleAdmin}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			// We run the request through the whole server, so the security handler and the error handler are used like in production
+			mockedAuthService := new(MockedAuthService)
+			mockedAuthService.On("AuthenticateApiKey", "player-key").Return(player, nil)
+			mockedAuthService.On("AuthenticateApiKey", "admin-key").Return(admin, nil)
+			mockedAuthService.On("AuthenticateApiKey", "broken-key").Return((*models.Caller)(nil), errors.New("database is gone"))
+			mockedAuthService.On("AuthenticateApiKey", mock.Anything).Return((*models.Caller)(nil), models.ErrInvalidCredentials)
+			mockedAuthService.On("AuthenticateJWT", "admin-jwt").Return(admin, nil)
+			mockedAuthService.On("AuthenticateJWT", mock.Anything).Return((*models.Caller)(nil), models.ErrInvalidCredentials)
+
+			mockedCatalogueService := new(MockedCatalogueService)
+			mockedCatalogueService.On("DeleteQuote", 70).Return(nil)
+
+			mockedQuoteService := new(MockedQuoteService)
+			mockedQuoteService.On("CreateQuoteGame", tt.expectedPlayerID, models.GameModeMatch, models.LanguageEnglish).Return(&models.QuoteGame{ID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e")}, nil)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := &application{
+				logger:           &logger,
+				quoteService:     mockedQuoteService,
+				catalogueService: mockedCatalogueService,
+				authService:      mockedAuthService,
+			}
+			srv, err := openapi.NewServer(app, &securityHandler{authService: mockedAuthService}, openapi.WithErrorHandler(app.handleError))
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rec.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, strings.TrimSpace(rec.Body.String()))
+			}
+		}
+	}
+
+	t.Run("allows admin operations with an admin api key", run(Test{
+		method:             http.MethodDelete,
+		path:               "/admin/quotes/70",
+		headers:            map[string]string{"X-Api-Key": "admin-key"},
+		expectedStatusCode: http.StatusNoContent,
+	}))
+
+	t.Run("allows admin operations with an admin jwt", run(Test{
+		method:             http.MethodDelete,
+		path:               "/admin/quotes/70",
+		headers:            map[string]string{"Authorization": "Bearer admin-jwt"},
+		expectedStatusCode: http.StatusNoContent,
+	}))
+
+	t.Run("rejects admin operations without credentials", run(Test{
+		method:             http.MethodDelete,
+		path:               "/admin/quotes/70",
+		expectedStatusCode: http.StatusUnauthorized,
+		expectedBody:       `{"message":"unauthorized"}`,
+	}))
+
+	t.Run("rejects admin operations for players", run(Test{
+		method:             http.MethodDelete,
+		path:               "/admin/quotes/70",
+		headers:            map[string]string{"X-Api-Key": "player-key"},
+		expectedStatusCode: http.StatusForbidden,
+		expectedBody:       `{"message":"forbidden"}`,
+	}))
+
+	t.Run("rejects invalid jwts", run(Test{
+		method:             http.MethodDelete,
+		path:               "/admin/quotes/70",
+		headers:            map[string]string{"Authorization": "Bearer expired-jwt"},
+		expectedStatusCode: http.StatusUnauthorized,
+		expectedBody:       `{"message":"unauthorized"}`,
+	}))
+
+	t.Run("returns a server error when authentication fails unexpectedly", run(Test{
+		method:             http.MethodDelete,
+		path:               "/admin/quotes/70",
+		headers:            map[string]string{"X-Api-Key": "broken-key"},
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedBody:       `{"message":"unknown_error"}`,
+	}))
+
+	t.Run("allows anonymous players", run(Test{
+		method:             http.MethodPost,
+		path:               "/quote-game",
+		headers:            map[string]string{"X-Player-Id": "anonymous-7"},
+		expectedStatusCode: http.StatusOK,
+		expectedPlayerID:   "anonymous-7",
+	}))
+
+	t.Run("attributes games to the authenticated caller", run(Test{
+		method:             http.MethodPost,
+		path:               "/quote-game",
+		headers:            map[string]string{"X-Api-Key": "player-key", "X-Player-Id": "someone-else"},
+		expectedStatusCode: http.StatusOK,
+		expectedPlayerID:   "player-42",
+	}))
+
+	t.Run("rejects invalid credentials, also when they are optional", run(Test{
+		method:             http.MethodPost,
+		path:               "/quote-game",
+		headers:            map[string]string{"X-Api-Key": "unknown-key"},
+		expectedStatusCode: http.StatusUnauthorized,
+		expectedBody:       `{"message":"unauthorized"}`,
+	}))
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/openapi"
+)
+
+// ListQuoteStats returns a page of the answered quotes with their accuracy, the hardest quotes first
+func (app *application) ListQuoteStats(ctx context.Context, params openapi.ListQuoteStatsParams) (openapi.ListQuoteStatsRes, error) {
+	filter := models.QuoteStatsFilter{
+		MinAttempts: params.MinAttempts.Or(1),
+		Limit:       params.Limit.Or(defaultPageLimit),
+		Offset:      params.Offset.Or(0),
+	}
+	if mode, ok := params.Mode.Get(); ok {
+		filter.Mode = models.GameMode(mode)
+	}
+	page, err := app.statsService.ListQuoteStats(ctx, filter)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling statsService.ListQuoteStats")
+		return app.internalServerError()
+	}
+
+	result := &openapi.QuoteStatsPage{
+		Items:  make([]openapi.QuoteStats, len(page.Items)),
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	for i, stats := range page.Items {
+		result.Items[i] = openapi.QuoteStats{
+			QuoteId:  stats.QuoteID,
+			Attempts: stats.Attempts,
+			Correct:  stats.Correct,
+			Accuracy: stats.Accuracy(),
+		}
+		if stats.Quote != "" {
+			result.Items[i].Quote = openapi.NewOptString(stats.Quote)
+			result.Items[i].Author = openapi.NewOptString(stats.Author)
+		}
+	}
+
+	return result, nil
+}
+
+// ListAuthorConfusions returns a page of the authors that players guessed for the quotes of another author, the most frequent first
+func (app *application) ListAuthorConfusions(ctx context.Context, params openapi.ListAuthorConfusionsParams) (openapi.ListAuthorConfusionsRes, error) {
+	page, err := app.statsService.ListAuthorConfusions(ctx, models.AuthorConfusionFilter{
+		Author: params.Author.Or(""),
+		Limit:  params.Limit.Or(defaultPageLimit),
+		Offset: params.Offset.Or(0),
+	})
+	if err != nil {
+		app.logger.Error().Err(err).Msg("unexpected error when calling statsService.ListAuthorConfusions")
+		return app.internalServerError()
+	}
+
+	result := &openapi.AuthorConfusionPage{
+		Items:  make([]openapi.AuthorConfusion, len(page.Items)),
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	for i, confusion := range page.Items {
+		result.Items[i] = openapi.AuthorConfusion{
+			Author:        confusion.Author,
+			GuessedAuthor: confusion.GuessedAuthor,
+			Count:         confusion.Count,
+		}
+	}
+
+	return result, nil
+}
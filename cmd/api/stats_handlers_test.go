@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/openapi"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplication_ListQuoteStats(t *testing.T) {
+	type Test struct {
+		params              openapi.ListQuoteStatsParams
+		expectedFilter      models.QuoteStatsFilter
+		mockedServiceResult *models.Page[*models.QuoteStats]
+		mockedServiceError  error
+		expectedResult      openapi.ListQuoteStatsRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedStatsService := new(MockedStatsService)
+			mockedStatsService.On("ListQuoteStats", tt.expectedFilter).Once().Return(tt.mockedServiceResult, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:       &logger,
+				statsService: mockedStatsService,
+			}
+
+			res, err := app.ListQuoteStats(context.TODO(), tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("returns a page of quote stats", run(Test{
+		params: openapi.ListQuoteStatsParams{
+			Mode:        openapi.NewOptGameMode(openapi.GameModeMultipleChoice),
+			MinAttempts: openapi.NewOptInt(3),
+			Limit:       openapi.NewOptInt(2),
+			Offset:      openapi.NewOptInt(4),
+		},
+		expectedFilter: models.QuoteStatsFilter{Mode: models.GameModeMultipleChoice, MinAttempts: 3, Limit: 2, Offset: 4},
+		mockedServiceResult: &models.Page[*models.QuoteStats]{
+			Items: []*models.QuoteStats{
+				{QuoteID: 414, Quote: "A quote", Author: "C. S. Lewis", Attempts: 4, Correct: 1},
+				{QuoteID: 999, Attempts: 3, Correct: 3},
+			},
+			Total:  10,
+			Limit:  2,
+			Offset: 4,
+		},
+		expectedResult: &openapi.QuoteStatsPage{
+			Items: []openapi.QuoteStats{
+				{QuoteId: 414, Quote: openapi.NewOptString("A quote"), Author: openapi.NewOptString("C. S. Lewis"), Attempts: 4, Correct: 1, Accuracy: 0.25},
+				{QuoteId: 999, Attempts: 3, Correct: 3, Accuracy: 1},
+			},
+			Total:  10,
+			Limit:  2,
+			Offset: 4,
+		},
+	}))
+
+	t.Run("counts all modes by default", run(Test{
+		expectedFilter:      models.QuoteStatsFilter{MinAttempts: 1, Limit: 20},
+		mockedServiceResult: &models.Page[*models.QuoteStats]{Items: []*models.QuoteStats{}, Limit: 20},
+		expectedResult:      &openapi.QuoteStatsPage{Items: []openapi.QuoteStats{}, Limit: 20},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		expectedFilter:     models.QuoteStatsFilter{MinAttempts: 1, Limit: 20},
+		mockedServiceError: errors.New("something went wrong"),
+		expectedResult: &openapi.R500{
+			Message: "unknown_error",
+		},
+	}))
+}
+
+func TestApplication_ListAuthorConfusions(t *testing.T) {
+	type Test struct {
+		params              openapi.ListAuthorConfusionsParams
+		expectedFilter      models.AuthorConfusionFilter
+		mockedServiceResult *models.Page[*models.AuthorConfusion]
+		mockedServiceError  error
+		expectedResult      openapi.ListAuthorConfusionsRes
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedStatsService := new(MockedStatsService)
+			mockedStatsService.On("ListAuthorConfusions", tt.expectedFilter).Once().Return(tt.mockedServiceResult, tt.mockedServiceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			app := application{
+				logger:       &logger,
+				statsService: mockedStatsService,
+			}
+
+			res, err := app.ListAuthorConfusions(context.TODO(), tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("returns a page of confusions", run(Test{
+		params:         openapi.ListAuthorConfusionsParams{Author: openapi.NewOptString("C. S. Lewis"), Limit: openapi.NewOptInt(5)},
+		expectedFilter: models.AuthorConfusionFilter{Author: "C. S. Lewis", Limit: 5},
+		mockedServiceResult: &models.Page[*models.AuthorConfusion]{
+			Items: []*models.AuthorConfusion{{Author: "C. S. Lewis", GuessedAuthor: "Rumi", Count: 2}},
+			Total: 1,
+			Limit: 5,
+		},
+		expectedResult: &openapi.AuthorConfusionPage{
+			Items: []openapi.AuthorConfusion{{Author: "C. S. Lewis", GuessedAuthor: "Rumi", Count: 2}},
+			Total: 1,
+			Limit: 5,
+		},
+	}))
+
+	t.Run("returns a server error when something went wrong", run(Test{
+		expectedFilter:     models.AuthorConfusionFilter{Limit: 20},
+		mockedServiceError: errors.New("something went wrong"),
+		expectedResult: &openapi.R500{
+			Message: "unknown_error",
+		},
+	}))
+}
@@ -2,13 +2,69 @@ package main
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pietdevries94/Kabisa/models"
 )
 
 type quoteService interface {
-	GetRandomQuote(ctx context.Context) (*models.Quote, error)
-	CreateQuoteGame(ctx context.Context) (*models.QuoteGame, error)
+	GetRandomQuote(ctx context.Context, language models.Language) (*models.Quote, error)
+	CreateQuoteGame(ctx context.Context, playerID string, mode models.GameMode, language models.Language) (*models.QuoteGame, error)
 	SubmitAnswerToQuoteGame(ctx context.Context, id uuid.UUID, answers models.QuoteGameAnswerMap) (*models.QuoteGameResult, error)
+	SubmitBlanksToQuoteGame(ctx context.Context, id uuid.UUID, answers models.QuoteGameAnswerMap) (*models.QuoteGameResult, error)
+	RequestHint(ctx context.Context, id uuid.UUID, hintType models.HintType, quoteID int) (*models.Hint, error)
+	GetQuoteGameStatus(ctx context.Context, id uuid.UUID) (*models.QuoteGameStatus, error)
+	CreateDailyQuoteGame(ctx context.Context, playerID string) (*models.DailyQuoteGame, error)
+	GetDailyLeaderboard(ctx context.Context, day time.Time, limit, offset int) (*models.Page[*models.DailyLeaderboardEntry], error)
+}
+
+type catalogueService interface {
+	SyncCatalogue(ctx context.Context) error
+	ListQuotes(ctx context.Context, filter models.QuoteFilter) (*models.Page[*models.Quote], error)
+	GetQuote(ctx context.Context, id int) (*models.Quote, error)
+	ListAuthors(ctx context.Context, limit, offset int) (*models.Page[*models.AuthorQuoteCount], error)
+	GetDailyQuote(ctx context.Context, day time.Time) (*models.DailyQuote, error)
+	CreateQuote(ctx context.Context, edit models.QuoteEdit) (*models.CuratedQuote, error)
+	UpdateQuote(ctx context.Context, id int, edit models.QuoteEdit) (*models.CuratedQuote, error)
+	DeleteQuote(ctx context.Context, id int) error
+}
+
+type authService interface {
+	AuthenticateApiKey(ctx context.Context, key string) (*models.Caller, error)
+	AuthenticateJWT(ctx context.Context, token string) (*models.Caller, error)
+	CreateApiKey(ctx context.Context, subject string, role models.Role) (*models.ApiKey, string, error)
+	RevokeApiKey(ctx context.Context, id uuid.UUID) error
+}
+
+type roomService interface {
+	CreateRoom(ctx context.Context, hostID string) (*models.RoomGame, error)
+	JoinRoom(ctx context.Context, code string, playerID string) (*models.RoomGame, error)
+	SubmitRoomAnswer(ctx context.Context, code string, playerID string, answers models.QuoteGameAnswerMap) (*models.QuoteGameResult, error)
+	GetRoomResult(ctx context.Context, code string) (*models.RoomResult, error)
+}
+
+type idempotencyService interface {
+	Begin(ctx context.Context, scope, key, fingerprint string) (*models.IdempotentResponse, error)
+	Complete(ctx context.Context, scope, key string, response *models.IdempotentResponse) error
+	Release(ctx context.Context, scope, key string) error
+}
+
+type backupService interface {
+	Backup(ctx context.Context) (*models.Backup, error)
+	ListBackups(ctx context.Context) ([]*models.Backup, error)
+}
+
+type exportService interface {
+	ExportGames(ctx context.Context, w io.Writer, format models.ExportFormat, from, to time.Time) error
+}
+
+type statsService interface {
+	ListQuoteStats(ctx context.Context, filter models.QuoteStatsFilter) (*models.Page[*models.QuoteStats], error)
+	ListAuthorConfusions(ctx context.Context, filter models.AuthorConfusionFilter) (*models.Page[*models.AuthorConfusion], error)
+}
+
+type eventBroker interface {
+	Subscribe(topic string) (events <-chan models.Event, unsubscribe func())
 }
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pietdevries94/Kabisa/models"
@@ -13,14 +15,14 @@ type MockedQuoteService struct {
 }
 
 // GetRandomQuote is fully mocked here
-func (m *MockedQuoteService) GetRandomQuote(_ context.Context) (*models.Quote, error) {
-	args := m.Called()
+func (m *MockedQuoteService) GetRandomQuote(_ context.Context, language models.Language) (*models.Quote, error) {
+	args := m.Called(language)
 	return args.Get(0).(*models.Quote), args.Error(1)
 }
 
 // CreateQuoteGame is fully mocked here
-func (m *MockedQuoteService) CreateQuoteGame(_ context.Context) (*models.QuoteGame, error) {
-	args := m.Called()
+func (m *MockedQuoteService) CreateQuoteGame(_ context.Context, playerID string, mode models.GameMode, language models.Language) (*models.QuoteGame, error) {
+	args := m.Called(playerID, mode, language)
 	return args.Get(0).(*models.QuoteGame), args.Error(1)
 }
 
@@ -29,3 +31,201 @@ func (m *MockedQuoteService) SubmitAnswerToQuoteGame(_ context.Context, id uuid.
 	args := m.Called(id, answers)
 	return args.Get(0).(*models.QuoteGameResult), args.Error(1)
 }
+
+// SubmitBlanksToQuoteGame is fully mocked here
+func (m *MockedQuoteService) SubmitBlanksToQuoteGame(_ context.Context, id uuid.UUID, answers models.QuoteGameAnswerMap) (*models.QuoteGameResult, error) {
+	args := m.Called(id, answers)
+	return args.Get(0).(*models.QuoteGameResult), args.Error(1)
+}
+
+// RequestHint is fully mocked here
+func (m *MockedQuoteService) RequestHint(_ context.Context, id uuid.UUID, hintType models.HintType, quoteID int) (*models.Hint, error) {
+	args := m.Called(id, hintType, quoteID)
+	return args.Get(0).(*models.Hint), args.Error(1)
+}
+
+// GetQuoteGameStatus is fully mocked here
+func (m *MockedQuoteService) GetQuoteGameStatus(_ context.Context, id uuid.UUID) (*models.QuoteGameStatus, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.QuoteGameStatus), args.Error(1)
+}
+
+// CreateDailyQuoteGame is fully mocked here
+func (m *MockedQuoteService) CreateDailyQuoteGame(_ context.Context, playerID string) (*models.DailyQuoteGame, error) {
+	args := m.Called(playerID)
+	return args.Get(0).(*models.DailyQuoteGame), args.Error(1)
+}
+
+// GetDailyLeaderboard is fully mocked here
+func (m *MockedQuoteService) GetDailyLeaderboard(_ context.Context, day time.Time, limit, offset int) (*models.Page[*models.DailyLeaderboardEntry], error) {
+	args := m.Called(day, limit, offset)
+	return args.Get(0).(*models.Page[*models.DailyLeaderboardEntry]), args.Error(1)
+}
+
+type MockedCatalogueService struct {
+	mock.Mock
+}
+
+// SyncCatalogue is fully mocked here
+func (m *MockedCatalogueService) SyncCatalogue(_ context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// ListQuotes is fully mocked here
+func (m *MockedCatalogueService) ListQuotes(_ context.Context, filter models.QuoteFilter) (*models.Page[*models.Quote], error) {
+	args := m.Called(filter)
+	return args.Get(0).(*models.Page[*models.Quote]), args.Error(1)
+}
+
+// GetQuote is fully mocked here
+func (m *MockedCatalogueService) GetQuote(_ context.Context, id int) (*models.Quote, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Quote), args.Error(1)
+}
+
+// ListAuthors is fully mocked here
+func (m *MockedCatalogueService) ListAuthors(_ context.Context, limit, offset int) (*models.Page[*models.AuthorQuoteCount], error) {
+	args := m.Called(limit, offset)
+	return args.Get(0).(*models.Page[*models.AuthorQuoteCount]), args.Error(1)
+}
+
+// GetDailyQuote is fully mocked here
+func (m *MockedCatalogueService) GetDailyQuote(_ context.Context, day time.Time) (*models.DailyQuote, error) {
+	args := m.Called(day)
+	return args.Get(0).(*models.DailyQuote), args.Error(1)
+}
+
+func (m *MockedCatalogueService) CreateQuote(_ context.Context, edit models.QuoteEdit) (*models.CuratedQuote, error) {
+	args := m.Called(edit)
+	return args.Get(0).(*models.CuratedQuote), args.Error(1)
+}
+
+func (m *MockedCatalogueService) UpdateQuote(_ context.Context, id int, edit models.QuoteEdit) (*models.CuratedQuote, error) {
+	args := m.Called(id, edit)
+	return args.Get(0).(*models.CuratedQuote), args.Error(1)
+}
+
+func (m *MockedCatalogueService) DeleteQuote(_ context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type MockedAuthService struct {
+	mock.Mock
+}
+
+func (m *MockedAuthService) AuthenticateApiKey(_ context.Context, key string) (*models.Caller, error) {
+	args := m.Called(key)
+	return args.Get(0).(*models.Caller), args.Error(1)
+}
+
+func (m *MockedAuthService) AuthenticateJWT(_ context.Context, token string) (*models.Caller, error) {
+	args := m.Called(token)
+	return args.Get(0).(*models.Caller), args.Error(1)
+}
+
+func (m *MockedAuthService) CreateApiKey(_ context.Context, subject string, role models.Role) (*models.ApiKey, string, error) {
+	args := m.Called(subject, role)
+	return args.Get(0).(*models.ApiKey), args.String(1), args.Error(2)
+}
+
+func (m *MockedAuthService) RevokeApiKey(_ context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type MockedRoomService struct {
+	mock.Mock
+}
+
+// CreateRoom is fully mocked here
+func (m *MockedRoomService) CreateRoom(_ context.Context, hostID string) (*models.RoomGame, error) {
+	args := m.Called(hostID)
+	return args.Get(0).(*models.RoomGame), args.Error(1)
+}
+
+// JoinRoom is fully mocked here
+func (m *MockedRoomService) JoinRoom(_ context.Context, code string, playerID string) (*models.RoomGame, error) {
+	args := m.Called(code, playerID)
+	return args.Get(0).(*models.RoomGame), args.Error(1)
+}
+
+// SubmitRoomAnswer is fully mocked here
+func (m *MockedRoomService) SubmitRoomAnswer(_ context.Context, code string, playerID string, answers models.QuoteGameAnswerMap) (*models.QuoteGameResult, error) {
+	args := m.Called(code, playerID, answers)
+	return args.Get(0).(*models.QuoteGameResult), args.Error(1)
+}
+
+// GetRoomResult is fully mocked here
+func (m *MockedRoomService) GetRoomResult(_ context.Context, code string) (*models.RoomResult, error) {
+	args := m.Called(code)
+	return args.Get(0).(*models.RoomResult), args.Error(1)
+}
+
+type MockedBackupService struct {
+	mock.Mock
+}
+
+// Backup is fully mocked here
+func (m *MockedBackupService) Backup(_ context.Context) (*models.Backup, error) {
+	args := m.Called()
+	return args.Get(0).(*models.Backup), args.Error(1)
+}
+
+// ListBackups is fully mocked here
+func (m *MockedBackupService) ListBackups(_ context.Context) ([]*models.Backup, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.Backup), args.Error(1)
+}
+
+type MockedExportService struct {
+	mock.Mock
+}
+
+// ExportGames writes the output the mock returns to w
+func (m *MockedExportService) ExportGames(_ context.Context, w io.Writer, format models.ExportFormat, from, to time.Time) error {
+	args := m.Called(format, from, to)
+	if _, err := io.WriteString(w, args.String(0)); err != nil {
+		return err
+	}
+	return args.Error(1)
+}
+
+type MockedStatsService struct {
+	mock.Mock
+}
+
+// ListQuoteStats is fully mocked here
+func (m *MockedStatsService) ListQuoteStats(_ context.Context, filter models.QuoteStatsFilter) (*models.Page[*models.QuoteStats], error) {
+	args := m.Called(filter)
+	return args.Get(0).(*models.Page[*models.QuoteStats]), args.Error(1)
+}
+
+// ListAuthorConfusions is fully mocked here
+func (m *MockedStatsService) ListAuthorConfusions(_ context.Context, filter models.AuthorConfusionFilter) (*models.Page[*models.AuthorConfusion], error) {
+	args := m.Called(filter)
+	return args.Get(0).(*models.Page[*models.AuthorConfusion]), args.Error(1)
+}
+
+type MockedIdempotencyService struct {
+	mock.Mock
+}
+
+// Begin is fully mocked here
+func (m *MockedIdempotencyService) Begin(_ context.Context, scope, key, fingerprint string) (*models.IdempotentResponse, error) {
+	args := m.Called(scope, key, fingerprint)
+	return args.Get(0).(*models.IdempotentResponse), args.Error(1)
+}
+
+// Complete is fully mocked here
+func (m *MockedIdempotencyService) Complete(_ context.Context, scope, key string, response *models.IdempotentResponse) error {
+	args := m.Called(scope, key, response)
+	return args.Error(0)
+}
+
+// Release is fully mocked here
+func (m *MockedIdempotencyService) Release(_ context.Context, scope, key string) error {
+	args := m.Called(scope, key)
+	return args.Error(0)
+}
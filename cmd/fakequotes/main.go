@@ -0,0 +1,41 @@
+// fakequotes is a stub of the dummyjson quotes api. It serves a fixed dataset, so the api can run without network.
+// Point KABISAQUOTE_DUMMYJSON_BASE_URL of the api at the listen address of this server to use it.
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/pietdevries94/Kabisa/fakequotes"
+	"github.com/rs/zerolog"
+)
+
+func main() {
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).
+		With().Timestamp().
+		Logger()
+
+	// The listen address and dataset can be configured with environment variables, like the api itself
+	listenAddress := "127.0.0.1:3334"
+	if val, found := os.LookupEnv("KABISAQUOTE_FAKEQUOTES_LISTEN_ADDRESS"); found {
+		listenAddress = val
+	}
+
+	quotes := fakequotes.DefaultQuotes()
+	if path, found := os.LookupEnv("KABISAQUOTE_FAKEQUOTES_DATASET"); found && path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.Fatal().Err(err).Str("path", path).Msg("can't read set dataset")
+		}
+		quotes, err = fakequotes.ParseQuotes(data)
+		if err != nil {
+			logger.Fatal().Err(err).Str("path", path).Msg("could not parse set dataset")
+		}
+	}
+
+	logger.Info().Str("address", listenAddress).Int("quotes", len(quotes)).Msg("starting fake quotes server")
+	err := http.ListenAndServe(listenAddress, fakequotes.NewHandler(quotes))
+	if err != nil {
+		logger.Fatal().Err(err).Str("address", listenAddress).Msg("failed to start http server")
+	}
+}
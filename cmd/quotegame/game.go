@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/openapi"
+)
+
+const (
+	colorReset = "\033[0m"
+	colorBold  = "\033[1m"
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+)
+
+// game plays a single quote game against the api
+type game struct {
+	client   *openapi.Client
+	out      io.Writer
+	color    bool
+	timeout  time.Duration
+	playerID string
+}
+
+// create starts a new game
+func (g *game) create() (*openapi.CreateNewQuoteGameOK, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+
+	params := openapi.CreateNewQuoteGameParams{}
+	if g.playerID != "" {
+		params.XPlayerID = openapi.NewOptString(g.playerID)
+	}
+	res, err := g.client.CreateNewQuoteGame(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("could not create game: %w", err)
+	}
+
+	switch res := res.(type) {
+	case *openapi.CreateNewQuoteGameOK:
+		return res, nil
+	case *openapi.R429Headers:
+		return nil, fmt.Errorf("could not create game: too many games, try again in %d seconds", res.RetryAfter)
+	case *openapi.R500:
+		return nil, fmt.Errorf("could not create game: %s", res.Message)
+	case *openapi.R503:
+		return nil, fmt.Errorf("could not create game: %s", res.Message)
+	default:
+		return nil, fmt.Errorf("could not create game: unexpected response %T", res)
+	}
+}
+
+// submit submits the matching of the game and returns the result
+func (g *game) submit(id openapi.UUID, answers []openapi.QuoteGameAnswer) (*openapi.QuoteGameResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+
+	res, err := g.client.SubmitAnswerForQuoteGame(ctx, answers, openapi.SubmitAnswerForQuoteGameParams{ID: id})
+	if err != nil {
+		return nil, fmt.Errorf("could not submit answers: %w", err)
+	}
+
+	switch res := res.(type) {
+	case *openapi.QuoteGameResult:
+		return res, nil
+	case *openapi.R404:
+		return nil, errors.New("could not submit answers: the game expired or was already answered")
+	case *openapi.R422:
+		return nil, fmt.Errorf("could not submit answers: %s", res.Message)
+	case *openapi.R429Headers:
+		return nil, fmt.Errorf("could not submit answers: too many requests, try again in %d seconds", res.RetryAfter)
+	case *openapi.R500:
+		return nil, fmt.Errorf("could not submit answers: %s", res.Message)
+	case *openapi.R503:
+		return nil, fmt.Errorf("could not submit answers: %s", res.Message)
+	default:
+		return nil, fmt.Errorf("could not submit answers: unexpected response %T", res)
+	}
+}
+
+// show prints the quotes numbered and the authors lettered, so the player can match them
+func (g *game) show(created *openapi.CreateNewQuoteGameOK) {
+	fmt.Fprintln(g.out, g.paint(colorBold, "Who said what?"))
+	fmt.Fprintln(g.out)
+	for i, q := range created.Quotes {
+		fmt.Fprintf(g.out, "  %d. %q\n", i+1, q.Quote)
+	}
+	fmt.Fprintln(g.out)
+	for i, a := range created.Authors {
+		fmt.Fprintf(g.out, "  %c. %s\n", authorLetter(i), a)
+	}
+	fmt.Fprintln(g.out)
+}
+
+// ask asks the author of every quote. An invalid letter or an author that's already chosen is asked again
+func (g *game) ask(created *openapi.CreateNewQuoteGameOK, in *bufio.Reader) ([]openapi.QuoteGameAnswer, error) {
+	answers := make([]openapi.QuoteGameAnswer, len(created.Quotes))
+	chosen := map[int]bool{}
+	lastLetter := authorLetter(len(created.Authors) - 1)
+
+	for i, q := range created.Quotes {
+		for {
+			fmt.Fprintf(g.out, "Author of quote %d [A-%c]: ", i+1, lastLetter)
+			line, err := in.ReadString('\n')
+			if err != nil && (err != io.EOF || line == "") {
+				return nil, errors.New("no answer given")
+			}
+
+			author, ok := parseAuthorLetter(strings.TrimSpace(line), len(created.Authors))
+			if !ok {
+				fmt.Fprintf(g.out, "Please choose a letter from A to %c\n", lastLetter)
+				continue
+			}
+			if chosen[author] {
+				fmt.Fprintf(g.out, "%s is already chosen for another quote\n", created.Authors[author])
+				continue
+			}
+
+			chosen[author] = true
+			answers[i] = openapi.QuoteGameAnswer{ID: q.ID, Author: created.Authors[author]}
+			break
+		}
+	}
+	return answers, nil
+}
+
+// printResult prints for every quote whether the answer was correct, followed by the score
+func (g *game) printResult(created *openapi.CreateNewQuoteGameOK, result *openapi.QuoteGameResult) {
+	quotes := map[int]string{}
+	for _, q := range created.Quotes {
+		quotes[q.ID] = q.Quote
+	}
+
+	fmt.Fprintln(g.out)
+	correct := 0
+	for _, a := range result.Answers {
+		if a.Correct {
+			correct++
+			fmt.Fprintf(g.out, "%s %q was said by %s\n", g.paint(colorGreen, "✓"), quotes[a.ID], a.ActualAuthor)
+		} else {
+			fmt.Fprintf(g.out, "%s %q was said by %s\n", g.paint(colorRed, "✗"), quotes[a.ID], a.ActualAuthor)
+		}
+	}
+
+	scoreColor := colorRed
+	if correct == len(result.Answers) {
+		scoreColor = colorGreen
+	}
+	fmt.Fprintln(g.out)
+	fmt.Fprintln(g.out, g.paint(colorBold+scoreColor, fmt.Sprintf("You matched %d of %d quotes", correct, len(result.Answers))))
+}
+
+func (g *game) paint(color, text string) string {
+	if !g.color {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// parseAnswers parses the answers of the non-interactive mode. It's a JSON object of quote id to author, like
+//
+//	{"23": "Linus Torvalds", "18": "Albert Einstein"}
+//
+// It may contain more quotes than the game, so a script can answer every game with the same answers.
+func parseAnswers(data []byte) (map[int]string, error) {
+	var raw map[string]string
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("answers should be a JSON object of quote id to author: %w", err)
+	}
+
+	answers := make(map[int]string, len(raw))
+	for key, author := range raw {
+		id, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("answers should be a JSON object of quote id to author, %q is not a quote id", key)
+		}
+		answers[id] = author
+	}
+	return answers, nil
+}
+
+// matchAnswers picks the answers for the quotes of the game
+func matchAnswers(created *openapi.CreateNewQuoteGameOK, answers map[int]string) ([]openapi.QuoteGameAnswer, error) {
+	res := make([]openapi.QuoteGameAnswer, len(created.Quotes))
+	for i, q := range created.Quotes {
+		author, ok := answers[q.ID]
+		if !ok {
+			return nil, fmt.Errorf("no answer given for quote %d: %q", q.ID, q.Quote)
+		}
+		res[i] = openapi.QuoteGameAnswer{ID: q.ID, Author: author}
+	}
+	return res, nil
+}
+
+func authorLetter(i int) rune {
+	return rune('A' + i)
+}
+
+// parseAuthorLetter returns the index of the author of the letter, case insensitive
+func parseAuthorLetter(s string, authors int) (int, bool) {
+	if len(s) != 1 {
+		return 0, false
+	}
+	i := int(strings.ToUpper(s)[0] - 'A')
+	if i < 0 || i >= authors {
+		return 0, false
+	}
+	return i, true
+}
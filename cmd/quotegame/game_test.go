@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubApi is a minimal implementation of the api with a single fixed game
+type stubApi struct {
+	openapi.UnimplementedHandler
+	submitted []openapi.QuoteGameAnswer
+	playerID  string
+}
+
+var stubGame = &openapi.CreateNewQuoteGameOK{
+	ID:   "03f17f15-5d0a-49ea-aa05-039f2f18373e",
+	Mode: openapi.GameModeMatch,
+	Quotes: []openapi.QuoteWithoutAuthor{
+		{ID: 18, Quote: "Imagination is more important than knowledge."},
+		{ID: 22, Quote: "The only way to do great work is to love what you do."},
+		{ID: 23, Quote: "Talk is cheap. Show me the code."},
+	},
+	Authors: []string{"Albert Einstein", "Linus Torvalds", "Steve Jobs"},
+}
+
+var stubAuthors = map[int]string{18: "Albert Einstein", 22: "Steve Jobs", 23: "Linus Torvalds"}
+
+func (api *stubApi) CreateNewQuoteGame(_ context.Context, params openapi.CreateNewQuoteGameParams) (openapi.CreateNewQuoteGameRes, error) {
+	api.playerID = params.XPlayerID.Or("")
+	return stubGame, nil
+}
+
+func (api *stubApi) SubmitAnswerForQuoteGame(_ context.Context, answers []openapi.QuoteGameAnswer, _ openapi.SubmitAnswerForQuoteGameParams) (openapi.SubmitAnswerForQuoteGameRes, error) {
+	api.submitted = answers
+	result := &openapi.QuoteGameResult{ID: stubGame.ID}
+	for _, a := range answers {
+		result.Answers = append(result.Answers, openapi.QuoteGameResultAnswersItem{
+			ID:           a.ID,
+			Correct:      stubAuthors[a.ID] == a.Author,
+			ActualAuthor: stubAuthors[a.ID],
+		})
+	}
+	return result, nil
+}
+
+// allowAll accepts all credentials, the stub doesn't need authentication
+type allowAll struct{}
+
+func (allowAll) HandleApiKey(ctx context.Context, _ openapi.OperationName, _ openapi.ApiKey) (context.Context, error) {
+	return ctx, nil
+}
+
+func (allowAll) HandleBearerAuth(ctx context.Context, _ openapi.OperationName, _ openapi.BearerAuth) (context.Context, error) {
+	return ctx, nil
+}
+
+func TestRun(t *testing.T) {
+	type Test struct {
+		answers           string
+		input             string
+		expectedSubmitted []openapi.QuoteGameAnswer
+		expectedOutput    []string
+		expectedError     string
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			api := &stubApi{}
+			srv, err := openapi.NewServer(api, allowAll{})
+			require.NoError(t, err)
+			httpServer := httptest.NewServer(srv)
+			defer httpServer.Close()
+
+			out := &bytes.Buffer{}
+			opts := &options{
+				url:      httpServer.URL,
+				playerID: "player-42",
+				answers:  tt.answers,
+				noColor:  true,
+				timeout:  time.Second,
+			}
+			err = run(opts, strings.NewReader(tt.input), out)
+
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "player-42", api.playerID)
+			}
+			assert.Equal(t, tt.expectedSubmitted, api.submitted)
+			for _, expected := range tt.expectedOutput {
+				assert.Contains(t, out.String(), expected)
+			}
+		}
+	}
+
+	t.Run("plays interactively", run(Test{
+		input: "a\nC\nb\n",
+		expectedSubmitted: []openapi.QuoteGameAnswer{
+			{ID: 18, Author: "Albert Einstein"},
+			{ID: 22, Author: "Steve Jobs"},
+			{ID: 23, Author: "Linus Torvalds"},
+		},
+		expectedOutput: []string{
+			`1. "Imagination is more important than knowledge."`,
+			"C. Steve Jobs",
+			`✓ "Talk is cheap. Show me the code." was said by Linus Torvalds`,
+			"You matched 3 of 3 quotes",
+		},
+	}))
+
+	t.Run("asks again for invalid and already chosen letters", run(Test{
+		input: "D\nB\nb\nA\nC\n",
+		expectedSubmitted: []openapi.QuoteGameAnswer{
+			{ID: 18, Author: "Linus Torvalds"},
+			{ID: 22, Author: "Albert Einstein"},
+			{ID: 23, Author: "Steve Jobs"},
+		},
+		expectedOutput: []string{
+			"Please choose a letter from A to C",
+			"Linus Torvalds is already chosen for another quote",
+			`✗ "Imagination is more important than knowledge." was said by Albert Einstein`,
+			"You matched 0 of 3 quotes",
+		},
+	}))
+
+	t.Run("stops when the input ends", run(Test{
+		input:         "A\n",
+		expectedError: "no answer given",
+	}))
+
+	t.Run("plays with answers from json", run(Test{
+		answers: `{"18": "Albert Einstein", "22": "Linus Torvalds", "23": "Steve Jobs", "99": "Someone else"}`,
+		expectedSubmitted: []openapi.QuoteGameAnswer{
+			{ID: 18, Author: "Albert Einstein"},
+			{ID: 22, Author: "Linus Torvalds"},
+			{ID: 23, Author: "Steve Jobs"},
+		},
+		expectedOutput: []string{"You matched 1 of 3 quotes"},
+	}))
+
+	t.Run("reads the answers from stdin", run(Test{
+		answers: "-",
+		input:   `{"18": "Albert Einstein", "22": "Steve Jobs", "23": "Linus Torvalds"}`,
+		expectedSubmitted: []openapi.QuoteGameAnswer{
+			{ID: 18, Author: "Albert Einstein"},
+			{ID: 22, Author: "Steve Jobs"},
+			{ID: 23, Author: "Linus Torvalds"},
+		},
+		expectedOutput: []string{"You matched 3 of 3 quotes"},
+	}))
+
+	t.Run("fails when the answers miss a quote of the game", run(Test{
+		answers:       `{"18": "Albert Einstein"}`,
+		expectedError: "no answer given for quote 22",
+	}))
+
+	t.Run("fails on invalid answers json", run(Test{
+		answers:       `{"eighteen": "Albert Einstein"}`,
+		expectedError: `"eighteen" is not a quote id`,
+	}))
+}
+
+func TestGame_PrintResultInColor(t *testing.T) {
+	out := &bytes.Buffer{}
+	g := &game{out: out, color: true}
+	g.printResult(stubGame, &openapi.QuoteGameResult{
+		Answers: []openapi.QuoteGameResultAnswersItem{
+			{ID: 18, Correct: true, ActualAuthor: "Albert Einstein"},
+			{ID: 22, Correct: false, ActualAuthor: "Steve Jobs"},
+		},
+	})
+
+	assert.Contains(t, out.String(), colorGreen+"✓"+colorReset)
+	assert.Contains(t, out.String(), colorRed+"✗"+colorReset)
+	assert.Contains(t, out.String(), colorBold+colorRed+"You matched 1 of 2 quotes"+colorReset)
+}
@@ -0,0 +1,138 @@
+// quotegame plays the quote game of the api in the terminal.
+//
+// By default the game is played interactively: the quotes and authors are shown and the author of every quote is asked.
+// With --answers the matching is read from JSON instead, so the game can be scripted. See parseAnswers for the format.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ogen-go/ogen/ogenerrors"
+	"github.com/pietdevries94/Kabisa/openapi"
+)
+
+type options struct {
+	url      string
+	apiKey   string
+	token    string
+	playerID string
+	answers  string
+	noColor  bool
+	timeout  time.Duration
+}
+
+func main() {
+	opts := &options{}
+	flag.StringVar(&opts.url, "url", envOr("KABISAQUOTE_URL", "http://127.0.0.1:3333"), "the base url of the api")
+	flag.StringVar(&opts.apiKey, "api-key", os.Getenv("KABISAQUOTE_API_KEY"), "an API key to play as its subject")
+	flag.StringVar(&opts.token, "token", os.Getenv("KABISAQUOTE_TOKEN"), "a JWT to play as its subject")
+	flag.StringVar(&opts.playerID, "player-id", "", "the player id used to avoid quotes of recent games, when not authenticated")
+	flag.StringVar(&opts.answers, "answers", "", "answer non-interactively with a JSON object of quote id to author. Use @file to read a file or - to read stdin")
+	flag.BoolVar(&opts.noColor, "no-color", os.Getenv("NO_COLOR") != "", "print the result without colours")
+	flag.DurationVar(&opts.timeout, "timeout", 10*time.Second, "the timeout of every request to the api")
+	flag.Parse()
+
+	err := run(opts, os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(opts *options, stdin io.Reader, stdout io.Writer) error {
+	client, err := openapi.NewClient(opts.url, &security{apiKey: opts.apiKey, token: opts.token})
+	if err != nil {
+		return err
+	}
+
+	// The answers are read before the game is created, so a broken answer file doesn't leave an unfinished game behind
+	var answers map[int]string
+	if opts.answers != "" {
+		answers, err = readAnswers(opts.answers, stdin)
+		if err != nil {
+			return err
+		}
+	}
+
+	g := &game{
+		client:   client,
+		out:      stdout,
+		color:    !opts.noColor,
+		timeout:  opts.timeout,
+		playerID: opts.playerID,
+	}
+	created, err := g.create()
+	if err != nil {
+		return err
+	}
+
+	var matching []openapi.QuoteGameAnswer
+	if answers != nil {
+		matching, err = matchAnswers(created, answers)
+	} else {
+		g.show(created)
+		matching, err = g.ask(created, bufio.NewReader(stdin))
+	}
+	if err != nil {
+		return err
+	}
+
+	result, err := g.submit(created.ID, matching)
+	if err != nil {
+		return err
+	}
+	g.printResult(created, result)
+	return nil
+}
+
+// readAnswers reads the answers from the --answers flag. It's either JSON itself, @ followed by a file path, or - for stdin
+func readAnswers(flagValue string, stdin io.Reader) (map[int]string, error) {
+	var data []byte
+	var err error
+	switch {
+	case flagValue == "-":
+		data, err = io.ReadAll(stdin)
+	case strings.HasPrefix(flagValue, "@"):
+		data, err = os.ReadFile(strings.TrimPrefix(flagValue, "@"))
+	default:
+		data = []byte(flagValue)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read answers: %w", err)
+	}
+	return parseAnswers(data)
+}
+
+func envOr(key, defaultValue string) string {
+	if val, found := os.LookupEnv(key); found {
+		return val
+	}
+	return defaultValue
+}
+
+// security provides the credentials to the generated client. Credentials that are not set are not sent
+type security struct {
+	apiKey string
+	token  string
+}
+
+func (sec *security) ApiKey(context.Context, openapi.OperationName) (openapi.ApiKey, error) {
+	if sec.apiKey == "" {
+		return openapi.ApiKey{}, ogenerrors.ErrSkipClientSecurity
+	}
+	return openapi.ApiKey{APIKey: sec.apiKey}, nil
+}
+
+func (sec *security) BearerAuth(context.Context, openapi.OperationName) (openapi.BearerAuth, error) {
+	if sec.token == "" {
+		return openapi.BearerAuth{}, ogenerrors.ErrSkipClientSecurity
+	}
+	return openapi.BearerAuth{Token: sec.token}, nil
+}
@@ -3,6 +3,11 @@ package database
 import (
 	"database/sql"
 	"embed"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/sqlite"
@@ -16,15 +21,109 @@ import (
 //go:embed migrations
 var migrations embed.FS
 
-// init creates db connection and runs migrations
-func Init(logger *zerolog.Logger, dsn string) *sql.DB {
-	db, err := sql.Open("sqlite", dsn)
+// timeFormatParam makes the driver write times in the sqlite format, like 2025-02-01 12:00:00.5+00:00, instead of the text of time.Time.String.
+// Times that are written in UTC in that format sort in the order of the times, so queries can compare them to arguments in UTC.
+const timeFormatParam = "_time_format=sqlite"
+
+// journalModes and synchronousModes are the values sqlite accepts for the pragmas. They are checked, as pragmas can't be passed as arguments
+var (
+	journalModes     = []string{"DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF"}
+	synchronousModes = []string{"OFF", "NORMAL", "FULL", "EXTRA"}
+)
+
+// Config tunes the connections to the database. The pragmas are applied to every new connection
+type Config struct {
+	// JournalMode is the journal_mode pragma. With WAL, reads continue while a write is in progress
+	JournalMode string
+	// Synchronous is the synchronous pragma. NORMAL is safe with WAL, a power loss can only undo the last transactions
+	Synchronous string
+	// BusyTimeout is how long a connection waits for a lock held by another connection, before it fails with SQLITE_BUSY
+	BusyTimeout time.Duration
+	// ForeignKeys enables the enforcement of foreign key constraints
+	ForeignKeys bool
+	// ReadConnections is the size of the read pool. With 0, reads share the single connection of the write pool
+	ReadConnections int
+}
+
+// DefaultConfig returns the config used by Init
+func DefaultConfig() Config {
+	return Config{
+		JournalMode:     "WAL",
+		Synchronous:     "NORMAL",
+		BusyTimeout:     5 * time.Second,
+		ForeignKeys:     true,
+		ReadConnections: 4,
+	}
+}
+
+// DB is a sqlite database with separate pools for writing and reading. sqlite allows only one writer at a time,
+// so the embedded write pool has a single connection. Queries that don't write should use Reader, so they don't wait for it.
+type DB struct {
+	*sql.DB
+	reader *sql.DB
+}
+
+// Reader returns the pool for queries that only read. Its connections are query only, so a write fails instead of waiting for the writer.
+// Reads in a transaction that also writes should use the transaction instead.
+func (db *DB) Reader() *sql.DB {
+	return db.reader
+}
+
+// Close closes both pools
+func (db *DB) Close() error {
+	if db.reader == db.DB {
+		return db.DB.Close()
+	}
+	return errors.Join(db.reader.Close(), db.DB.Close())
+}
+
+// Init creates the db connections with the DefaultConfig and runs the migrations
+func Init(logger *zerolog.Logger, dsn string) *DB {
+	return InitWithConfig(logger, dsn, DefaultConfig())
+}
+
+// InitWithConfig creates the db connections and runs the migrations.
+//
+// An in-memory database only exists within its connections, so it gets a single connection for both writing and reading.
+// The journal mode of an in-memory database can't be changed, so that pragma is skipped for them.
+func InitWithConfig(logger *zerolog.Logger, dsn string, conf Config) *DB {
+	pragmas, err := conf.pragmas()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid sqlite config")
+	}
+	memory := IsMemory(dsn)
+	if !memory && strings.Contains(dsn, "cache=shared") {
+		logger.Warn().Str("DSN", dsn).Msg("a shared cache serializes all connections to a database file, it's better to leave it out")
+	}
+	if memory {
+		pragmas = slices.DeleteFunc(pragmas, func(p string) bool { return strings.HasPrefix(p, "journal_mode") })
+	}
+
+	// Writes start their transactions with the write lock, so they can't fail halfway when another process is writing
+	writer, err := sql.Open("sqlite", withParams(dsn, pragmas, timeFormatParam, "_txlock=immediate"))
 	if err != nil {
 		logger.Fatal().Err(err).Str("DSN", dsn).Msg("could not open sqlite db")
 	}
-	// This is to prevent the db from having locks. This is a sqlite specific issue
-	db.SetMaxOpenConns(1)
+	// sqlite allows one writer at a time, more connections would only wait for each other's locks
+	writer.SetMaxOpenConns(1)
+	migrateUp(logger, dsn, writer)
 
+	db := &DB{DB: writer, reader: writer}
+	if memory || conf.ReadConnections == 0 {
+		return db
+	}
+
+	db.reader, err = sql.Open("sqlite", withParams(dsn, append(pragmas, "query_only(1)"), timeFormatParam))
+	if err != nil {
+		logger.Fatal().Err(err).Str("DSN", dsn).Msg("could not open sqlite db for reading")
+	}
+	db.reader.SetMaxOpenConns(conf.ReadConnections)
+	db.reader.SetMaxIdleConns(conf.ReadConnections)
+	return db
+}
+
+// migrateUp runs the migrations on the write pool
+func migrateUp(logger *zerolog.Logger, dsn string, db *sql.DB) {
 	source, err := iofs.New(migrations, "migrations")
 	if err != nil {
 		logger.Fatal().Err(err).Str("DSN", dsn).Msg("could not create source for migration")
@@ -43,9 +142,58 @@ func Init(logger *zerolog.Logger, dsn string) *sql.DB {
 		logger.Fatal().Err(err).Str("DSN", dsn).Msg("could not create migrator with source")
 	}
 	err = m.Up()
-	if err != nil {
+	// A database file that is up to date has no migrations to run
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
 		logger.Fatal().Err(err).Str("DSN", dsn).Msg("could not run migration")
 	}
+}
 
-	return db
+// pragmas returns the pragmas of the config, formatted for the _pragma parameter of the driver
+func (conf Config) pragmas() ([]string, error) {
+	journalMode := strings.ToUpper(conf.JournalMode)
+	if !slices.Contains(journalModes, journalMode) {
+		return nil, fmt.Errorf("journal mode should be one of %v. Given: %q", journalModes, conf.JournalMode)
+	}
+	synchronous := strings.ToUpper(conf.Synchronous)
+	if !slices.Contains(synchronousModes, synchronous) {
+		return nil, fmt.Errorf("synchronous should be one of %v. Given: %q", synchronousModes, conf.Synchronous)
+	}
+	if conf.BusyTimeout < 0 {
+		return nil, fmt.Errorf("busy timeout can't be negative. Given: %s", conf.BusyTimeout)
+	}
+	if conf.ReadConnections < 0 {
+		return nil, fmt.Errorf("read connections can't be negative. Given: %d", conf.ReadConnections)
+	}
+
+	foreignKeys := 0
+	if conf.ForeignKeys {
+		foreignKeys = 1
+	}
+	return []string{
+		fmt.Sprintf("busy_timeout(%d)", conf.BusyTimeout.Milliseconds()),
+		fmt.Sprintf("foreign_keys(%d)", foreignKeys),
+		fmt.Sprintf("journal_mode(%s)", journalMode),
+		fmt.Sprintf("synchronous(%s)", synchronous),
+	}, nil
+}
+
+// withParams adds the pragmas and other parameters to the query of the dsn
+func withParams(dsn string, pragmas []string, params ...string) string {
+	for _, p := range pragmas {
+		params = append(params, "_pragma="+p)
+	}
+	if len(params) == 0 {
+		return dsn
+	}
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	return dsn + separator + strings.Join(params, "&")
+}
+
+// IsMemory reports whether the dsn is for an in-memory database, including the temporary database of an empty dsn
+func IsMemory(dsn string) bool {
+	name, query, _ := strings.Cut(strings.TrimPrefix(dsn, "file:"), "?")
+	return name == "" || name == ":memory:" || strings.Contains(query, "mode=memory")
 }
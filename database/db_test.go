@@ -0,0 +1,188 @@
+package database
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	dsn := "file:" + filepath.Join(t.TempDir(), "kabisa.db")
+
+	db := Init(&logger, dsn)
+	defer db.Close()
+	assert.NotSame(t, db.DB, db.Reader())
+
+	// Both pools get the pragmas
+	for _, pool := range []interface {
+		QueryRow(query string, args ...any) *sql.Row
+	}{db, db.Reader()} {
+		var journalMode string
+		var busyTimeout, foreignKeys int
+		require.NoError(t, pool.QueryRow("pragma journal_mode").Scan(&journalMode))
+		require.NoError(t, pool.QueryRow("pragma busy_timeout").Scan(&busyTimeout))
+		require.NoError(t, pool.QueryRow("pragma foreign_keys").Scan(&foreignKeys))
+		assert.Equal(t, "wal", journalMode)
+		assert.Equal(t, 5000, busyTimeout)
+		assert.Equal(t, 1, foreignKeys)
+	}
+
+	// The read pool can't write
+	_, err := db.Reader().Exec("insert into api_key(key_hash, subject, role, created_at) values ('hash', 'ops', 'admin', 0)")
+	require.Error(t, err)
+	_, err = db.Exec("insert into api_key(key_hash, subject, role, created_at) values ('hash', 'ops', 'admin', 0)")
+	require.NoError(t, err)
+
+	// A database file that is migrated already can be opened again
+	require.NoError(t, db.Close())
+	db = Init(&logger, dsn)
+	defer db.Close()
+	var count int
+	require.NoError(t, db.Reader().QueryRow("select count(*) from api_key").Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func TestInitWithConfig(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+
+	t.Run("reads with the write pool without read connections", func(t *testing.T) {
+		conf := DefaultConfig()
+		conf.ReadConnections = 0
+		db := InitWithConfig(&logger, "file:"+filepath.Join(t.TempDir(), "kabisa.db"), conf)
+		defer db.Close()
+		assert.Same(t, db.DB, db.Reader())
+	})
+
+	t.Run("shares the connection of an in-memory database", func(t *testing.T) {
+		db := InitWithConfig(&logger, ":memory:", DefaultConfig())
+		defer db.Close()
+		assert.Same(t, db.DB, db.Reader())
+
+		var journalMode string
+		require.NoError(t, db.QueryRow("pragma journal_mode").Scan(&journalMode))
+		assert.Equal(t, "memory", journalMode)
+	})
+}
+
+func TestMigration_NormalizeTimesOfGames(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := Init(&logger, ":memory:")
+	defer db.Close()
+
+	// The games are written like the driver did before, in the time zone of the server. A game written in UTC already is kept as it is
+	_, err := db.Exec(`insert into quote_game(id, quote1_id, quote2_id, quote3_id, created_at, completed_at) values
+		('cet', 1, 2, 3, '2025-02-01 13:00:00.5 +0100 CET m=+0.012345678', '2025-02-01 13:01:00 +0100 CET'),
+		('est', 1, 2, 3, '2025-02-01 06:30:00 -0500 EST', null),
+		('utc', 1, 2, 3, '2025-02-01 12:15:00+00:00', null)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`insert into daily_challenge(date, quote1_id, quote2_id, quote3_id, created_at) values ('2025-02-01', 1, 2, 3, '2025-02-01 01:00:00 +0100 CET')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`insert into quote_game_hint(quote_game_id, quote_id, type, author, cost, created_at) values ('cet', 1, 'reveal_pair', 'Bob', 2, '2025-02-01 13:00:30.25 +0100 CET')`)
+	require.NoError(t, err)
+
+	up, err := migrations.ReadFile("migrations/000015_normalize_times_of_games.up.sql")
+	require.NoError(t, err)
+	_, err = db.Exec(string(up))
+	require.NoError(t, err)
+
+	rows, err := db.Query("select id, created_at || '', coalesce(completed_at || '', '') from quote_game order by created_at")
+	require.NoError(t, err)
+	defer rows.Close()
+	var games [][3]string
+	for rows.Next() {
+		var game [3]string
+		require.NoError(t, rows.Scan(&game[0], &game[1], &game[2]))
+		games = append(games, game)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, [][3]string{
+		{"est", "2025-02-01 11:30:00+00:00", ""},
+		{"cet", "2025-02-01 12:00:00.5+00:00", "2025-02-01 12:01:00+00:00"},
+		{"utc", "2025-02-01 12:15:00+00:00", ""},
+	}, games)
+
+	// The daily challenges and hints are converted as well
+	var challengeCreatedAt, hintCreatedAt string
+	require.NoError(t, db.QueryRow("select created_at || '' from daily_challenge").Scan(&challengeCreatedAt))
+	require.NoError(t, db.QueryRow("select created_at || '' from quote_game_hint").Scan(&hintCreatedAt))
+	assert.Equal(t, "2025-02-01 00:00:00+00:00", challengeCreatedAt)
+	assert.Equal(t, "2025-02-01 12:00:30.25+00:00", hintCreatedAt)
+}
+
+func TestConfig_Pragmas(t *testing.T) {
+	type Test struct {
+		conf            Config
+		expectedPragmas []string
+		expectedError   string
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			pragmas, err := tt.conf.pragmas()
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPragmas, pragmas)
+		}
+	}
+
+	t.Run("formats the defaults", run(Test{
+		conf:            DefaultConfig(),
+		expectedPragmas: []string{"busy_timeout(5000)", "foreign_keys(1)", "journal_mode(WAL)", "synchronous(NORMAL)"},
+	}))
+
+	t.Run("accepts lower case modes", run(Test{
+		conf:            Config{JournalMode: "delete", Synchronous: "full", BusyTimeout: 250 * time.Millisecond},
+		expectedPragmas: []string{"busy_timeout(250)", "foreign_keys(0)", "journal_mode(DELETE)", "synchronous(FULL)"},
+	}))
+
+	t.Run("rejects an unknown journal mode", run(Test{
+		conf:          Config{JournalMode: "WAL); drop table quote; --", Synchronous: "NORMAL"},
+		expectedError: "journal mode should be one of",
+	}))
+
+	t.Run("rejects an unknown synchronous mode", run(Test{
+		conf:          Config{JournalMode: "WAL", Synchronous: "SOMETIMES"},
+		expectedError: "synchronous should be one of",
+	}))
+
+	t.Run("rejects a negative busy timeout", run(Test{
+		conf:          Config{JournalMode: "WAL", Synchronous: "NORMAL", BusyTimeout: -time.Second},
+		expectedError: "busy timeout can't be negative",
+	}))
+
+	t.Run("rejects a negative number of read connections", run(Test{
+		conf:          Config{JournalMode: "WAL", Synchronous: "NORMAL", ReadConnections: -1},
+		expectedError: "read connections can't be negative",
+	}))
+}
+
+func TestWithParams(t *testing.T) {
+	assert.Equal(t, "quotes.db", withParams("quotes.db", nil))
+	assert.Equal(t, "quotes.db?_txlock=immediate&_pragma=foreign_keys(1)", withParams("quotes.db", []string{"foreign_keys(1)"}, "_txlock=immediate"))
+	assert.Equal(t, "file:quotes.db?mode=rwc&_pragma=foreign_keys(1)", withParams("file:quotes.db?mode=rwc", []string{"foreign_keys(1)"}))
+}
+
+func TestIsMemory(t *testing.T) {
+	for dsn, expected := range map[string]bool{
+		"":                                    true,
+		":memory:":                            true,
+		"file::memory:?cache=shared":          true,
+		"file:e2e-1?mode=memory&cache=shared": true,
+		"quotes.db":                           false,
+		"file:quotes.db?cache=shared":         false,
+		"file:/var/lib/kabisa/quotes.db":      false,
+	} {
+		assert.Equal(t, expected, IsMemory(dsn), dsn)
+	}
+}
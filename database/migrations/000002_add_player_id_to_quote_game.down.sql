@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS quote_game_player_id_created_at;
+ALTER TABLE quote_game DROP COLUMN player_id;
@@ -0,0 +1,2 @@
+ALTER TABLE quote_game ADD COLUMN player_id TEXT NULL;
+CREATE INDEX IF NOT EXISTS quote_game_player_id_created_at ON quote_game(player_id, created_at);
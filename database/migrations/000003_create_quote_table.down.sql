@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS quote_after_update;
+DROP TRIGGER IF EXISTS quote_after_delete;
+DROP TRIGGER IF EXISTS quote_after_insert;
+DROP TABLE IF EXISTS quote_fts;
+DROP TABLE IF EXISTS quote;
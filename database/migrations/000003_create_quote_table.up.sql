@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS quote(
+   id INTEGER PRIMARY KEY,
+   quote TEXT NOT NULL,
+   author TEXT NOT NULL,
+   synced_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS quote_author ON quote(author);
+
+-- The full-text index only contains the quote text and is kept in sync with the quote table by triggers
+CREATE VIRTUAL TABLE IF NOT EXISTS quote_fts USING fts5(quote, content='quote', content_rowid='id');
+
+CREATE TRIGGER IF NOT EXISTS quote_after_insert AFTER INSERT ON quote BEGIN
+   INSERT INTO quote_fts(rowid, quote) VALUES (new.id, new.quote);
+END;
+
+CREATE TRIGGER IF NOT EXISTS quote_after_delete AFTER DELETE ON quote BEGIN
+   INSERT INTO quote_fts(quote_fts, rowid, quote) VALUES ('delete', old.id, old.quote);
+END;
+
+CREATE TRIGGER IF NOT EXISTS quote_after_update AFTER UPDATE OF quote ON quote BEGIN
+   INSERT INTO quote_fts(quote_fts, rowid, quote) VALUES ('delete', old.id, old.quote);
+   INSERT INTO quote_fts(rowid, quote) VALUES (new.id, new.quote);
+END;
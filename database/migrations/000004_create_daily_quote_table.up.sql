@@ -0,0 +1,8 @@
+-- The quote itself is copied, so the quote of a day stays the same when the catalogue changes
+CREATE TABLE IF NOT EXISTS daily_quote(
+   date TEXT PRIMARY KEY,
+   quote_id INT NOT NULL,
+   quote TEXT NOT NULL,
+   author TEXT NOT NULL,
+   created_at DATETIME NOT NULL
+);
@@ -0,0 +1,4 @@
+ALTER TABLE quote DROP COLUMN deleted_at;
+ALTER TABLE quote DROP COLUMN hidden;
+ALTER TABLE quote DROP COLUMN edited_at;
+ALTER TABLE quote DROP COLUMN source;
@@ -0,0 +1,6 @@
+-- source is either 'dummyjson' or 'admin'. Quotes that are edited, hidden or deleted by an admin are left alone by the catalogue sync
+ALTER TABLE quote ADD COLUMN source TEXT NOT NULL DEFAULT 'dummyjson';
+ALTER TABLE quote ADD COLUMN edited_at DATETIME NULL;
+ALTER TABLE quote ADD COLUMN hidden BOOLEAN NOT NULL DEFAULT FALSE;
+-- Deleted quotes are kept as a tombstone, so the sync doesn't bring them back
+ALTER TABLE quote ADD COLUMN deleted_at DATETIME NULL;
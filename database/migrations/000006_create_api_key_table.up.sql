@@ -0,0 +1,9 @@
+-- Only a SHA-256 hash of the key is stored, so a leaked database doesn't leak usable keys
+CREATE TABLE IF NOT EXISTS api_key(
+   id TEXT PRIMARY KEY,
+   key_hash TEXT NOT NULL UNIQUE,
+   subject TEXT NOT NULL,
+   role TEXT NOT NULL,
+   created_at DATETIME NOT NULL,
+   revoked_at DATETIME NULL
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS room_participant;
+DROP TABLE IF EXISTS room;
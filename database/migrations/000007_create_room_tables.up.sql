@@ -0,0 +1,19 @@
+-- A room shares a single quote_game between several players. The quote_game row is never completed,
+-- every participant stores their own answer in room_participant instead.
+CREATE TABLE IF NOT EXISTS room(
+   code TEXT PRIMARY KEY,
+   quote_game_id BLOB NOT NULL UNIQUE,
+   host_id TEXT NOT NULL,
+   created_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS room_participant(
+   room_code TEXT NOT NULL,
+   player_id TEXT NOT NULL,
+   joined_at DATETIME NOT NULL,
+   quote1_correct BOOLEAN NULL,
+   quote2_correct BOOLEAN NULL,
+   quote3_correct BOOLEAN NULL,
+   submitted_at DATETIME NULL,
+   PRIMARY KEY (room_code, player_id)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS quote_game_daily_date_player_id;
+ALTER TABLE quote_game DROP COLUMN daily_date;
+DROP TABLE IF EXISTS daily_challenge;
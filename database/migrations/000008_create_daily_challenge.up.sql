@@ -0,0 +1,11 @@
+-- The quotes of a day are stored on first use, so every player of that day answers the same quotes
+CREATE TABLE IF NOT EXISTS daily_challenge(
+   date TEXT PRIMARY KEY,
+   quote1_id INT NOT NULL,
+   quote2_id INT NOT NULL,
+   quote3_id INT NOT NULL,
+   created_at DATETIME NOT NULL
+);
+ALTER TABLE quote_game ADD COLUMN daily_date TEXT NULL;
+-- Every player can attempt the challenge of a day only once
+CREATE UNIQUE INDEX IF NOT EXISTS quote_game_daily_date_player_id ON quote_game(daily_date, player_id) WHERE daily_date IS NOT NULL;
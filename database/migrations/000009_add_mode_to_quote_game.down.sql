@@ -0,0 +1 @@
+ALTER TABLE quote_game DROP COLUMN mode;
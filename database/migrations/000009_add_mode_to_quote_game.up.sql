@@ -0,0 +1 @@
+ALTER TABLE quote_game ADD COLUMN mode TEXT NOT NULL DEFAULT 'match';
@@ -0,0 +1,11 @@
+-- The hints a player asked for during a quote game. Every quote gets at most one hint of every type.
+-- The cost is stored with the hint, so changing the cost of a type doesn't change the score of old games.
+CREATE TABLE IF NOT EXISTS quote_game_hint(
+   quote_game_id BLOB NOT NULL,
+   quote_id INTEGER NOT NULL,
+   type TEXT NOT NULL,
+   author TEXT NOT NULL,
+   cost INTEGER NOT NULL,
+   created_at DATETIME NOT NULL,
+   PRIMARY KEY (quote_game_id, quote_id, type)
+);
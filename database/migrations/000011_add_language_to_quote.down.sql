@@ -0,0 +1,2 @@
+ALTER TABLE daily_quote DROP COLUMN language;
+ALTER TABLE quote DROP COLUMN language;
@@ -0,0 +1,3 @@
+-- language is the ISO 639-1 code of the language the quote is written in. Everything from dummyjson is English
+ALTER TABLE quote ADD COLUMN language TEXT NOT NULL DEFAULT 'en';
+ALTER TABLE daily_quote ADD COLUMN language TEXT NOT NULL DEFAULT 'en';
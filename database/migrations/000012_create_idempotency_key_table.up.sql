@@ -0,0 +1,13 @@
+-- The responses of requests with an Idempotency-Key header, so retries get the same response instead of being handled twice.
+-- While the first request is being handled, the response is null. expires_at is a unix timestamp, so it can be compared in queries.
+CREATE TABLE IF NOT EXISTS idempotency_key(
+   scope TEXT NOT NULL,
+   key TEXT NOT NULL,
+   fingerprint TEXT NOT NULL,
+   status_code INTEGER NULL,
+   content_type TEXT NULL,
+   body BLOB NULL,
+   expires_at INTEGER NOT NULL,
+   PRIMARY KEY (scope, key)
+);
+CREATE INDEX IF NOT EXISTS idempotency_key_expires_at ON idempotency_key(expires_at);
@@ -0,0 +1,3 @@
+ALTER TABLE quote_game DROP COLUMN quote3_answer;
+ALTER TABLE quote_game DROP COLUMN quote2_answer;
+ALTER TABLE quote_game DROP COLUMN quote1_answer;
@@ -0,0 +1,5 @@
+-- The answers a player submitted, so a repeated submission can get the stored result back.
+-- Games completed before these columns existed have no answers.
+ALTER TABLE quote_game ADD COLUMN quote1_answer TEXT NULL;
+ALTER TABLE quote_game ADD COLUMN quote2_answer TEXT NULL;
+ALTER TABLE quote_game ADD COLUMN quote3_answer TEXT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS quote_game_quote3_answer_stats;
+DROP INDEX IF EXISTS quote_game_quote2_answer_stats;
+DROP INDEX IF EXISTS quote_game_quote1_answer_stats;
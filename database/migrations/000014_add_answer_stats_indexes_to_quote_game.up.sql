@@ -0,0 +1,6 @@
+-- The answer statistics aggregate the completed games per quote position. These indexes hold everything the statistics read,
+-- so they don't have to scan the table. sqlite only uses an index that way when it also holds the columns of its condition,
+-- so completed_at is part of them. Wrong answers come first, as the confusions between authors only read those.
+CREATE INDEX IF NOT EXISTS quote_game_quote1_answer_stats ON quote_game(quote1_correct, quote1_id, quote1_answer, mode, completed_at) WHERE completed_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS quote_game_quote2_answer_stats ON quote_game(quote2_correct, quote2_id, quote2_answer, mode, completed_at) WHERE completed_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS quote_game_quote3_answer_stats ON quote_game(quote3_correct, quote3_id, quote3_answer, mode, completed_at) WHERE completed_at IS NOT NULL;
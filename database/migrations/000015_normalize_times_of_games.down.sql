@@ -0,0 +1,2 @@
+-- The times stay in UTC, the driver reads both formats
+DROP INDEX IF EXISTS quote_game_created_at;
@@ -0,0 +1,22 @@
+-- The times of the games were written in the default format of the driver, in the time zone of the server,
+-- like 2025-02-01 13:00:00.5 +0100 CET. That text doesn't sort in the order of the times, so it can't be compared in a query.
+-- They are converted to the sqlite format in UTC, like 2025-02-01 12:00:00.5+00:00, which is how they are written from now on.
+-- The offset after the first space following the seconds is given to datetime, and the fraction of the seconds is kept as it was.
+UPDATE quote_game
+SET created_at = datetime(substr(created_at, 1, 19) || substr(created_at, 20 + instr(substr(created_at, 20), ' '), 3) || ':' || substr(created_at, 23 + instr(substr(created_at, 20), ' '), 2))
+   || substr(created_at, 20, instr(substr(created_at, 20), ' ') - 1) || '+00:00'
+WHERE instr(substr(created_at, 20), ' ') > 0;
+UPDATE quote_game
+SET completed_at = datetime(substr(completed_at, 1, 19) || substr(completed_at, 20 + instr(substr(completed_at, 20), ' '), 3) || ':' || substr(completed_at, 23 + instr(substr(completed_at, 20), ' '), 2))
+   || substr(completed_at, 20, instr(substr(completed_at, 20), ' ') - 1) || '+00:00'
+WHERE instr(substr(completed_at, 20), ' ') > 0;
+UPDATE daily_challenge
+SET created_at = datetime(substr(created_at, 1, 19) || substr(created_at, 20 + instr(substr(created_at, 20), ' '), 3) || ':' || substr(created_at, 23 + instr(substr(created_at, 20), ' '), 2))
+   || substr(created_at, 20, instr(substr(created_at, 20), ' ') - 1) || '+00:00'
+WHERE instr(substr(created_at, 20), ' ') > 0;
+UPDATE quote_game_hint
+SET created_at = datetime(substr(created_at, 1, 19) || substr(created_at, 20 + instr(substr(created_at, 20), ' '), 3) || ':' || substr(created_at, 23 + instr(substr(created_at, 20), ' '), 2))
+   || substr(created_at, 20, instr(substr(created_at, 20), ' ') - 1) || '+00:00'
+WHERE instr(substr(created_at, 20), ' ') > 0;
+-- The export reads the games of a range of creation times
+CREATE INDEX IF NOT EXISTS quote_game_created_at ON quote_game(created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE quote_game DROP COLUMN quote3_blank;
+ALTER TABLE quote_game DROP COLUMN quote2_blank;
+ALTER TABLE quote_game DROP COLUMN quote1_blank;
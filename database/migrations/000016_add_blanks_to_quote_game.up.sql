@@ -0,0 +1,5 @@
+-- The words removed from the quotes of a game in the fill_in_the_blank mode. The answers are checked against these,
+-- so an edit of a quote during the game doesn't change the answer. Games created before these columns existed have no blanks.
+ALTER TABLE quote_game ADD COLUMN quote1_blank TEXT NULL;
+ALTER TABLE quote_game ADD COLUMN quote2_blank TEXT NULL;
+ALTER TABLE quote_game ADD COLUMN quote3_blank TEXT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE room_participant DROP COLUMN quote3_answer;
+ALTER TABLE room_participant DROP COLUMN quote2_answer;
+ALTER TABLE room_participant DROP COLUMN quote1_answer;
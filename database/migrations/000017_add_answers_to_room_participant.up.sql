@@ -0,0 +1,5 @@
+-- The answers a participant submitted in a room, like the answers of solo games in quote_game.
+-- Participants that answered before these columns existed have no answers.
+ALTER TABLE room_participant ADD COLUMN quote1_answer TEXT NULL;
+ALTER TABLE room_participant ADD COLUMN quote2_answer TEXT NULL;
+ALTER TABLE room_participant ADD COLUMN quote3_answer TEXT NULL;
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS quote_game_quote3_answer_stats;
+DROP INDEX IF EXISTS quote_game_quote2_answer_stats;
+DROP INDEX IF EXISTS quote_game_quote1_answer_stats;
+CREATE INDEX IF NOT EXISTS quote_game_quote1_answer_stats ON quote_game(quote1_correct, quote1_id, quote1_answer, mode, completed_at) WHERE completed_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS quote_game_quote2_answer_stats ON quote_game(quote2_correct, quote2_id, quote2_answer, mode, completed_at) WHERE completed_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS quote_game_quote3_answer_stats ON quote_game(quote3_correct, quote3_id, quote3_answer, mode, completed_at) WHERE completed_at IS NOT NULL;
+
+ALTER TABLE quote_game DROP COLUMN quote3_choices;
+ALTER TABLE quote_game DROP COLUMN quote2_choices;
+ALTER TABLE quote_game DROP COLUMN quote1_choices;
+ALTER TABLE quote_game DROP COLUMN quote3_author;
+ALTER TABLE quote_game DROP COLUMN quote2_author;
+ALTER TABLE quote_game DROP COLUMN quote1_author;
@@ -0,0 +1,17 @@
+-- The author of every quote and the authors the player could choose from, as they were when the game was created.
+-- The confusions between authors only count wrong answers that were one of these choices, so a typed answer is never listed.
+-- The choices are a JSON array. Games created before these columns existed have no authors or choices.
+ALTER TABLE quote_game ADD COLUMN quote1_author TEXT NULL;
+ALTER TABLE quote_game ADD COLUMN quote2_author TEXT NULL;
+ALTER TABLE quote_game ADD COLUMN quote3_author TEXT NULL;
+ALTER TABLE quote_game ADD COLUMN quote1_choices TEXT NULL;
+ALTER TABLE quote_game ADD COLUMN quote2_choices TEXT NULL;
+ALTER TABLE quote_game ADD COLUMN quote3_choices TEXT NULL;
+
+-- The answer statistics read the authors and choices as well, so they are added to the indexes of the statistics
+DROP INDEX IF EXISTS quote_game_quote1_answer_stats;
+DROP INDEX IF EXISTS quote_game_quote2_answer_stats;
+DROP INDEX IF EXISTS quote_game_quote3_answer_stats;
+CREATE INDEX IF NOT EXISTS quote_game_quote1_answer_stats ON quote_game(quote1_correct, quote1_id, quote1_answer, mode, completed_at, quote1_author, quote1_choices) WHERE completed_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS quote_game_quote2_answer_stats ON quote_game(quote2_correct, quote2_id, quote2_answer, mode, completed_at, quote2_author, quote2_choices) WHERE completed_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS quote_game_quote3_answer_stats ON quote_game(quote3_correct, quote3_id, quote3_answer, mode, completed_at, quote3_author, quote3_choices) WHERE completed_at IS NOT NULL;
@@ -0,0 +1,145 @@
+// Package fakequotes serves a fixed set of quotes with the same api shape as dummyjson.com.
+// It's used to run the api without network, both by the end-to-end tests and by cmd/fakequotes.
+package fakequotes
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+)
+
+// maxRandomQuotes is the maximum number of quotes dummyjson returns in a single random request. Larger amounts get 10 quotes as well
+const maxRandomQuotes = 10
+
+//go:embed quotes.json
+var defaultQuotes []byte
+
+// Quote is a quote in the format of dummyjson
+type Quote struct {
+	ID     int    `json:"id"`
+	Quote  string `json:"quote"`
+	Author string `json:"author"`
+}
+
+// DefaultQuotes returns the embedded dataset of 30 quotes by 20 different authors
+func DefaultQuotes() []Quote {
+	quotes, err := ParseQuotes(defaultQuotes)
+	if err != nil {
+		// The dataset is embedded, so this can only happen when quotes.json is broken
+		panic(err)
+	}
+	return quotes
+}
+
+// ParseQuotes parses a JSON array of quotes, in the same format as quotes.json
+func ParseQuotes(data []byte) ([]Quote, error) {
+	var quotes []Quote
+	err := json.Unmarshal(data, &quotes)
+	if err != nil {
+		return nil, errors.Join(errors.New("could not parse quotes"), err)
+	}
+
+	ids := map[int]bool{}
+	for _, q := range quotes {
+		if ids[q.ID] {
+			return nil, fmt.Errorf("quote id %d is used more than once", q.ID)
+		}
+		ids[q.ID] = true
+	}
+	return quotes, nil
+}
+
+// NewTestServer starts a httptest.Server serving the default quotes. Use its URL as base url of the DummyJsonRepo.
+// The caller should close the server when done.
+func NewTestServer() *httptest.Server {
+	return httptest.NewServer(NewHandler(DefaultQuotes()))
+}
+
+// NewHandler returns a handler serving the given quotes on the endpoints of dummyjson used by the api:
+//   - GET /quotes?limit=&skip= returns a page of quotes. A limit of 0 returns all quotes
+//   - GET /quotes/{id} returns a single quote, or a 404 if it doesn't exist
+//   - GET /quotes/random/{amount} returns the amount of distinct random quotes
+func NewHandler(quotes []Quote) http.Handler {
+	byID := make(map[int]Quote, len(quotes))
+	for _, q := range quotes {
+		byID[q.ID] = q
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /quotes", func(w http.ResponseWriter, r *http.Request) {
+		limit, err := queryInt(r, "limit", 30)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+			return
+		}
+		skip, err := queryInt(r, "skip", 0)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+			return
+		}
+
+		start := min(skip, len(quotes))
+		end := len(quotes)
+		if limit > 0 {
+			end = min(start+limit, len(quotes))
+		}
+		page := quotes[start:end]
+		writeJSON(w, http.StatusOK, map[string]any{
+			"quotes": page,
+			"total":  len(quotes),
+			"skip":   skip,
+			"limit":  len(page),
+		})
+	})
+	mux.HandleFunc("GET /quotes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": fmt.Sprintf("Invalid quote id '%s'", r.PathValue("id"))})
+			return
+		}
+		quote, ok := byID[id]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": fmt.Sprintf("Quote with id '%d' not found", id)})
+			return
+		}
+		writeJSON(w, http.StatusOK, quote)
+	})
+	mux.HandleFunc("GET /quotes/random/{amount}", func(w http.ResponseWriter, r *http.Request) {
+		amount, err := strconv.Atoi(r.PathValue("amount"))
+		if err != nil || amount < 1 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": fmt.Sprintf("Invalid amount '%s'", r.PathValue("amount"))})
+			return
+		}
+		amount = min(amount, maxRandomQuotes, len(quotes))
+
+		random := make([]Quote, amount)
+		for i, j := range rand.Perm(len(quotes))[:amount] {
+			random[i] = quotes[j]
+		}
+		writeJSON(w, http.StatusOK, random)
+	})
+	return mux
+}
+
+func queryInt(r *http.Request, key string, defaultValue int) (int, error) {
+	val := r.URL.Query().Get(key)
+	if val == "" {
+		return defaultValue, nil
+	}
+	i, err := strconv.Atoi(val)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("invalid %s '%s'", key, val)
+	}
+	return i, nil
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
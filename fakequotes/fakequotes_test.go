@@ -0,0 +1,132 @@
+package fakequotes_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/fakequotes"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/pietdevries94/Kabisa/repositories"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests use the real DummyJsonRepo, so they break when the stub doesn't match the shape the repo expects
+func TestFakeQuotes_WithDummyJsonRepo(t *testing.T) {
+	srv := fakequotes.NewTestServer()
+	defer srv.Close()
+
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	repo := repositories.NewDummyJsonRepo(&logger, srv.Client(), srv.URL, 10, time.Second)
+	defaultQuotes := fakequotes.DefaultQuotes()
+
+	t.Run("returns all quotes", func(t *testing.T) {
+		quotes, err := repo.GetAllQuotes(context.TODO())
+		require.NoError(t, err)
+		require.Len(t, quotes, len(defaultQuotes))
+		assert.Equal(t, &models.Quote{ID: defaultQuotes[0].ID, Quote: defaultQuotes[0].Quote, Author: defaultQuotes[0].Author, Language: models.LanguageEnglish}, quotes[0])
+	})
+
+	t.Run("returns a quote by id", func(t *testing.T) {
+		quote, err := repo.GetQuote(context.TODO(), 23)
+		require.NoError(t, err)
+		assert.Equal(t, &models.Quote{ID: 23, Quote: "Talk is cheap. Show me the code.", Author: "Linus Torvalds", Language: models.LanguageEnglish}, quote)
+	})
+
+	t.Run("returns a public error for an unknown id", func(t *testing.T) {
+		_, err := repo.GetQuote(context.TODO(), 9999)
+		assert.IsType(t, &models.PublicError{}, err)
+	})
+
+	t.Run("returns distinct random quotes", func(t *testing.T) {
+		quotes, err := repo.GetRandomQuotes(context.TODO(), 10)
+		require.NoError(t, err)
+		require.Len(t, quotes, 10)
+
+		ids := map[int]bool{}
+		for _, q := range quotes {
+			ids[q.ID] = true
+		}
+		assert.Len(t, ids, 10)
+	})
+}
+
+func TestFakeQuotes_Pagination(t *testing.T) {
+	srv := fakequotes.NewTestServer()
+	defer srv.Close()
+
+	type Test struct {
+		query              string
+		expectedStatusCode int
+		expectedIDs        []int
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			resp, err := srv.Client().Get(srv.URL + "/quotes" + tt.query)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, tt.expectedStatusCode, resp.StatusCode)
+			if tt.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Quotes []fakequotes.Quote `json:"quotes"`
+				Total  int                `json:"total"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, 30, body.Total)
+			ids := make([]int, len(body.Quotes))
+			for i, q := range body.Quotes {
+				ids[i] = q.ID
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+		}
+	}
+
+	t.Run("skips and limits", run(Test{
+		query:              "?limit=3&skip=5",
+		expectedStatusCode: http.StatusOK,
+		expectedIDs:        []int{6, 7, 8},
+	}))
+
+	t.Run("returns an empty page after the last quote", run(Test{
+		query:              "?skip=100",
+		expectedStatusCode: http.StatusOK,
+		expectedIDs:        []int{},
+	}))
+
+	t.Run("rejects an invalid limit", run(Test{
+		query:              "?limit=-1",
+		expectedStatusCode: http.StatusBadRequest,
+	}))
+}
+
+func TestFakeQuotes_RandomLimit(t *testing.T) {
+	srv := fakequotes.NewTestServer()
+	defer srv.Close()
+
+	// Like dummyjson, a random request returns at most 10 quotes
+	resp, err := srv.Client().Get(srv.URL + "/quotes/random/20")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var quotes []fakequotes.Quote
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&quotes))
+	assert.Len(t, quotes, 10)
+}
+
+func TestParseQuotes(t *testing.T) {
+	_, err := fakequotes.ParseQuotes([]byte(`[{"id":1,"quote":"a","author":"b"},{"id":1,"quote":"c","author":"d"}]`))
+	assert.ErrorContains(t, err, "quote id 1 is used more than once")
+
+	_, err = fakequotes.ParseQuotes([]byte(`{}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+// Package i18n translates the machine readable codes of the api, like the messages of models.PublicError, into human readable
+// messages in the language of the caller. The messages live in the embedded catalogs in locales, one per models.Languages.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"golang.org/x/text/language"
+)
+
+// fallback is the language used when the caller accepts none of the supported languages, or a message isn't translated
+const fallback = models.LanguageEnglish
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var (
+	// catalogs maps every supported language to its messages, keyed by code
+	catalogs = loadCatalogs()
+	// matcher picks the best supported language for an Accept-Language header. It's built in the order of models.Languages
+	matcher = newMatcher()
+)
+
+// MatchLanguage returns the supported language that fits the given Accept-Language header best.
+// When the header is empty, invalid or accepts none of the supported languages, English is returned.
+func MatchLanguage(acceptLanguage string) models.Language {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return fallback
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return fallback
+	}
+	return models.Languages[index]
+}
+
+// Translate returns the human readable message for the code in the given language. Codes that are missing from the catalog of the language
+// are translated to English. When the English catalog doesn't have the code either, ok is false.
+func Translate(lang models.Language, code string) (message string, ok bool) {
+	if message, ok = catalogs[lang][code]; ok {
+		return message, true
+	}
+	message, ok = catalogs[fallback][code]
+	return message, ok
+}
+
+// loadCatalogs reads the catalogs of all supported languages. The catalogs are embedded, so a missing or broken catalog is a bug and panics
+func loadCatalogs() map[models.Language]map[string]string {
+	res := make(map[models.Language]map[string]string, len(models.Languages))
+	for _, lang := range models.Languages {
+		data, err := locales.ReadFile(fmt.Sprintf("locales/%s.json", lang))
+		if err != nil {
+			panic(fmt.Sprintf("missing message catalog for language %s: %s", lang, err))
+		}
+
+		messages := map[string]string{}
+		err = json.Unmarshal(data, &messages)
+		if err != nil {
+			panic(fmt.Sprintf("invalid message catalog for language %s: %s", lang, err))
+		}
+		res[lang] = messages
+	}
+	return res
+}
+
+func newMatcher() language.Matcher {
+	tags := make([]language.Tag, len(models.Languages))
+	for i, lang := range models.Languages {
+		tags[i] = language.Make(string(lang))
+	}
+	return language.NewMatcher(tags)
+}
@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchLanguage(t *testing.T) {
+	type Test struct {
+		acceptLanguage string
+		expected       models.Language
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+			assert.Equal(t, tt.expected, MatchLanguage(tt.acceptLanguage))
+		}
+	}
+
+	t.Run("matches Dutch", run(Test{acceptLanguage: "nl", expected: models.LanguageDutch}))
+	t.Run("matches a regional variant", run(Test{acceptLanguage: "nl-BE", expected: models.LanguageDutch}))
+	t.Run("prefers the language with the highest weight", run(Test{acceptLanguage: "en;q=0.5, nl;q=0.8", expected: models.LanguageDutch}))
+	t.Run("skips unsupported languages", run(Test{acceptLanguage: "fr-FR, nl;q=0.9, en;q=0.8", expected: models.LanguageDutch}))
+	t.Run("falls back to English for an unsupported language", run(Test{acceptLanguage: "fr", expected: models.LanguageEnglish}))
+	t.Run("falls back to English without a header", run(Test{acceptLanguage: "", expected: models.LanguageEnglish}))
+	t.Run("falls back to English for an invalid header", run(Test{acceptLanguage: "nl;q=x;;", expected: models.LanguageEnglish}))
+}
+
+func TestTranslate(t *testing.T) {
+	message, ok := Translate(models.LanguageDutch, "room_not_found")
+	assert.True(t, ok)
+	assert.Equal(t, "De kamer bestaat niet.", message)
+
+	message, ok = Translate(models.LanguageEnglish, "room_not_found")
+	assert.True(t, ok)
+	assert.Equal(t, "The room does not exist.", message)
+
+	// A language without a catalog falls back to English
+	message, ok = Translate("de", "room_not_found")
+	assert.True(t, ok)
+	assert.Equal(t, "The room does not exist.", message)
+
+	_, ok = Translate(models.LanguageDutch, "unknown_quote_id: 414")
+	assert.False(t, ok)
+}
+
+func TestCatalogs(t *testing.T) {
+	// Every public error the api returns should have a human readable message in every language
+	codes := []string{"not_found", "unknown_error", "unauthorized", "forbidden", "too_many_requests", "too_many_streams", "request_too_large"}
+	for _, err := range []*models.PublicError{
+		models.ErrQuoteGameIdNotFound, models.ErrInvalidQuoteID, models.ErrQuoteNotFound, models.ErrDailyQuoteNotFound,
+		models.ErrApiKeyNotFound, models.ErrRoomNotFound, models.ErrDailyChallengeNotFound, models.ErrUpstreamBusy,
+		models.ErrRoomNotJoined, models.ErrRoomAlreadyAnswered, models.ErrDailyChallengeAlreadyPlayed, models.ErrInvalidGameMode,
+		models.ErrWrongGameMode, models.ErrInvalidHintType, models.ErrNoHintAvailable, models.ErrPlayerIDRequired,
+		models.ErrIdempotencyKeyReused, models.ErrIdempotencyKeyInProgress, models.ErrQuoteGameAlreadyAnswered, models.ErrBackupsDisabled,
+	} {
+		codes = append(codes, err.Error())
+	}
+
+	for _, lang := range models.Languages {
+		for _, code := range codes {
+			assert.NotEmpty(t, catalogs[lang][code], "%s has no message for %s", lang, code)
+		}
+		// Catalogs shouldn't contain messages for codes that don't exist (anymore)
+		assert.Len(t, catalogs[lang], len(codes), "%s has messages for unknown codes", lang)
+	}
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Backup is a consistent snapshot of the database, stored as a file that can be used as the database of the api
+type Backup struct {
+	// Name is the file name of the backup in the backup directory
+	Name string
+	// Size is the size of the file in bytes
+	Size      int64
+	CreatedAt time.Time
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role determines which endpoints a caller may use
+type Role string
+
+const (
+	RolePlayer Role = "player"
+	RoleAdmin  Role = "admin"
+)
+
+// Valid returns if the role is one of the known roles
+func (r Role) Valid() bool {
+	return r == RolePlayer || r == RoleAdmin
+}
+
+// Caller is the authenticated identity behind a request. ID is the subject of the API key or JWT
+type Caller struct {
+	ID   string
+	Role Role
+}
+
+// ApiKey is an API key stored in the database. The key itself is never stored, only its hash
+type ApiKey struct {
+	ID        uuid.UUID
+	Subject   string
+	Role      Role
+	CreatedAt time.Time
+}
+
+type callerContextKey struct{}
+
+// ContextWithCaller returns a copy of the context that carries the authenticated caller
+func ContextWithCaller(ctx context.Context, caller *Caller) context.Context {
+	return context.WithValue(ctx, callerContextKey{}, caller)
+}
+
+// CallerFromContext returns the authenticated caller of the request, if there is one
+func CallerFromContext(ctx context.Context) (*Caller, bool) {
+	caller, ok := ctx.Value(callerContextKey{}).(*Caller)
+	return caller, ok && caller != nil
+}
@@ -1,6 +1,9 @@
 package models
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // PublicError is the only type of error that gets returned by the api. No other type of error should be returned.
 type PublicError struct {
@@ -28,4 +31,57 @@ func NewPublicErrorf(msg string, args ...any) *PublicError {
 var (
 	ErrQuoteGameIdNotFound = NewPublicError("quote_game_id_not_found")
 	ErrInvalidQuoteID      = NewPublicError("invalid_quote_id")
+	ErrQuoteNotFound       = NewPublicError("quote_not_found")
+	ErrDailyQuoteNotFound  = NewPublicError("daily_quote_not_found")
+	ErrApiKeyNotFound      = NewPublicError("api_key_not_found")
+	ErrRoomNotFound        = NewPublicError("room_not_found")
+	// ErrDailyChallengeNotFound is returned when the quotes of the daily challenge of a day have not been chosen yet
+	ErrDailyChallengeNotFound = NewPublicError("daily_challenge_not_found")
+	// ErrUpstreamBusy is returned when too many requests to dummyjson are waiting already
+	ErrUpstreamBusy = NewPublicError("upstream_busy")
+	// ErrRoomNotJoined is returned when a player answers in a room without joining it first
+	ErrRoomNotJoined = NewPublicError("room_not_joined")
+	// ErrRoomAlreadyAnswered is returned when a player answers in a room for the second time
+	ErrRoomAlreadyAnswered = NewPublicError("room_already_answered")
+	// ErrDailyChallengeAlreadyPlayed is returned when a player starts the daily challenge for the second time on the same day
+	ErrDailyChallengeAlreadyPlayed = NewPublicError("daily_challenge_already_played")
+	// ErrInvalidGameMode is returned when a game is created in a mode that doesn't exist
+	ErrInvalidGameMode = NewPublicError("invalid_game_mode")
+	// ErrWrongGameMode is returned when the answers to a game are submitted to the endpoint of another game mode
+	ErrWrongGameMode = NewPublicError("wrong_game_mode")
+	// ErrInvalidHintType is returned when a hint of a type that doesn't exist is requested
+	ErrInvalidHintType = NewPublicError("invalid_hint_type")
+	// ErrNoHintAvailable is returned when every quote of a game already got a hint of the requested type
+	ErrNoHintAvailable = NewPublicError("no_hint_available")
+	// ErrPlayerIDRequired is returned when a feature needs to know the player, but the player is not authenticated and gave no X-Player-Id
+	ErrPlayerIDRequired = NewPublicError("player_id_required")
+	// ErrQuoteGameAlreadyAnswered is returned when a completed game is answered again, with other answers than the ones that completed it
+	ErrQuoteGameAlreadyAnswered = NewPublicError("quote_game_already_answered")
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent again with a different request
+	ErrIdempotencyKeyReused = NewPublicError("idempotency_key_reused")
+	// ErrIdempotencyKeyInProgress is returned when an idempotency key is sent again while the first request is still being handled
+	ErrIdempotencyKeyInProgress = NewPublicError("idempotency_key_in_progress")
+	// ErrBackupsDisabled is returned when a backup is requested, but no backup directory is configured
+	ErrBackupsDisabled = NewPublicError("backups_disabled")
 )
+
+// ErrNotEnoughDistinctAuthors is returned when the quote source can't supply enough quotes by different authors to create a game
+var ErrNotEnoughDistinctAuthors = errors.New("quote source could not supply enough quotes with distinct authors")
+
+// ErrCatalogueEmpty is returned when a feature needs the local catalogue, but it hasn't been synced yet
+var ErrCatalogueEmpty = errors.New("the quote catalogue is empty")
+
+// ErrInvalidCredentials is returned when an API key or JWT is unknown, revoked, expired or otherwise invalid
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrQuoteGameCompleted is returned when answers are submitted to a game that is answered already
+var ErrQuoteGameCompleted = errors.New("quote game is already completed")
+
+// ErrIdempotencyKeyExists is returned when an idempotency key is stored, while it is already in use
+var ErrIdempotencyKeyExists = errors.New("idempotency key is already in use")
+
+// ErrIdempotencyKeyNotFound is returned when an idempotency key is not stored, or has expired
+var ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+
+// ErrRoomCodeTaken is returned when a new room gets a code that is already in use. A new code should be tried
+var ErrRoomCodeTaken = errors.New("room code is already in use")
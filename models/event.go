@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType is the kind of change in a game or room that is pushed to the clients following it
+type EventType string
+
+const (
+	EventPlayerJoined        EventType = "player_joined"
+	EventAnswerSubmitted     EventType = "answer_submitted"
+	EventDeadlineApproaching EventType = "deadline_approaching"
+	EventGameExpired         EventType = "game_expired"
+	EventResultsAvailable    EventType = "results_available"
+)
+
+// DeadlineWarning is how long before the deadline of a game EventDeadlineApproaching is published
+const DeadlineWarning = time.Minute
+
+// Event is published on the topic of a game or room. PlayerID and Score are only set when the event is about a single player
+type Event struct {
+	Topic    string
+	Type     EventType
+	PlayerID string
+	Score    *int
+	Deadline time.Time
+	Time     time.Time
+}
+
+// QuoteGameTopic is the topic of the events of a single player quote game
+func QuoteGameTopic(id uuid.UUID) string {
+	return "quote-game:" + id.String()
+}
+
+// RoomTopic is the topic of the events of a room
+func RoomTopic(code string) string {
+	return "room:" + code
+}
+
+// QuoteGameStatus tells whether a quote game can still be answered
+type QuoteGameStatus struct {
+	ID        uuid.UUID
+	Deadline  time.Time
+	Completed bool
+}
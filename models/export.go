@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportFormat is the file format of an export
+type ExportFormat string
+
+const (
+	// ExportFormatCSV has a header row, followed by a row per item
+	ExportFormatCSV ExportFormat = "csv"
+	// ExportFormatNDJSON has a JSON object per line
+	ExportFormatNDJSON ExportFormat = "ndjson"
+)
+
+// Valid reports whether the format exists
+func (f ExportFormat) Valid() bool {
+	return f == ExportFormatCSV || f == ExportFormatNDJSON
+}
+
+// GameExport is a game in an export of the raw game data
+type GameExport struct {
+	ID   uuid.UUID
+	Mode GameMode
+	// PlayerID is empty for games of anonymous players
+	PlayerID string
+	// DailyDate is the day of the daily challenge the game belongs to. It's empty for other games
+	DailyDate string
+	QuoteIDs  []int
+	// Correct is in the order of the QuoteIDs. It's nil while the game is not completed
+	Correct   []bool
+	CreatedAt time.Time
+	// CompletedAt is nil while the game is not completed
+	CompletedAt *time.Time
+}
+
+// Duration returns the time the player took to answer the game, or 0 while the game is not completed
+func (game *GameExport) Duration() time.Duration {
+	if game.CompletedAt == nil {
+		return 0
+	}
+	return game.CompletedAt.Sub(game.CreatedAt)
+}
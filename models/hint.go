@@ -0,0 +1,26 @@
+package models
+
+// HintType determines what a hint in a quote game reveals
+type HintType string
+
+const (
+	// HintRevealPair reveals the author of a quote
+	HintRevealPair HintType = "reveal_pair"
+	// HintEliminateAuthor reveals an author of the game that did not say a quote
+	HintEliminateAuthor HintType = "eliminate_author"
+)
+
+// HintCosts are the number of points a hint of every type costs. Revealing a pair gives away more than eliminating an author, so it costs more
+var HintCosts = map[HintType]int{
+	HintRevealPair:      2,
+	HintEliminateAuthor: 1,
+}
+
+// Hint is a hint a player used in a quote game. For HintRevealPair, Author is the author of the quote.
+// For HintEliminateAuthor, Author is an author that did not say the quote
+type Hint struct {
+	Type    HintType
+	QuoteID int
+	Author  string
+	Cost    int
+}
@@ -0,0 +1,15 @@
+package models
+
+// IdempotencyKey is a key a client sent with a request, so a retry of the request gets the same response instead of being handled twice.
+// Fingerprint identifies the request the key was first used for. Response is nil while that request is still being handled
+type IdempotencyKey struct {
+	Fingerprint string
+	Response    *IdempotentResponse
+}
+
+// IdempotentResponse is the stored response of a request with an idempotency key, which is replayed for a retry of the request
+type IdempotentResponse struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
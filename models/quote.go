@@ -1,12 +1,80 @@
 package models
 
 type Quote struct {
-	ID     int
-	Quote  string
-	Author string
+	ID       int
+	Quote    string
+	Author   string
+	Language Language
 }
 
+// Language is the ISO 639-1 code of the language a quote is written in
+type Language string
+
+const (
+	LanguageEnglish Language = "en"
+	LanguageDutch   Language = "nl"
+)
+
+// Languages are the languages quotes and messages are available in. The first one is the fallback when nothing else matches
+var Languages = []Language{LanguageEnglish, LanguageDutch}
+
 type QuoteWithoutAuthor struct {
-	ID    int
+	ID int
+	// Quote is the text of the quote. In GameModeFillInTheBlank, the key word is replaced by a blank
 	Quote string
+	// Choices are the candidate authors of the quote. Only set in GameModeMultipleChoice
+	Choices []string
+}
+
+// QuoteFilter contains the optional filters used when browsing the quote catalogue
+type QuoteFilter struct {
+	// Author only matches quotes by exactly this author
+	Author string
+	// Search is a full-text search over the quote text
+	Search string
+	Limit  int
+	Offset int
+}
+
+type AuthorQuoteCount struct {
+	Author     string
+	QuoteCount int
+}
+
+// Page is a single page of a paginated list. Total contains the total number of items, ignoring limit and offset
+type Page[T any] struct {
+	Items  []T
+	Total  int
+	Limit  int
+	Offset int
+}
+
+// DailyQuote is the quote chosen for a single day. Date is formatted as YYYY-MM-DD
+type DailyQuote struct {
+	Date  string
+	Quote Quote
+}
+
+// QuoteSource tells where a quote in the local store originates from
+type QuoteSource string
+
+const (
+	QuoteSourceDummyJson QuoteSource = "dummyjson"
+	QuoteSourceAdmin     QuoteSource = "admin"
+)
+
+// CuratedQuote is a quote from the local store together with its curation state, as seen by admins.
+// Hidden quotes are not shown to players, but can be made visible again.
+type CuratedQuote struct {
+	Quote
+	Source QuoteSource
+	Hidden bool
+}
+
+// QuoteEdit contains the fields an admin can set when creating or editing a quote
+type QuoteEdit struct {
+	Quote    string
+	Author   string
+	Language Language
+	Hidden   bool
 }
@@ -1,21 +1,152 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuoteGameDuration is the time players have to answer a quote game after it's created
+const QuoteGameDuration = 5 * time.Minute
+
+// GameMode determines what the player of a quote game is shown and how the answers are scored
+type GameMode string
+
+const (
+	// GameModeMatch shows three quotes and three authors, which the player has to match
+	GameModeMatch GameMode = "match"
+	// GameModeMultipleChoice shows every quote with a few candidate authors, of which the player has to choose the right one
+	GameModeMultipleChoice GameMode = "multiple_choice"
+	// GameModeFillInTheBlank shows every quote with a key word removed, which the player has to fill in
+	GameModeFillInTheBlank GameMode = "fill_in_the_blank"
+)
 
 type QuoteGame struct {
-	ID      uuid.UUID
-	Quotes  []*QuoteWithoutAuthor
+	ID   uuid.UUID
+	Mode GameMode
+	// Quotes are the quotes the player has to answer. Their ids are stored in this order
+	Quotes []*QuoteWithoutAuthor
+	// Authors are the authors to match to the quotes. Only set in GameModeMatch
 	Authors []string
+	// Keys are the right answers to the quotes by the id of the quote. They are stored with the game and never shown to the player
+	Keys map[int]*QuoteKey
+}
+
+// QuoteKey is the right answer to a quote of a game as it was when the game was created, so an edit of the quote in the catalogue
+// during the game doesn't change what the player has to answer
+type QuoteKey struct {
+	// Author is the author of the quote
+	Author string
+	// Choices are the authors the player could choose from for the quote. Not set in GameModeFillInTheBlank
+	Choices []string
+	// Blank is the word that was removed from the quote. Only set in GameModeFillInTheBlank
+	Blank string
+}
+
+// NewQuoteGame builds a QuoteGame in GameModeMatch from the given quotes. The quotes are split from the authors and both are sorted alphabetically,
+// so the order doesn't give away which author belongs to which quote.
+func NewQuoteGame(id uuid.UUID, quotes []*Quote) *QuoteGame {
+	game := &QuoteGame{
+		ID:      id,
+		Mode:    GameModeMatch,
+		Quotes:  make([]*QuoteWithoutAuthor, len(quotes)),
+		Authors: make([]string, len(quotes)),
+		Keys:    make(map[int]*QuoteKey, len(quotes)),
+	}
+
+	for i, q := range quotes {
+		game.Quotes[i] = &QuoteWithoutAuthor{
+			ID:    q.ID,
+			Quote: q.Quote,
+		}
+		game.Authors[i] = q.Author
+	}
+
+	slices.SortFunc(game.Quotes, func(a *QuoteWithoutAuthor, b *QuoteWithoutAuthor) int {
+		return strings.Compare(a.Quote, b.Quote)
+	})
+	slices.Sort(game.Authors)
+	// Every quote can be matched to all authors of the game
+	for _, q := range quotes {
+		game.Keys[q.ID] = &QuoteKey{Author: q.Author, Choices: game.Authors}
+	}
+	return game
 }
 
+// QuoteGameAnswerMap holds the answer to every quote of a game by the id of the quote. The answer is an author,
+// except in GameModeFillInTheBlank, where it's the missing word of the quote
 type QuoteGameAnswerMap map[int]string
 
+// QuoteGameSubmission contains the answers that completed a game. QuoteIDs and Correct are in the order of the quotes of the game
+type QuoteGameSubmission struct {
+	Mode     GameMode
+	QuoteIDs []int
+	Answers  QuoteGameAnswerMap
+	Correct  []bool
+}
+
 type QuoteGameResult struct {
 	ID      uuid.UUID
 	Answers []*QuoteGameActualAnswer
+	// Hints are the hints the player used during the game. Their costs are deducted from the score
+	Hints []*Hint
+}
+
+// NewQuoteGameResult compares the given answers to the authors of the quotes. The answers are in the order of quoteIDs.
+func NewQuoteGameResult(id uuid.UUID, quoteIDs []int, quotes map[int]*Quote, answers QuoteGameAnswerMap) *QuoteGameResult {
+	result := &QuoteGameResult{
+		ID:      id,
+		Answers: make([]*QuoteGameActualAnswer, len(quoteIDs)),
+	}
+	for i, id := range quoteIDs {
+		quote := quotes[id]
+		result.Answers[i] = &QuoteGameActualAnswer{
+			Quote:   *quote,
+			Correct: quote.Author == answers[id],
+		}
+	}
+	return result
+}
+
+// Score returns the number of correct answers minus the costs of the used hints. The score is never negative
+func (result *QuoteGameResult) Score() int {
+	score := 0
+	for _, a := range result.Answers {
+		if a.Correct {
+			score++
+		}
+	}
+	for _, h := range result.Hints {
+		score -= h.Cost
+	}
+	return max(score, 0)
 }
 
 type QuoteGameActualAnswer struct {
 	Quote
 	Correct bool
+	// Blank is the answer to the blank in the quote. Only set in GameModeFillInTheBlank
+	Blank *BlankAnswer
+}
+
+// BlankAnswer is the answer a player gave to a blank in a quote, together with the word that was removed
+type BlankAnswer struct {
+	Given    string
+	Expected string
+}
+
+// DailyQuoteGame is a quote game of the daily challenge. Date is formatted as YYYY-MM-DD
+type DailyQuoteGame struct {
+	QuoteGame
+	Date string
+}
+
+// DailyLeaderboardEntry is the result of a player in the daily challenge
+type DailyLeaderboardEntry struct {
+	Rank        int
+	PlayerID    string
+	Score       int
+	CompletedAt time.Time
 }
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Room lets several players answer the same quote game. Players join with the code of the room
+type Room struct {
+	Code     string
+	HostID   string
+	GameID   uuid.UUID
+	QuoteIDs []int
+	Deadline time.Time
+}
+
+// NormalizeRoomCode makes codes case insensitive, as they are shared by voice or chat
+func NormalizeRoomCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// RoomGame is a room together with the game its participants have to answer
+type RoomGame struct {
+	Room
+	Game *QuoteGame
+}
+
+// RoomParticipant is a player in a room. Score and SubmittedAt are nil until the player submitted an answer
+type RoomParticipant struct {
+	PlayerID    string
+	JoinedAt    time.Time
+	Score       *int
+	SubmittedAt *time.Time
+}
+
+// RoomResult contains the scores of all participants of a room, best scores first
+type RoomResult struct {
+	Room
+	Participants []*RoomParticipant
+}
@@ -0,0 +1,44 @@
+package models
+
+// QuoteStats are the answers given to a quote in completed games
+type QuoteStats struct {
+	QuoteID int
+	// Quote and Author are empty when the quote is not in the local catalogue
+	Quote    string
+	Author   string
+	Attempts int
+	Correct  int
+}
+
+// Accuracy returns the share of the attempts that were correct, from 0 to 1
+func (stats *QuoteStats) Accuracy() float64 {
+	if stats.Attempts == 0 {
+		return 0
+	}
+	return float64(stats.Correct) / float64(stats.Attempts)
+}
+
+// QuoteStatsFilter selects and pages the QuoteStats
+type QuoteStatsFilter struct {
+	// Mode only counts the games of this mode. All modes are counted when it's empty
+	Mode GameMode
+	// MinAttempts leaves out the quotes with fewer attempts, whose accuracy says little
+	MinAttempts int
+	Limit       int
+	Offset      int
+}
+
+// AuthorConfusion is how often players guessed GuessedAuthor for a quote of Author
+type AuthorConfusion struct {
+	Author        string
+	GuessedAuthor string
+	Count         int
+}
+
+// AuthorConfusionFilter selects and pages the AuthorConfusions
+type AuthorConfusionFilter struct {
+	// Author only matches the confusions of quotes by exactly this author
+	Author string
+	Limit  int
+	Offset int
+}
@@ -18,6 +18,7 @@ import (
 
 var regexMap = map[string]ogenregex.Regexp{
 	"^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$": ogenregex.MustCompile("^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$"),
+	"^[A-Za-z0-9]{6}$": ogenregex.MustCompile("^[A-Za-z0-9]{6}$"),
 }
 var (
 	// Allocate option closure once.
@@ -1,14 +1,102 @@
 // Code generated by ogen, DO NOT EDIT.
 package openapi
 
+type CreateApiKeyRes interface {
+	createApiKeyRes()
+}
+
+type CreateBackupRes interface {
+	createBackupRes()
+}
+
+type CreateDailyQuoteGameRes interface {
+	createDailyQuoteGameRes()
+}
+
 type CreateNewQuoteGameRes interface {
 	createNewQuoteGameRes()
 }
 
+type CreateQuoteRes interface {
+	createQuoteRes()
+}
+
+type CreateRoomRes interface {
+	createRoomRes()
+}
+
+type DeleteQuoteRes interface {
+	deleteQuoteRes()
+}
+
+type ExportGamesRes interface {
+	exportGamesRes()
+}
+
+type GetDailyLeaderboardRes interface {
+	getDailyLeaderboardRes()
+}
+
+type GetDailyQuoteRes interface {
+	getDailyQuoteRes()
+}
+
+type GetQuoteRes interface {
+	getQuoteRes()
+}
+
 type GetRandomQuoteRes interface {
 	getRandomQuoteRes()
 }
 
+type GetRoomResultRes interface {
+	getRoomResultRes()
+}
+
+type JoinRoomRes interface {
+	joinRoomRes()
+}
+
+type ListAuthorConfusionsRes interface {
+	listAuthorConfusionsRes()
+}
+
+type ListAuthorsRes interface {
+	listAuthorsRes()
+}
+
+type ListBackupsRes interface {
+	listBackupsRes()
+}
+
+type ListQuoteStatsRes interface {
+	listQuoteStatsRes()
+}
+
+type ListQuotesRes interface {
+	listQuotesRes()
+}
+
+type RequestHintForQuoteGameRes interface {
+	requestHintForQuoteGameRes()
+}
+
+type RevokeApiKeyRes interface {
+	revokeApiKeyRes()
+}
+
 type SubmitAnswerForQuoteGameRes interface {
 	submitAnswerForQuoteGameRes()
 }
+
+type SubmitAnswerForRoomRes interface {
+	submitAnswerForRoomRes()
+}
+
+type SubmitBlanksForQuoteGameRes interface {
+	submitBlanksForQuoteGameRes()
+}
+
+type UpdateQuoteRes interface {
+	updateQuoteRes()
+}
@@ -6,7 +6,29 @@ package openapi
 type OperationName = string
 
 const (
+	CreateApiKeyOperation             OperationName = "CreateApiKey"
+	CreateBackupOperation             OperationName = "CreateBackup"
+	CreateDailyQuoteGameOperation     OperationName = "CreateDailyQuoteGame"
 	CreateNewQuoteGameOperation       OperationName = "CreateNewQuoteGame"
+	CreateQuoteOperation              OperationName = "CreateQuote"
+	CreateRoomOperation               OperationName = "CreateRoom"
+	DeleteQuoteOperation              OperationName = "DeleteQuote"
+	ExportGamesOperation              OperationName = "ExportGames"
+	GetDailyLeaderboardOperation      OperationName = "GetDailyLeaderboard"
+	GetDailyQuoteOperation            OperationName = "GetDailyQuote"
+	GetQuoteOperation                 OperationName = "GetQuote"
 	GetRandomQuoteOperation           OperationName = "GetRandomQuote"
+	GetRoomResultOperation            OperationName = "GetRoomResult"
+	JoinRoomOperation                 OperationName = "JoinRoom"
+	ListAuthorConfusionsOperation     OperationName = "ListAuthorConfusions"
+	ListAuthorsOperation              OperationName = "ListAuthors"
+	ListBackupsOperation              OperationName = "ListBackups"
+	ListQuoteStatsOperation           OperationName = "ListQuoteStats"
+	ListQuotesOperation               OperationName = "ListQuotes"
+	RequestHintForQuoteGameOperation  OperationName = "RequestHintForQuoteGame"
+	RevokeApiKeyOperation             OperationName = "RevokeApiKey"
 	SubmitAnswerForQuoteGameOperation OperationName = "SubmitAnswerForQuoteGame"
+	SubmitAnswerForRoomOperation      OperationName = "SubmitAnswerForRoom"
+	SubmitBlanksForQuoteGameOperation OperationName = "SubmitBlanksForQuoteGame"
+	UpdateQuoteOperation              OperationName = "UpdateQuote"
 )
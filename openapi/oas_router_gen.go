@@ -49,203 +49,1295 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			break
 		}
 		switch elem[0] {
-		case '/': // Prefix: "/quote"
+		case '/': // Prefix: "/"
 			origElem := elem
-			if l := len("/quote"); len(elem) >= l && elem[0:l] == "/quote" {
+			if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
 				elem = elem[l:]
 			} else {
 				break
 			}
 
 			if len(elem) == 0 {
-				switch r.Method {
-				case "GET":
-					s.handleGetRandomQuoteRequest([0]string{}, elemIsEscaped, w, r)
-				default:
-					s.notAllowed(w, r, "GET")
+				break
+			}
+			switch elem[0] {
+			case 'a': // Prefix: "a"
+				origElem := elem
+				if l := len("a"); len(elem) >= l && elem[0:l] == "a" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case 'd': // Prefix: "dmin/"
+					origElem := elem
+					if l := len("dmin/"); len(elem) >= l && elem[0:l] == "dmin/" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						break
+					}
+					switch elem[0] {
+					case 'a': // Prefix: "api-keys"
+						origElem := elem
+						if l := len("api-keys"); len(elem) >= l && elem[0:l] == "api-keys" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							switch r.Method {
+							case "POST":
+								s.handleCreateApiKeyRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "POST")
+							}
+
+							return
+						}
+						switch elem[0] {
+						case '/': // Prefix: "/"
+							origElem := elem
+							if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							// Param: "id"
+							// Leaf parameter
+							args[0] = elem
+							elem = ""
+
+							if len(elem) == 0 {
+								// Leaf node.
+								switch r.Method {
+								case "DELETE":
+									s.handleRevokeApiKeyRequest([1]string{
+										args[0],
+									}, elemIsEscaped, w, r)
+								default:
+									s.notAllowed(w, r, "DELETE")
+								}
+
+								return
+							}
+
+							elem = origElem
+						}
+
+						elem = origElem
+					case 'b': // Prefix: "backups"
+						origElem := elem
+						if l := len("backups"); len(elem) >= l && elem[0:l] == "backups" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "GET":
+								s.handleListBackupsRequest([0]string{}, elemIsEscaped, w, r)
+							case "POST":
+								s.handleCreateBackupRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "GET,POST")
+							}
+
+							return
+						}
+
+						elem = origElem
+					case 'e': // Prefix: "exports/games"
+						origElem := elem
+						if l := len("exports/games"); len(elem) >= l && elem[0:l] == "exports/games" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "GET":
+								s.handleExportGamesRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "GET")
+							}
+
+							return
+						}
+
+						elem = origElem
+					case 'q': // Prefix: "quotes"
+						origElem := elem
+						if l := len("quotes"); len(elem) >= l && elem[0:l] == "quotes" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							switch r.Method {
+							case "POST":
+								s.handleCreateQuoteRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "POST")
+							}
+
+							return
+						}
+						switch elem[0] {
+						case '/': // Prefix: "/"
+							origElem := elem
+							if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							// Param: "id"
+							// Leaf parameter
+							args[0] = elem
+							elem = ""
+
+							if len(elem) == 0 {
+								// Leaf node.
+								switch r.Method {
+								case "DELETE":
+									s.handleDeleteQuoteRequest([1]string{
+										args[0],
+									}, elemIsEscaped, w, r)
+								case "PUT":
+									s.handleUpdateQuoteRequest([1]string{
+										args[0],
+									}, elemIsEscaped, w, r)
+								default:
+									s.notAllowed(w, r, "DELETE,PUT")
+								}
+
+								return
+							}
+
+							elem = origElem
+						}
+
+						elem = origElem
+					}
+
+					elem = origElem
+				case 'u': // Prefix: "uthors"
+					origElem := elem
+					if l := len("uthors"); len(elem) >= l && elem[0:l] == "uthors" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handleListAuthorsRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "GET")
+						}
+
+						return
+					}
+
+					elem = origElem
+				}
+
+				elem = origElem
+			case 'q': // Prefix: "quote"
+				origElem := elem
+				if l := len("quote"); len(elem) >= l && elem[0:l] == "quote" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					switch r.Method {
+					case "GET":
+						s.handleGetRandomQuoteRequest([0]string{}, elemIsEscaped, w, r)
+					default:
+						s.notAllowed(w, r, "GET")
+					}
+
+					return
+				}
+				switch elem[0] {
+				case '-': // Prefix: "-game"
+					origElem := elem
+					if l := len("-game"); len(elem) >= l && elem[0:l] == "-game" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						switch r.Method {
+						case "POST":
+							s.handleCreateNewQuoteGameRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "POST")
+						}
+
+						return
+					}
+					switch elem[0] {
+					case '/': // Prefix: "/"
+						origElem := elem
+						if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							break
+						}
+						switch elem[0] {
+						case 'd': // Prefix: "daily"
+							origElem := elem
+							if l := len("daily"); len(elem) >= l && elem[0:l] == "daily" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							if len(elem) == 0 {
+								switch r.Method {
+								case "POST":
+									s.handleCreateDailyQuoteGameRequest([0]string{}, elemIsEscaped, w, r)
+								default:
+									s.notAllowed(w, r, "POST")
+								}
+
+								return
+							}
+							switch elem[0] {
+							case '/': // Prefix: "/leaderboard"
+								origElem := elem
+								if l := len("/leaderboard"); len(elem) >= l && elem[0:l] == "/leaderboard" {
+									elem = elem[l:]
+								} else {
+									break
+								}
+
+								if len(elem) == 0 {
+									// Leaf node.
+									switch r.Method {
+									case "GET":
+										s.handleGetDailyLeaderboardRequest([0]string{}, elemIsEscaped, w, r)
+									default:
+										s.notAllowed(w, r, "GET")
+									}
+
+									return
+								}
+
+								elem = origElem
+							}
+
+							elem = origElem
+						}
+						// Param: "id"
+						// Match until "/"
+						idx := strings.IndexByte(elem, '/')
+						if idx < 0 {
+							idx = len(elem)
+						}
+						args[0] = elem[:idx]
+						elem = elem[idx:]
+
+						if len(elem) == 0 {
+							break
+						}
+						switch elem[0] {
+						case '/': // Prefix: "/"
+							origElem := elem
+							if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							if len(elem) == 0 {
+								break
+							}
+							switch elem[0] {
+							case 'a': // Prefix: "answer"
+								origElem := elem
+								if l := len("answer"); len(elem) >= l && elem[0:l] == "answer" {
+									elem = elem[l:]
+								} else {
+									break
+								}
+
+								if len(elem) == 0 {
+									// Leaf node.
+									switch r.Method {
+									case "POST":
+										s.handleSubmitAnswerForQuoteGameRequest([1]string{
+											args[0],
+										}, elemIsEscaped, w, r)
+									default:
+										s.notAllowed(w, r, "POST")
+									}
+
+									return
+								}
+
+								elem = origElem
+							case 'b': // Prefix: "blanks"
+								origElem := elem
+								if l := len("blanks"); len(elem) >= l && elem[0:l] == "blanks" {
+									elem = elem[l:]
+								} else {
+									break
+								}
+
+								if len(elem) == 0 {
+									// Leaf node.
+									switch r.Method {
+									case "POST":
+										s.handleSubmitBlanksForQuoteGameRequest([1]string{
+											args[0],
+										}, elemIsEscaped, w, r)
+									default:
+										s.notAllowed(w, r, "POST")
+									}
+
+									return
+								}
+
+								elem = origElem
+							case 'h': // Prefix: "hint"
+								origElem := elem
+								if l := len("hint"); len(elem) >= l && elem[0:l] == "hint" {
+									elem = elem[l:]
+								} else {
+									break
+								}
+
+								if len(elem) == 0 {
+									// Leaf node.
+									switch r.Method {
+									case "POST":
+										s.handleRequestHintForQuoteGameRequest([1]string{
+											args[0],
+										}, elemIsEscaped, w, r)
+									default:
+										s.notAllowed(w, r, "POST")
+									}
+
+									return
+								}
+
+								elem = origElem
+							}
+
+							elem = origElem
+						}
+
+						elem = origElem
+					}
+
+					elem = origElem
+				case '/': // Prefix: "/daily"
+					origElem := elem
+					if l := len("/daily"); len(elem) >= l && elem[0:l] == "/daily" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handleGetDailyQuoteRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "GET")
+						}
+
+						return
+					}
+
+					elem = origElem
+				case 's': // Prefix: "s"
+					origElem := elem
+					if l := len("s"); len(elem) >= l && elem[0:l] == "s" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						switch r.Method {
+						case "GET":
+							s.handleListQuotesRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "GET")
+						}
+
+						return
+					}
+					switch elem[0] {
+					case '/': // Prefix: "/"
+						origElem := elem
+						if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						// Param: "id"
+						// Leaf parameter
+						args[0] = elem
+						elem = ""
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "GET":
+								s.handleGetQuoteRequest([1]string{
+									args[0],
+								}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "GET")
+							}
+
+							return
+						}
+
+						elem = origElem
+					}
+
+					elem = origElem
+				}
+
+				elem = origElem
+			case 'r': // Prefix: "rooms"
+				origElem := elem
+				if l := len("rooms"); len(elem) >= l && elem[0:l] == "rooms" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					switch r.Method {
+					case "POST":
+						s.handleCreateRoomRequest([0]string{}, elemIsEscaped, w, r)
+					default:
+						s.notAllowed(w, r, "POST")
+					}
+
+					return
+				}
+				switch elem[0] {
+				case '/': // Prefix: "/"
+					origElem := elem
+					if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					// Param: "code"
+					// Match until "/"
+					idx := strings.IndexByte(elem, '/')
+					if idx < 0 {
+						idx = len(elem)
+					}
+					args[0] = elem[:idx]
+					elem = elem[idx:]
+
+					if len(elem) == 0 {
+						switch r.Method {
+						case "GET":
+							s.handleGetRoomResultRequest([1]string{
+								args[0],
+							}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "GET")
+						}
+
+						return
+					}
+					switch elem[0] {
+					case '/': // Prefix: "/"
+						origElem := elem
+						if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							break
+						}
+						switch elem[0] {
+						case 'a': // Prefix: "answer"
+							origElem := elem
+							if l := len("answer"); len(elem) >= l && elem[0:l] == "answer" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							if len(elem) == 0 {
+								// Leaf node.
+								switch r.Method {
+								case "POST":
+									s.handleSubmitAnswerForRoomRequest([1]string{
+										args[0],
+									}, elemIsEscaped, w, r)
+								default:
+									s.notAllowed(w, r, "POST")
+								}
+
+								return
+							}
+
+							elem = origElem
+						case 'j': // Prefix: "join"
+							origElem := elem
+							if l := len("join"); len(elem) >= l && elem[0:l] == "join" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							if len(elem) == 0 {
+								// Leaf node.
+								switch r.Method {
+								case "POST":
+									s.handleJoinRoomRequest([1]string{
+										args[0],
+									}, elemIsEscaped, w, r)
+								default:
+									s.notAllowed(w, r, "POST")
+								}
+
+								return
+							}
+
+							elem = origElem
+						}
+
+						elem = origElem
+					}
+
+					elem = origElem
+				}
+
+				elem = origElem
+			case 's': // Prefix: "stats/"
+				origElem := elem
+				if l := len("stats/"); len(elem) >= l && elem[0:l] == "stats/" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case 'c': // Prefix: "confusions"
+					origElem := elem
+					if l := len("confusions"); len(elem) >= l && elem[0:l] == "confusions" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handleListAuthorConfusionsRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "GET")
+						}
+
+						return
+					}
+
+					elem = origElem
+				case 'q': // Prefix: "quotes"
+					origElem := elem
+					if l := len("quotes"); len(elem) >= l && elem[0:l] == "quotes" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handleListQuoteStatsRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "GET")
+						}
+
+						return
+					}
+
+					elem = origElem
+				}
+
+				elem = origElem
+			}
+
+			elem = origElem
+		}
+	}
+	s.notFound(w, r)
+}
+
+// Route is route object.
+type Route struct {
+	name        string
+	summary     string
+	operationID string
+	pathPattern string
+	count       int
+	args        [1]string
+}
+
+// Name returns ogen operation name.
+//
+// It is guaranteed to be unique and not empty.
+func (r Route) Name() string {
+	return r.name
+}
+
+// Summary returns OpenAPI summary.
+func (r Route) Summary() string {
+	return r.summary
+}
+
+// OperationID returns OpenAPI operationId.
+func (r Route) OperationID() string {
+	return r.operationID
+}
+
+// PathPattern returns OpenAPI path.
+func (r Route) PathPattern() string {
+	return r.pathPattern
+}
+
+// Args returns parsed arguments.
+func (r Route) Args() []string {
+	return r.args[:r.count]
+}
+
+// FindRoute finds Route for given method and path.
+//
+// Note: this method does not unescape path or handle reserved characters in path properly. Use FindPath instead.
+func (s *Server) FindRoute(method, path string) (Route, bool) {
+	return s.FindPath(method, &url.URL{Path: path})
+}
+
+// FindPath finds Route for given method and URL.
+func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
+	var (
+		elem = u.Path
+		args = r.args
+	)
+	if rawPath := u.RawPath; rawPath != "" {
+		if normalized, ok := uri.NormalizeEscapedPath(rawPath); ok {
+			elem = normalized
+		}
+		defer func() {
+			for i, arg := range r.args[:r.count] {
+				if unescaped, err := url.PathUnescape(arg); err == nil {
+					r.args[i] = unescaped
+				}
+			}
+		}()
+	}
+
+	elem, ok := s.cutPrefix(elem)
+	if !ok {
+		return r, false
+	}
+
+	// Static code generated router with unwrapped path search.
+	switch {
+	default:
+		if len(elem) == 0 {
+			break
+		}
+		switch elem[0] {
+		case '/': // Prefix: "/"
+			origElem := elem
+			if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+				elem = elem[l:]
+			} else {
+				break
+			}
+
+			if len(elem) == 0 {
+				break
+			}
+			switch elem[0] {
+			case 'a': // Prefix: "a"
+				origElem := elem
+				if l := len("a"); len(elem) >= l && elem[0:l] == "a" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case 'd': // Prefix: "dmin/"
+					origElem := elem
+					if l := len("dmin/"); len(elem) >= l && elem[0:l] == "dmin/" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						break
+					}
+					switch elem[0] {
+					case 'a': // Prefix: "api-keys"
+						origElem := elem
+						if l := len("api-keys"); len(elem) >= l && elem[0:l] == "api-keys" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							switch method {
+							case "POST":
+								r.name = CreateApiKeyOperation
+								r.summary = "Create API key"
+								r.operationID = "createApiKey"
+								r.pathPattern = "/admin/api-keys"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
+						switch elem[0] {
+						case '/': // Prefix: "/"
+							origElem := elem
+							if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							// Param: "id"
+							// Leaf parameter
+							args[0] = elem
+							elem = ""
+
+							if len(elem) == 0 {
+								// Leaf node.
+								switch method {
+								case "DELETE":
+									r.name = RevokeApiKeyOperation
+									r.summary = "Revoke API key"
+									r.operationID = "revokeApiKey"
+									r.pathPattern = "/admin/api-keys/{id}"
+									r.args = args
+									r.count = 1
+									return r, true
+								default:
+									return
+								}
+							}
+
+							elem = origElem
+						}
+
+						elem = origElem
+					case 'b': // Prefix: "backups"
+						origElem := elem
+						if l := len("backups"); len(elem) >= l && elem[0:l] == "backups" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "GET":
+								r.name = ListBackupsOperation
+								r.summary = "List backups"
+								r.operationID = "listBackups"
+								r.pathPattern = "/admin/backups"
+								r.args = args
+								r.count = 0
+								return r, true
+							case "POST":
+								r.name = CreateBackupOperation
+								r.summary = "Create backup"
+								r.operationID = "createBackup"
+								r.pathPattern = "/admin/backups"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
+
+						elem = origElem
+					case 'e': // Prefix: "exports/games"
+						origElem := elem
+						if l := len("exports/games"); len(elem) >= l && elem[0:l] == "exports/games" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "GET":
+								r.name = ExportGamesOperation
+								r.summary = "Export games"
+								r.operationID = "exportGames"
+								r.pathPattern = "/admin/exports/games"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
+
+						elem = origElem
+					case 'q': // Prefix: "quotes"
+						origElem := elem
+						if l := len("quotes"); len(elem) >= l && elem[0:l] == "quotes" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							switch method {
+							case "POST":
+								r.name = CreateQuoteOperation
+								r.summary = "Create quote"
+								r.operationID = "createQuote"
+								r.pathPattern = "/admin/quotes"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
+						switch elem[0] {
+						case '/': // Prefix: "/"
+							origElem := elem
+							if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							// Param: "id"
+							// Leaf parameter
+							args[0] = elem
+							elem = ""
+
+							if len(elem) == 0 {
+								// Leaf node.
+								switch method {
+								case "DELETE":
+									r.name = DeleteQuoteOperation
+									r.summary = "Delete quote"
+									r.operationID = "deleteQuote"
+									r.pathPattern = "/admin/quotes/{id}"
+									r.args = args
+									r.count = 1
+									return r, true
+								case "PUT":
+									r.name = UpdateQuoteOperation
+									r.summary = "Edit quote"
+									r.operationID = "updateQuote"
+									r.pathPattern = "/admin/quotes/{id}"
+									r.args = args
+									r.count = 1
+									return r, true
+								default:
+									return
+								}
+							}
+
+							elem = origElem
+						}
+
+						elem = origElem
+					}
+
+					elem = origElem
+				case 'u': // Prefix: "uthors"
+					origElem := elem
+					if l := len("uthors"); len(elem) >= l && elem[0:l] == "uthors" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = ListAuthorsOperation
+							r.summary = "List authors"
+							r.operationID = "listAuthors"
+							r.pathPattern = "/authors"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+
+					elem = origElem
 				}
 
-				return
-			}
-			switch elem[0] {
-			case '-': // Prefix: "-game"
+				elem = origElem
+			case 'q': // Prefix: "quote"
 				origElem := elem
-				if l := len("-game"); len(elem) >= l && elem[0:l] == "-game" {
+				if l := len("quote"); len(elem) >= l && elem[0:l] == "quote" {
 					elem = elem[l:]
 				} else {
 					break
 				}
 
 				if len(elem) == 0 {
-					switch r.Method {
-					case "POST":
-						s.handleCreateNewQuoteGameRequest([0]string{}, elemIsEscaped, w, r)
+					switch method {
+					case "GET":
+						r.name = GetRandomQuoteOperation
+						r.summary = "Get random quote"
+						r.operationID = "getRandomQuote"
+						r.pathPattern = "/quote"
+						r.args = args
+						r.count = 0
+						return r, true
 					default:
-						s.notAllowed(w, r, "POST")
+						return
 					}
-
-					return
 				}
 				switch elem[0] {
-				case '/': // Prefix: "/"
+				case '-': // Prefix: "-game"
 					origElem := elem
-					if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+					if l := len("-game"); len(elem) >= l && elem[0:l] == "-game" {
 						elem = elem[l:]
 					} else {
 						break
 					}
 
-					// Param: "id"
-					// Match until "/"
-					idx := strings.IndexByte(elem, '/')
-					if idx < 0 {
-						idx = len(elem)
-					}
-					args[0] = elem[:idx]
-					elem = elem[idx:]
-
 					if len(elem) == 0 {
-						break
+						switch method {
+						case "POST":
+							r.name = CreateNewQuoteGameOperation
+							r.summary = "Create new quote game"
+							r.operationID = "createNewQuoteGame"
+							r.pathPattern = "/quote-game"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
 					}
 					switch elem[0] {
-					case '/': // Prefix: "/answer"
+					case '/': // Prefix: "/"
 						origElem := elem
-						if l := len("/answer"); len(elem) >= l && elem[0:l] == "/answer" {
+						if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
 							elem = elem[l:]
 						} else {
 							break
 						}
 
 						if len(elem) == 0 {
-							// Leaf node.
-							switch r.Method {
-							case "POST":
-								s.handleSubmitAnswerForQuoteGameRequest([1]string{
-									args[0],
-								}, elemIsEscaped, w, r)
-							default:
-								s.notAllowed(w, r, "POST")
+							break
+						}
+						switch elem[0] {
+						case 'd': // Prefix: "daily"
+							origElem := elem
+							if l := len("daily"); len(elem) >= l && elem[0:l] == "daily" {
+								elem = elem[l:]
+							} else {
+								break
 							}
 
-							return
+							if len(elem) == 0 {
+								switch method {
+								case "POST":
+									r.name = CreateDailyQuoteGameOperation
+									r.summary = "Play the daily challenge"
+									r.operationID = "createDailyQuoteGame"
+									r.pathPattern = "/quote-game/daily"
+									r.args = args
+									r.count = 0
+									return r, true
+								default:
+									return
+								}
+							}
+							switch elem[0] {
+							case '/': // Prefix: "/leaderboard"
+								origElem := elem
+								if l := len("/leaderboard"); len(elem) >= l && elem[0:l] == "/leaderboard" {
+									elem = elem[l:]
+								} else {
+									break
+								}
+
+								if len(elem) == 0 {
+									// Leaf node.
+									switch method {
+									case "GET":
+										r.name = GetDailyLeaderboardOperation
+										r.summary = "Get the leaderboard of the daily challenge"
+										r.operationID = "getDailyLeaderboard"
+										r.pathPattern = "/quote-game/daily/leaderboard"
+										r.args = args
+										r.count = 0
+										return r, true
+									default:
+										return
+									}
+								}
+
+								elem = origElem
+							}
+
+							elem = origElem
+						}
+						// Param: "id"
+						// Match until "/"
+						idx := strings.IndexByte(elem, '/')
+						if idx < 0 {
+							idx = len(elem)
 						}
+						args[0] = elem[:idx]
+						elem = elem[idx:]
 
-						elem = origElem
-					}
+						if len(elem) == 0 {
+							break
+						}
+						switch elem[0] {
+						case '/': // Prefix: "/"
+							origElem := elem
+							if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+								elem = elem[l:]
+							} else {
+								break
+							}
 
-					elem = origElem
-				}
+							if len(elem) == 0 {
+								break
+							}
+							switch elem[0] {
+							case 'a': // Prefix: "answer"
+								origElem := elem
+								if l := len("answer"); len(elem) >= l && elem[0:l] == "answer" {
+									elem = elem[l:]
+								} else {
+									break
+								}
 
-				elem = origElem
-			}
+								if len(elem) == 0 {
+									// Leaf node.
+									switch method {
+									case "POST":
+										r.name = SubmitAnswerForQuoteGameOperation
+										r.summary = "Submit answer for quote game"
+										r.operationID = "submitAnswerForQuoteGame"
+										r.pathPattern = "/quote-game/{id}/answer"
+										r.args = args
+										r.count = 1
+										return r, true
+									default:
+										return
+									}
+								}
 
-			elem = origElem
-		}
-	}
-	s.notFound(w, r)
-}
+								elem = origElem
+							case 'b': // Prefix: "blanks"
+								origElem := elem
+								if l := len("blanks"); len(elem) >= l && elem[0:l] == "blanks" {
+									elem = elem[l:]
+								} else {
+									break
+								}
 
-// Route is route object.
-type Route struct {
-	name        string
-	summary     string
-	operationID string
-	pathPattern string
-	count       int
-	args        [1]string
-}
+								if len(elem) == 0 {
+									// Leaf node.
+									switch method {
+									case "POST":
+										r.name = SubmitBlanksForQuoteGameOperation
+										r.summary = "Submit the missing words of a fill in the blank game"
+										r.operationID = "submitBlanksForQuoteGame"
+										r.pathPattern = "/quote-game/{id}/blanks"
+										r.args = args
+										r.count = 1
+										return r, true
+									default:
+										return
+									}
+								}
 
-// Name returns ogen operation name.
-//
-// It is guaranteed to be unique and not empty.
-func (r Route) Name() string {
-	return r.name
-}
+								elem = origElem
+							case 'h': // Prefix: "hint"
+								origElem := elem
+								if l := len("hint"); len(elem) >= l && elem[0:l] == "hint" {
+									elem = elem[l:]
+								} else {
+									break
+								}
 
-// Summary returns OpenAPI summary.
-func (r Route) Summary() string {
-	return r.summary
-}
+								if len(elem) == 0 {
+									// Leaf node.
+									switch method {
+									case "POST":
+										r.name = RequestHintForQuoteGameOperation
+										r.summary = "Request a hint for a quote game"
+										r.operationID = "requestHintForQuoteGame"
+										r.pathPattern = "/quote-game/{id}/hint"
+										r.args = args
+										r.count = 1
+										return r, true
+									default:
+										return
+									}
+								}
 
-// OperationID returns OpenAPI operationId.
-func (r Route) OperationID() string {
-	return r.operationID
-}
+								elem = origElem
+							}
 
-// PathPattern returns OpenAPI path.
-func (r Route) PathPattern() string {
-	return r.pathPattern
-}
+							elem = origElem
+						}
 
-// Args returns parsed arguments.
-func (r Route) Args() []string {
-	return r.args[:r.count]
-}
+						elem = origElem
+					}
 
-// FindRoute finds Route for given method and path.
-//
-// Note: this method does not unescape path or handle reserved characters in path properly. Use FindPath instead.
-func (s *Server) FindRoute(method, path string) (Route, bool) {
-	return s.FindPath(method, &url.URL{Path: path})
-}
+					elem = origElem
+				case '/': // Prefix: "/daily"
+					origElem := elem
+					if l := len("/daily"); len(elem) >= l && elem[0:l] == "/daily" {
+						elem = elem[l:]
+					} else {
+						break
+					}
 
-// FindPath finds Route for given method and URL.
-func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
-	var (
-		elem = u.Path
-		args = r.args
-	)
-	if rawPath := u.RawPath; rawPath != "" {
-		if normalized, ok := uri.NormalizeEscapedPath(rawPath); ok {
-			elem = normalized
-		}
-		defer func() {
-			for i, arg := range r.args[:r.count] {
-				if unescaped, err := url.PathUnescape(arg); err == nil {
-					r.args[i] = unescaped
-				}
-			}
-		}()
-	}
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = GetDailyQuoteOperation
+							r.summary = "Get quote of the day"
+							r.operationID = "getDailyQuote"
+							r.pathPattern = "/quote/daily"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
 
-	elem, ok := s.cutPrefix(elem)
-	if !ok {
-		return r, false
-	}
+					elem = origElem
+				case 's': // Prefix: "s"
+					origElem := elem
+					if l := len("s"); len(elem) >= l && elem[0:l] == "s" {
+						elem = elem[l:]
+					} else {
+						break
+					}
 
-	// Static code generated router with unwrapped path search.
-	switch {
-	default:
-		if len(elem) == 0 {
-			break
-		}
-		switch elem[0] {
-		case '/': // Prefix: "/quote"
-			origElem := elem
-			if l := len("/quote"); len(elem) >= l && elem[0:l] == "/quote" {
-				elem = elem[l:]
-			} else {
-				break
-			}
+					if len(elem) == 0 {
+						switch method {
+						case "GET":
+							r.name = ListQuotesOperation
+							r.summary = "Browse and search quotes"
+							r.operationID = "listQuotes"
+							r.pathPattern = "/quotes"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+					switch elem[0] {
+					case '/': // Prefix: "/"
+						origElem := elem
+						if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+							elem = elem[l:]
+						} else {
+							break
+						}
 
-			if len(elem) == 0 {
-				switch method {
-				case "GET":
-					r.name = GetRandomQuoteOperation
-					r.summary = "Get random quote"
-					r.operationID = "getRandomQuote"
-					r.pathPattern = "/quote"
-					r.args = args
-					r.count = 0
-					return r, true
-				default:
-					return
+						// Param: "id"
+						// Leaf parameter
+						args[0] = elem
+						elem = ""
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "GET":
+								r.name = GetQuoteOperation
+								r.summary = "Get quote"
+								r.operationID = "getQuote"
+								r.pathPattern = "/quotes/{id}"
+								r.args = args
+								r.count = 1
+								return r, true
+							default:
+								return
+							}
+						}
+
+						elem = origElem
+					}
+
+					elem = origElem
 				}
-			}
-			switch elem[0] {
-			case '-': // Prefix: "-game"
+
+				elem = origElem
+			case 'r': // Prefix: "rooms"
 				origElem := elem
-				if l := len("-game"); len(elem) >= l && elem[0:l] == "-game" {
+				if l := len("rooms"); len(elem) >= l && elem[0:l] == "rooms" {
 					elem = elem[l:]
 				} else {
 					break
@@ -254,10 +1346,10 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 				if len(elem) == 0 {
 					switch method {
 					case "POST":
-						r.name = CreateNewQuoteGameOperation
-						r.summary = "Create new quote game"
-						r.operationID = "createNewQuoteGame"
-						r.pathPattern = "/quote-game"
+						r.name = CreateRoomOperation
+						r.summary = "Create a room"
+						r.operationID = "createRoom"
+						r.pathPattern = "/rooms"
 						r.args = args
 						r.count = 0
 						return r, true
@@ -274,7 +1366,7 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 						break
 					}
 
-					// Param: "id"
+					// Param: "code"
 					// Match until "/"
 					idx := strings.IndexByte(elem, '/')
 					if idx < 0 {
@@ -284,31 +1376,82 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 					elem = elem[idx:]
 
 					if len(elem) == 0 {
-						break
+						switch method {
+						case "GET":
+							r.name = GetRoomResultOperation
+							r.summary = "Get the result of a room"
+							r.operationID = "getRoomResult"
+							r.pathPattern = "/rooms/{code}"
+							r.args = args
+							r.count = 1
+							return r, true
+						default:
+							return
+						}
 					}
 					switch elem[0] {
-					case '/': // Prefix: "/answer"
+					case '/': // Prefix: "/"
 						origElem := elem
-						if l := len("/answer"); len(elem) >= l && elem[0:l] == "/answer" {
+						if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
 							elem = elem[l:]
 						} else {
 							break
 						}
 
 						if len(elem) == 0 {
-							// Leaf node.
-							switch method {
-							case "POST":
-								r.name = SubmitAnswerForQuoteGameOperation
-								r.summary = "Submit answer for quote game"
-								r.operationID = "submitAnswerForQuoteGame"
-								r.pathPattern = "/quote-game/{id}/answer"
-								r.args = args
-								r.count = 1
-								return r, true
-							default:
-								return
+							break
+						}
+						switch elem[0] {
+						case 'a': // Prefix: "answer"
+							origElem := elem
+							if l := len("answer"); len(elem) >= l && elem[0:l] == "answer" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							if len(elem) == 0 {
+								// Leaf node.
+								switch method {
+								case "POST":
+									r.name = SubmitAnswerForRoomOperation
+									r.summary = "Submit answer for a room"
+									r.operationID = "submitAnswerForRoom"
+									r.pathPattern = "/rooms/{code}/answer"
+									r.args = args
+									r.count = 1
+									return r, true
+								default:
+									return
+								}
+							}
+
+							elem = origElem
+						case 'j': // Prefix: "join"
+							origElem := elem
+							if l := len("join"); len(elem) >= l && elem[0:l] == "join" {
+								elem = elem[l:]
+							} else {
+								break
 							}
+
+							if len(elem) == 0 {
+								// Leaf node.
+								switch method {
+								case "POST":
+									r.name = JoinRoomOperation
+									r.summary = "Join a room"
+									r.operationID = "joinRoom"
+									r.pathPattern = "/rooms/{code}/join"
+									r.args = args
+									r.count = 1
+									return r, true
+								default:
+									return
+								}
+							}
+
+							elem = origElem
 						}
 
 						elem = origElem
@@ -317,6 +1460,71 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 					elem = origElem
 				}
 
+				elem = origElem
+			case 's': // Prefix: "stats/"
+				origElem := elem
+				if l := len("stats/"); len(elem) >= l && elem[0:l] == "stats/" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case 'c': // Prefix: "confusions"
+					origElem := elem
+					if l := len("confusions"); len(elem) >= l && elem[0:l] == "confusions" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = ListAuthorConfusionsOperation
+							r.summary = "List author confusions"
+							r.operationID = "listAuthorConfusions"
+							r.pathPattern = "/stats/confusions"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+
+					elem = origElem
+				case 'q': // Prefix: "quotes"
+					origElem := elem
+					if l := len("quotes"); len(elem) >= l && elem[0:l] == "quotes" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = ListQuoteStatsOperation
+							r.summary = "List quote stats"
+							r.operationID = "listQuoteStats"
+							r.pathPattern = "/stats/quotes"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+
+					elem = origElem
+				}
+
 				elem = origElem
 			}
 
@@ -2,50 +2,1520 @@
 
 package openapi
 
+import (
+	"io"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+type ApiKey struct {
+	APIKey string
+}
+
+// GetAPIKey returns the value of APIKey.
+func (s *ApiKey) GetAPIKey() string {
+	return s.APIKey
+}
+
+// SetAPIKey sets the value of APIKey.
+func (s *ApiKey) SetAPIKey(val string) {
+	s.APIKey = val
+}
+
+// The owner of a new API key.
+// Ref: #/components/schemas/ApiKeyRequest
+type ApiKeyRequest struct {
+	// Identifies the caller, for example as player in the game.
+	Subject string `json:"subject"`
+	Role    Role   `json:"role"`
+}
+
+// GetSubject returns the value of Subject.
+func (s *ApiKeyRequest) GetSubject() string {
+	return s.Subject
+}
+
+// GetRole returns the value of Role.
+func (s *ApiKeyRequest) GetRole() Role {
+	return s.Role
+}
+
+// SetSubject sets the value of Subject.
+func (s *ApiKeyRequest) SetSubject(val string) {
+	s.Subject = val
+}
+
+// SetRole sets the value of Role.
+func (s *ApiKeyRequest) SetRole(val Role) {
+	s.Role = val
+}
+
+// An author and the number of quotes they have in the catalogue.
+// Ref: #/components/schemas/Author
+type Author struct {
+	Name       string `json:"name"`
+	QuoteCount int    `json:"quote_count"`
+}
+
+// GetName returns the value of Name.
+func (s *Author) GetName() string {
+	return s.Name
+}
+
+// GetQuoteCount returns the value of QuoteCount.
+func (s *Author) GetQuoteCount() int {
+	return s.QuoteCount
+}
+
+// SetName sets the value of Name.
+func (s *Author) SetName(val string) {
+	s.Name = val
+}
+
+// SetQuoteCount sets the value of QuoteCount.
+func (s *Author) SetQuoteCount(val int) {
+	s.QuoteCount = val
+}
+
+// An author that players took for another author.
+// Ref: #/components/schemas/AuthorConfusion
+type AuthorConfusion struct {
+	// The author of the quotes.
+	Author string `json:"author"`
+	// The author players guessed instead.
+	GuessedAuthor string `json:"guessedAuthor"`
+	// The number of times players guessed guessedAuthor for a quote of author.
+	Count int `json:"count"`
+}
+
+// GetAuthor returns the value of Author.
+func (s *AuthorConfusion) GetAuthor() string {
+	return s.Author
+}
+
+// GetGuessedAuthor returns the value of GuessedAuthor.
+func (s *AuthorConfusion) GetGuessedAuthor() string {
+	return s.GuessedAuthor
+}
+
+// GetCount returns the value of Count.
+func (s *AuthorConfusion) GetCount() int {
+	return s.Count
+}
+
+// SetAuthor sets the value of Author.
+func (s *AuthorConfusion) SetAuthor(val string) {
+	s.Author = val
+}
+
+// SetGuessedAuthor sets the value of GuessedAuthor.
+func (s *AuthorConfusion) SetGuessedAuthor(val string) {
+	s.GuessedAuthor = val
+}
+
+// SetCount sets the value of Count.
+func (s *AuthorConfusion) SetCount(val int) {
+	s.Count = val
+}
+
+// A page of author confusions.
+// Ref: #/components/schemas/AuthorConfusionPage
+type AuthorConfusionPage struct {
+	Items []AuthorConfusion `json:"items"`
+	// The total number of confusions.
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// GetItems returns the value of Items.
+func (s *AuthorConfusionPage) GetItems() []AuthorConfusion {
+	return s.Items
+}
+
+// GetTotal returns the value of Total.
+func (s *AuthorConfusionPage) GetTotal() int {
+	return s.Total
+}
+
+// GetLimit returns the value of Limit.
+func (s *AuthorConfusionPage) GetLimit() int {
+	return s.Limit
+}
+
+// GetOffset returns the value of Offset.
+func (s *AuthorConfusionPage) GetOffset() int {
+	return s.Offset
+}
+
+// SetItems sets the value of Items.
+func (s *AuthorConfusionPage) SetItems(val []AuthorConfusion) {
+	s.Items = val
+}
+
+// SetTotal sets the value of Total.
+func (s *AuthorConfusionPage) SetTotal(val int) {
+	s.Total = val
+}
+
+// SetLimit sets the value of Limit.
+func (s *AuthorConfusionPage) SetLimit(val int) {
+	s.Limit = val
+}
+
+// SetOffset sets the value of Offset.
+func (s *AuthorConfusionPage) SetOffset(val int) {
+	s.Offset = val
+}
+
+func (*AuthorConfusionPage) listAuthorConfusionsRes() {}
+
+// A page of authors.
+// Ref: #/components/schemas/AuthorPage
+type AuthorPage struct {
+	Items []Author `json:"items"`
+	// The total number of authors.
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// GetItems returns the value of Items.
+func (s *AuthorPage) GetItems() []Author {
+	return s.Items
+}
+
+// GetTotal returns the value of Total.
+func (s *AuthorPage) GetTotal() int {
+	return s.Total
+}
+
+// GetLimit returns the value of Limit.
+func (s *AuthorPage) GetLimit() int {
+	return s.Limit
+}
+
+// GetOffset returns the value of Offset.
+func (s *AuthorPage) GetOffset() int {
+	return s.Offset
+}
+
+// SetItems sets the value of Items.
+func (s *AuthorPage) SetItems(val []Author) {
+	s.Items = val
+}
+
+// SetTotal sets the value of Total.
+func (s *AuthorPage) SetTotal(val int) {
+	s.Total = val
+}
+
+// SetLimit sets the value of Limit.
+func (s *AuthorPage) SetLimit(val int) {
+	s.Limit = val
+}
+
+// SetOffset sets the value of Offset.
+func (s *AuthorPage) SetOffset(val int) {
+	s.Offset = val
+}
+
+func (*AuthorPage) listAuthorsRes() {}
+
+// A snapshot of the database, which can be used as the database file of the api.
+// Ref: #/components/schemas/Backup
+type Backup struct {
+	// The file name of the backup in the backup directory.
+	Name string `json:"name"`
+	// The size of the backup in bytes.
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// GetName returns the value of Name.
+func (s *Backup) GetName() string {
+	return s.Name
+}
+
+// GetSize returns the value of Size.
+func (s *Backup) GetSize() int64 {
+	return s.Size
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *Backup) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// SetName sets the value of Name.
+func (s *Backup) SetName(val string) {
+	s.Name = val
+}
+
+// SetSize sets the value of Size.
+func (s *Backup) SetSize(val int64) {
+	s.Size = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *Backup) SetCreatedAt(val time.Time) {
+	s.CreatedAt = val
+}
+
+func (*Backup) createBackupRes() {}
+
+type BearerAuth struct {
+	Token string
+}
+
+// GetToken returns the value of Token.
+func (s *BearerAuth) GetToken() string {
+	return s.Token
+}
+
+// SetToken sets the value of Token.
+func (s *BearerAuth) SetToken(val string) {
+	s.Token = val
+}
+
+// The missing word of a quote in the fill in the blank game.
+// Ref: #/components/schemas/BlankAnswer
+type BlankAnswer struct {
+	ID     int    `json:"id"`
+	Answer string `json:"answer"`
+}
+
+// GetID returns the value of ID.
+func (s *BlankAnswer) GetID() int {
+	return s.ID
+}
+
+// GetAnswer returns the value of Answer.
+func (s *BlankAnswer) GetAnswer() string {
+	return s.Answer
+}
+
+// SetID sets the value of ID.
+func (s *BlankAnswer) SetID(val int) {
+	s.ID = val
+}
+
+// SetAnswer sets the value of Answer.
+func (s *BlankAnswer) SetAnswer(val string) {
+	s.Answer = val
+}
+
+// The result of a fill in the blank game, with a breakdown for every blank.
+// Ref: #/components/schemas/BlankResult
+type BlankResult struct {
+	ID     UUID                    `json:"id"`
+	Blanks []BlankResultBlanksItem `json:"blanks"`
+}
+
+// GetID returns the value of ID.
+func (s *BlankResult) GetID() UUID {
+	return s.ID
+}
+
+// GetBlanks returns the value of Blanks.
+func (s *BlankResult) GetBlanks() []BlankResultBlanksItem {
+	return s.Blanks
+}
+
+// SetID sets the value of ID.
+func (s *BlankResult) SetID(val UUID) {
+	s.ID = val
+}
+
+// SetBlanks sets the value of Blanks.
+func (s *BlankResult) SetBlanks(val []BlankResultBlanksItem) {
+	s.Blanks = val
+}
+
+func (*BlankResult) submitBlanksForQuoteGameRes() {}
+
+type BlankResultBlanksItem struct {
+	ID int `json:"id"`
+	// The complete quote.
+	Quote  string `json:"quote"`
+	Author string `json:"author"`
+	// The word the player filled in.
+	Answer string `json:"answer"`
+	// The word that was removed from the quote.
+	Expected string `json:"expected"`
+	Correct  bool   `json:"correct"`
+}
+
+// GetID returns the value of ID.
+func (s *BlankResultBlanksItem) GetID() int {
+	return s.ID
+}
+
+// GetQuote returns the value of Quote.
+func (s *BlankResultBlanksItem) GetQuote() string {
+	return s.Quote
+}
+
+// GetAuthor returns the value of Author.
+func (s *BlankResultBlanksItem) GetAuthor() string {
+	return s.Author
+}
+
+// GetAnswer returns the value of Answer.
+func (s *BlankResultBlanksItem) GetAnswer() string {
+	return s.Answer
+}
+
+// GetExpected returns the value of Expected.
+func (s *BlankResultBlanksItem) GetExpected() string {
+	return s.Expected
+}
+
+// GetCorrect returns the value of Correct.
+func (s *BlankResultBlanksItem) GetCorrect() bool {
+	return s.Correct
+}
+
+// SetID sets the value of ID.
+func (s *BlankResultBlanksItem) SetID(val int) {
+	s.ID = val
+}
+
+// SetQuote sets the value of Quote.
+func (s *BlankResultBlanksItem) SetQuote(val string) {
+	s.Quote = val
+}
+
+// SetAuthor sets the value of Author.
+func (s *BlankResultBlanksItem) SetAuthor(val string) {
+	s.Author = val
+}
+
+// SetAnswer sets the value of Answer.
+func (s *BlankResultBlanksItem) SetAnswer(val string) {
+	s.Answer = val
+}
+
+// SetExpected sets the value of Expected.
+func (s *BlankResultBlanksItem) SetExpected(val string) {
+	s.Expected = val
+}
+
+// SetCorrect sets the value of Correct.
+func (s *BlankResultBlanksItem) SetCorrect(val bool) {
+	s.Correct = val
+}
+
 type CreateNewQuoteGameOK struct {
+	ID     UUID                 `json:"id"`
+	Mode   GameMode             `json:"mode"`
+	Quotes []QuoteWithoutAuthor `json:"quotes"`
+	// The authors to match to the quotes. Only set in the match mode.
+	Authors []string `json:"authors"`
+}
+
+// GetID returns the value of ID.
+func (s *CreateNewQuoteGameOK) GetID() UUID {
+	return s.ID
+}
+
+// GetMode returns the value of Mode.
+func (s *CreateNewQuoteGameOK) GetMode() GameMode {
+	return s.Mode
+}
+
+// GetQuotes returns the value of Quotes.
+func (s *CreateNewQuoteGameOK) GetQuotes() []QuoteWithoutAuthor {
+	return s.Quotes
+}
+
+// GetAuthors returns the value of Authors.
+func (s *CreateNewQuoteGameOK) GetAuthors() []string {
+	return s.Authors
+}
+
+// SetID sets the value of ID.
+func (s *CreateNewQuoteGameOK) SetID(val UUID) {
+	s.ID = val
+}
+
+// SetMode sets the value of Mode.
+func (s *CreateNewQuoteGameOK) SetMode(val GameMode) {
+	s.Mode = val
+}
+
+// SetQuotes sets the value of Quotes.
+func (s *CreateNewQuoteGameOK) SetQuotes(val []QuoteWithoutAuthor) {
+	s.Quotes = val
+}
+
+// SetAuthors sets the value of Authors.
+func (s *CreateNewQuoteGameOK) SetAuthors(val []string) {
+	s.Authors = val
+}
+
+func (*CreateNewQuoteGameOK) createNewQuoteGameRes() {}
+
+// A newly created API key.
+// Ref: #/components/schemas/CreatedApiKey
+type CreatedApiKey struct {
+	ID UUID `json:"id"`
+	// The API key, to be sent in the X-Api-Key header.
+	Key     string `json:"key"`
+	Subject string `json:"subject"`
+	Role    Role   `json:"role"`
+}
+
+// GetID returns the value of ID.
+func (s *CreatedApiKey) GetID() UUID {
+	return s.ID
+}
+
+// GetKey returns the value of Key.
+func (s *CreatedApiKey) GetKey() string {
+	return s.Key
+}
+
+// GetSubject returns the value of Subject.
+func (s *CreatedApiKey) GetSubject() string {
+	return s.Subject
+}
+
+// GetRole returns the value of Role.
+func (s *CreatedApiKey) GetRole() Role {
+	return s.Role
+}
+
+// SetID sets the value of ID.
+func (s *CreatedApiKey) SetID(val UUID) {
+	s.ID = val
+}
+
+// SetKey sets the value of Key.
+func (s *CreatedApiKey) SetKey(val string) {
+	s.Key = val
+}
+
+// SetSubject sets the value of Subject.
+func (s *CreatedApiKey) SetSubject(val string) {
+	s.Subject = val
+}
+
+// SetRole sets the value of Role.
+func (s *CreatedApiKey) SetRole(val Role) {
+	s.Role = val
+}
+
+func (*CreatedApiKey) createApiKeyRes() {}
+
+// A quote from the local quote store together with its curation state.
+// Ref: #/components/schemas/CuratedQuote
+type CuratedQuote struct {
+	ID       int      `json:"id"`
+	Quote    string   `json:"quote"`
+	Author   string   `json:"author"`
+	Language Language `json:"language"`
+	// Where the quote originates from.
+	Source CuratedQuoteSource `json:"source"`
+	Hidden bool               `json:"hidden"`
+}
+
+// GetID returns the value of ID.
+func (s *CuratedQuote) GetID() int {
+	return s.ID
+}
+
+// GetQuote returns the value of Quote.
+func (s *CuratedQuote) GetQuote() string {
+	return s.Quote
+}
+
+// GetAuthor returns the value of Author.
+func (s *CuratedQuote) GetAuthor() string {
+	return s.Author
+}
+
+// GetLanguage returns the value of Language.
+func (s *CuratedQuote) GetLanguage() Language {
+	return s.Language
+}
+
+// GetSource returns the value of Source.
+func (s *CuratedQuote) GetSource() CuratedQuoteSource {
+	return s.Source
+}
+
+// GetHidden returns the value of Hidden.
+func (s *CuratedQuote) GetHidden() bool {
+	return s.Hidden
+}
+
+// SetID sets the value of ID.
+func (s *CuratedQuote) SetID(val int) {
+	s.ID = val
+}
+
+// SetQuote sets the value of Quote.
+func (s *CuratedQuote) SetQuote(val string) {
+	s.Quote = val
+}
+
+// SetAuthor sets the value of Author.
+func (s *CuratedQuote) SetAuthor(val string) {
+	s.Author = val
+}
+
+// SetLanguage sets the value of Language.
+func (s *CuratedQuote) SetLanguage(val Language) {
+	s.Language = val
+}
+
+// SetSource sets the value of Source.
+func (s *CuratedQuote) SetSource(val CuratedQuoteSource) {
+	s.Source = val
+}
+
+// SetHidden sets the value of Hidden.
+func (s *CuratedQuote) SetHidden(val bool) {
+	s.Hidden = val
+}
+
+func (*CuratedQuote) createQuoteRes() {}
+func (*CuratedQuote) updateQuoteRes() {}
+
+// Where the quote originates from.
+type CuratedQuoteSource string
+
+const (
+	CuratedQuoteSourceDummyjson CuratedQuoteSource = "dummyjson"
+	CuratedQuoteSourceAdmin     CuratedQuoteSource = "admin"
+)
+
+// AllValues returns all CuratedQuoteSource values.
+func (CuratedQuoteSource) AllValues() []CuratedQuoteSource {
+	return []CuratedQuoteSource{
+		CuratedQuoteSourceDummyjson,
+		CuratedQuoteSourceAdmin,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s CuratedQuoteSource) MarshalText() ([]byte, error) {
+	switch s {
+	case CuratedQuoteSourceDummyjson:
+		return []byte(s), nil
+	case CuratedQuoteSourceAdmin:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *CuratedQuoteSource) UnmarshalText(data []byte) error {
+	switch CuratedQuoteSource(data) {
+	case CuratedQuoteSourceDummyjson:
+		*s = CuratedQuoteSourceDummyjson
+		return nil
+	case CuratedQuoteSourceAdmin:
+		*s = CuratedQuoteSourceAdmin
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// A page of the leaderboard of the daily challenge of a single day.
+// Ref: #/components/schemas/DailyLeaderboard
+type DailyLeaderboard struct {
+	Date  time.Time               `json:"date"`
+	Items []DailyLeaderboardEntry `json:"items"`
+	// The total number of players that answered the challenge.
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// GetDate returns the value of Date.
+func (s *DailyLeaderboard) GetDate() time.Time {
+	return s.Date
+}
+
+// GetItems returns the value of Items.
+func (s *DailyLeaderboard) GetItems() []DailyLeaderboardEntry {
+	return s.Items
+}
+
+// GetTotal returns the value of Total.
+func (s *DailyLeaderboard) GetTotal() int {
+	return s.Total
+}
+
+// GetLimit returns the value of Limit.
+func (s *DailyLeaderboard) GetLimit() int {
+	return s.Limit
+}
+
+// GetOffset returns the value of Offset.
+func (s *DailyLeaderboard) GetOffset() int {
+	return s.Offset
+}
+
+// SetDate sets the value of Date.
+func (s *DailyLeaderboard) SetDate(val time.Time) {
+	s.Date = val
+}
+
+// SetItems sets the value of Items.
+func (s *DailyLeaderboard) SetItems(val []DailyLeaderboardEntry) {
+	s.Items = val
+}
+
+// SetTotal sets the value of Total.
+func (s *DailyLeaderboard) SetTotal(val int) {
+	s.Total = val
+}
+
+// SetLimit sets the value of Limit.
+func (s *DailyLeaderboard) SetLimit(val int) {
+	s.Limit = val
+}
+
+// SetOffset sets the value of Offset.
+func (s *DailyLeaderboard) SetOffset(val int) {
+	s.Offset = val
+}
+
+func (*DailyLeaderboard) getDailyLeaderboardRes() {}
+
+// The result of a player in the daily challenge.
+// Ref: #/components/schemas/DailyLeaderboardEntry
+type DailyLeaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	PlayerId string `json:"playerId"`
+	// The number of correct answers.
+	Score       int       `json:"score"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// GetRank returns the value of Rank.
+func (s *DailyLeaderboardEntry) GetRank() int {
+	return s.Rank
+}
+
+// GetPlayerId returns the value of PlayerId.
+func (s *DailyLeaderboardEntry) GetPlayerId() string {
+	return s.PlayerId
+}
+
+// GetScore returns the value of Score.
+func (s *DailyLeaderboardEntry) GetScore() int {
+	return s.Score
+}
+
+// GetCompletedAt returns the value of CompletedAt.
+func (s *DailyLeaderboardEntry) GetCompletedAt() time.Time {
+	return s.CompletedAt
+}
+
+// SetRank sets the value of Rank.
+func (s *DailyLeaderboardEntry) SetRank(val int) {
+	s.Rank = val
+}
+
+// SetPlayerId sets the value of PlayerId.
+func (s *DailyLeaderboardEntry) SetPlayerId(val string) {
+	s.PlayerId = val
+}
+
+// SetScore sets the value of Score.
+func (s *DailyLeaderboardEntry) SetScore(val int) {
+	s.Score = val
+}
+
+// SetCompletedAt sets the value of CompletedAt.
+func (s *DailyLeaderboardEntry) SetCompletedAt(val time.Time) {
+	s.CompletedAt = val
+}
+
+// The quote of a single day.
+// Ref: #/components/schemas/DailyQuote
+type DailyQuote struct {
+	Date  time.Time `json:"date"`
+	Quote Quote     `json:"quote"`
+}
+
+// GetDate returns the value of Date.
+func (s *DailyQuote) GetDate() time.Time {
+	return s.Date
+}
+
+// GetQuote returns the value of Quote.
+func (s *DailyQuote) GetQuote() Quote {
+	return s.Quote
+}
+
+// SetDate sets the value of Date.
+func (s *DailyQuote) SetDate(val time.Time) {
+	s.Date = val
+}
+
+// SetQuote sets the value of Quote.
+func (s *DailyQuote) SetQuote(val Quote) {
+	s.Quote = val
+}
+
+// A quote game of the daily challenge of a single day.
+// Ref: #/components/schemas/DailyQuoteGame
+type DailyQuoteGame struct {
+	Date    time.Time            `json:"date"`
 	ID      UUID                 `json:"id"`
 	Quotes  []QuoteWithoutAuthor `json:"quotes"`
 	Authors []string             `json:"authors"`
 }
 
-// GetID returns the value of ID.
-func (s *CreateNewQuoteGameOK) GetID() UUID {
-	return s.ID
+// GetDate returns the value of Date.
+func (s *DailyQuoteGame) GetDate() time.Time {
+	return s.Date
+}
+
+// GetID returns the value of ID.
+func (s *DailyQuoteGame) GetID() UUID {
+	return s.ID
+}
+
+// GetQuotes returns the value of Quotes.
+func (s *DailyQuoteGame) GetQuotes() []QuoteWithoutAuthor {
+	return s.Quotes
+}
+
+// GetAuthors returns the value of Authors.
+func (s *DailyQuoteGame) GetAuthors() []string {
+	return s.Authors
+}
+
+// SetDate sets the value of Date.
+func (s *DailyQuoteGame) SetDate(val time.Time) {
+	s.Date = val
+}
+
+// SetID sets the value of ID.
+func (s *DailyQuoteGame) SetID(val UUID) {
+	s.ID = val
+}
+
+// SetQuotes sets the value of Quotes.
+func (s *DailyQuoteGame) SetQuotes(val []QuoteWithoutAuthor) {
+	s.Quotes = val
+}
+
+// SetAuthors sets the value of Authors.
+func (s *DailyQuoteGame) SetAuthors(val []string) {
+	s.Authors = val
+}
+
+func (*DailyQuoteGame) createDailyQuoteGameRes() {}
+
+// DailyQuoteHeaders wraps DailyQuote with response headers.
+type DailyQuoteHeaders struct {
+	CacheControl string
+	ETag         string
+	Response     DailyQuote
+}
+
+// GetCacheControl returns the value of CacheControl.
+func (s *DailyQuoteHeaders) GetCacheControl() string {
+	return s.CacheControl
+}
+
+// GetETag returns the value of ETag.
+func (s *DailyQuoteHeaders) GetETag() string {
+	return s.ETag
+}
+
+// GetResponse returns the value of Response.
+func (s *DailyQuoteHeaders) GetResponse() DailyQuote {
+	return s.Response
+}
+
+// SetCacheControl sets the value of CacheControl.
+func (s *DailyQuoteHeaders) SetCacheControl(val string) {
+	s.CacheControl = val
+}
+
+// SetETag sets the value of ETag.
+func (s *DailyQuoteHeaders) SetETag(val string) {
+	s.ETag = val
+}
+
+// SetResponse sets the value of Response.
+func (s *DailyQuoteHeaders) SetResponse(val DailyQuote) {
+	s.Response = val
+}
+
+func (*DailyQuoteHeaders) getDailyQuoteRes() {}
+
+// DeleteQuoteNoContent is response for DeleteQuote operation.
+type DeleteQuoteNoContent struct{}
+
+func (*DeleteQuoteNoContent) deleteQuoteRes() {}
+
+type ExportGamesFormat string
+
+const (
+	ExportGamesFormatCsv    ExportGamesFormat = "csv"
+	ExportGamesFormatNdjson ExportGamesFormat = "ndjson"
+)
+
+// AllValues returns all ExportGamesFormat values.
+func (ExportGamesFormat) AllValues() []ExportGamesFormat {
+	return []ExportGamesFormat{
+		ExportGamesFormatCsv,
+		ExportGamesFormatNdjson,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s ExportGamesFormat) MarshalText() ([]byte, error) {
+	switch s {
+	case ExportGamesFormatCsv:
+		return []byte(s), nil
+	case ExportGamesFormatNdjson:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *ExportGamesFormat) UnmarshalText(data []byte) error {
+	switch ExportGamesFormat(data) {
+	case ExportGamesFormatCsv:
+		*s = ExportGamesFormatCsv
+		return nil
+	case ExportGamesFormatNdjson:
+		*s = ExportGamesFormatNdjson
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+type ExportGamesOKApplicationXNdjson struct {
+	Data io.Reader
+}
+
+// Read reads data from the Data reader.
+//
+// Kept to satisfy the io.Reader interface.
+func (s ExportGamesOKApplicationXNdjson) Read(p []byte) (n int, err error) {
+	if s.Data == nil {
+		return 0, io.EOF
+	}
+	return s.Data.Read(p)
+}
+
+func (*ExportGamesOKApplicationXNdjson) exportGamesRes() {}
+
+type ExportGamesOKTextCsv struct {
+	Data io.Reader
+}
+
+// Read reads data from the Data reader.
+//
+// Kept to satisfy the io.Reader interface.
+func (s ExportGamesOKTextCsv) Read(p []byte) (n int, err error) {
+	if s.Data == nil {
+		return 0, io.EOF
+	}
+	return s.Data.Read(p)
+}
+
+func (*ExportGamesOKTextCsv) exportGamesRes() {}
+
+// The mode of a quote game. In `match`, the player matches the quotes to the authors. In
+// `multiple_choice`, the player picks the author of every quote from its candidate authors. In
+// `fill_in_the_blank`, the player fills in the missing word of every quote.
+// Ref: #/components/schemas/GameMode
+type GameMode string
+
+const (
+	GameModeMatch          GameMode = "match"
+	GameModeMultipleChoice GameMode = "multiple_choice"
+	GameModeFillInTheBlank GameMode = "fill_in_the_blank"
+)
+
+// AllValues returns all GameMode values.
+func (GameMode) AllValues() []GameMode {
+	return []GameMode{
+		GameModeMatch,
+		GameModeMultipleChoice,
+		GameModeFillInTheBlank,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s GameMode) MarshalText() ([]byte, error) {
+	switch s {
+	case GameModeMatch:
+		return []byte(s), nil
+	case GameModeMultipleChoice:
+		return []byte(s), nil
+	case GameModeFillInTheBlank:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *GameMode) UnmarshalText(data []byte) error {
+	switch GameMode(data) {
+	case GameModeMatch:
+		*s = GameModeMatch
+		return nil
+	case GameModeMultipleChoice:
+		*s = GameModeMultipleChoice
+		return nil
+	case GameModeFillInTheBlank:
+		*s = GameModeFillInTheBlank
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// GetDailyQuoteNotModified is response for GetDailyQuote operation.
+type GetDailyQuoteNotModified struct {
+	CacheControl string
+	ETag         string
+}
+
+// GetCacheControl returns the value of CacheControl.
+func (s *GetDailyQuoteNotModified) GetCacheControl() string {
+	return s.CacheControl
+}
+
+// GetETag returns the value of ETag.
+func (s *GetDailyQuoteNotModified) GetETag() string {
+	return s.ETag
+}
+
+// SetCacheControl sets the value of CacheControl.
+func (s *GetDailyQuoteNotModified) SetCacheControl(val string) {
+	s.CacheControl = val
+}
+
+// SetETag sets the value of ETag.
+func (s *GetDailyQuoteNotModified) SetETag(val string) {
+	s.ETag = val
+}
+
+func (*GetDailyQuoteNotModified) getDailyQuoteRes() {}
+
+// A hint used in a quote game.
+// Ref: #/components/schemas/Hint
+type Hint struct {
+	Type    HintType `json:"type"`
+	QuoteId int      `json:"quoteId"`
+	// For `reveal_pair`, the author of the quote. For `eliminate_author`, an author that did not say the
+	// quote.
+	Author string `json:"author"`
+	// The number of points deducted from the score.
+	Cost int `json:"cost"`
+}
+
+// GetType returns the value of Type.
+func (s *Hint) GetType() HintType {
+	return s.Type
+}
+
+// GetQuoteId returns the value of QuoteId.
+func (s *Hint) GetQuoteId() int {
+	return s.QuoteId
+}
+
+// GetAuthor returns the value of Author.
+func (s *Hint) GetAuthor() string {
+	return s.Author
+}
+
+// GetCost returns the value of Cost.
+func (s *Hint) GetCost() int {
+	return s.Cost
+}
+
+// SetType sets the value of Type.
+func (s *Hint) SetType(val HintType) {
+	s.Type = val
+}
+
+// SetQuoteId sets the value of QuoteId.
+func (s *Hint) SetQuoteId(val int) {
+	s.QuoteId = val
+}
+
+// SetAuthor sets the value of Author.
+func (s *Hint) SetAuthor(val string) {
+	s.Author = val
+}
+
+// SetCost sets the value of Cost.
+func (s *Hint) SetCost(val int) {
+	s.Cost = val
+}
+
+func (*Hint) requestHintForQuoteGameRes() {}
+
+// Ref: #/components/schemas/HintRequest
+type HintRequest struct {
+	Type HintType `json:"type"`
+	// The quote the hint should be about. When not given, a random quote that can still get a hint of
+	// this type is chosen.
+	QuoteId OptInt `json:"quoteId"`
+}
+
+// GetType returns the value of Type.
+func (s *HintRequest) GetType() HintType {
+	return s.Type
+}
+
+// GetQuoteId returns the value of QuoteId.
+func (s *HintRequest) GetQuoteId() OptInt {
+	return s.QuoteId
+}
+
+// SetType sets the value of Type.
+func (s *HintRequest) SetType(val HintType) {
+	s.Type = val
+}
+
+// SetQuoteId sets the value of QuoteId.
+func (s *HintRequest) SetQuoteId(val OptInt) {
+	s.QuoteId = val
+}
+
+// A `reveal_pair` hint reveals the author of a quote. An `eliminate_author` hint reveals an author
+// that did not say a quote.
+// Ref: #/components/schemas/HintType
+type HintType string
+
+const (
+	HintTypeRevealPair      HintType = "reveal_pair"
+	HintTypeEliminateAuthor HintType = "eliminate_author"
+)
+
+// AllValues returns all HintType values.
+func (HintType) AllValues() []HintType {
+	return []HintType{
+		HintTypeRevealPair,
+		HintTypeEliminateAuthor,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s HintType) MarshalText() ([]byte, error) {
+	switch s {
+	case HintTypeRevealPair:
+		return []byte(s), nil
+	case HintTypeEliminateAuthor:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *HintType) UnmarshalText(data []byte) error {
+	switch HintType(data) {
+	case HintTypeRevealPair:
+		*s = HintTypeRevealPair
+		return nil
+	case HintTypeEliminateAuthor:
+		*s = HintTypeEliminateAuthor
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// The ISO 639-1 code of the language a quote is written in.
+// Ref: #/components/schemas/Language
+type Language string
+
+const (
+	LanguageEn Language = "en"
+	LanguageNl Language = "nl"
+)
+
+// AllValues returns all Language values.
+func (Language) AllValues() []Language {
+	return []Language{
+		LanguageEn,
+		LanguageNl,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Language) MarshalText() ([]byte, error) {
+	switch s {
+	case LanguageEn:
+		return []byte(s), nil
+	case LanguageNl:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
 }
 
-// GetQuotes returns the value of Quotes.
-func (s *CreateNewQuoteGameOK) GetQuotes() []QuoteWithoutAuthor {
-	return s.Quotes
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Language) UnmarshalText(data []byte) error {
+	switch Language(data) {
+	case LanguageEn:
+		*s = LanguageEn
+		return nil
+	case LanguageNl:
+		*s = LanguageNl
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
 }
 
-// GetAuthors returns the value of Authors.
-func (s *CreateNewQuoteGameOK) GetAuthors() []string {
-	return s.Authors
+type ListBackupsOKApplicationJSON []Backup
+
+func (*ListBackupsOKApplicationJSON) listBackupsRes() {}
+
+// NewOptBool returns new OptBool with value set to v.
+func NewOptBool(v bool) OptBool {
+	return OptBool{
+		Value: v,
+		Set:   true,
+	}
 }
 
-// SetID sets the value of ID.
-func (s *CreateNewQuoteGameOK) SetID(val UUID) {
-	s.ID = val
+// OptBool is optional bool.
+type OptBool struct {
+	Value bool
+	Set   bool
 }
 
-// SetQuotes sets the value of Quotes.
-func (s *CreateNewQuoteGameOK) SetQuotes(val []QuoteWithoutAuthor) {
-	s.Quotes = val
+// IsSet returns true if OptBool was set.
+func (o OptBool) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptBool) Reset() {
+	var v bool
+	o.Value = v
+	o.Set = false
 }
 
-// SetAuthors sets the value of Authors.
-func (s *CreateNewQuoteGameOK) SetAuthors(val []string) {
-	s.Authors = val
+// SetTo sets value to v.
+func (o *OptBool) SetTo(v bool) {
+	o.Set = true
+	o.Value = v
 }
 
-func (*CreateNewQuoteGameOK) createNewQuoteGameRes() {}
+// Get returns value and boolean that denotes whether value was set.
+func (o OptBool) Get() (v bool, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptBool) Or(d bool) bool {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptDate returns new OptDate with value set to v.
+func NewOptDate(v time.Time) OptDate {
+	return OptDate{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptDate is optional time.Time.
+type OptDate struct {
+	Value time.Time
+	Set   bool
+}
+
+// IsSet returns true if OptDate was set.
+func (o OptDate) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptDate) Reset() {
+	var v time.Time
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptDate) SetTo(v time.Time) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptDate) Get() (v time.Time, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptDate) Or(d time.Time) time.Time {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptDateTime returns new OptDateTime with value set to v.
+func NewOptDateTime(v time.Time) OptDateTime {
+	return OptDateTime{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptDateTime is optional time.Time.
+type OptDateTime struct {
+	Value time.Time
+	Set   bool
+}
+
+// IsSet returns true if OptDateTime was set.
+func (o OptDateTime) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptDateTime) Reset() {
+	var v time.Time
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptDateTime) SetTo(v time.Time) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptDateTime) Get() (v time.Time, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptDateTime) Or(d time.Time) time.Time {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptGameMode returns new OptGameMode with value set to v.
+func NewOptGameMode(v GameMode) OptGameMode {
+	return OptGameMode{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptGameMode is optional GameMode.
+type OptGameMode struct {
+	Value GameMode
+	Set   bool
+}
+
+// IsSet returns true if OptGameMode was set.
+func (o OptGameMode) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptGameMode) Reset() {
+	var v GameMode
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptGameMode) SetTo(v GameMode) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptGameMode) Get() (v GameMode, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptGameMode) Or(d GameMode) GameMode {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptInt returns new OptInt with value set to v.
+func NewOptInt(v int) OptInt {
+	return OptInt{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptInt is optional int.
+type OptInt struct {
+	Value int
+	Set   bool
+}
+
+// IsSet returns true if OptInt was set.
+func (o OptInt) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptInt) Reset() {
+	var v int
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptInt) SetTo(v int) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptInt) Get() (v int, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptInt) Or(d int) int {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptLanguage returns new OptLanguage with value set to v.
+func NewOptLanguage(v Language) OptLanguage {
+	return OptLanguage{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptLanguage is optional Language.
+type OptLanguage struct {
+	Value Language
+	Set   bool
+}
+
+// IsSet returns true if OptLanguage was set.
+func (o OptLanguage) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptLanguage) Reset() {
+	var v Language
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptLanguage) SetTo(v Language) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptLanguage) Get() (v Language, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptLanguage) Or(d Language) Language {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptString returns new OptString with value set to v.
+func NewOptString(v string) OptString {
+	return OptString{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptString is optional string.
+type OptString struct {
+	Value string
+	Set   bool
+}
+
+// IsSet returns true if OptString was set.
+func (o OptString) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptString) Reset() {
+	var v string
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptString) SetTo(v string) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptString) Get() (v string, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptString) Or(d string) string {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
 
 // A basic quote.
 // Ref: #/components/schemas/Quote
 type Quote struct {
-	ID     int    `json:"id"`
-	Quote  string `json:"quote"`
-	Author string `json:"author"`
+	ID       int      `json:"id"`
+	Quote    string   `json:"quote"`
+	Author   string   `json:"author"`
+	Language Language `json:"language"`
 }
 
 // GetID returns the value of ID.
@@ -63,6 +1533,11 @@ func (s *Quote) GetAuthor() string {
 	return s.Author
 }
 
+// GetLanguage returns the value of Language.
+func (s *Quote) GetLanguage() Language {
+	return s.Language
+}
+
 // SetID sets the value of ID.
 func (s *Quote) SetID(val int) {
 	s.ID = val
@@ -78,8 +1553,64 @@ func (s *Quote) SetAuthor(val string) {
 	s.Author = val
 }
 
+// SetLanguage sets the value of Language.
+func (s *Quote) SetLanguage(val Language) {
+	s.Language = val
+}
+
+func (*Quote) getQuoteRes()       {}
 func (*Quote) getRandomQuoteRes() {}
 
+// The fields an admin can set when creating or editing a quote.
+// Ref: #/components/schemas/QuoteEdit
+type QuoteEdit struct {
+	Quote    string      `json:"quote"`
+	Author   string      `json:"author"`
+	Language OptLanguage `json:"language"`
+	// Hidden quotes are never shown to players.
+	Hidden OptBool `json:"hidden"`
+}
+
+// GetQuote returns the value of Quote.
+func (s *QuoteEdit) GetQuote() string {
+	return s.Quote
+}
+
+// GetAuthor returns the value of Author.
+func (s *QuoteEdit) GetAuthor() string {
+	return s.Author
+}
+
+// GetLanguage returns the value of Language.
+func (s *QuoteEdit) GetLanguage() OptLanguage {
+	return s.Language
+}
+
+// GetHidden returns the value of Hidden.
+func (s *QuoteEdit) GetHidden() OptBool {
+	return s.Hidden
+}
+
+// SetQuote sets the value of Quote.
+func (s *QuoteEdit) SetQuote(val string) {
+	s.Quote = val
+}
+
+// SetAuthor sets the value of Author.
+func (s *QuoteEdit) SetAuthor(val string) {
+	s.Author = val
+}
+
+// SetLanguage sets the value of Language.
+func (s *QuoteEdit) SetLanguage(val OptLanguage) {
+	s.Language = val
+}
+
+// SetHidden sets the value of Hidden.
+func (s *QuoteEdit) SetHidden(val OptBool) {
+	s.Hidden = val
+}
+
 // An answer to the quote game.
 // Ref: #/components/schemas/QuoteGameAnswer
 type QuoteGameAnswer struct {
@@ -103,80 +1634,287 @@ func (s *QuoteGameAnswer) SetID(val int) {
 }
 
 // SetAuthor sets the value of Author.
-func (s *QuoteGameAnswer) SetAuthor(val string) {
+func (s *QuoteGameAnswer) SetAuthor(val string) {
+	s.Author = val
+}
+
+// The result of a quote game.
+// Ref: #/components/schemas/QuoteGameResult
+type QuoteGameResult struct {
+	ID UUID `json:"id"`
+	// The number of correct answers minus the costs of the used hints, but at least zero.
+	Score int `json:"score"`
+	// The hints used during the game.
+	Hints   []Hint                       `json:"hints"`
+	Answers []QuoteGameResultAnswersItem `json:"answers"`
+}
+
+// GetID returns the value of ID.
+func (s *QuoteGameResult) GetID() UUID {
+	return s.ID
+}
+
+// GetScore returns the value of Score.
+func (s *QuoteGameResult) GetScore() int {
+	return s.Score
+}
+
+// GetHints returns the value of Hints.
+func (s *QuoteGameResult) GetHints() []Hint {
+	return s.Hints
+}
+
+// GetAnswers returns the value of Answers.
+func (s *QuoteGameResult) GetAnswers() []QuoteGameResultAnswersItem {
+	return s.Answers
+}
+
+// SetID sets the value of ID.
+func (s *QuoteGameResult) SetID(val UUID) {
+	s.ID = val
+}
+
+// SetScore sets the value of Score.
+func (s *QuoteGameResult) SetScore(val int) {
+	s.Score = val
+}
+
+// SetHints sets the value of Hints.
+func (s *QuoteGameResult) SetHints(val []Hint) {
+	s.Hints = val
+}
+
+// SetAnswers sets the value of Answers.
+func (s *QuoteGameResult) SetAnswers(val []QuoteGameResultAnswersItem) {
+	s.Answers = val
+}
+
+func (*QuoteGameResult) submitAnswerForQuoteGameRes() {}
+func (*QuoteGameResult) submitAnswerForRoomRes()      {}
+
+type QuoteGameResultAnswersItem struct {
+	ID           int    `json:"id"`
+	Correct      bool   `json:"correct"`
+	ActualAuthor string `json:"actual_author"`
+}
+
+// GetID returns the value of ID.
+func (s *QuoteGameResultAnswersItem) GetID() int {
+	return s.ID
+}
+
+// GetCorrect returns the value of Correct.
+func (s *QuoteGameResultAnswersItem) GetCorrect() bool {
+	return s.Correct
+}
+
+// GetActualAuthor returns the value of ActualAuthor.
+func (s *QuoteGameResultAnswersItem) GetActualAuthor() string {
+	return s.ActualAuthor
+}
+
+// SetID sets the value of ID.
+func (s *QuoteGameResultAnswersItem) SetID(val int) {
+	s.ID = val
+}
+
+// SetCorrect sets the value of Correct.
+func (s *QuoteGameResultAnswersItem) SetCorrect(val bool) {
+	s.Correct = val
+}
+
+// SetActualAuthor sets the value of ActualAuthor.
+func (s *QuoteGameResultAnswersItem) SetActualAuthor(val string) {
+	s.ActualAuthor = val
+}
+
+// A page of quotes.
+// Ref: #/components/schemas/QuotePage
+type QuotePage struct {
+	Items []Quote `json:"items"`
+	// The total number of quotes matching the filters.
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// GetItems returns the value of Items.
+func (s *QuotePage) GetItems() []Quote {
+	return s.Items
+}
+
+// GetTotal returns the value of Total.
+func (s *QuotePage) GetTotal() int {
+	return s.Total
+}
+
+// GetLimit returns the value of Limit.
+func (s *QuotePage) GetLimit() int {
+	return s.Limit
+}
+
+// GetOffset returns the value of Offset.
+func (s *QuotePage) GetOffset() int {
+	return s.Offset
+}
+
+// SetItems sets the value of Items.
+func (s *QuotePage) SetItems(val []Quote) {
+	s.Items = val
+}
+
+// SetTotal sets the value of Total.
+func (s *QuotePage) SetTotal(val int) {
+	s.Total = val
+}
+
+// SetLimit sets the value of Limit.
+func (s *QuotePage) SetLimit(val int) {
+	s.Limit = val
+}
+
+// SetOffset sets the value of Offset.
+func (s *QuotePage) SetOffset(val int) {
+	s.Offset = val
+}
+
+func (*QuotePage) listQuotesRes() {}
+
+// How a quote is answered.
+// Ref: #/components/schemas/QuoteStats
+type QuoteStats struct {
+	QuoteId int `json:"quoteId"`
+	// Not set when the quote is not in the local catalogue.
+	Quote OptString `json:"quote"`
+	// Not set when the quote is not in the local catalogue.
+	Author OptString `json:"author"`
+	// The number of answers to the quote in completed games.
+	Attempts int `json:"attempts"`
+	// The number of correct answers.
+	Correct int `json:"correct"`
+	// The share of the answers that was correct.
+	Accuracy float64 `json:"accuracy"`
+}
+
+// GetQuoteId returns the value of QuoteId.
+func (s *QuoteStats) GetQuoteId() int {
+	return s.QuoteId
+}
+
+// GetQuote returns the value of Quote.
+func (s *QuoteStats) GetQuote() OptString {
+	return s.Quote
+}
+
+// GetAuthor returns the value of Author.
+func (s *QuoteStats) GetAuthor() OptString {
+	return s.Author
+}
+
+// GetAttempts returns the value of Attempts.
+func (s *QuoteStats) GetAttempts() int {
+	return s.Attempts
+}
+
+// GetCorrect returns the value of Correct.
+func (s *QuoteStats) GetCorrect() int {
+	return s.Correct
+}
+
+// GetAccuracy returns the value of Accuracy.
+func (s *QuoteStats) GetAccuracy() float64 {
+	return s.Accuracy
+}
+
+// SetQuoteId sets the value of QuoteId.
+func (s *QuoteStats) SetQuoteId(val int) {
+	s.QuoteId = val
+}
+
+// SetQuote sets the value of Quote.
+func (s *QuoteStats) SetQuote(val OptString) {
+	s.Quote = val
+}
+
+// SetAuthor sets the value of Author.
+func (s *QuoteStats) SetAuthor(val OptString) {
 	s.Author = val
 }
 
-// The result of a quote game.
-// Ref: #/components/schemas/QuoteGameResult
-type QuoteGameResult struct {
-	ID      UUID                         `json:"id"`
-	Answers []QuoteGameResultAnswersItem `json:"answers"`
+// SetAttempts sets the value of Attempts.
+func (s *QuoteStats) SetAttempts(val int) {
+	s.Attempts = val
 }
 
-// GetID returns the value of ID.
-func (s *QuoteGameResult) GetID() UUID {
-	return s.ID
+// SetCorrect sets the value of Correct.
+func (s *QuoteStats) SetCorrect(val int) {
+	s.Correct = val
 }
 
-// GetAnswers returns the value of Answers.
-func (s *QuoteGameResult) GetAnswers() []QuoteGameResultAnswersItem {
-	return s.Answers
+// SetAccuracy sets the value of Accuracy.
+func (s *QuoteStats) SetAccuracy(val float64) {
+	s.Accuracy = val
 }
 
-// SetID sets the value of ID.
-func (s *QuoteGameResult) SetID(val UUID) {
-	s.ID = val
+// A page of quote stats.
+// Ref: #/components/schemas/QuoteStatsPage
+type QuoteStatsPage struct {
+	Items []QuoteStats `json:"items"`
+	// The total number of quotes with enough attempts.
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
 }
 
-// SetAnswers sets the value of Answers.
-func (s *QuoteGameResult) SetAnswers(val []QuoteGameResultAnswersItem) {
-	s.Answers = val
+// GetItems returns the value of Items.
+func (s *QuoteStatsPage) GetItems() []QuoteStats {
+	return s.Items
 }
 
-func (*QuoteGameResult) submitAnswerForQuoteGameRes() {}
-
-type QuoteGameResultAnswersItem struct {
-	ID           int    `json:"id"`
-	Correct      bool   `json:"correct"`
-	ActualAuthor string `json:"actual_author"`
+// GetTotal returns the value of Total.
+func (s *QuoteStatsPage) GetTotal() int {
+	return s.Total
 }
 
-// GetID returns the value of ID.
-func (s *QuoteGameResultAnswersItem) GetID() int {
-	return s.ID
+// GetLimit returns the value of Limit.
+func (s *QuoteStatsPage) GetLimit() int {
+	return s.Limit
 }
 
-// GetCorrect returns the value of Correct.
-func (s *QuoteGameResultAnswersItem) GetCorrect() bool {
-	return s.Correct
+// GetOffset returns the value of Offset.
+func (s *QuoteStatsPage) GetOffset() int {
+	return s.Offset
 }
 
-// GetActualAuthor returns the value of ActualAuthor.
-func (s *QuoteGameResultAnswersItem) GetActualAuthor() string {
-	return s.ActualAuthor
+// SetItems sets the value of Items.
+func (s *QuoteStatsPage) SetItems(val []QuoteStats) {
+	s.Items = val
 }
 
-// SetID sets the value of ID.
-func (s *QuoteGameResultAnswersItem) SetID(val int) {
-	s.ID = val
+// SetTotal sets the value of Total.
+func (s *QuoteStatsPage) SetTotal(val int) {
+	s.Total = val
 }
 
-// SetCorrect sets the value of Correct.
-func (s *QuoteGameResultAnswersItem) SetCorrect(val bool) {
-	s.Correct = val
+// SetLimit sets the value of Limit.
+func (s *QuoteStatsPage) SetLimit(val int) {
+	s.Limit = val
 }
 
-// SetActualAuthor sets the value of ActualAuthor.
-func (s *QuoteGameResultAnswersItem) SetActualAuthor(val string) {
-	s.ActualAuthor = val
+// SetOffset sets the value of Offset.
+func (s *QuoteStatsPage) SetOffset(val int) {
+	s.Offset = val
 }
 
+func (*QuoteStatsPage) listQuoteStatsRes() {}
+
 // QuoteWithoutAuthor is used by the quote game.
 // Ref: #/components/schemas/QuoteWithoutAuthor
 type QuoteWithoutAuthor struct {
 	ID    int    `json:"id"`
 	Quote string `json:"quote"`
+	// The candidate authors of the quote, of which one is right. Only set in the multiple_choice mode.
+	Choices []string `json:"choices"`
 }
 
 // GetID returns the value of ID.
@@ -189,6 +1927,11 @@ func (s *QuoteWithoutAuthor) GetQuote() string {
 	return s.Quote
 }
 
+// GetChoices returns the value of Choices.
+func (s *QuoteWithoutAuthor) GetChoices() []string {
+	return s.Choices
+}
+
 // SetID sets the value of ID.
 func (s *QuoteWithoutAuthor) SetID(val int) {
 	s.ID = val
@@ -199,8 +1942,88 @@ func (s *QuoteWithoutAuthor) SetQuote(val string) {
 	s.Quote = val
 }
 
+// SetChoices sets the value of Choices.
+func (s *QuoteWithoutAuthor) SetChoices(val []string) {
+	s.Choices = val
+}
+
+type R401 struct {
+	Message string `json:"message"`
+	// The message in the language preferred in the Accept-Language header, for humans. Only set when the
+	// message is known.
+	Detail OptString `json:"detail"`
+}
+
+// GetMessage returns the value of Message.
+func (s *R401) GetMessage() string {
+	return s.Message
+}
+
+// GetDetail returns the value of Detail.
+func (s *R401) GetDetail() OptString {
+	return s.Detail
+}
+
+// SetMessage sets the value of Message.
+func (s *R401) SetMessage(val string) {
+	s.Message = val
+}
+
+// SetDetail sets the value of Detail.
+func (s *R401) SetDetail(val OptString) {
+	s.Detail = val
+}
+
+func (*R401) createApiKeyRes() {}
+func (*R401) createBackupRes() {}
+func (*R401) createQuoteRes()  {}
+func (*R401) deleteQuoteRes()  {}
+func (*R401) exportGamesRes()  {}
+func (*R401) listBackupsRes()  {}
+func (*R401) revokeApiKeyRes() {}
+func (*R401) updateQuoteRes()  {}
+
+type R403 struct {
+	Message string `json:"message"`
+	// The message in the language preferred in the Accept-Language header, for humans. Only set when the
+	// message is known.
+	Detail OptString `json:"detail"`
+}
+
+// GetMessage returns the value of Message.
+func (s *R403) GetMessage() string {
+	return s.Message
+}
+
+// GetDetail returns the value of Detail.
+func (s *R403) GetDetail() OptString {
+	return s.Detail
+}
+
+// SetMessage sets the value of Message.
+func (s *R403) SetMessage(val string) {
+	s.Message = val
+}
+
+// SetDetail sets the value of Detail.
+func (s *R403) SetDetail(val OptString) {
+	s.Detail = val
+}
+
+func (*R403) createApiKeyRes() {}
+func (*R403) createBackupRes() {}
+func (*R403) createQuoteRes()  {}
+func (*R403) deleteQuoteRes()  {}
+func (*R403) exportGamesRes()  {}
+func (*R403) listBackupsRes()  {}
+func (*R403) revokeApiKeyRes() {}
+func (*R403) updateQuoteRes()  {}
+
 type R404 struct {
 	Message string `json:"message"`
+	// The message in the language preferred in the Accept-Language header, for humans. Only set when the
+	// message is known.
+	Detail OptString `json:"detail"`
 }
 
 // GetMessage returns the value of Message.
@@ -208,16 +2031,102 @@ func (s *R404) GetMessage() string {
 	return s.Message
 }
 
+// GetDetail returns the value of Detail.
+func (s *R404) GetDetail() OptString {
+	return s.Detail
+}
+
 // SetMessage sets the value of Message.
 func (s *R404) SetMessage(val string) {
 	s.Message = val
 }
 
+// SetDetail sets the value of Detail.
+func (s *R404) SetDetail(val OptString) {
+	s.Detail = val
+}
+
+func (*R404) deleteQuoteRes()              {}
+func (*R404) getDailyQuoteRes()            {}
+func (*R404) getQuoteRes()                 {}
+func (*R404) getRoomResultRes()            {}
+func (*R404) joinRoomRes()                 {}
+func (*R404) requestHintForQuoteGameRes()  {}
+func (*R404) revokeApiKeyRes()             {}
 func (*R404) submitAnswerForQuoteGameRes() {}
+func (*R404) submitAnswerForRoomRes()      {}
+func (*R404) submitBlanksForQuoteGameRes() {}
+func (*R404) updateQuoteRes()              {}
+
+type R409 struct {
+	Message string `json:"message"`
+	// The message in the language preferred in the Accept-Language header, for humans. Only set when the
+	// message is known.
+	Detail OptString `json:"detail"`
+}
+
+// GetMessage returns the value of Message.
+func (s *R409) GetMessage() string {
+	return s.Message
+}
+
+// GetDetail returns the value of Detail.
+func (s *R409) GetDetail() OptString {
+	return s.Detail
+}
+
+// SetMessage sets the value of Message.
+func (s *R409) SetMessage(val string) {
+	s.Message = val
+}
+
+// SetDetail sets the value of Detail.
+func (s *R409) SetDetail(val OptString) {
+	s.Detail = val
+}
+
+func (*R409) createDailyQuoteGameRes()     {}
+func (*R409) createNewQuoteGameRes()       {}
+func (*R409) submitAnswerForQuoteGameRes() {}
+func (*R409) submitBlanksForQuoteGameRes() {}
+
+type R413 struct {
+	Message string `json:"message"`
+	// The message in the language preferred in the Accept-Language header, for humans. Only set when the
+	// message is known.
+	Detail OptString `json:"detail"`
+}
+
+// GetMessage returns the value of Message.
+func (s *R413) GetMessage() string {
+	return s.Message
+}
+
+// GetDetail returns the value of Detail.
+func (s *R413) GetDetail() OptString {
+	return s.Detail
+}
+
+// SetMessage sets the value of Message.
+func (s *R413) SetMessage(val string) {
+	s.Message = val
+}
+
+// SetDetail sets the value of Detail.
+func (s *R413) SetDetail(val OptString) {
+	s.Detail = val
+}
+
+func (*R413) createNewQuoteGameRes()       {}
+func (*R413) submitAnswerForQuoteGameRes() {}
+func (*R413) submitBlanksForQuoteGameRes() {}
 
 type R422 struct {
 	Errors  []R422ErrorsItem `json:"errors"`
 	Message string           `json:"message"`
+	// The message in the language preferred in the Accept-Language header, for humans. Only set when the
+	// message is known.
+	Detail OptString `json:"detail"`
 }
 
 // GetErrors returns the value of Errors.
@@ -230,6 +2139,11 @@ func (s *R422) GetMessage() string {
 	return s.Message
 }
 
+// GetDetail returns the value of Detail.
+func (s *R422) GetDetail() OptString {
+	return s.Detail
+}
+
 // SetErrors sets the value of Errors.
 func (s *R422) SetErrors(val []R422ErrorsItem) {
 	s.Errors = val
@@ -240,7 +2154,19 @@ func (s *R422) SetMessage(val string) {
 	s.Message = val
 }
 
+// SetDetail sets the value of Detail.
+func (s *R422) SetDetail(val OptString) {
+	s.Detail = val
+}
+
+func (*R422) createDailyQuoteGameRes()     {}
+func (*R422) createNewQuoteGameRes()       {}
+func (*R422) createRoomRes()               {}
+func (*R422) joinRoomRes()                 {}
+func (*R422) requestHintForQuoteGameRes()  {}
 func (*R422) submitAnswerForQuoteGameRes() {}
+func (*R422) submitAnswerForRoomRes()      {}
+func (*R422) submitBlanksForQuoteGameRes() {}
 
 type R422ErrorsItem struct {
 	Field   string `json:"field"`
@@ -267,8 +2193,71 @@ func (s *R422ErrorsItem) SetMessage(val string) {
 	s.Message = val
 }
 
+type R429 struct {
+	Message string `json:"message"`
+	// The message in the language preferred in the Accept-Language header, for humans. Only set when the
+	// message is known.
+	Detail OptString `json:"detail"`
+}
+
+// GetMessage returns the value of Message.
+func (s *R429) GetMessage() string {
+	return s.Message
+}
+
+// GetDetail returns the value of Detail.
+func (s *R429) GetDetail() OptString {
+	return s.Detail
+}
+
+// SetMessage sets the value of Message.
+func (s *R429) SetMessage(val string) {
+	s.Message = val
+}
+
+// SetDetail sets the value of Detail.
+func (s *R429) SetDetail(val OptString) {
+	s.Detail = val
+}
+
+// R429Headers wraps R429 with response headers.
+type R429Headers struct {
+	RetryAfter int
+	Response   R429
+}
+
+// GetRetryAfter returns the value of RetryAfter.
+func (s *R429Headers) GetRetryAfter() int {
+	return s.RetryAfter
+}
+
+// GetResponse returns the value of Response.
+func (s *R429Headers) GetResponse() R429 {
+	return s.Response
+}
+
+// SetRetryAfter sets the value of RetryAfter.
+func (s *R429Headers) SetRetryAfter(val int) {
+	s.RetryAfter = val
+}
+
+// SetResponse sets the value of Response.
+func (s *R429Headers) SetResponse(val R429) {
+	s.Response = val
+}
+
+func (*R429Headers) createDailyQuoteGameRes()     {}
+func (*R429Headers) createNewQuoteGameRes()       {}
+func (*R429Headers) createRoomRes()               {}
+func (*R429Headers) requestHintForQuoteGameRes()  {}
+func (*R429Headers) submitAnswerForQuoteGameRes() {}
+func (*R429Headers) submitBlanksForQuoteGameRes() {}
+
 type R500 struct {
 	Message string `json:"message"`
+	// The message in the language preferred in the Accept-Language header, for humans. Only set when the
+	// message is known.
+	Detail OptString `json:"detail"`
 }
 
 // GetMessage returns the value of Message.
@@ -276,13 +2265,307 @@ func (s *R500) GetMessage() string {
 	return s.Message
 }
 
+// GetDetail returns the value of Detail.
+func (s *R500) GetDetail() OptString {
+	return s.Detail
+}
+
 // SetMessage sets the value of Message.
 func (s *R500) SetMessage(val string) {
 	s.Message = val
 }
 
+// SetDetail sets the value of Detail.
+func (s *R500) SetDetail(val OptString) {
+	s.Detail = val
+}
+
+func (*R500) createApiKeyRes()             {}
+func (*R500) createBackupRes()             {}
+func (*R500) createDailyQuoteGameRes()     {}
 func (*R500) createNewQuoteGameRes()       {}
+func (*R500) createQuoteRes()              {}
+func (*R500) createRoomRes()               {}
+func (*R500) deleteQuoteRes()              {}
+func (*R500) exportGamesRes()              {}
+func (*R500) getDailyLeaderboardRes()      {}
+func (*R500) getDailyQuoteRes()            {}
+func (*R500) getQuoteRes()                 {}
 func (*R500) getRandomQuoteRes()           {}
+func (*R500) getRoomResultRes()            {}
+func (*R500) joinRoomRes()                 {}
+func (*R500) listAuthorConfusionsRes()     {}
+func (*R500) listAuthorsRes()              {}
+func (*R500) listBackupsRes()              {}
+func (*R500) listQuoteStatsRes()           {}
+func (*R500) listQuotesRes()               {}
+func (*R500) requestHintForQuoteGameRes()  {}
+func (*R500) revokeApiKeyRes()             {}
 func (*R500) submitAnswerForQuoteGameRes() {}
+func (*R500) submitAnswerForRoomRes()      {}
+func (*R500) submitBlanksForQuoteGameRes() {}
+func (*R500) updateQuoteRes()              {}
+
+type R503 struct {
+	Message string `json:"message"`
+	// The message in the language preferred in the Accept-Language header, for humans. Only set when the
+	// message is known.
+	Detail OptString `json:"detail"`
+}
+
+// GetMessage returns the value of Message.
+func (s *R503) GetMessage() string {
+	return s.Message
+}
+
+// GetDetail returns the value of Detail.
+func (s *R503) GetDetail() OptString {
+	return s.Detail
+}
+
+// SetMessage sets the value of Message.
+func (s *R503) SetMessage(val string) {
+	s.Message = val
+}
+
+// SetDetail sets the value of Detail.
+func (s *R503) SetDetail(val OptString) {
+	s.Detail = val
+}
+
+func (*R503) createBackupRes()             {}
+func (*R503) createDailyQuoteGameRes()     {}
+func (*R503) createNewQuoteGameRes()       {}
+func (*R503) createRoomRes()               {}
+func (*R503) getRandomQuoteRes()           {}
+func (*R503) joinRoomRes()                 {}
+func (*R503) listBackupsRes()              {}
+func (*R503) requestHintForQuoteGameRes()  {}
+func (*R503) submitAnswerForQuoteGameRes() {}
+func (*R503) submitAnswerForRoomRes()      {}
+func (*R503) submitBlanksForQuoteGameRes() {}
+
+// RevokeApiKeyNoContent is response for RevokeApiKey operation.
+type RevokeApiKeyNoContent struct{}
+
+func (*RevokeApiKeyNoContent) revokeApiKeyRes() {}
+
+// The role of a caller. Admins can use the admin endpoints.
+// Ref: #/components/schemas/Role
+type Role string
+
+const (
+	RolePlayer Role = "player"
+	RoleAdmin  Role = "admin"
+)
+
+// AllValues returns all Role values.
+func (Role) AllValues() []Role {
+	return []Role{
+		RolePlayer,
+		RoleAdmin,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Role) MarshalText() ([]byte, error) {
+	switch s {
+	case RolePlayer:
+		return []byte(s), nil
+	case RoleAdmin:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Role) UnmarshalText(data []byte) error {
+	switch Role(data) {
+	case RolePlayer:
+		*s = RolePlayer
+		return nil
+	case RoleAdmin:
+		*s = RoleAdmin
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// A room with the quote game that all participants answer.
+// Ref: #/components/schemas/Room
+type Room struct {
+	Code     string               `json:"code"`
+	Host     string               `json:"host"`
+	Deadline time.Time            `json:"deadline"`
+	ID       UUID                 `json:"id"`
+	Quotes   []QuoteWithoutAuthor `json:"quotes"`
+	Authors  []string             `json:"authors"`
+}
+
+// GetCode returns the value of Code.
+func (s *Room) GetCode() string {
+	return s.Code
+}
+
+// GetHost returns the value of Host.
+func (s *Room) GetHost() string {
+	return s.Host
+}
+
+// GetDeadline returns the value of Deadline.
+func (s *Room) GetDeadline() time.Time {
+	return s.Deadline
+}
+
+// GetID returns the value of ID.
+func (s *Room) GetID() UUID {
+	return s.ID
+}
+
+// GetQuotes returns the value of Quotes.
+func (s *Room) GetQuotes() []QuoteWithoutAuthor {
+	return s.Quotes
+}
+
+// GetAuthors returns the value of Authors.
+func (s *Room) GetAuthors() []string {
+	return s.Authors
+}
+
+// SetCode sets the value of Code.
+func (s *Room) SetCode(val string) {
+	s.Code = val
+}
+
+// SetHost sets the value of Host.
+func (s *Room) SetHost(val string) {
+	s.Host = val
+}
+
+// SetDeadline sets the value of Deadline.
+func (s *Room) SetDeadline(val time.Time) {
+	s.Deadline = val
+}
+
+// SetID sets the value of ID.
+func (s *Room) SetID(val UUID) {
+	s.ID = val
+}
+
+// SetQuotes sets the value of Quotes.
+func (s *Room) SetQuotes(val []QuoteWithoutAuthor) {
+	s.Quotes = val
+}
+
+// SetAuthors sets the value of Authors.
+func (s *Room) SetAuthors(val []string) {
+	s.Authors = val
+}
+
+func (*Room) createRoomRes() {}
+func (*Room) joinRoomRes()   {}
+
+// A participant of a room.
+// Ref: #/components/schemas/RoomParticipant
+type RoomParticipant struct {
+	PlayerId  string `json:"playerId"`
+	Submitted bool   `json:"submitted"`
+	// The number of correct answers, only set when the participant answered.
+	Score       OptInt      `json:"score"`
+	SubmittedAt OptDateTime `json:"submittedAt"`
+}
+
+// GetPlayerId returns the value of PlayerId.
+func (s *RoomParticipant) GetPlayerId() string {
+	return s.PlayerId
+}
+
+// GetSubmitted returns the value of Submitted.
+func (s *RoomParticipant) GetSubmitted() bool {
+	return s.Submitted
+}
+
+// GetScore returns the value of Score.
+func (s *RoomParticipant) GetScore() OptInt {
+	return s.Score
+}
+
+// GetSubmittedAt returns the value of SubmittedAt.
+func (s *RoomParticipant) GetSubmittedAt() OptDateTime {
+	return s.SubmittedAt
+}
+
+// SetPlayerId sets the value of PlayerId.
+func (s *RoomParticipant) SetPlayerId(val string) {
+	s.PlayerId = val
+}
+
+// SetSubmitted sets the value of Submitted.
+func (s *RoomParticipant) SetSubmitted(val bool) {
+	s.Submitted = val
+}
+
+// SetScore sets the value of Score.
+func (s *RoomParticipant) SetScore(val OptInt) {
+	s.Score = val
+}
+
+// SetSubmittedAt sets the value of SubmittedAt.
+func (s *RoomParticipant) SetSubmittedAt(val OptDateTime) {
+	s.SubmittedAt = val
+}
+
+// The scores of all participants of a room.
+// Ref: #/components/schemas/RoomResult
+type RoomResult struct {
+	Code         string            `json:"code"`
+	Host         string            `json:"host"`
+	Deadline     time.Time         `json:"deadline"`
+	Participants []RoomParticipant `json:"participants"`
+}
+
+// GetCode returns the value of Code.
+func (s *RoomResult) GetCode() string {
+	return s.Code
+}
+
+// GetHost returns the value of Host.
+func (s *RoomResult) GetHost() string {
+	return s.Host
+}
+
+// GetDeadline returns the value of Deadline.
+func (s *RoomResult) GetDeadline() time.Time {
+	return s.Deadline
+}
+
+// GetParticipants returns the value of Participants.
+func (s *RoomResult) GetParticipants() []RoomParticipant {
+	return s.Participants
+}
+
+// SetCode sets the value of Code.
+func (s *RoomResult) SetCode(val string) {
+	s.Code = val
+}
+
+// SetHost sets the value of Host.
+func (s *RoomResult) SetHost(val string) {
+	s.Host = val
+}
+
+// SetDeadline sets the value of Deadline.
+func (s *RoomResult) SetDeadline(val time.Time) {
+	s.Deadline = val
+}
+
+// SetParticipants sets the value of Participants.
+func (s *RoomResult) SetParticipants(val []RoomParticipant) {
+	s.Participants = val
+}
+
+func (*RoomResult) getRoomResultRes() {}
 
 type UUID string
@@ -13,31 +13,274 @@ type UnimplementedHandler struct{}
 
 var _ Handler = UnimplementedHandler{}
 
+// CreateApiKey implements createApiKey operation.
+//
+// Creates a new API key for the given subject and role. Only a hash of the key is stored, so the key
+// can't be retrieved later.
+//
+// POST /admin/api-keys
+func (UnimplementedHandler) CreateApiKey(ctx context.Context, req *ApiKeyRequest) (r CreateApiKeyRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// CreateBackup implements createBackup operation.
+//
+// Writes a consistent snapshot of the database to a new file in the backup directory, while the api
+// keeps serving requests. The oldest backups are removed beyond the configured retention. Responds
+// with a `503` when no backup directory is configured.
+//
+// POST /admin/backups
+func (UnimplementedHandler) CreateBackup(ctx context.Context) (r CreateBackupRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// CreateDailyQuoteGame implements createDailyQuoteGame operation.
+//
+// Starts a quote game with the quotes of the daily challenge, which are the same for everyone during
+// a day (in UTC). Every player can play the challenge once a day. The game is answered with `POST
+// /quote-game/:id/answer`, with the same deadline as a normal game.
+//
+// POST /quote-game/daily
+func (UnimplementedHandler) CreateDailyQuoteGame(ctx context.Context, params CreateDailyQuoteGameParams) (r CreateDailyQuoteGameRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // CreateNewQuoteGame implements createNewQuoteGame operation.
 //
-// The quote game returns three quotes and three authors. In `PUT /quote-game/:id`, the player can
-// respond with their answer. There is a deadline of five minutes.
+// In the `match` mode, the quote game returns three quotes and three authors. In the
+// `multiple_choice` mode, it returns three quotes that each come with four candidate authors, of
+// which one is right. In both modes, the player responds with `POST /quote-game/{id}/answer`. In the
+// `fill_in_the_blank` mode, a key word of every quote is replaced by `_____`, and the player
+// responds with the missing words in `POST /quote-game/{id}/blanks`. There is a deadline of five
+// minutes. The quotes are in the language preferred in the Accept-Language header, as long as the
+// catalogue has enough quotes in it. Otherwise they are in English.
 //
 // POST /quote-game
-func (UnimplementedHandler) CreateNewQuoteGame(ctx context.Context) (r CreateNewQuoteGameRes, _ error) {
+func (UnimplementedHandler) CreateNewQuoteGame(ctx context.Context, params CreateNewQuoteGameParams) (r CreateNewQuoteGameRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// CreateQuote implements createQuote operation.
+//
+// Adds a new quote to the local quote store. Unless it's hidden, the quote is used by the game and
+// shown in the catalogue like the quotes from dummyjson.
+//
+// POST /admin/quotes
+func (UnimplementedHandler) CreateQuote(ctx context.Context, req *QuoteEdit) (r CreateQuoteRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// CreateRoom implements createRoom operation.
+//
+// Creates a quote game in a room. The caller becomes the host of the room and its first participant.
+// Other players can join the room with its code and answer the same quotes until the deadline of the
+// game.
+//
+// POST /rooms
+func (UnimplementedHandler) CreateRoom(ctx context.Context, params CreateRoomParams) (r CreateRoomRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// DeleteQuote implements deleteQuote operation.
+//
+// Soft-deletes a quote. The quote is blocked permanently, so it's never shown to players again, also
+// not when dummyjson returns it.
+//
+// DELETE /admin/quotes/{id}
+func (UnimplementedHandler) DeleteQuote(ctx context.Context, params DeleteQuoteParams) (r DeleteQuoteRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// ExportGames implements exportGames operation.
+//
+// Streams the raw data of the games, one row or line per game, for analysis. The CSV export starts
+// with a header row, the NDJSON export has a JSON object per line with the same fields. The
+// correctness, the completion time and the duration are empty (or `null`) for games that are not
+// completed. The export is written while the games are read, so a large export starts right away. A
+// `500` can only be returned before the first game is written, a failure after that ends the
+// response early.
+//
+// GET /admin/exports/games
+func (UnimplementedHandler) ExportGames(ctx context.Context, params ExportGamesParams) (r ExportGamesRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// GetDailyLeaderboard implements getDailyLeaderboard operation.
+//
+// Returns the players that answered the daily challenge of a day, ordered by score. Players with the
+// same score are ordered by who answered first.
+//
+// GET /quote-game/daily/leaderboard
+func (UnimplementedHandler) GetDailyLeaderboard(ctx context.Context, params GetDailyLeaderboardParams) (r GetDailyLeaderboardRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// GetDailyQuote implements getDailyQuote operation.
+//
+// Returns one quote per day, which is the same for everyone. Once chosen, the quote of a day never
+// changes, even when the catalogue does. The response can be cached until midnight UTC.
+//
+// GET /quote/daily
+func (UnimplementedHandler) GetDailyQuote(ctx context.Context, params GetDailyQuoteParams) (r GetDailyQuoteRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// GetQuote implements getQuote operation.
+//
+// Returns a single quote from the locally cached catalogue.
+//
+// GET /quotes/{id}
+func (UnimplementedHandler) GetQuote(ctx context.Context, params GetQuoteParams) (r GetQuoteRes, _ error) {
 	return r, ht.ErrNotImplemented
 }
 
 // GetRandomQuote implements getRandomQuote operation.
 //
-// Returns a random quote.
+// Returns a random quote, in the language preferred in the Accept-Language header when the catalogue
+// has quotes in it. Otherwise the quote is in English.
 //
 // GET /quote
-func (UnimplementedHandler) GetRandomQuote(ctx context.Context) (r GetRandomQuoteRes, _ error) {
+func (UnimplementedHandler) GetRandomQuote(ctx context.Context, params GetRandomQuoteParams) (r GetRandomQuoteRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// GetRoomResult implements getRoomResult operation.
+//
+// Returns every participant of the room. Participants that answered are ordered by their score, the
+// others have no score yet. The result stays available after the deadline.
+//
+// GET /rooms/{code}
+func (UnimplementedHandler) GetRoomResult(ctx context.Context, params GetRoomResultParams) (r GetRoomResultRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// JoinRoom implements joinRoom operation.
+//
+// Adds the player to the room and returns the quote game of the room. Joining twice is allowed and
+// returns the same game. A room can't be joined after its deadline.
+//
+// POST /rooms/{code}/join
+func (UnimplementedHandler) JoinRoom(ctx context.Context, params JoinRoomParams) (r JoinRoomRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// ListAuthorConfusions implements listAuthorConfusions operation.
+//
+// Returns which author players guessed for the quotes of another author, and how often. Only the
+// wrong answers of the `match` and `multiple_choice` modes count, as the answers of
+// `fill_in_the_blank` are words. A wrong answer only counts when it was one of the authors the game
+// offered, and the author of a quote is the one it had when the game was played. Games created
+// before the offered authors were stored and the answers of rooms are not counted.
+//
+// GET /stats/confusions
+func (UnimplementedHandler) ListAuthorConfusions(ctx context.Context, params ListAuthorConfusionsParams) (r ListAuthorConfusionsRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// ListAuthors implements listAuthors operation.
+//
+// Returns a page of all authors in the locally cached catalogue together with the number of quotes
+// they have.
+//
+// GET /authors
+func (UnimplementedHandler) ListAuthors(ctx context.Context, params ListAuthorsParams) (r ListAuthorsRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// ListBackups implements listBackups operation.
+//
+// Lists the backups in the backup directory, made with `POST /admin/backups`, the schedule or the
+// `backup` command. Responds with a `503` when no backup directory is configured.
+//
+// GET /admin/backups
+func (UnimplementedHandler) ListBackups(ctx context.Context) (r ListBackupsRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// ListQuoteStats implements listQuoteStats operation.
+//
+// Returns how often every quote was answered in completed games and how many of those answers were
+// correct. The quotes with the lowest accuracy come first, quotes with the same accuracy are ordered
+// by their number of attempts, the most first.
+//
+// GET /stats/quotes
+func (UnimplementedHandler) ListQuoteStats(ctx context.Context, params ListQuoteStatsParams) (r ListQuoteStatsRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// ListQuotes implements listQuotes operation.
+//
+// Returns a page of quotes from the locally cached catalogue. Without a search the quotes are
+// ordered by id.
+//
+// GET /quotes
+func (UnimplementedHandler) ListQuotes(ctx context.Context, params ListQuotesParams) (r ListQuotesRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// RequestHintForQuoteGame implements requestHintForQuoteGame operation.
+//
+// Gives a hint for a game in the `match` mode. A `reveal_pair` hint reveals the author of a quote
+// and costs two points. An `eliminate_author` hint reveals an author of the game that did not say a
+// quote and costs one point. The costs of all hints are deducted from the score when the game is
+// answered, but the score never drops below zero. A revealed quote gets no more hints and every
+// quote gets one author eliminated at most. Hints are not available in rooms.
+//
+// POST /quote-game/{id}/hint
+func (UnimplementedHandler) RequestHintForQuoteGame(ctx context.Context, req *HintRequest, params RequestHintForQuoteGameParams) (r RequestHintForQuoteGameRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// RevokeApiKey implements revokeApiKey operation.
+//
+// Revokes an API key created with `POST /admin/api-keys`.
+//
+// DELETE /admin/api-keys/{id}
+func (UnimplementedHandler) RevokeApiKey(ctx context.Context, params RevokeApiKeyParams) (r RevokeApiKeyRes, _ error) {
 	return r, ht.ErrNotImplemented
 }
 
 // SubmitAnswerForQuoteGame implements submitAnswerForQuoteGame operation.
 //
 // This request expects an answer from the user and will return if the answer was correct and what
-// the correct answer should be.
+// the correct answer should be. Games in the `fill_in_the_blank` mode are answered with `POST
+// /quote-game/{id}/blanks` instead, and return a 422 here. A game can be answered once. Submitting
+// the same answers again returns the same result, other answers return a 409.
 //
 // POST /quote-game/{id}/answer
 func (UnimplementedHandler) SubmitAnswerForQuoteGame(ctx context.Context, req []QuoteGameAnswer, params SubmitAnswerForQuoteGameParams) (r SubmitAnswerForQuoteGameRes, _ error) {
 	return r, ht.ErrNotImplemented
 }
+
+// SubmitAnswerForRoom implements submitAnswerForRoom operation.
+//
+// Every participant of the room can answer the quote game once, before the deadline. The result of
+// the participant is returned and added to the result of the room.
+//
+// POST /rooms/{code}/answer
+func (UnimplementedHandler) SubmitAnswerForRoom(ctx context.Context, req []QuoteGameAnswer, params SubmitAnswerForRoomParams) (r SubmitAnswerForRoomRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// SubmitBlanksForQuoteGame implements submitBlanksForQuoteGame operation.
+//
+// Answers a game in the `fill_in_the_blank` mode with the missing word of every quote. Case and
+// punctuation are ignored, and a typo in words of four to seven letters, or two typos in longer
+// words, are still correct. Games in other modes return a 422. Submitting the same words again
+// returns the same result, other words return a 409.
+//
+// POST /quote-game/{id}/blanks
+func (UnimplementedHandler) SubmitBlanksForQuoteGame(ctx context.Context, req []BlankAnswer, params SubmitBlanksForQuoteGameParams) (r SubmitBlanksForQuoteGameRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// UpdateQuote implements updateQuote operation.
+//
+// Edits or hides a quote. Edited and hidden quotes are no longer overwritten by the catalogue sync.
+// Hidden quotes are never shown to players, also not when dummyjson returns them, but can be made
+// visible again.
+//
+// PUT /admin/quotes/{id}
+func (UnimplementedHandler) UpdateQuote(ctx context.Context, req *QuoteEdit, params UpdateQuoteParams) (r UpdateQuoteRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
@@ -0,0 +1,129 @@
+// Package pubsub delivers game and room events from the services to the clients that follow them.
+package pubsub
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+)
+
+// Broker is an in-process publish/subscribe hub. Events are only delivered to subscribers in the same process,
+// so a client has to be connected to the instance that handles the game or room.
+type Broker struct {
+	logger *zerolog.Logger
+	// bufferSize is the number of events a subscriber can lag behind before events are dropped for it
+	bufferSize int
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan models.Event]struct{}
+	timers      map[string]map[*time.Timer]struct{}
+}
+
+// NewBroker returns a new Broker. A subscriber that doesn't keep up misses events once bufferSize events are waiting for it,
+// so a slow client can never block the service that publishes.
+func NewBroker(logger *zerolog.Logger, bufferSize int) *Broker {
+	return &Broker{
+		logger:      logger,
+		bufferSize:  bufferSize,
+		subscribers: map[string]map[chan models.Event]struct{}{},
+		timers:      map[string]map[*time.Timer]struct{}{},
+	}
+}
+
+// Subscribe returns the events published on the topic from now on. The channel is closed after unsubscribe is called,
+// which has to happen when the subscriber stops reading.
+func (b *Broker) Subscribe(topic string) (events <-chan models.Event, unsubscribe func()) {
+	ch := make(chan models.Event, b.bufferSize)
+
+	b.mu.Lock()
+	if b.subscribers[topic] == nil {
+		b.subscribers[topic] = map[chan models.Event]struct{}{}
+	}
+	b.subscribers[topic][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			delete(b.subscribers[topic], ch)
+			if len(b.subscribers[topic]) == 0 {
+				delete(b.subscribers, topic)
+			}
+			close(ch)
+		})
+	}
+}
+
+// Publish delivers the event to all current subscribers of its topic. The time of the event is set when it's empty.
+func (b *Broker) Publish(event models.Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[event.Topic] {
+		select {
+		case ch <- event:
+		default:
+			b.logger.Warn().Str("topic", event.Topic).Str("type", string(event.Type)).Msg("subscriber is too slow, dropping event")
+		}
+	}
+}
+
+// Schedule publishes the events in order at the given time, unless the topic is cancelled before then.
+// All events have to be on the same topic. A time in the past publishes the events right away.
+func (b *Broker) Schedule(at time.Time, events []models.Event) {
+	if len(events) == 0 {
+		return
+	}
+	topic := events[0].Topic
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(at), func() {
+		b.mu.Lock()
+		_, scheduled := b.timers[topic][timer]
+		b.removeTimer(topic, timer)
+		b.mu.Unlock()
+
+		// The topic was cancelled while the timer already fired
+		if !scheduled {
+			return
+		}
+		for _, event := range events {
+			b.Publish(event)
+		}
+	})
+	if b.timers[topic] == nil {
+		b.timers[topic] = map[*time.Timer]struct{}{}
+	}
+	b.timers[topic][timer] = struct{}{}
+}
+
+// Cancel stops all scheduled events of the topic, for example because the game ended before its deadline
+func (b *Broker) Cancel(topic string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for timer := range b.timers[topic] {
+		timer.Stop()
+	}
+	delete(b.timers, topic)
+}
+
+// removeTimer forgets a timer that fired. The lock has to be held by the caller
+func (b *Broker) removeTimer(topic string, timer *time.Timer) {
+	delete(b.timers[topic], timer)
+	if len(b.timers[topic]) == 0 {
+		delete(b.timers, topic)
+	}
+}
@@ -0,0 +1,117 @@
+package pubsub
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBroker(bufferSize int) *Broker {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	return NewBroker(&logger, bufferSize)
+}
+
+// receive waits for the next event, so the test fails instead of hanging when nothing is published
+func receive(t *testing.T, events <-chan models.Event) models.Event {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		require.FailNow(t, "no event received")
+		return models.Event{}
+	}
+}
+
+func TestBroker_Publish(t *testing.T) {
+	broker := newTestBroker(10)
+
+	room1, unsubscribe1 := broker.Subscribe("room:ABC234")
+	room1Again, unsubscribe2 := broker.Subscribe("room:ABC234")
+	room2, unsubscribe3 := broker.Subscribe("room:ZZZ999")
+	defer unsubscribe3()
+
+	broker.Publish(models.Event{Topic: "room:ABC234", Type: models.EventPlayerJoined, PlayerID: "player-1"})
+
+	// Every subscriber of the topic gets the event, with the time filled in
+	for _, events := range []<-chan models.Event{room1, room1Again} {
+		event := receive(t, events)
+		assert.Equal(t, models.EventPlayerJoined, event.Type)
+		assert.Equal(t, "player-1", event.PlayerID)
+		assert.WithinDuration(t, time.Now(), event.Time, time.Second)
+	}
+	assert.Empty(t, room2)
+
+	// After unsubscribing, the channel is closed and gets no more events
+	unsubscribe1()
+	unsubscribe1()
+	_, open := <-room1
+	assert.False(t, open)
+
+	broker.Publish(models.Event{Topic: "room:ABC234", Type: models.EventAnswerSubmitted})
+	assert.Equal(t, models.EventAnswerSubmitted, receive(t, room1Again).Type)
+
+	unsubscribe2()
+	assert.NotContains(t, broker.subscribers, "room:ABC234")
+}
+
+func TestBroker_PublishDropsEventsForSlowSubscribers(t *testing.T) {
+	broker := newTestBroker(1)
+	events, unsubscribe := broker.Subscribe("room:ABC234")
+	defer unsubscribe()
+
+	// The second event doesn't fit in the buffer and must not block the publisher
+	broker.Publish(models.Event{Topic: "room:ABC234", Type: models.EventPlayerJoined})
+	broker.Publish(models.Event{Topic: "room:ABC234", Type: models.EventAnswerSubmitted})
+
+	assert.Equal(t, models.EventPlayerJoined, receive(t, events).Type)
+	assert.Empty(t, events)
+}
+
+func TestBroker_Schedule(t *testing.T) {
+	broker := newTestBroker(10)
+	events, unsubscribe := broker.Subscribe("room:ABC234")
+	defer unsubscribe()
+
+	broker.Schedule(time.Now().Add(20*time.Millisecond), []models.Event{
+		{Topic: "room:ABC234", Type: models.EventResultsAvailable},
+		{Topic: "room:ABC234", Type: models.EventGameExpired},
+	})
+	broker.Schedule(time.Now().Add(-time.Second), []models.Event{
+		{Topic: "room:ABC234", Type: models.EventDeadlineApproaching},
+	})
+
+	// Events in the past are published right away, events scheduled together keep their order
+	assert.Equal(t, models.EventDeadlineApproaching, receive(t, events).Type)
+	assert.Equal(t, models.EventResultsAvailable, receive(t, events).Type)
+	assert.Equal(t, models.EventGameExpired, receive(t, events).Type)
+
+	assert.Eventually(t, func() bool {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		return len(broker.timers) == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestBroker_Cancel(t *testing.T) {
+	broker := newTestBroker(10)
+	events, unsubscribe := broker.Subscribe("room:ABC234")
+	defer unsubscribe()
+	other, unsubscribeOther := broker.Subscribe("room:ZZZ999")
+	defer unsubscribeOther()
+
+	broker.Schedule(time.Now().Add(20*time.Millisecond), []models.Event{{Topic: "room:ABC234", Type: models.EventGameExpired}})
+	broker.Schedule(time.Now().Add(20*time.Millisecond), []models.Event{{Topic: "room:ZZZ999", Type: models.EventGameExpired}})
+	broker.Cancel("room:ABC234")
+
+	// Only the events of the cancelled topic are stopped
+	assert.Equal(t, models.EventGameExpired, receive(t, other).Type)
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, events)
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/database"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+
+	"github.com/stephenafamo/bob/dialect/sqlite"
+	"github.com/stephenafamo/bob/dialect/sqlite/im"
+	"github.com/stephenafamo/bob/dialect/sqlite/sm"
+	"github.com/stephenafamo/bob/dialect/sqlite/um"
+)
+
+type ApiKeyRepo struct {
+	logger *zerolog.Logger
+	db     *database.DB
+}
+
+// NewApiKeyRepo returns a new ApiKeyRepo, which stores the API keys created by admins.
+func NewApiKeyRepo(logger *zerolog.Logger, db *database.DB) *ApiKeyRepo {
+	return &ApiKeyRepo{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// CreateApiKey stores a new API key by its hash and returns the stored key
+func (repo *ApiKeyRepo) CreateApiKey(ctx context.Context, keyHash string, subject string, role models.Role) (*models.ApiKey, error) {
+	apiKey := &models.ApiKey{
+		ID:        uuid.New(),
+		Subject:   subject,
+		Role:      role,
+		CreatedAt: time.Now(),
+	}
+
+	queryString, args, err := sqlite.Insert(
+		im.Into("api_key", "id", "key_hash", "subject", "role", "created_at"),
+		im.Values(sqlite.Arg(apiKey.ID, keyHash, apiKey.Subject, apiKey.Role, apiKey.CreatedAt)),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	_, err = repo.db.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+
+	return apiKey, nil
+}
+
+// GetCallerByKeyHash returns the caller the API key with the given hash belongs to.
+// ErrApiKeyNotFound is returned if the key doesn't exist or is revoked.
+func (repo *ApiKeyRepo) GetCallerByKeyHash(ctx context.Context, keyHash string) (*models.Caller, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("api_key"),
+		sm.Columns("subject", "role"),
+		sm.Where(sqlite.Quote("key_hash").EQ(sqlite.Arg(keyHash))),
+		sm.Where(sqlite.Quote("revoked_at").IsNull()),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	caller := &models.Caller{}
+	err = repo.db.Reader().QueryRowContext(ctx, queryString, args...).Scan(&caller.ID, &caller.Role)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrApiKeyNotFound
+	}
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	return caller, nil
+}
+
+// RevokeApiKey revokes the API key, so it can't be used anymore. The key is kept for reference.
+// ErrApiKeyNotFound is returned if the key doesn't exist or is already revoked.
+func (repo *ApiKeyRepo) RevokeApiKey(ctx context.Context, id uuid.UUID) error {
+	queryString, args, err := sqlite.Update(
+		um.Table("api_key"),
+		um.SetCol("revoked_at").ToArg(time.Now()),
+		um.Where(sqlite.Quote("id").EQ(sqlite.Arg(id))),
+		um.Where(sqlite.Quote("revoked_at").IsNull()),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return errors.Join(errors.New("could not build query"), err)
+	}
+
+	res, err := repo.db.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return errors.Join(errors.New("could not execute query"), err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not get affected rows")
+		return errors.Join(errors.New("could not get affected rows"), err)
+	}
+	if affected == 0 {
+		return models.ErrApiKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/database"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApiKeyRepo(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := database.Init(&logger, ":memory:")
+	defer db.Close()
+	repo := NewApiKeyRepo(&logger, db)
+
+	apiKey, err := repo.CreateApiKey(context.TODO(), "a-hash", "player-42", models.RolePlayer)
+	require.NoError(t, err)
+	assert.Equal(t, "player-42", apiKey.Subject)
+	assert.Equal(t, models.RolePlayer, apiKey.Role)
+
+	caller, err := repo.GetCallerByKeyHash(context.TODO(), "a-hash")
+	require.NoError(t, err)
+	assert.Equal(t, &models.Caller{ID: "player-42", Role: models.RolePlayer}, caller)
+
+	_, err = repo.GetCallerByKeyHash(context.TODO(), "another-hash")
+	assert.Equal(t, models.ErrApiKeyNotFound, err)
+
+	// Revoked keys can't be used anymore, nor revoked again
+	err = repo.RevokeApiKey(context.TODO(), apiKey.ID)
+	require.NoError(t, err)
+
+	_, err = repo.GetCallerByKeyHash(context.TODO(), "a-hash")
+	assert.Equal(t, models.ErrApiKeyNotFound, err)
+
+	err = repo.RevokeApiKey(context.TODO(), apiKey.ID)
+	assert.Equal(t, models.ErrApiKeyNotFound, err)
+	err = repo.RevokeApiKey(context.TODO(), uuid.New())
+	assert.Equal(t, models.ErrApiKeyNotFound, err)
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pietdevries94/Kabisa/database"
+	"github.com/rs/zerolog"
+)
+
+type BackupRepo struct {
+	logger *zerolog.Logger
+	db     *database.DB
+}
+
+// NewBackupRepo returns a new BackupRepo, which writes snapshots of the database
+func NewBackupRepo(logger *zerolog.Logger, db *database.DB) *BackupRepo {
+	return &BackupRepo{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// VacuumInto writes a consistent snapshot of the database to a new file at path. The file may not exist yet.
+//
+// VACUUM INTO only reads the database, but the read pool is query only, which doesn't allow it. So it uses the write pool
+// and writes wait for the snapshot. The query builder has no VACUUM, so the query is written out.
+func (repo *BackupRepo) VacuumInto(ctx context.Context, path string) error {
+	_, err := repo.db.ExecContext(ctx, "VACUUM INTO ?", path)
+	if err != nil {
+		repo.logger.Error().Err(err).Str("path", path).Msg("could not execute query")
+		return errors.Join(errors.New("could not execute query"), err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/database"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupRepo_VacuumInto(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	dir := t.TempDir()
+	db := database.Init(&logger, "file:"+filepath.Join(dir, "kabisa.db"))
+	defer db.Close()
+
+	id := uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e")
+	db.Exec( //nolint:errcheck // this is a test
+		"insert into quote_game(id, quote1_id, quote2_id, quote3_id, created_at) values (?,?,?,?,?)",
+		id, 12, 72, 33, time.Now(),
+	)
+
+	path := filepath.Join(dir, "backup.db")
+	err := NewBackupRepo(&logger, db).VacuumInto(context.TODO(), path)
+	require.NoError(t, err)
+
+	// The backup is a database with the same data, which can be opened like the original
+	backup := database.Init(&logger, "file:"+path)
+	defer backup.Close()
+	var quoteIDs [3]int
+	err = backup.QueryRow("select quote1_id, quote2_id, quote3_id from quote_game where id = ?", id).Scan(&quoteIDs[0], &quoteIDs[1], &quoteIDs[2])
+	require.NoError(t, err)
+	assert.Equal(t, [3]int{12, 72, 33}, quoteIDs)
+
+	// An existing file is never overwritten
+	err = NewBackupRepo(&logger, db).VacuumInto(context.TODO(), path)
+	assert.ErrorContains(t, err, "output file already exists")
+}
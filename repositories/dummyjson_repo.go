@@ -5,22 +5,40 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/pietdevries94/Kabisa/models"
 	"github.com/rs/zerolog"
+	"golang.org/x/sync/singleflight"
 )
 
 type DummyJsonRepo struct {
 	logger     *zerolog.Logger
 	httpClient httpClient
+	// baseURL is the url of the dummyjson api without trailing slash, so a mirror or a stub like cmd/fakequotes can be used
+	baseURL string
+	// requests is a semaphore that limits the number of concurrent requests to dummyjson
+	requests chan struct{}
+	// queueTimeout is how long a request waits for a free slot in requests, before ErrUpstreamBusy is returned
+	queueTimeout time.Duration
+	// quoteLookups coalesces concurrent GetQuote calls for the same id into a single request
+	quoteLookups singleflight.Group
 }
 
-// NewDummyJsonRepo returns a new DummyJsonRepo, which handles all calls to the dummyjson.com api.
-func NewDummyJsonRepo(logger *zerolog.Logger, httpClient httpClient) *DummyJsonRepo {
+// NewDummyJsonRepo returns a new DummyJsonRepo, which handles all calls to the dummyjson.com api, or another api with the same shape at baseURL.
+// At most maxConcurrentRequests requests are done at the same time. Other requests wait in line for at most queueTimeout.
+func NewDummyJsonRepo(logger *zerolog.Logger, httpClient httpClient, baseURL string, maxConcurrentRequests int, queueTimeout time.Duration) *DummyJsonRepo {
 	return &DummyJsonRepo{
-		logger:     logger,
-		httpClient: httpClient,
+		logger:       logger,
+		httpClient:   httpClient,
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		requests:     make(chan struct{}, maxConcurrentRequests),
+		queueTimeout: queueTimeout,
 	}
 }
 
@@ -31,7 +49,7 @@ func (repo *DummyJsonRepo) GetRandomQuotes(ctx context.Context, amount int) ([]*
 		return nil, fmt.Errorf("amount should be between 1 and 10. Given: %d", amount)
 	}
 
-	url := fmt.Sprintf("https://dummyjson.com/quotes/random/%d", amount)
+	url := fmt.Sprintf("%s/quotes/random/%d", repo.baseURL, amount)
 
 	resp, err := repo.get(ctx, url)
 	if err != nil {
@@ -51,12 +69,33 @@ func (repo *DummyJsonRepo) GetRandomQuotes(ctx context.Context, amount int) ([]*
 		return nil, errors.Join(errors.New("unexpected error when decoding result to models.Quote"), err)
 	}
 
+	markEnglish(quotes...)
 	return quotes, nil
 }
 
 // GetQuote gets a quote by id, or returns a public error when not found. Other errors get logged
+// Concurrent calls for the same id share a single request to dummyjson. That request isn't canceled when one of the callers
+// goes away, as the other callers still need the result. The http client timeout still applies.
 func (repo *DummyJsonRepo) GetQuote(ctx context.Context, id int) (*models.Quote, error) {
-	url := fmt.Sprintf("https://dummyjson.com/quotes/%d", id)
+	resultChan := repo.quoteLookups.DoChan(strconv.Itoa(id), func() (any, error) {
+		return repo.getQuote(context.WithoutCancel(ctx), id)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-resultChan:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		// Every caller gets its own copy, so they can't change the quote of the others
+		quote := *result.Val.(*models.Quote)
+		return &quote, nil
+	}
+}
+
+func (repo *DummyJsonRepo) getQuote(ctx context.Context, id int) (*models.Quote, error) {
+	url := fmt.Sprintf("%s/quotes/%d", repo.baseURL, id)
 
 	resp, err := repo.get(ctx, url)
 	if err != nil {
@@ -80,9 +119,60 @@ func (repo *DummyJsonRepo) GetQuote(ctx context.Context, id int) (*models.Quote,
 		repo.logger.Error().Err(err).Msg("unexpected error when decoding result to models.Quote")
 		return nil, errors.Join(errors.New("unexpected error when decoding result to models.Quote"), err)
 	}
+	if quote == nil {
+		return nil, errors.New("dummyjson returned an empty quote")
+	}
+	markEnglish(quote)
 	return quote, err
 }
 
+// GetAllQuotes retrieves the complete list of quotes from dummyjson. This is used to fill the local quote catalogue.
+func (repo *DummyJsonRepo) GetAllQuotes(ctx context.Context) ([]*models.Quote, error) {
+	// A limit of 0 makes dummyjson return all quotes at once
+	url := repo.baseURL + "/quotes?limit=0"
+
+	resp, err := repo.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		repo.logger.Error().Int("status code", resp.StatusCode).Msg("unexpected status code received")
+		return nil, fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Quotes []*models.Quote `json:"quotes"`
+		Total  int             `json:"total"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("unexpected error when decoding result to models.Quote")
+		return nil, errors.Join(errors.New("unexpected error when decoding result to models.Quote"), err)
+	}
+
+	// We don't want to continue with a partial catalogue
+	if len(body.Quotes) != body.Total {
+		repo.logger.Error().Int("received", len(body.Quotes)).Int("total", body.Total).Msg("did not receive all quotes")
+		return nil, fmt.Errorf("did not receive all quotes. Received %d of %d", len(body.Quotes), body.Total)
+	}
+
+	markEnglish(body.Quotes...)
+	return body.Quotes, nil
+}
+
+// markEnglish sets the language of quotes from dummyjson, which only serves English quotes
+func markEnglish(quotes ...*models.Quote) {
+	for _, q := range quotes {
+		if q != nil {
+			q.Language = models.LanguageEnglish
+		}
+	}
+}
+
+// get does a GET request to dummyjson, once there is a free slot. The slot is released when the body of the response is closed,
+// so the caller has to close it like any other response body.
 func (repo *DummyJsonRepo) get(ctx context.Context, url string) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
 	if err != nil {
@@ -90,20 +180,64 @@ func (repo *DummyJsonRepo) get(ctx context.Context, url string) (*http.Response,
 		return nil, errors.Join(errors.New("unexpected error when creating request for retrieving random quote from api"), err)
 	}
 
+	release, err := repo.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	resp, err := repo.httpClient.Do(req)
 	if err != nil {
+		release()
 		repo.logger.Error().Err(err).Msg("unexpected error when retrieving random quote from api")
 		return nil, errors.Join(errors.New("unexpected error when retrieving random quote from api"), err)
 	}
 
 	if resp.Body == nil {
+		release()
 		repo.logger.Error().Err(err).Int("status code", resp.StatusCode).Msg("no body received")
 		return nil, errors.New("no body received")
 	}
 
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
 	return resp, nil
 }
 
+// acquire waits for a free slot to do a request. If there is no free slot within the queueTimeout, ErrUpstreamBusy is returned
+func (repo *DummyJsonRepo) acquire(ctx context.Context) (release func(), err error) {
+	release = func() { <-repo.requests }
+
+	// Most of the time there is a free slot, so we don't need a timer
+	select {
+	case repo.requests <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	timer := time.NewTimer(repo.queueTimeout)
+	defer timer.Stop()
+	select {
+	case repo.requests <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		repo.logger.Warn().Dur("queue timeout", repo.queueTimeout).Msg("no free slot for a request to dummyjson")
+		return nil, models.ErrUpstreamBusy
+	}
+}
+
+// releasingBody releases the slot of a request once the body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releasingBody) Close() error {
+	b.once.Do(b.release)
+	return b.ReadCloser.Close()
+}
+
 // GetQuotes retrieves a map of quotes from an api. The api only supports doing this one by one.
 // It does so synchronized, but if this function needs to handle larger numbers, it could be
 // refactored to build up the map async with a limit of parallel fetches.
@@ -8,7 +8,10 @@ import (
 	"net/http"
 	"os"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/pietdevries94/Kabisa/models"
 	"github.com/rs/zerolog"
@@ -39,7 +42,7 @@ func TestDummyJsonRepo_GetRandomQuotes(t *testing.T) {
 
 			// We inject the mocked repo and expect to get the same quote back, but now as a struct
 			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
-			res, err := NewDummyJsonRepo(&logger, mockedHttpClient).GetRandomQuotes(context.TODO(), tt.amount)
+			res, err := NewDummyJsonRepo(&logger, mockedHttpClient, "https://dummyjson.com", 10, time.Second).GetRandomQuotes(context.TODO(), tt.amount)
 
 			if tt.expectedError != nil {
 				require.ErrorContains(t, err, tt.expectedError.Error())
@@ -62,9 +65,10 @@ func TestDummyJsonRepo_GetRandomQuotes(t *testing.T) {
 		mockedResponse: CreateMockedResponse(http.StatusOK, bytes.NewBufferString(`[{"id":414,"quote":"When We Lose One Blessing, Another Is Often Most Unexpectedly Given In Its Place.","author":"C. S. Lewis"}]`)),
 		expectedResult: []*models.Quote{
 			{
-				ID:     414,
-				Quote:  "When We Lose One Blessing, Another Is Often Most Unexpectedly Given In Its Place.",
-				Author: "C. S. Lewis",
+				ID:       414,
+				Quote:    "When We Lose One Blessing, Another Is Often Most Unexpectedly Given In Its Place.",
+				Author:   "C. S. Lewis",
+				Language: models.LanguageEnglish,
 			},
 		},
 		expectApiToBeCalled: true,
@@ -103,19 +107,22 @@ func TestDummyJsonRepo_GetRandomQuotes(t *testing.T) {
 		mockedResponse: CreateMockedResponse(http.StatusOK, bytes.NewBufferString(`[{"id":1386,"quote":"It Is Most Pleasant To Commit A Just Action Which Is Disagreeable To Someone Whom One Does Not Like.","author":"Victor Hugo"},{"id":172,"quote":"The only lasting beauty is the beauty of the heart.","author":"Rumi"},{"id":454,"quote":"Risk Comes From Not Knowing What You'Re Doing.","author":"Warren Buffett"}]`)),
 		expectedResult: []*models.Quote{
 			{
-				ID:     1386,
-				Quote:  "It Is Most Pleasant To Commit A Just Action Which Is Disagreeable To Someone Whom One Does Not Like.",
-				Author: "Victor Hugo",
+				ID:       1386,
+				Quote:    "It Is Most Pleasant To Commit A Just Action Which Is Disagreeable To Someone Whom One Does Not Like.",
+				Author:   "Victor Hugo",
+				Language: models.LanguageEnglish,
 			},
 			{
-				ID:     172,
-				Quote:  "The only lasting beauty is the beauty of the heart.",
-				Author: "Rumi",
+				ID:       172,
+				Quote:    "The only lasting beauty is the beauty of the heart.",
+				Author:   "Rumi",
+				Language: models.LanguageEnglish,
 			},
 			{
-				ID:     454,
-				Quote:  "Risk Comes From Not Knowing What You'Re Doing.",
-				Author: "Warren Buffett",
+				ID:       454,
+				Quote:    "Risk Comes From Not Knowing What You'Re Doing.",
+				Author:   "Warren Buffett",
+				Language: models.LanguageEnglish,
 			},
 		},
 		expectApiToBeCalled: true,
@@ -162,7 +169,7 @@ func TestDummyJsonRepo_GetQuote(t *testing.T) {
 
 			// We inject the mocked repo and expect to get the same quote back, but now as a struct
 			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
-			res, err := NewDummyJsonRepo(&logger, mockedHttpClient).GetQuote(context.TODO(), tt.id)
+			res, err := NewDummyJsonRepo(&logger, mockedHttpClient, "https://dummyjson.com", 10, time.Second).GetQuote(context.TODO(), tt.id)
 
 			if tt.expectedError != nil {
 				// We want to explicitly check if the error going out was meant to be a public type
@@ -190,9 +197,10 @@ func TestDummyJsonRepo_GetQuote(t *testing.T) {
 		id:             414,
 		mockedResponse: CreateMockedResponse(http.StatusOK, bytes.NewBufferString(`{"id":414,"quote":"When We Lose One Blessing, Another Is Often Most Unexpectedly Given In Its Place.","author":"C. S. Lewis"}`)),
 		expectedResult: &models.Quote{
-			ID:     414,
-			Quote:  "When We Lose One Blessing, Another Is Often Most Unexpectedly Given In Its Place.",
-			Author: "C. S. Lewis",
+			ID:       414,
+			Quote:    "When We Lose One Blessing, Another Is Often Most Unexpectedly Given In Its Place.",
+			Author:   "C. S. Lewis",
+			Language: models.LanguageEnglish,
 		},
 		expectApiToBeCalled: true,
 	}))
@@ -234,6 +242,60 @@ func TestDummyJsonRepo_GetQuote(t *testing.T) {
 	}))
 }
 
+func TestDummyJsonRepo_GetAllQuotes(t *testing.T) {
+	type Test struct {
+		mockedResponse *http.Response
+		mockedError    error
+		expectedResult []*models.Quote
+		expectedError  error
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedHttpClient := new(MockedHttpClient)
+			mockedHttpClient.On("Do", "https://dummyjson.com/quotes?limit=0").
+				Once().
+				Return(tt.mockedResponse, tt.mockedError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			res, err := NewDummyJsonRepo(&logger, mockedHttpClient, "https://dummyjson.com", 10, time.Second).GetAllQuotes(context.TODO())
+
+			if tt.expectedError != nil {
+				require.ErrorContains(t, err, tt.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("returns all quotes when receiving expected response from api", run(Test{
+		mockedResponse: CreateMockedResponse(http.StatusOK, bytes.NewBufferString(`{"quotes":[{"id":1,"quote":"Your heart is the size of an ocean. Go find yourself in its hidden depths.","author":"Rumi"},{"id":2,"quote":"The Bay of Bengal is hit frequently by cyclones.","author":"Abdul Kalam"}],"total":2,"skip":0,"limit":2}`)),
+		expectedResult: []*models.Quote{
+			{ID: 1, Quote: "Your heart is the size of an ocean. Go find yourself in its hidden depths.", Author: "Rumi", Language: models.LanguageEnglish},
+			{ID: 2, Quote: "The Bay of Bengal is hit frequently by cyclones.", Author: "Abdul Kalam", Language: models.LanguageEnglish},
+		},
+	}))
+
+	t.Run("returns an error when not all quotes are received", run(Test{
+		mockedResponse: CreateMockedResponse(http.StatusOK, bytes.NewBufferString(`{"quotes":[{"id":1,"quote":"Your heart is the size of an ocean. Go find yourself in its hidden depths.","author":"Rumi"}],"total":1454,"skip":0,"limit":1}`)),
+		expectedError:  errors.New("did not receive all quotes. Received 1 of 1454"),
+	}))
+
+	t.Run("returns an error when client.Get returns an error", run(Test{
+		mockedError:   http.ErrHandlerTimeout,
+		expectedError: http.ErrHandlerTimeout,
+	}))
+
+	t.Run("returns an error when the client.Get response doesn't return a 200", run(Test{
+		mockedResponse: CreateMockedResponse(http.StatusTeapot, bytes.NewBufferString("{}")),
+		expectedError:  errors.New("unexpected status code received: 418"),
+	}))
+}
+
 // TODO: implement test for GetQuotes
 func TestDummyJsonRepo_GetQuotes(t *testing.T) {
 	type MockSets struct {
@@ -266,7 +328,7 @@ func TestDummyJsonRepo_GetQuotes(t *testing.T) {
 
 			// We inject the mocked repo and expect to get the same quote back, but now as a struct
 			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
-			res, err := NewDummyJsonRepo(&logger, mockedHttpClient).GetQuotes(context.TODO(), tt.ids)
+			res, err := NewDummyJsonRepo(&logger, mockedHttpClient, "https://dummyjson.com", 10, time.Second).GetQuotes(context.TODO(), tt.ids)
 
 			if tt.expectedError != nil {
 				// We want to explicitly check if the error going out was meant to be a public type
@@ -301,14 +363,16 @@ func TestDummyJsonRepo_GetQuotes(t *testing.T) {
 		},
 		expectedResult: map[int]*models.Quote{
 			414: {
-				ID:     414,
-				Quote:  "When We Lose One Blessing, Another Is Often Most Unexpectedly Given In Its Place.",
-				Author: "C. S. Lewis",
+				ID:       414,
+				Quote:    "When We Lose One Blessing, Another Is Often Most Unexpectedly Given In Its Place.",
+				Author:   "C. S. Lewis",
+				Language: models.LanguageEnglish,
 			},
 			172: {
-				ID:     172,
-				Quote:  "The only lasting beauty is the beauty of the heart.",
-				Author: "Rumi",
+				ID:       172,
+				Quote:    "The only lasting beauty is the beauty of the heart.",
+				Author:   "Rumi",
+				Language: models.LanguageEnglish,
 			},
 		},
 		expectApiToBeCalled: map[int]bool{
@@ -345,3 +409,70 @@ func TestDummyJsonRepo_GetQuotes(t *testing.T) {
 		},
 	}))
 }
+
+// blockingHttpClient answers every request with the same quote, but only after unblock is closed
+type blockingHttpClient struct {
+	unblock chan struct{}
+	calls   atomic.Int32
+}
+
+func (c *blockingHttpClient) Do(req *http.Request) (*http.Response, error) {
+	c.calls.Add(1)
+	<-c.unblock
+	return CreateMockedResponse(http.StatusOK, bytes.NewBufferString(`{"id":414,"quote":"A quote","author":"An author"}`)), nil
+}
+
+func TestDummyJsonRepo_GetQuote_CoalescesConcurrentCalls(t *testing.T) {
+	client := &blockingHttpClient{unblock: make(chan struct{})}
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	repo := NewDummyJsonRepo(&logger, client, "https://dummyjson.com", 10, time.Second)
+
+	var wg sync.WaitGroup
+	results := make([]*models.Quote, 5)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			quote, err := repo.GetQuote(context.TODO(), 414)
+			assert.NoError(t, err)
+			results[i] = quote
+		}()
+	}
+
+	// We give all goroutines the time to join the first request, before letting it finish
+	require.Eventually(t, func() bool { return client.calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	close(client.unblock)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), client.calls.Load())
+	for _, quote := range results {
+		assert.Equal(t, &models.Quote{ID: 414, Quote: "A quote", Author: "An author", Language: models.LanguageEnglish}, quote)
+	}
+	// Every caller gets its own copy
+	assert.NotSame(t, results[0], results[1])
+}
+
+func TestDummyJsonRepo_ReturnsErrUpstreamBusyAfterQueueTimeout(t *testing.T) {
+	client := &blockingHttpClient{unblock: make(chan struct{})}
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	repo := NewDummyJsonRepo(&logger, client, "https://dummyjson.com", 1, 10*time.Millisecond)
+
+	// The first request takes the only slot until it's unblocked
+	done := make(chan error)
+	go func() {
+		_, err := repo.GetQuote(context.TODO(), 414)
+		done <- err
+	}()
+	require.Eventually(t, func() bool { return client.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	_, err := repo.GetQuote(context.TODO(), 415)
+	assert.Equal(t, models.ErrUpstreamBusy, err)
+
+	// Once the first request is done, its slot is free again
+	close(client.unblock)
+	require.NoError(t, <-done)
+	_, err = repo.GetQuote(context.TODO(), 415)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), client.calls.Load())
+}
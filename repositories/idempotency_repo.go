@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/database"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+
+	"github.com/stephenafamo/bob/dialect/sqlite"
+	"github.com/stephenafamo/bob/dialect/sqlite/dm"
+	"github.com/stephenafamo/bob/dialect/sqlite/im"
+	"github.com/stephenafamo/bob/dialect/sqlite/sm"
+	"github.com/stephenafamo/bob/dialect/sqlite/um"
+)
+
+type IdempotencyRepo struct {
+	logger *zerolog.Logger
+	db     *database.DB
+}
+
+// NewIdempotencyRepo returns a new IdempotencyRepo, which stores the idempotency keys of requests together with their response.
+func NewIdempotencyRepo(logger *zerolog.Logger, db *database.DB) *IdempotencyRepo {
+	return &IdempotencyRepo{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// CreateIdempotencyKey claims the key within the scope for the request with the given fingerprint, without a response yet.
+// An expired key is claimed again. ErrIdempotencyKeyExists is returned if the key is in use and not expired.
+func (repo *IdempotencyRepo) CreateIdempotencyKey(ctx context.Context, scope, key, fingerprint string, expiresAt time.Time) error {
+	queryString, args, err := sqlite.Insert(
+		im.Into("idempotency_key", "scope", "key", "fingerprint", "status_code", "content_type", "body", "expires_at"),
+		im.Values(sqlite.Arg(scope, key, fingerprint, nil, nil, nil, expiresAt.Unix())),
+		im.OnConflict("scope", "key").DoUpdate(
+			im.SetExcluded("fingerprint", "status_code", "content_type", "body", "expires_at"),
+			im.Where(sqlite.Quote("idempotency_key", "expires_at").LT(sqlite.Arg(time.Now().Unix()))),
+		),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return errors.Join(errors.New("could not build query"), err)
+	}
+
+	res, err := repo.db.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return errors.Join(errors.New("could not execute query"), err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not get affected rows")
+		return errors.Join(errors.New("could not get affected rows"), err)
+	}
+	if affected == 0 {
+		return models.ErrIdempotencyKeyExists
+	}
+	return nil
+}
+
+// GetIdempotencyKey returns the key within the scope, with its response if the request is handled already.
+// ErrIdempotencyKeyNotFound is returned if the key doesn't exist or is expired.
+func (repo *IdempotencyRepo) GetIdempotencyKey(ctx context.Context, scope, key string) (*models.IdempotencyKey, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("idempotency_key"),
+		sm.Columns("fingerprint", "status_code", "content_type", "body"),
+		sm.Where(sqlite.Quote("scope").EQ(sqlite.Arg(scope))),
+		sm.Where(sqlite.Quote("key").EQ(sqlite.Arg(key))),
+		sm.Where(sqlite.Quote("expires_at").GTE(sqlite.Arg(time.Now().Unix()))),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	res := &models.IdempotencyKey{}
+	var statusCode sql.NullInt64
+	var contentType sql.NullString
+	var body []byte
+	err = repo.db.Reader().QueryRowContext(ctx, queryString, args...).Scan(&res.Fingerprint, &statusCode, &contentType, &body)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrIdempotencyKeyNotFound
+	}
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+
+	if statusCode.Valid {
+		res.Response = &models.IdempotentResponse{
+			StatusCode:  int(statusCode.Int64),
+			ContentType: contentType.String,
+			Body:        body,
+		}
+	}
+	return res, nil
+}
+
+// SaveIdempotentResponse stores the response of the request the key within the scope was claimed for, and extends its expiry.
+// ErrIdempotencyKeyNotFound is returned if the key doesn't exist.
+func (repo *IdempotencyRepo) SaveIdempotentResponse(ctx context.Context, scope, key string, response *models.IdempotentResponse, expiresAt time.Time) error {
+	queryString, args, err := sqlite.Update(
+		um.Table("idempotency_key"),
+		um.SetCol("status_code").ToArg(response.StatusCode),
+		um.SetCol("content_type").ToArg(response.ContentType),
+		um.SetCol("body").ToArg(response.Body),
+		um.SetCol("expires_at").ToArg(expiresAt.Unix()),
+		um.Where(sqlite.Quote("scope").EQ(sqlite.Arg(scope))),
+		um.Where(sqlite.Quote("key").EQ(sqlite.Arg(key))),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return errors.Join(errors.New("could not build query"), err)
+	}
+
+	res, err := repo.db.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return errors.Join(errors.New("could not execute query"), err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not get affected rows")
+		return errors.Join(errors.New("could not get affected rows"), err)
+	}
+	if affected == 0 {
+		return models.ErrIdempotencyKeyNotFound
+	}
+	return nil
+}
+
+// DeleteIdempotencyKey removes the key within the scope, so it can be used again. Deleting a key that doesn't exist is a no-op.
+func (repo *IdempotencyRepo) DeleteIdempotencyKey(ctx context.Context, scope, key string) error {
+	queryString, args, err := sqlite.Delete(
+		dm.From("idempotency_key"),
+		dm.Where(sqlite.Quote("scope").EQ(sqlite.Arg(scope))),
+		dm.Where(sqlite.Quote("key").EQ(sqlite.Arg(key))),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return errors.Join(errors.New("could not build query"), err)
+	}
+
+	_, err = repo.db.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return errors.Join(errors.New("could not execute query"), err)
+	}
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys removes all expired keys and returns how many were removed
+func (repo *IdempotencyRepo) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	queryString, args, err := sqlite.Delete(
+		dm.From("idempotency_key"),
+		dm.Where(sqlite.Quote("expires_at").LT(sqlite.Arg(time.Now().Unix()))),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return 0, errors.Join(errors.New("could not build query"), err)
+	}
+
+	res, err := repo.db.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return 0, errors.Join(errors.New("could not execute query"), err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not get affected rows")
+		return 0, errors.Join(errors.New("could not get affected rows"), err)
+	}
+	return affected, nil
+}
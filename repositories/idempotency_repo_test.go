@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/database"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyRepo(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := database.Init(&logger, ":memory:")
+	defer db.Close()
+	repo := NewIdempotencyRepo(&logger, db)
+	ctx := context.TODO()
+
+	err := repo.CreateIdempotencyKey(ctx, "player-42", "key-1", "a-fingerprint", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	// While the request is handled, the key has no response and can't be claimed again
+	key, err := repo.GetIdempotencyKey(ctx, "player-42", "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, &models.IdempotencyKey{Fingerprint: "a-fingerprint"}, key)
+	err = repo.CreateIdempotencyKey(ctx, "player-42", "key-1", "another-fingerprint", time.Now().Add(time.Minute))
+	assert.Equal(t, models.ErrIdempotencyKeyExists, err)
+
+	// Keys are scoped, so another caller can use the same key
+	err = repo.CreateIdempotencyKey(ctx, "player-43", "key-1", "another-fingerprint", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	response := &models.IdempotentResponse{StatusCode: 200, ContentType: "application/json", Body: []byte(`{"id":"abc"}`)}
+	err = repo.SaveIdempotentResponse(ctx, "player-42", "key-1", response, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	key, err = repo.GetIdempotencyKey(ctx, "player-42", "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, &models.IdempotencyKey{Fingerprint: "a-fingerprint", Response: response}, key)
+
+	err = repo.SaveIdempotentResponse(ctx, "player-42", "key-2", response, time.Now().Add(time.Hour))
+	assert.Equal(t, models.ErrIdempotencyKeyNotFound, err)
+
+	// A deleted key can be claimed again
+	err = repo.DeleteIdempotencyKey(ctx, "player-43", "key-1")
+	require.NoError(t, err)
+	_, err = repo.GetIdempotencyKey(ctx, "player-43", "key-1")
+	assert.Equal(t, models.ErrIdempotencyKeyNotFound, err)
+	err = repo.CreateIdempotencyKey(ctx, "player-43", "key-1", "a-fingerprint", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	// Expired keys are not found, can be claimed again and are cleaned up
+	err = repo.CreateIdempotencyKey(ctx, "player-42", "key-2", "a-fingerprint", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	_, err = repo.GetIdempotencyKey(ctx, "player-42", "key-2")
+	assert.Equal(t, models.ErrIdempotencyKeyNotFound, err)
+	err = repo.CreateIdempotencyKey(ctx, "player-42", "key-2", "another-fingerprint", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	deleted, err := repo.DeleteExpiredIdempotencyKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	_, err = repo.GetIdempotencyKey(ctx, "player-42", "key-1")
+	require.NoError(t, err)
+}
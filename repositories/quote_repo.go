@@ -0,0 +1,628 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/database"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/sqlite"
+	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
+	"github.com/stephenafamo/bob/dialect/sqlite/dm"
+	"github.com/stephenafamo/bob/dialect/sqlite/im"
+	"github.com/stephenafamo/bob/dialect/sqlite/sm"
+	"github.com/stephenafamo/bob/dialect/sqlite/um"
+)
+
+// adminQuoteIDStart is the first id given to quotes created by admins, so they never collide with the ids of dummyjson
+const adminQuoteIDStart = 1_000_000
+
+type QuoteRepo struct {
+	logger *zerolog.Logger
+	db     *database.DB
+}
+
+// NewQuoteRepo returns a new QuoteRepo, which manages the local catalogue of quotes.
+func NewQuoteRepo(logger *zerolog.Logger, db *database.DB) *QuoteRepo {
+	return &QuoteRepo{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// ReplaceQuotes replaces the catalogue with the given quotes. Existing quotes are updated, new quotes are inserted
+// and quotes that are no longer present are removed. This all happens in a single transaction, so readers never see a partial catalogue.
+// Quotes that are created, edited, hidden or deleted by an admin are left untouched, so curation survives a sync.
+func (repo *QuoteRepo) ReplaceQuotes(ctx context.Context, quotes []*models.Quote) error {
+	syncedAt := time.Now()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not start transaction")
+		return errors.Join(errors.New("could not start transaction"), err)
+	}
+	defer tx.Rollback() //nolint:errcheck // the rollback is a no-op after a successful commit
+
+	for _, q := range quotes {
+		queryString, args, err := sqlite.Insert(
+			im.Into("quote", "id", "quote", "author", "language", "synced_at"),
+			im.Values(sqlite.Arg(q.ID, q.Quote, q.Author, q.Language, syncedAt)),
+			im.OnConflict("id").DoUpdate(
+				im.SetExcluded("quote", "author", "language", "synced_at"),
+				im.Where(sqlite.Quote("quote", "source").EQ(sqlite.Arg(models.QuoteSourceDummyJson)).
+					And(sqlite.Quote("quote", "edited_at").IsNull())),
+			),
+		).Build(ctx)
+		if err != nil {
+			repo.logger.Error().Err(err).Msg("could not build query")
+			return errors.Join(errors.New("could not build query"), err)
+		}
+
+		_, err = tx.ExecContext(ctx, queryString, args...)
+		if err != nil {
+			repo.logger.Error().Err(err).Msg("could not execute query")
+			return errors.Join(errors.New("could not execute query"), err)
+		}
+	}
+
+	// Everything that wasn't touched by this sync is no longer part of the catalogue.
+	// Hidden and deleted quotes are kept, so they stay blocked if dummyjson ever returns them again
+	queryString, args, err := sqlite.Delete(
+		dm.From("quote"),
+		dm.Where(sqlite.Quote("synced_at").NE(sqlite.Arg(syncedAt))),
+		dm.Where(sqlite.Quote("source").EQ(sqlite.Arg(models.QuoteSourceDummyJson))),
+		dm.Where(sqlite.Quote("edited_at").IsNull()),
+		dm.Where(sqlite.Quote("hidden").EQ(sqlite.Arg(false))),
+		dm.Where(sqlite.Quote("deleted_at").IsNull()),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return errors.Join(errors.New("could not build query"), err)
+	}
+
+	_, err = tx.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return errors.Join(errors.New("could not execute query"), err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not commit transaction")
+		return errors.Join(errors.New("could not commit transaction"), err)
+	}
+	return nil
+}
+
+// ListQuotes returns a page of visible quotes from the catalogue matching the filter.
+// Without a search the quotes are ordered by id, with a search they are ordered by relevance.
+func (repo *QuoteRepo) ListQuotes(ctx context.Context, filter models.QuoteFilter) (*models.Page[*models.Quote], error) {
+	filterMods := []bob.Mod[*dialect.SelectQuery]{
+		sm.From("quote"),
+		sm.Where(visibleQuote()),
+	}
+	orderBy := sqlite.Quote("quote", "id")
+
+	if filter.Author != "" {
+		filterMods = append(filterMods, sm.Where(sqlite.Quote("quote", "author").EQ(sqlite.Arg(filter.Author))))
+	}
+	if search := ftsQuery(filter.Search); search != "" {
+		filterMods = append(filterMods,
+			sm.InnerJoin("quote_fts").On(sqlite.Quote("quote_fts", "rowid").EQ(sqlite.Quote("quote", "id"))),
+			sm.Where(sqlite.Raw("quote_fts MATCH ?", search)),
+		)
+		orderBy = sqlite.Quote("quote_fts", "rank")
+	}
+
+	page := &models.Page[*models.Quote]{
+		Items:  []*models.Quote{},
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+
+	err := repo.count(ctx, filterMods, &page.Total, "quote.id")
+	if err != nil {
+		return nil, err
+	}
+
+	queryString, args, err := sqlite.Select(append(filterMods,
+		sm.Columns(sqlite.Quote("quote", "id"), sqlite.Quote("quote", "quote"), sqlite.Quote("quote", "author"), sqlite.Quote("quote", "language")),
+		sm.OrderBy(orderBy),
+		sm.Limit(filter.Limit),
+		sm.Offset(filter.Offset),
+	)...).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	rows, err := repo.db.Reader().QueryContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		q := &models.Quote{}
+		err = rows.Scan(&q.ID, &q.Quote, &q.Author, &q.Language)
+		if err != nil {
+			repo.logger.Error().Err(err).Msg("could not scan row")
+			return nil, errors.Join(errors.New("could not scan row"), err)
+		}
+		page.Items = append(page.Items, q)
+	}
+	if err = rows.Err(); err != nil {
+		repo.logger.Error().Err(err).Msg("could not iterate rows")
+		return nil, errors.Join(errors.New("could not iterate rows"), err)
+	}
+
+	return page, nil
+}
+
+// GetQuote returns a single visible quote from the catalogue, or ErrQuoteNotFound if it doesn't exist
+func (repo *QuoteRepo) GetQuote(ctx context.Context, id int) (*models.Quote, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("quote"),
+		sm.Columns("id", "quote", "author", "language"),
+		sm.Where(sqlite.Quote("id").EQ(sqlite.Arg(id))),
+		sm.Where(visibleQuote()),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	q := &models.Quote{}
+	err = repo.db.Reader().QueryRowContext(ctx, queryString, args...).Scan(&q.ID, &q.Quote, &q.Author, &q.Language)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrQuoteNotFound
+	}
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	return q, nil
+}
+
+// ListAuthors returns a page of all authors in the catalogue, ordered by name, together with the number of visible quotes they have in the catalogue
+func (repo *QuoteRepo) ListAuthors(ctx context.Context, limit, offset int) (*models.Page[*models.AuthorQuoteCount], error) {
+	page := &models.Page[*models.AuthorQuoteCount]{
+		Items:  []*models.AuthorQuoteCount{},
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	err := repo.count(ctx, []bob.Mod[*dialect.SelectQuery]{
+		sm.From("quote"),
+		sm.Where(visibleQuote()),
+		sm.Distinct(),
+	}, &page.Total, "author")
+	if err != nil {
+		return nil, err
+	}
+
+	queryString, args, err := sqlite.Select(
+		sm.From("quote"),
+		sm.Columns("author", sqlite.F("count", "*")),
+		sm.Where(visibleQuote()),
+		sm.GroupBy("author"),
+		sm.OrderBy("author"),
+		sm.Limit(limit),
+		sm.Offset(offset),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	rows, err := repo.db.Reader().QueryContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a := &models.AuthorQuoteCount{}
+		err = rows.Scan(&a.Author, &a.QuoteCount)
+		if err != nil {
+			repo.logger.Error().Err(err).Msg("could not scan row")
+			return nil, errors.Join(errors.New("could not scan row"), err)
+		}
+		page.Items = append(page.Items, a)
+	}
+	if err = rows.Err(); err != nil {
+		repo.logger.Error().Err(err).Msg("could not iterate rows")
+		return nil, errors.Join(errors.New("could not iterate rows"), err)
+	}
+
+	return page, nil
+}
+
+// GetQuoteIDs returns the ids of all visible quotes in the catalogue, ordered by id
+func (repo *QuoteRepo) GetQuoteIDs(ctx context.Context) ([]int, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("quote"),
+		sm.Columns("id"),
+		sm.Where(visibleQuote()),
+		sm.OrderBy("id"),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	rows, err := repo.db.Reader().QueryContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		err = rows.Scan(&id)
+		if err != nil {
+			repo.logger.Error().Err(err).Msg("could not scan row")
+			return nil, errors.Join(errors.New("could not scan row"), err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		repo.logger.Error().Err(err).Msg("could not iterate rows")
+		return nil, errors.Join(errors.New("could not iterate rows"), err)
+	}
+
+	return ids, nil
+}
+
+// GetRandomQuotes returns the given amount of random visible quotes in the given language from the catalogue.
+// When the catalogue contains fewer of those quotes, all of them are returned. When there are none, an empty slice is returned.
+func (repo *QuoteRepo) GetRandomQuotes(ctx context.Context, amount int, language models.Language) ([]*models.Quote, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("quote"),
+		sm.Columns("id", "quote", "author", "language"),
+		sm.Where(visibleQuote()),
+		sm.Where(sqlite.Quote("language").EQ(sqlite.Arg(language))),
+		sm.OrderBy(sqlite.F("random")),
+		sm.Limit(amount),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	return repo.queryQuotes(ctx, queryString, args)
+}
+
+// GetQuotes returns the quotes with the given ids from the local store, mapped by id. Ids that are unknown are missing from the map.
+// Hidden and deleted quotes are included, so games that were started before a quote got blocked can still be finished.
+func (repo *QuoteRepo) GetQuotes(ctx context.Context, ids []int) (map[int]*models.Quote, error) {
+	idArgs := make([]any, len(ids))
+	for i, id := range ids {
+		idArgs[i] = id
+	}
+
+	queryString, args, err := sqlite.Select(
+		sm.From("quote"),
+		sm.Columns("id", "quote", "author", "language"),
+		sm.Where(sqlite.Quote("id").In(sqlite.Arg(idArgs...))),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	quotes, err := repo.queryQuotes(ctx, queryString, args)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[int]*models.Quote, len(quotes))
+	for _, q := range quotes {
+		res[q.ID] = q
+	}
+	return res, nil
+}
+
+// GetBlockedQuoteIDs returns the ids of all quotes that are hidden or deleted by an admin. These may never be shown to players,
+// also not when they are returned by dummyjson directly.
+func (repo *QuoteRepo) GetBlockedQuoteIDs(ctx context.Context) ([]int, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("quote"),
+		sm.Columns("id"),
+		sm.Where(sqlite.Not(visibleQuote())),
+		sm.OrderBy("id"),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	rows, err := repo.db.Reader().QueryContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		err = rows.Scan(&id)
+		if err != nil {
+			repo.logger.Error().Err(err).Msg("could not scan row")
+			return nil, errors.Join(errors.New("could not scan row"), err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		repo.logger.Error().Err(err).Msg("could not iterate rows")
+		return nil, errors.Join(errors.New("could not iterate rows"), err)
+	}
+
+	return ids, nil
+}
+
+// CreateQuote adds a new quote to the local store and returns it. Admin quotes get ids starting at adminQuoteIDStart.
+func (repo *QuoteRepo) CreateQuote(ctx context.Context, edit models.QuoteEdit) (*models.CuratedQuote, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not start transaction")
+		return nil, errors.Join(errors.New("could not start transaction"), err)
+	}
+	defer tx.Rollback() //nolint:errcheck // the rollback is a no-op after a successful commit
+
+	// The next id is determined within the transaction, so concurrent creates can't get the same id
+	queryString, args, err := sqlite.Select(
+		sm.From("quote"),
+		sm.Columns(sqlite.Raw("max(coalesce(max(id), 0) + 1, ?)", adminQuoteIDStart)),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	var id int
+	err = tx.QueryRowContext(ctx, queryString, args...).Scan(&id)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+
+	now := time.Now().UTC()
+	queryString, args, err = sqlite.Insert(
+		im.Into("quote", "id", "quote", "author", "language", "synced_at", "source", "edited_at", "hidden"),
+		im.Values(sqlite.Arg(id, edit.Quote, edit.Author, edit.Language, now, models.QuoteSourceAdmin, now, edit.Hidden)),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	_, err = tx.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not commit transaction")
+		return nil, errors.Join(errors.New("could not commit transaction"), err)
+	}
+
+	return repo.GetCuratedQuote(ctx, id)
+}
+
+// UpdateQuote overwrites the quote, author, language and hidden state of a quote that is not deleted and returns the result.
+// Edited quotes are no longer updated by the catalogue sync. ErrQuoteNotFound is returned if the quote doesn't exist.
+func (repo *QuoteRepo) UpdateQuote(ctx context.Context, id int, edit models.QuoteEdit) (*models.CuratedQuote, error) {
+	queryString, args, err := sqlite.Update(
+		um.Table("quote"),
+		um.SetCol("quote").ToArg(edit.Quote),
+		um.SetCol("author").ToArg(edit.Author),
+		um.SetCol("language").ToArg(edit.Language),
+		um.SetCol("hidden").ToArg(edit.Hidden),
+		um.SetCol("edited_at").ToArg(time.Now().UTC()),
+		um.Where(sqlite.Quote("id").EQ(sqlite.Arg(id))),
+		um.Where(sqlite.Quote("deleted_at").IsNull()),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	err = repo.execAffectingQuote(ctx, queryString, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.GetCuratedQuote(ctx, id)
+}
+
+// DeleteQuote soft-deletes a quote. The quote is kept as a tombstone, so the catalogue sync doesn't bring it back
+// and games never use it, even when dummyjson returns it. ErrQuoteNotFound is returned if the quote doesn't exist.
+func (repo *QuoteRepo) DeleteQuote(ctx context.Context, id int) error {
+	queryString, args, err := sqlite.Update(
+		um.Table("quote"),
+		um.SetCol("deleted_at").ToArg(time.Now().UTC()),
+		um.Where(sqlite.Quote("id").EQ(sqlite.Arg(id))),
+		um.Where(sqlite.Quote("deleted_at").IsNull()),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return errors.Join(errors.New("could not build query"), err)
+	}
+
+	return repo.execAffectingQuote(ctx, queryString, args)
+}
+
+// GetCuratedQuote returns a quote that is not deleted, including hidden quotes, together with its curation state.
+// ErrQuoteNotFound is returned if the quote doesn't exist.
+func (repo *QuoteRepo) GetCuratedQuote(ctx context.Context, id int) (*models.CuratedQuote, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("quote"),
+		sm.Columns("id", "quote", "author", "language", "source", "hidden"),
+		sm.Where(sqlite.Quote("id").EQ(sqlite.Arg(id))),
+		sm.Where(sqlite.Quote("deleted_at").IsNull()),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	q := &models.CuratedQuote{}
+	err = repo.db.Reader().QueryRowContext(ctx, queryString, args...).Scan(&q.ID, &q.Quote.Quote, &q.Author, &q.Language, &q.Source, &q.Hidden)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrQuoteNotFound
+	}
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	return q, nil
+}
+
+// GetDailyQuote returns the stored quote of the given day (YYYY-MM-DD), or ErrDailyQuoteNotFound if none has been chosen yet
+func (repo *QuoteRepo) GetDailyQuote(ctx context.Context, date string) (*models.DailyQuote, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("daily_quote"),
+		sm.Columns("date", "quote_id", "quote", "author", "language"),
+		sm.Where(sqlite.Quote("date").EQ(sqlite.Arg(date))),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	dq := &models.DailyQuote{}
+	err = repo.db.Reader().QueryRowContext(ctx, queryString, args...).Scan(&dq.Date, &dq.Quote.ID, &dq.Quote.Quote, &dq.Quote.Author, &dq.Quote.Language)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrDailyQuoteNotFound
+	}
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	return dq, nil
+}
+
+// CreateDailyQuote stores the quote as the quote of the given day (YYYY-MM-DD) and returns the stored quote of the day.
+// If another request already stored a quote for this day, that one is kept and returned instead, so everyone gets the same quote.
+func (repo *QuoteRepo) CreateDailyQuote(ctx context.Context, date string, quote *models.Quote) (*models.DailyQuote, error) {
+	queryString, args, err := sqlite.Insert(
+		im.OrIgnore(),
+		im.Into("daily_quote", "date", "quote_id", "quote", "author", "language", "created_at"),
+		im.Values(sqlite.Arg(date, quote.ID, quote.Quote, quote.Author, quote.Language, time.Now().UTC())),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	_, err = repo.db.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+
+	return repo.GetDailyQuote(ctx, date)
+}
+
+// count counts the rows selected by the given mods and scans the result into dest.
+// When columns are given, the rows are counted over those columns instead of the full row, which is useful together with sm.Distinct
+func (repo *QuoteRepo) count(ctx context.Context, mods []bob.Mod[*dialect.SelectQuery], dest *int, columns ...string) error {
+	counted := "*"
+	if len(columns) > 0 {
+		counted = strings.Join(columns, ", ")
+	}
+
+	// We wrap the query, so joins and distinct selections are counted correctly
+	inner := sqlite.Select(append(mods, sm.Columns(sqlite.Raw(counted)))...)
+	queryString, args, err := sqlite.Select(
+		sm.From(inner).As("counted"),
+		sm.Columns(sqlite.F("count", "*")),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return errors.Join(errors.New("could not build query"), err)
+	}
+
+	err = repo.db.Reader().QueryRowContext(ctx, queryString, args...).Scan(dest)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return errors.Join(errors.New("could not execute query"), err)
+	}
+	return nil
+}
+
+// execAffectingQuote executes a statement that should affect exactly one quote and returns ErrQuoteNotFound if it affected none
+func (repo *QuoteRepo) execAffectingQuote(ctx context.Context, queryString string, args []any) error {
+	res, err := repo.db.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return errors.Join(errors.New("could not execute query"), err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not get affected rows")
+		return errors.Join(errors.New("could not get affected rows"), err)
+	}
+	if affected == 0 {
+		return models.ErrQuoteNotFound
+	}
+	return nil
+}
+
+// queryQuotes executes a query selecting the id, quote, author and language of quotes and scans the results
+func (repo *QuoteRepo) queryQuotes(ctx context.Context, queryString string, args []any) ([]*models.Quote, error) {
+	rows, err := repo.db.Reader().QueryContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	defer rows.Close()
+
+	quotes := []*models.Quote{}
+	for rows.Next() {
+		q := &models.Quote{}
+		err = rows.Scan(&q.ID, &q.Quote, &q.Author, &q.Language)
+		if err != nil {
+			repo.logger.Error().Err(err).Msg("could not scan row")
+			return nil, errors.Join(errors.New("could not scan row"), err)
+		}
+		quotes = append(quotes, q)
+	}
+	if err = rows.Err(); err != nil {
+		repo.logger.Error().Err(err).Msg("could not iterate rows")
+		return nil, errors.Join(errors.New("could not iterate rows"), err)
+	}
+
+	return quotes, nil
+}
+
+// visibleQuote matches the quotes that may be shown to players, so neither hidden nor deleted by an admin
+func visibleQuote() bob.Expression {
+	return sqlite.Quote("quote", "hidden").EQ(sqlite.Arg(false)).
+		And(sqlite.Quote("quote", "deleted_at").IsNull())
+}
+
+// ftsQuery turns free text into a fts5 query in which every word has to match as a prefix.
+// Every word is quoted, so users can't (accidentally) use the fts5 query syntax and cause syntax errors.
+func ftsQuery(search string) string {
+	words := strings.Fields(search)
+	for i, w := range words {
+		words[i] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"*`
+	}
+	return strings.Join(words, " ")
+}
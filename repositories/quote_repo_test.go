@@ -0,0 +1,341 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pietdevries94/Kabisa/database"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedQuoteCatalogue creates a fresh inmem db with a small quote catalogue
+func seedQuoteCatalogue(t *testing.T, logger *zerolog.Logger) *database.DB {
+	t.Helper()
+
+	db := database.Init(logger, ":memory:")
+	err := NewQuoteRepo(logger, db).ReplaceQuotes(context.TODO(), []*models.Quote{
+		{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi", Language: models.LanguageEnglish},
+		{ID: 172, Quote: "The only lasting beauty is the beauty of the heart.", Author: "Rumi", Language: models.LanguageEnglish},
+		{ID: 414, Quote: "When We Lose One Blessing, Another Is Often Most Unexpectedly Given In Its Place.", Author: "C. S. Lewis", Language: models.LanguageEnglish},
+		{ID: 451, Quote: "We should not give up and we should not allow the problem to defeat us.", Author: "Abdul Kalam", Language: models.LanguageEnglish},
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestQuoteRepo_ReplaceQuotes(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := seedQuoteCatalogue(t, &logger)
+	defer db.Close()
+	repo := NewQuoteRepo(&logger, db)
+
+	// A second sync updates, inserts and removes quotes
+	err := repo.ReplaceQuotes(context.TODO(), []*models.Quote{
+		{ID: 70, Quote: "The cure for the pain is in the pain.", Author: "Rumi", Language: models.LanguageEnglish},
+		{ID: 905, Quote: "Try as much as you can to mention death.", Author: "Umar ibn Al-Khattāb (R.A)", Language: models.LanguageEnglish},
+	})
+	require.NoError(t, err)
+
+	page, err := repo.ListQuotes(context.TODO(), models.QuoteFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, &models.Page[*models.Quote]{
+		Items: []*models.Quote{
+			{ID: 70, Quote: "The cure for the pain is in the pain.", Author: "Rumi", Language: models.LanguageEnglish},
+			{ID: 905, Quote: "Try as much as you can to mention death.", Author: "Umar ibn Al-Khattāb (R.A)", Language: models.LanguageEnglish},
+		},
+		Total: 2,
+		Limit: 10,
+	}, page)
+
+	// The full-text index has to follow the changes
+	page, err = repo.ListQuotes(context.TODO(), models.QuoteFilter{Search: "beauty", Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+
+	page, err = repo.ListQuotes(context.TODO(), models.QuoteFilter{Search: "death", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, page.Total)
+}
+
+func TestQuoteRepo_ListQuotes(t *testing.T) {
+	type Test struct {
+		filter        models.QuoteFilter
+		expectedIDs   []int
+		expectedTotal int
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			db := seedQuoteCatalogue(t, &logger)
+			defer db.Close()
+
+			res, err := NewQuoteRepo(&logger, db).ListQuotes(context.TODO(), tt.filter)
+			require.NoError(t, err)
+
+			ids := []int{}
+			for _, q := range res.Items {
+				ids = append(ids, q.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+			assert.Equal(t, tt.expectedTotal, res.Total)
+			assert.Equal(t, tt.filter.Limit, res.Limit)
+			assert.Equal(t, tt.filter.Offset, res.Offset)
+		}
+	}
+
+	t.Run("returns all quotes ordered by id", run(Test{
+		filter:        models.QuoteFilter{Limit: 10},
+		expectedIDs:   []int{70, 172, 414, 451},
+		expectedTotal: 4,
+	}))
+
+	t.Run("paginates using limit and offset", run(Test{
+		filter:        models.QuoteFilter{Limit: 2, Offset: 1},
+		expectedIDs:   []int{172, 414},
+		expectedTotal: 4,
+	}))
+
+	t.Run("filters by author", run(Test{
+		filter:        models.QuoteFilter{Author: "Rumi", Limit: 10},
+		expectedIDs:   []int{70, 172},
+		expectedTotal: 2,
+	}))
+
+	t.Run("searches the quote text by word prefixes", run(Test{
+		filter:        models.QuoteFilter{Search: "beau HEART", Limit: 10},
+		expectedIDs:   []int{172},
+		expectedTotal: 1,
+	}))
+
+	t.Run("combines search and author filter and orders by relevance", run(Test{
+		filter:        models.QuoteFilter{Search: "the", Author: "Rumi", Limit: 1},
+		expectedIDs:   []int{172},
+		expectedTotal: 2,
+	}))
+
+	t.Run("does not choke on fts syntax in the search", run(Test{
+		filter:        models.QuoteFilter{Search: `pain" OR NEAR(`, Limit: 10},
+		expectedIDs:   []int{},
+		expectedTotal: 0,
+	}))
+}
+
+func TestQuoteRepo_GetQuote(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := seedQuoteCatalogue(t, &logger)
+	defer db.Close()
+	repo := NewQuoteRepo(&logger, db)
+
+	res, err := repo.GetQuote(context.TODO(), 414)
+	require.NoError(t, err)
+	assert.Equal(t, &models.Quote{ID: 414, Quote: "When We Lose One Blessing, Another Is Often Most Unexpectedly Given In Its Place.", Author: "C. S. Lewis", Language: models.LanguageEnglish}, res)
+
+	res, err = repo.GetQuote(context.TODO(), 1)
+	assert.Equal(t, models.ErrQuoteNotFound, err)
+	assert.Nil(t, res)
+}
+
+func TestQuoteRepo_ListAuthors(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := seedQuoteCatalogue(t, &logger)
+	defer db.Close()
+
+	res, err := NewQuoteRepo(&logger, db).ListAuthors(context.TODO(), 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, &models.Page[*models.AuthorQuoteCount]{
+		Items: []*models.AuthorQuoteCount{
+			{Author: "C. S. Lewis", QuoteCount: 1},
+			{Author: "Rumi", QuoteCount: 2},
+		},
+		Total:  3,
+		Limit:  2,
+		Offset: 1,
+	}, res)
+}
+
+func TestQuoteRepo_GetQuoteIDs(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := seedQuoteCatalogue(t, &logger)
+	defer db.Close()
+
+	res, err := NewQuoteRepo(&logger, db).GetQuoteIDs(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, []int{70, 172, 414, 451}, res)
+}
+
+func TestQuoteRepo_DailyQuote(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := seedQuoteCatalogue(t, &logger)
+	defer db.Close()
+	repo := NewQuoteRepo(&logger, db)
+
+	res, err := repo.GetDailyQuote(context.TODO(), "2025-02-01")
+	assert.Equal(t, models.ErrDailyQuoteNotFound, err)
+	assert.Nil(t, res)
+
+	res, err = repo.CreateDailyQuote(context.TODO(), "2025-02-01", &models.Quote{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi", Language: models.LanguageEnglish})
+	require.NoError(t, err)
+	assert.Equal(t, &models.DailyQuote{Date: "2025-02-01", Quote: models.Quote{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi", Language: models.LanguageEnglish}}, res)
+
+	// A second choice for the same day is ignored, the first one stays
+	res, err = repo.CreateDailyQuote(context.TODO(), "2025-02-01", &models.Quote{ID: 172, Quote: "The only lasting beauty is the beauty of the heart.", Author: "Rumi", Language: models.LanguageEnglish})
+	require.NoError(t, err)
+	assert.Equal(t, 70, res.Quote.ID)
+
+	// The stored quote doesn't depend on the catalogue anymore
+	err = repo.ReplaceQuotes(context.TODO(), []*models.Quote{{ID: 1, Quote: "Something else", Author: "Someone", Language: models.LanguageEnglish}})
+	require.NoError(t, err)
+	res, err = repo.GetDailyQuote(context.TODO(), "2025-02-01")
+	require.NoError(t, err)
+	assert.Equal(t, &models.DailyQuote{Date: "2025-02-01", Quote: models.Quote{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi", Language: models.LanguageEnglish}}, res)
+
+	var createdAt string
+	require.NoError(t, db.QueryRow("select created_at || '' from daily_quote where date = '2025-02-01'").Scan(&createdAt))
+	assert.True(t, strings.HasSuffix(createdAt, "+00:00"), createdAt)
+}
+
+func TestQuoteRepo_Curation(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := seedQuoteCatalogue(t, &logger)
+	defer db.Close()
+	repo := NewQuoteRepo(&logger, db)
+
+	// An admin adds a quote, edits one from dummyjson, hides one and deletes one
+	created, err := repo.CreateQuote(context.TODO(), models.QuoteEdit{Quote: "A quote added by an admin.", Author: "An admin", Language: models.LanguageEnglish})
+	require.NoError(t, err)
+	assert.Equal(t, &models.CuratedQuote{
+		Quote:  models.Quote{ID: 1000000, Quote: "A quote added by an admin.", Author: "An admin", Language: models.LanguageEnglish},
+		Source: models.QuoteSourceAdmin,
+	}, created)
+
+	updated, err := repo.UpdateQuote(context.TODO(), 70, models.QuoteEdit{Quote: "The cure for the pain is in the pain.", Author: "Rumi", Language: models.LanguageEnglish})
+	require.NoError(t, err)
+	assert.Equal(t, &models.CuratedQuote{
+		Quote:  models.Quote{ID: 70, Quote: "The cure for the pain is in the pain.", Author: "Rumi", Language: models.LanguageEnglish},
+		Source: models.QuoteSourceDummyJson,
+	}, updated)
+
+	hidden, err := repo.UpdateQuote(context.TODO(), 172, models.QuoteEdit{Quote: "The only lasting beauty is the beauty of the heart.", Author: "Rumi", Language: models.LanguageEnglish, Hidden: true})
+	require.NoError(t, err)
+	assert.True(t, hidden.Hidden)
+
+	err = repo.DeleteQuote(context.TODO(), 414)
+	require.NoError(t, err)
+
+	// Deleted quotes can't be edited or deleted again
+	_, err = repo.UpdateQuote(context.TODO(), 414, models.QuoteEdit{Quote: "Something", Author: "Someone", Language: models.LanguageEnglish})
+	assert.Equal(t, models.ErrQuoteNotFound, err)
+	err = repo.DeleteQuote(context.TODO(), 414)
+	assert.Equal(t, models.ErrQuoteNotFound, err)
+	err = repo.DeleteQuote(context.TODO(), 1)
+	assert.Equal(t, models.ErrQuoteNotFound, err)
+
+	// A new sync returns the original quotes, but may not undo the curation
+	err = repo.ReplaceQuotes(context.TODO(), []*models.Quote{
+		{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi", Language: models.LanguageEnglish},
+		{ID: 172, Quote: "The only lasting beauty is the beauty of the heart.", Author: "Rumi", Language: models.LanguageEnglish},
+		{ID: 414, Quote: "When We Lose One Blessing, Another Is Often Most Unexpectedly Given In Its Place.", Author: "C. S. Lewis", Language: models.LanguageEnglish},
+	})
+	require.NoError(t, err)
+
+	// Only visible quotes are shown to players
+	page, err := repo.ListQuotes(context.TODO(), models.QuoteFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Quote{
+		{ID: 70, Quote: "The cure for the pain is in the pain.", Author: "Rumi", Language: models.LanguageEnglish},
+		{ID: 1000000, Quote: "A quote added by an admin.", Author: "An admin", Language: models.LanguageEnglish},
+	}, page.Items)
+
+	_, err = repo.GetQuote(context.TODO(), 172)
+	assert.Equal(t, models.ErrQuoteNotFound, err)
+
+	ids, err := repo.GetQuoteIDs(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, []int{70, 1000000}, ids)
+
+	random, err := repo.GetRandomQuotes(context.TODO(), 10, models.LanguageEnglish)
+	require.NoError(t, err)
+	assert.Len(t, random, 2)
+
+	// Blocked quotes are known, so they can be filtered from dummyjson as well
+	blocked, err := repo.GetBlockedQuoteIDs(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, []int{172, 414}, blocked)
+
+	// Games that already contain a blocked quote can still be finished
+	quotes, err := repo.GetQuotes(context.TODO(), []int{70, 172, 414, 1})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]*models.Quote{
+		70:  {ID: 70, Quote: "The cure for the pain is in the pain.", Author: "Rumi", Language: models.LanguageEnglish},
+		172: {ID: 172, Quote: "The only lasting beauty is the beauty of the heart.", Author: "Rumi", Language: models.LanguageEnglish},
+		414: {ID: 414, Quote: "When We Lose One Blessing, Another Is Often Most Unexpectedly Given In Its Place.", Author: "C. S. Lewis", Language: models.LanguageEnglish},
+	}, quotes)
+
+	// Hidden quotes can be made visible again
+	visible, err := repo.UpdateQuote(context.TODO(), 172, models.QuoteEdit{Quote: "The only lasting beauty is the beauty of the heart.", Author: "Rumi", Language: models.LanguageEnglish})
+	require.NoError(t, err)
+	assert.False(t, visible.Hidden)
+
+	// The next quote created by an admin gets the next id
+	created, err = repo.CreateQuote(context.TODO(), models.QuoteEdit{Quote: "Another quote added by an admin.", Author: "An admin", Language: models.LanguageEnglish, Hidden: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1000001, created.ID)
+	assert.True(t, created.Hidden)
+
+	// The times of the curation are written in UTC
+	var editedAt, deletedAt, createdAt string
+	require.NoError(t, db.QueryRow("select edited_at || '' from quote where id = 70").Scan(&editedAt))
+	require.NoError(t, db.QueryRow("select deleted_at || '' from quote where id = 414").Scan(&deletedAt))
+	require.NoError(t, db.QueryRow("select edited_at || '' from quote where id = 1000001").Scan(&createdAt))
+	for _, ts := range []string{editedAt, deletedAt, createdAt} {
+		assert.True(t, strings.HasSuffix(ts, "+00:00"), ts)
+	}
+}
+
+func TestQuoteRepo_Languages(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := seedQuoteCatalogue(t, &logger)
+	defer db.Close()
+	repo := NewQuoteRepo(&logger, db)
+
+	// Without Dutch quotes, none are drawn
+	random, err := repo.GetRandomQuotes(context.TODO(), 10, models.LanguageDutch)
+	require.NoError(t, err)
+	assert.Empty(t, random)
+
+	created, err := repo.CreateQuote(context.TODO(), models.QuoteEdit{Quote: "Voorkomen is beter dan genezen.", Author: "Erasmus", Language: models.LanguageDutch})
+	require.NoError(t, err)
+	assert.Equal(t, models.LanguageDutch, created.Language)
+
+	// Only quotes in the requested language are drawn
+	random, err = repo.GetRandomQuotes(context.TODO(), 10, models.LanguageDutch)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Quote{{ID: 1000000, Quote: "Voorkomen is beter dan genezen.", Author: "Erasmus", Language: models.LanguageDutch}}, random)
+	random, err = repo.GetRandomQuotes(context.TODO(), 10, models.LanguageEnglish)
+	require.NoError(t, err)
+	assert.Len(t, random, 4)
+
+	// The language survives a sync and can be corrected by an admin
+	err = repo.ReplaceQuotes(context.TODO(), []*models.Quote{{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi", Language: models.LanguageEnglish}})
+	require.NoError(t, err)
+	updated, err := repo.UpdateQuote(context.TODO(), 1000000, models.QuoteEdit{Quote: "Prevention is better than cure.", Author: "Erasmus", Language: models.LanguageEnglish})
+	require.NoError(t, err)
+	assert.Equal(t, models.LanguageEnglish, updated.Language)
+
+	random, err = repo.GetRandomQuotes(context.TODO(), 10, models.LanguageDutch)
+	require.NoError(t, err)
+	assert.Empty(t, random)
+
+	// The quote of the day keeps its language
+	dailyQuote, err := repo.CreateDailyQuote(context.TODO(), "2025-02-01", &models.Quote{ID: 1000000, Quote: "Voorkomen is beter dan genezen.", Author: "Erasmus", Language: models.LanguageDutch})
+	require.NoError(t, err)
+	assert.Equal(t, models.LanguageDutch, dailyQuote.Quote.Language)
+}
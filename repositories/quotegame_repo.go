@@ -3,17 +3,19 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/database"
 	"github.com/pietdevries94/Kabisa/models"
 	"github.com/rs/zerolog"
-	"golang.org/x/exp/slices"
 
+	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/dialect/sqlite"
+	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
 	"github.com/stephenafamo/bob/dialect/sqlite/im"
 	"github.com/stephenafamo/bob/dialect/sqlite/sm"
 	"github.com/stephenafamo/bob/dialect/sqlite/um"
@@ -21,85 +23,224 @@ import (
 
 type QuoteGameRepo struct {
 	logger *zerolog.Logger
-	db     *sql.DB
+	db     *database.DB
 }
 
 // NewQuoteGameRepo returns a new QuoteGameRepo, which creates a manages instances of the quote game.
-func NewQuoteGameRepo(logger *zerolog.Logger, db *sql.DB) *QuoteGameRepo {
+func NewQuoteGameRepo(logger *zerolog.Logger, db *database.DB) *QuoteGameRepo {
 	return &QuoteGameRepo{
 		logger: logger,
 		db:     db,
 	}
 }
 
-// CreateQuoteGame builds a new QuoteGame struct from the given quotes, stores the game in the database for later retrieval and returns the struct
-// To make a QuoteGame, the function splits the quotes from the authors and sorts them both alphabetically. As id, it uses an uuid, so players can't
-// influence each other's games by guessing valid ids.
-func (repo *QuoteGameRepo) CreateQuoteGame(ctx context.Context, quotes []*models.Quote) (*models.QuoteGame, error) {
-	// Currently the game only supports three quotes
-	if len(quotes) != 3 {
-		return nil, fmt.Errorf("number of quotes should be 3. Given: %d", len(quotes))
+// CreateQuoteGame stores the game in the database for later retrieval. The quote ids are stored in the order of game.Quotes.
+// As id, the game should use an uuid, so players can't influence each other's games by guessing valid ids. The playerID is optional and stored as null when empty.
+func (repo *QuoteGameRepo) CreateQuoteGame(ctx context.Context, game *models.QuoteGame, playerID string) error {
+	return insertQuoteGame(ctx, repo.logger, repo.db, game, playerID, "")
+}
+
+// CreateDailyQuoteGame stores a new game of the daily challenge of the given day (YYYY-MM-DD) for the player.
+// Every player can play the challenge of a day only once, so ErrDailyChallengeAlreadyPlayed is returned for a second game.
+func (repo *QuoteGameRepo) CreateDailyQuoteGame(ctx context.Context, game *models.QuoteGame, playerID string, date string) error {
+	if playerID == "" {
+		return errors.New("a daily quote game needs a player")
 	}
+	return insertQuoteGame(ctx, repo.logger, repo.db, game, playerID, date)
+}
 
-	// First we prepare a new game
-	game := &models.QuoteGame{
-		ID:      uuid.New(),
-		Quotes:  make([]*models.QuoteWithoutAuthor, len(quotes)),
-		Authors: make([]string, len(quotes)),
+// insertQuoteGame stores a new game. The dailyDate is only set for games of the daily challenge.
+// It takes the db to write to, so the game of a room can be stored in the same transaction as the room
+func insertQuoteGame(ctx context.Context, logger *zerolog.Logger, db execer, game *models.QuoteGame, playerID string, dailyDate string) error {
+	// Currently every game mode has three quotes
+	if len(game.Quotes) != 3 {
+		return fmt.Errorf("number of quotes should be 3. Given: %d", len(game.Quotes))
 	}
 
-	// We split the quotes
-	for i, q := range quotes {
-		game.Quotes[i] = &models.QuoteWithoutAuthor{
-			ID:    q.ID,
-			Quote: q.Quote,
+	// Now we build the query to store it in the database. The keys are stored in the order of the quotes,
+	// a quote without a key gets nulls
+	columns := []string{"id", "mode", "quote1_id", "quote2_id", "quote3_id", "player_id", "daily_date", "created_at"}
+	values := []any{
+		game.ID, game.Mode, game.Quotes[0].ID, game.Quotes[1].ID, game.Quotes[2].ID,
+		sql.NullString{String: playerID, Valid: playerID != ""},
+		sql.NullString{String: dailyDate, Valid: dailyDate != ""},
+		time.Now().UTC(),
+	}
+	for i, q := range game.Quotes {
+		key, ok := game.Keys[q.ID]
+		if !ok {
+			key = &models.QuoteKey{}
 		}
-		game.Authors[i] = q.Author
+		var choices sql.NullString
+		if len(key.Choices) > 0 {
+			encoded, err := json.Marshal(key.Choices)
+			if err != nil {
+				logger.Error().Err(err).Msg("could not encode choices")
+				return errors.Join(errors.New("could not encode choices"), err)
+			}
+			choices = sql.NullString{String: string(encoded), Valid: true}
+		}
+		prefix := fmt.Sprintf("quote%d_", i+1)
+		columns = append(columns, prefix+"author", prefix+"choices", prefix+"blank")
+		values = append(values,
+			sql.NullString{String: key.Author, Valid: key.Author != ""},
+			choices,
+			sql.NullString{String: key.Blank, Valid: key.Blank != ""},
+		)
+	}
+	mods := []bob.Mod[*dialect.InsertQuery]{
+		im.Into("quote_game", columns...),
+		im.Values(sqlite.Arg(values...)),
+	}
+	// A second daily game of the same player conflicts with the unique index, and is left out. Other games return every error
+	if dailyDate != "" {
+		mods = append(mods, im.OnConflict("daily_date", "player_id").Where(sqlite.Quote("daily_date").IsNotNull()).DoNothing())
+	}
+	queryString, args, err := sqlite.Insert(mods...).Build(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msg("could not build query")
+		return errors.Join(errors.New("could not build query"), err)
+	}
+
+	// Execute the query
+	res, err := db.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		logger.Error().Err(err).Msg("could not execute query")
+		return errors.Join(errors.New("could not execute query"), err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		logger.Error().Err(err).Msg("could not get affected rows")
+		return errors.Join(errors.New("could not get affected rows"), err)
+	}
+	if affected == 0 && dailyDate != "" {
+		return models.ErrDailyChallengeAlreadyPlayed
+	}
+
+	return nil
+}
+
+// GetDailyChallenge returns the quote ids of the daily challenge of the given day (YYYY-MM-DD),
+// or ErrDailyChallengeNotFound if none have been chosen yet
+func (repo *QuoteGameRepo) GetDailyChallenge(ctx context.Context, date string) ([]int, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("daily_challenge"),
+		sm.Columns("quote1_id", "quote2_id", "quote3_id"),
+		sm.Where(sqlite.Quote("date").EQ(sqlite.Arg(date))),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	quoteIDs := make([]int, 3)
+	err = repo.db.Reader().QueryRowContext(ctx, queryString, args...).Scan(&quoteIDs[0], &quoteIDs[1], &quoteIDs[2])
+	if err == sql.ErrNoRows {
+		return nil, models.ErrDailyChallengeNotFound
+	}
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
 	}
+	return quoteIDs, nil
+}
 
-	// And sort them both
-	slices.SortFunc(game.Quotes, func(a *models.QuoteWithoutAuthor, b *models.QuoteWithoutAuthor) int {
-		return strings.Compare(a.Quote, b.Quote)
-	})
-	slices.Sort(game.Authors)
+// CreateDailyChallenge stores the quote ids as the daily challenge of the given day (YYYY-MM-DD) and returns the stored ids.
+// If another request already stored a challenge for this day, that one is kept and returned instead, so everyone gets the same quotes.
+func (repo *QuoteGameRepo) CreateDailyChallenge(ctx context.Context, date string, quoteIDs []int) ([]int, error) {
+	if len(quoteIDs) != 3 {
+		return nil, fmt.Errorf("number of quotes should be 3. Given: %d", len(quoteIDs))
+	}
 
-	// Now we build the query to store it in the database
 	queryString, args, err := sqlite.Insert(
-		im.Into("quote_game", "id", "quote1_id", "quote2_id", "quote3_id", "created_at"),
-		im.Values(sqlite.Arg(game.ID, game.Quotes[0].ID, game.Quotes[1].ID, game.Quotes[2].ID, time.Now())),
+		im.OrIgnore(),
+		im.Into("daily_challenge", "date", "quote1_id", "quote2_id", "quote3_id", "created_at"),
+		im.Values(sqlite.Arg(date, quoteIDs[0], quoteIDs[1], quoteIDs[2], time.Now().UTC())),
 	).Build(ctx)
 	if err != nil {
 		repo.logger.Error().Err(err).Msg("could not build query")
 		return nil, errors.Join(errors.New("could not build query"), err)
 	}
 
-	// Execute the query
 	_, err = repo.db.ExecContext(ctx, queryString, args...)
 	if err != nil {
 		repo.logger.Error().Err(err).Msg("could not execute query")
 		return nil, errors.Join(errors.New("could not execute query"), err)
 	}
 
-	// Finally, we return the game
-	return game, nil
+	return repo.GetDailyChallenge(ctx, date)
 }
 
-// ValidateIDAndAnswerIDs gets the game information from the database, runs a couple checks and returns the quote_ids in order from the database.
-// The following checks are performed:
-//   - Does the id exist
-//   - Is the completed_at null
-//   - Is the created_at within a minute ago
-//   - Are the quote ids present in the map
-//   - Are only the quote ids present in the map
-func (repo *QuoteGameRepo) ValidateIDAndAnswerIDs(ctx context.Context, id uuid.UUID, answers models.QuoteGameAnswerMap) (quoteIDs []int, err error) {
-	// There have to be exactly three quotes in the map
-	if len(answers) != 3 {
-		return nil, models.ErrInvalidQuoteID
+// GetDailyLeaderboard returns a page of the completed games of the daily challenge of the given day (YYYY-MM-DD).
+// The best score comes first, players with the same score are ordered by who completed the challenge first.
+// Like QuoteGameResult.Score, the costs of the used hints are deducted from the score.
+func (repo *QuoteGameRepo) GetDailyLeaderboard(ctx context.Context, date string, limit, offset int) (*models.Page[*models.DailyLeaderboardEntry], error) {
+	where := []bob.Mod[*dialect.SelectQuery]{
+		sm.From("quote_game"),
+		sm.Where(sqlite.Quote("daily_date").EQ(sqlite.Arg(date))),
+		sm.Where(sqlite.Quote("completed_at").IsNotNull()),
 	}
 
+	page := &models.Page[*models.DailyLeaderboardEntry]{
+		Items:  []*models.DailyLeaderboardEntry{},
+		Limit:  limit,
+		Offset: offset,
+	}
+	countQueryString, countArgs, err := sqlite.Select(append(where, sm.Columns(sqlite.F("count", "*")))...).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+	err = repo.db.Reader().QueryRowContext(ctx, countQueryString, countArgs...).Scan(&page.Total)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+
+	score := sqlite.Raw("MAX(0, quote1_correct + quote2_correct + quote3_correct - " +
+		"COALESCE((SELECT SUM(cost) FROM quote_game_hint WHERE quote_game_hint.quote_game_id = quote_game.id), 0))")
+	queryString, args, err := sqlite.Select(append(where,
+		sm.Columns("player_id", score, "completed_at"),
+		sm.OrderBy(score).Desc(),
+		sm.OrderBy("completed_at"),
+		sm.Limit(limit),
+		sm.Offset(offset),
+	)...).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	rows, err := repo.db.Reader().QueryContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := &models.DailyLeaderboardEntry{Rank: offset + len(page.Items) + 1}
+		err = rows.Scan(&entry.PlayerID, &entry.Score, &entry.CompletedAt)
+		if err != nil {
+			repo.logger.Error().Err(err).Msg("could not scan row")
+			return nil, errors.Join(errors.New("could not scan row"), err)
+		}
+		page.Items = append(page.Items, entry)
+	}
+	if err = rows.Err(); err != nil {
+		repo.logger.Error().Err(err).Msg("could not iterate rows")
+		return nil, errors.Join(errors.New("could not iterate rows"), err)
+	}
+
+	return page, nil
+}
+
+// GetQuoteGameStatus returns the deadline of the game and whether it's answered already.
+// ErrQuoteGameIdNotFound is returned if the game doesn't exist.
+func (repo *QuoteGameRepo) GetQuoteGameStatus(ctx context.Context, id uuid.UUID) (*models.QuoteGameStatus, error) {
 	queryString, args, err := sqlite.Select(
 		sm.From("quote_game"),
-		sm.Columns("quote1_id", "quote2_id", "quote3_id", "created_at", "completed_at"),
+		sm.Columns("created_at", "completed_at"),
 		sm.Where(sqlite.Quote("id").EQ(sqlite.Arg(id))),
 	).Build(ctx)
 	if err != nil {
@@ -107,10 +248,9 @@ func (repo *QuoteGameRepo) ValidateIDAndAnswerIDs(ctx context.Context, id uuid.U
 		return nil, errors.Join(errors.New("could not build query"), err)
 	}
 
-	quoteIDs = make([]int, 3)
 	var createdAt time.Time
 	var completedAt sql.NullTime
-	err = repo.db.QueryRowContext(ctx, queryString, args...).Scan(&quoteIDs[0], &quoteIDs[1], &quoteIDs[2], &createdAt, &completedAt)
+	err = repo.db.Reader().QueryRowContext(ctx, queryString, args...).Scan(&createdAt, &completedAt)
 	if err == sql.ErrNoRows {
 		return nil, models.ErrQuoteGameIdNotFound
 	}
@@ -119,64 +259,409 @@ func (repo *QuoteGameRepo) ValidateIDAndAnswerIDs(ctx context.Context, id uuid.U
 		return nil, errors.Join(errors.New("could not execute query"), err)
 	}
 
+	return &models.QuoteGameStatus{
+		ID:        id,
+		Deadline:  createdAt.Add(models.QuoteGameDuration),
+		Completed: completedAt.Valid,
+	}, nil
+}
+
+// GetRecentQuoteIDs returns the ids of all quotes used in the last given number of games of a player, newest games first.
+func (repo *QuoteGameRepo) GetRecentQuoteIDs(ctx context.Context, playerID string, games int) ([]int, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("quote_game"),
+		sm.Columns("quote1_id", "quote2_id", "quote3_id"),
+		sm.Where(sqlite.Quote("player_id").EQ(sqlite.Arg(playerID))),
+		sm.OrderBy("created_at").Desc(),
+		sm.Limit(games),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	rows, err := repo.db.Reader().QueryContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	defer rows.Close()
+
+	quoteIDs := []int{}
+	for rows.Next() {
+		var quote1ID, quote2ID, quote3ID int
+		err = rows.Scan(&quote1ID, &quote2ID, &quote3ID)
+		if err != nil {
+			repo.logger.Error().Err(err).Msg("could not scan row")
+			return nil, errors.Join(errors.New("could not scan row"), err)
+		}
+		quoteIDs = append(quoteIDs, quote1ID, quote2ID, quote3ID)
+	}
+	if err = rows.Err(); err != nil {
+		repo.logger.Error().Err(err).Msg("could not iterate rows")
+		return nil, errors.Join(errors.New("could not iterate rows"), err)
+	}
+
+	return quoteIDs, nil
+}
+
+// ExportQuoteGames calls fn for every game created from from up to to, oldest first. The games are read one by one,
+// so an export of all games doesn't have to fit in memory. A zero from or to leaves that side of the range open.
+// When fn returns an error, the export stops with that error.
+// The shared games of rooms are left out. They are never completed, every participant answers them in the room instead.
+func (repo *QuoteGameRepo) ExportQuoteGames(ctx context.Context, from, to time.Time, fn func(*models.GameExport) error) error {
+	// The creation times are stored in UTC, so the range is compared in UTC as well
+	mods := []bob.Mod[*dialect.SelectQuery]{
+		sm.From("quote_game"),
+		sm.Columns(
+			"id", "mode", "player_id", "daily_date",
+			"quote1_id", "quote2_id", "quote3_id",
+			"quote1_correct", "quote2_correct", "quote3_correct",
+			"created_at", "completed_at",
+		),
+		sm.Where(sqlite.Raw("NOT EXISTS (SELECT 1 FROM room WHERE room.quote_game_id = quote_game.id)")),
+		sm.OrderBy("created_at"),
+	}
+	if !from.IsZero() {
+		mods = append(mods, sm.Where(sqlite.Quote("created_at").GTE(sqlite.Arg(from.UTC()))))
+	}
+	if !to.IsZero() {
+		mods = append(mods, sm.Where(sqlite.Quote("created_at").LT(sqlite.Arg(to.UTC()))))
+	}
+	queryString, args, err := sqlite.Select(mods...).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return errors.Join(errors.New("could not build query"), err)
+	}
+
+	rows, err := repo.db.Reader().QueryContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return errors.Join(errors.New("could not execute query"), err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		game := &models.GameExport{QuoteIDs: make([]int, 3)}
+		var playerID, dailyDate sql.NullString
+		var correct [3]sql.NullBool
+		var completedAt sql.NullTime
+		err = rows.Scan(
+			&game.ID, &game.Mode, &playerID, &dailyDate,
+			&game.QuoteIDs[0], &game.QuoteIDs[1], &game.QuoteIDs[2],
+			&correct[0], &correct[1], &correct[2],
+			&game.CreatedAt, &completedAt,
+		)
+		if err != nil {
+			repo.logger.Error().Err(err).Msg("could not scan row")
+			return errors.Join(errors.New("could not scan row"), err)
+		}
+		game.PlayerID = playerID.String
+		game.DailyDate = dailyDate.String
+		if completedAt.Valid {
+			game.CompletedAt = &completedAt.Time
+			game.Correct = []bool{correct[0].Bool, correct[1].Bool, correct[2].Bool}
+		}
+		err = fn(game)
+		if err != nil {
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		repo.logger.Error().Err(err).Msg("could not iterate rows")
+		return errors.Join(errors.New("could not iterate rows"), err)
+	}
+	return nil
+}
+
+// ValidateIDAndAnswerIDs gets the game information from the database, runs a couple checks and returns the mode and the quote_ids in order from the database.
+// The following checks are performed:
+//   - Does the id exist, as a game of its own. The shared game of a room is answered through the room, so it's not found here
+//   - Is the completed_at null, otherwise ErrQuoteGameCompleted is returned
+//   - Is the created_at within the duration of a game
+//   - Are the quote ids present in the map
+//   - Are only the quote ids present in the map
+func (repo *QuoteGameRepo) ValidateIDAndAnswerIDs(ctx context.Context, id uuid.UUID, answers models.QuoteGameAnswerMap) (mode models.GameMode, quoteIDs []int, err error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("quote_game"),
+		sm.Columns("mode", "quote1_id", "quote2_id", "quote3_id", "created_at", "completed_at"),
+		sm.Where(sqlite.Quote("id").EQ(sqlite.Arg(id))),
+		sm.Where(sqlite.Raw("NOT EXISTS (SELECT 1 FROM room WHERE room.quote_game_id = quote_game.id)")),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return "", nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	quoteIDs = make([]int, 3)
+	var createdAt time.Time
+	var completedAt sql.NullTime
+	err = repo.db.Reader().QueryRowContext(ctx, queryString, args...).Scan(&mode, &quoteIDs[0], &quoteIDs[1], &quoteIDs[2], &createdAt, &completedAt)
+	if err == sql.ErrNoRows {
+		return "", nil, models.ErrQuoteGameIdNotFound
+	}
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return "", nil, errors.Join(errors.New("could not execute query"), err)
+	}
+
 	// We check if the game is not completed yet
 	if completedAt.Valid {
-		return nil, models.ErrQuoteGameIdNotFound
+		return "", nil, models.ErrQuoteGameCompleted
 	}
 
 	// Or expired
-	if time.Now().After(createdAt.Add(time.Minute * 5)) {
-		return nil, models.ErrQuoteGameIdNotFound
+	if time.Now().After(createdAt.Add(models.QuoteGameDuration)) {
+		return "", nil, models.ErrQuoteGameIdNotFound
 	}
 
-	// And if the answer ids match with the quote ids
+	// And if there is exactly one answer for every quote
+	if len(answers) != len(quoteIDs) {
+		return "", nil, models.ErrInvalidQuoteID
+	}
 	for _, id := range quoteIDs {
 		if _, ok := answers[id]; !ok {
-			return nil, models.ErrInvalidQuoteID
+			return "", nil, models.ErrInvalidQuoteID
 		}
 	}
 
-	return quoteIDs, nil
+	return mode, quoteIDs, nil
+}
+
+// StoreQuoteGameResult marks the game of the result as completed and stores the submitted answers and which of them were correct.
+// The answers of the result are in the order of the quote ids of the game. A game is only completed once, so when another
+// submission completed it in the meantime, ErrQuoteGameCompleted is returned and nothing is stored.
+func (repo *QuoteGameRepo) StoreQuoteGameResult(ctx context.Context, result *models.QuoteGameResult, answers models.QuoteGameAnswerMap) error {
+	if len(result.Answers) != 3 {
+		return fmt.Errorf("number of answers should be 3. Given: %d", len(result.Answers))
+	}
+
+	queryString, args, err := sqlite.Update(
+		um.Table("quote_game"),
+		um.SetCol("quote1_correct").ToArg(result.Answers[0].Correct),
+		um.SetCol("quote2_correct").ToArg(result.Answers[1].Correct),
+		um.SetCol("quote3_correct").ToArg(result.Answers[2].Correct),
+		um.SetCol("quote1_answer").ToArg(answers[result.Answers[0].ID]),
+		um.SetCol("quote2_answer").ToArg(answers[result.Answers[1].ID]),
+		um.SetCol("quote3_answer").ToArg(answers[result.Answers[2].ID]),
+		um.SetCol("completed_at").ToArg(time.Now().UTC()),
+		um.Where(sqlite.Quote("id").EQ(sqlite.Arg(result.ID))),
+		um.Where(sqlite.Quote("completed_at").IsNull()),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return errors.Join(errors.New("could not build query"), err)
+	}
+
+	// Execute the query
+	res, err := repo.db.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return errors.Join(errors.New("could not execute query"), err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not get affected rows")
+		return errors.Join(errors.New("could not get affected rows"), err)
+	}
+	if affected == 0 {
+		return models.ErrQuoteGameCompleted
+	}
+	return nil
 }
 
-// ValidateAnswersAndCreateGameResult compares the given answers to the quote authors, compiles a result and puts it in the database.
-func (repo *QuoteGameRepo) ValidateAnswersAndCreateGameResult(ctx context.Context, id uuid.UUID, quoteIDs []int, quotes map[int]*models.Quote, answers models.QuoteGameAnswerMap) (*models.QuoteGameResult, error) {
-	gameResult := &models.QuoteGameResult{
-		ID:      id,
-		Answers: make([]*models.QuoteGameActualAnswer, len(quoteIDs)),
+// GetQuoteGameSubmission returns the answers that completed the game. ErrQuoteGameIdNotFound is returned if the game doesn't exist,
+// is not completed, or was completed before the answers were stored.
+func (repo *QuoteGameRepo) GetQuoteGameSubmission(ctx context.Context, id uuid.UUID) (*models.QuoteGameSubmission, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("quote_game"),
+		sm.Columns(
+			"mode", "quote1_id", "quote2_id", "quote3_id",
+			"quote1_answer", "quote2_answer", "quote3_answer",
+			"quote1_correct", "quote2_correct", "quote3_correct",
+		),
+		sm.Where(sqlite.Quote("id").EQ(sqlite.Arg(id))),
+		sm.Where(sqlite.Quote("completed_at").IsNotNull()),
+		sm.Where(sqlite.Quote("quote1_answer").IsNotNull()),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
 	}
 
-	// Compile the actual answers and check if the given answer is correct
-	for i, id := range quoteIDs {
-		quote := quotes[id]
-		correct := quote.Author == answers[id]
-		gameResult.Answers[i] = &models.QuoteGameActualAnswer{
-			Quote:   *quote,
-			Correct: correct,
+	submission := &models.QuoteGameSubmission{
+		QuoteIDs: make([]int, 3),
+		Correct:  make([]bool, 3),
+	}
+	answers := make([]string, 3)
+	err = repo.db.Reader().QueryRowContext(ctx, queryString, args...).Scan(
+		&submission.Mode, &submission.QuoteIDs[0], &submission.QuoteIDs[1], &submission.QuoteIDs[2],
+		&answers[0], &answers[1], &answers[2],
+		&submission.Correct[0], &submission.Correct[1], &submission.Correct[2],
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrQuoteGameIdNotFound
+	}
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+
+	submission.Answers = make(models.QuoteGameAnswerMap, len(answers))
+	for i, quoteID := range submission.QuoteIDs {
+		submission.Answers[quoteID] = answers[i]
+	}
+	return submission, nil
+}
+
+// GetQuoteGameKeys returns the keys that were stored when the game was created, by the id of the quote.
+// Quotes without a stored key, like the quotes of games created before keys were stored, are left out.
+// ErrQuoteGameIdNotFound is returned if the game doesn't exist.
+func (repo *QuoteGameRepo) GetQuoteGameKeys(ctx context.Context, id uuid.UUID) (map[int]*models.QuoteKey, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("quote_game"),
+		sm.Columns(
+			"quote1_id", "quote1_author", "quote1_choices", "quote1_blank",
+			"quote2_id", "quote2_author", "quote2_choices", "quote2_blank",
+			"quote3_id", "quote3_author", "quote3_choices", "quote3_blank",
+		),
+		sm.Where(sqlite.Quote("id").EQ(sqlite.Arg(id))),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	quoteIDs := make([]int, 3)
+	authors := make([]sql.NullString, 3)
+	choices := make([]sql.NullString, 3)
+	blanks := make([]sql.NullString, 3)
+	err = repo.db.Reader().QueryRowContext(ctx, queryString, args...).Scan(
+		&quoteIDs[0], &authors[0], &choices[0], &blanks[0],
+		&quoteIDs[1], &authors[1], &choices[1], &blanks[1],
+		&quoteIDs[2], &authors[2], &choices[2], &blanks[2],
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrQuoteGameIdNotFound
+	}
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+
+	keys := map[int]*models.QuoteKey{}
+	for i, quoteID := range quoteIDs {
+		if !authors[i].Valid && !blanks[i].Valid {
+			continue
+		}
+		key := &models.QuoteKey{Author: authors[i].String, Blank: blanks[i].String}
+		if choices[i].Valid {
+			err = json.Unmarshal([]byte(choices[i].String), &key.Choices)
+			if err != nil {
+				repo.logger.Error().Err(err).Msg("could not decode choices")
+				return nil, errors.Join(errors.New("could not decode choices"), err)
+			}
 		}
+		keys[quoteID] = key
 	}
+	return keys, nil
+}
 
-	// We set the result in the database
-	queryString, args, err := sqlite.Update(
-		um.Table("quote_game"),
-		um.SetCol("quote1_correct").ToArg(gameResult.Answers[0].Correct),
-		um.SetCol("quote2_correct").ToArg(gameResult.Answers[1].Correct),
-		um.SetCol("quote3_correct").ToArg(gameResult.Answers[2].Correct),
-		um.SetCol("completed_at").ToArg(time.Now()),
-		um.Where(sqlite.Quote("id").EQ(sqlite.Arg(id))),
+// GetOpenQuoteGame returns the mode and the quote ids in order of a game that can still be answered. ErrQuoteGameIdNotFound is returned
+// if the game doesn't exist, is completed or expired. The game of a room is shared by all its participants, so it's not found either.
+func (repo *QuoteGameRepo) GetOpenQuoteGame(ctx context.Context, id uuid.UUID) (mode models.GameMode, quoteIDs []int, err error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("quote_game"),
+		sm.Columns("mode", "quote1_id", "quote2_id", "quote3_id", "created_at"),
+		sm.Where(sqlite.Quote("id").EQ(sqlite.Arg(id))),
+		sm.Where(sqlite.Quote("completed_at").IsNull()),
+		sm.Where(sqlite.Raw("NOT EXISTS (SELECT 1 FROM room WHERE room.quote_game_id = quote_game.id)")),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return "", nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	quoteIDs = make([]int, 3)
+	var createdAt time.Time
+	err = repo.db.Reader().QueryRowContext(ctx, queryString, args...).Scan(&mode, &quoteIDs[0], &quoteIDs[1], &quoteIDs[2], &createdAt)
+	if err == sql.ErrNoRows {
+		return "", nil, models.ErrQuoteGameIdNotFound
+	}
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return "", nil, errors.Join(errors.New("could not execute query"), err)
+	}
+
+	if time.Now().After(createdAt.Add(models.QuoteGameDuration)) {
+		return "", nil, models.ErrQuoteGameIdNotFound
+	}
+	return mode, quoteIDs, nil
+}
+
+// CreateQuoteGameHint stores a hint the player of the game used. ErrNoHintAvailable is returned if the quote already got a hint of the same type.
+func (repo *QuoteGameRepo) CreateQuoteGameHint(ctx context.Context, id uuid.UUID, hint *models.Hint) error {
+	queryString, args, err := sqlite.Insert(
+		im.Into("quote_game_hint", "quote_game_id", "quote_id", "type", "author", "cost", "created_at"),
+		im.Values(sqlite.Arg(id, hint.QuoteID, hint.Type, hint.Author, hint.Cost, time.Now().UTC())),
+		im.OrIgnore(),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return errors.Join(errors.New("could not build query"), err)
+	}
+
+	res, err := repo.db.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return errors.Join(errors.New("could not execute query"), err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not get affected rows")
+		return errors.Join(errors.New("could not get affected rows"), err)
+	}
+	// The hint was requested twice at the same time
+	if affected == 0 {
+		return models.ErrNoHintAvailable
+	}
+	return nil
+}
+
+// GetQuoteGameHints returns the hints used in the game, in the order they were requested
+func (repo *QuoteGameRepo) GetQuoteGameHints(ctx context.Context, id uuid.UUID) ([]*models.Hint, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("quote_game_hint"),
+		sm.Columns("type", "quote_id", "author", "cost"),
+		sm.Where(sqlite.Quote("quote_game_id").EQ(sqlite.Arg(id))),
+		sm.OrderBy("created_at"),
 	).Build(ctx)
 	if err != nil {
 		repo.logger.Error().Err(err).Msg("could not build query")
 		return nil, errors.Join(errors.New("could not build query"), err)
 	}
 
-	// Execute the query
-	_, err = repo.db.ExecContext(ctx, queryString, args...)
+	rows, err := repo.db.Reader().QueryContext(ctx, queryString, args...)
 	if err != nil {
 		repo.logger.Error().Err(err).Msg("could not execute query")
 		return nil, errors.Join(errors.New("could not execute query"), err)
 	}
+	defer rows.Close()
+
+	hints := []*models.Hint{}
+	for rows.Next() {
+		hint := &models.Hint{}
+		err = rows.Scan(&hint.Type, &hint.QuoteID, &hint.Author, &hint.Cost)
+		if err != nil {
+			repo.logger.Error().Err(err).Msg("could not scan row")
+			return nil, errors.Join(errors.New("could not scan row"), err)
+		}
+		hints = append(hints, hint)
+	}
+	if err = rows.Err(); err != nil {
+		repo.logger.Error().Err(err).Msg("could not iterate rows")
+		return nil, errors.Join(errors.New("could not iterate rows"), err)
+	}
 
-	// And return the result
-	return gameResult, nil
+	return hints, nil
 }
@@ -4,7 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,11 +19,11 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestQuoteGameRepo_GetRandomQuotes(t *testing.T) {
+func TestQuoteGameRepo_CreateQuoteGame(t *testing.T) {
 	type Test struct {
-		quotes         []*models.Quote
-		expectedResult *models.QuoteGame
-		expectedError  error
+		game          *models.QuoteGame
+		playerID      string
+		expectedError error
 	}
 
 	run := func(tt Test) func(t *testing.T) {
@@ -32,102 +35,164 @@ func TestQuoteGameRepo_GetRandomQuotes(t *testing.T) {
 			db := database.Init(&logger, ":memory:")
 			defer db.Close()
 
-			res, err := NewQuoteGameRepo(&logger, db).CreateQuoteGame(context.TODO(), tt.quotes)
+			err := NewQuoteGameRepo(&logger, db).CreateQuoteGame(context.TODO(), tt.game, tt.playerID)
 
 			assrt := assert.New(t) // we rename to prevent shadowing
 			if tt.expectedError != nil {
 				require.ErrorContains(t, err, tt.expectedError.Error())
-				assrt.Equal(tt.expectedResult, res)
 				return
 			}
 
 			require.NoError(t, err)
 
-			// Because we can't predict a new uuid, and it's overkill to dependency inject the uuid function,
-			// we check the uuid for validity and add it to the expectedResult
-			assrt.NoError(uuid.Validate(res.ID.String()))
-			tt.expectedResult.ID = res.ID
-
-			assrt.Equal(tt.expectedResult, res)
-
-			// Finally we check if the data is in the db in the expected way
-			var id uuid.UUID
+			// We check if the data is in the db in the expected way
+			var mode models.GameMode
 			var quote1_id, quote2_id, quote3_id int
+			var playerID sql.NullString
 			var ts time.Time
-			err = db.QueryRow("select id, quote1_id, quote2_id, quote3_id, created_at from quote_game where id = ?", res.ID).
-				Scan(&id, &quote1_id, &quote2_id, &quote3_id, &ts)
+			err = db.QueryRow("select mode, quote1_id, quote2_id, quote3_id, player_id, created_at from quote_game where id = ?", tt.game.ID).
+				Scan(&mode, &quote1_id, &quote2_id, &quote3_id, &playerID, &ts)
 			require.NoError(t, err)
 
-			assrt.Equal(res.ID, id)
-			assrt.Equal(tt.expectedResult.Quotes[0].ID, quote1_id)
-			assrt.Equal(tt.expectedResult.Quotes[1].ID, quote2_id)
-			assrt.Equal(tt.expectedResult.Quotes[2].ID, quote3_id)
+			assrt.Equal(tt.game.Mode, mode)
+			assrt.Equal(tt.playerID != "", playerID.Valid)
+			assrt.Equal(tt.playerID, playerID.String)
+			assrt.Equal(tt.game.Quotes[0].ID, quote1_id)
+			assrt.Equal(tt.game.Quotes[1].ID, quote2_id)
+			assrt.Equal(tt.game.Quotes[2].ID, quote3_id)
+			assrt.WithinDuration(time.Now(), ts, time.Second)
 		}
 	}
 
-	t.Run("returns a quote when receiving expected response from api", run(Test{
-		quotes: []*models.Quote{
-			{
-				ID:     70,
-				Quote:  "The cure for pain is in the pain.",
-				Author: "Rumi",
-			},
-			{
-				ID:     905,
-				Quote:  "Try as much as you can to mention death. For if you were having hard times in your life, then it would give you more hope and would ease things for you. And if you were having abundant affluence of living in luxury, then it would make it less luxurious.",
-				Author: "Umar ibn Al-Khattāb (R.A)",
-			},
-			{
-				ID:     451,
-				Quote:  "We should not give up and we should not allow the problem to defeat us.",
-				Author: "Abdul Kalam",
-			},
-		},
-		expectedResult: &models.QuoteGame{
+	t.Run("stores the quote ids in the order of the game", run(Test{
+		game: models.NewQuoteGame(uuid.New(), []*models.Quote{
+			{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi"},
+			{ID: 905, Quote: "Try as much as you can to mention death.", Author: "Umar ibn Al-Khattāb (R.A)"},
+			{ID: 451, Quote: "We should not give up and we should not allow the problem to defeat us.", Author: "Abdul Kalam"},
+		}),
+	}))
+
+	t.Run("stores the mode and the player id when given", run(Test{
+		game: &models.QuoteGame{
+			ID:   uuid.New(),
+			Mode: models.GameModeMultipleChoice,
 			Quotes: []*models.QuoteWithoutAuthor{
-				{
-					ID:    70,
-					Quote: "The cure for pain is in the pain.",
-				},
-				{
-					ID:    905,
-					Quote: "Try as much as you can to mention death. For if you were having hard times in your life, then it would give you more hope and would ease things for you. And if you were having abundant affluence of living in luxury, then it would make it less luxurious.",
-				},
-				{
-					ID:    451,
-					Quote: "We should not give up and we should not allow the problem to defeat us.",
-				},
-			},
-			Authors: []string{
-				"Abdul Kalam",
-				"Rumi",
-				"Umar ibn Al-Khattāb (R.A)",
+				{ID: 72, Quote: "Bye", Choices: []string{"Bob", "Jan"}},
+				{ID: 33, Quote: "Hey", Choices: []string{"Jan", "Max"}},
+				{ID: 12, Quote: "Hi", Choices: []string{"Bob", "Max"}},
 			},
 		},
+		playerID: "player-42",
 	}))
 
 	t.Run("errors when not given 3 quotes", run(Test{
-		quotes: []*models.Quote{
-			{
-				ID:     70,
-				Quote:  "The cure for pain is in the pain.",
-				Author: "Rumi",
-			},
-			{
-				ID:     905,
-				Quote:  "Try as much as you can to mention death. For if you were having hard times in your life, then it would give you more hope and would ease things for you. And if you were having abundant affluence of living in luxury, then it would make it less luxurious.",
-				Author: "Umar ibn Al-Khattāb (R.A)",
-			},
-		},
+		game: models.NewQuoteGame(uuid.New(), []*models.Quote{
+			{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi"},
+			{ID: 905, Quote: "Try as much as you can to mention death.", Author: "Umar ibn Al-Khattāb (R.A)"},
+		}),
 		expectedError: errors.New("number of quotes should be 3. Given: 2"),
 	}))
 }
 
+func TestQuoteGameRepo_GetRecentQuoteIDs(t *testing.T) {
+	type Test struct {
+		playerID       string
+		games          int
+		expectedResult []int
+	}
+
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			// We get a new fresh inmem db for each test
+			db := database.Init(&logger, ":memory:")
+			defer db.Close()
+			// And seed it with games of two players and one anonymous game
+			seed := []struct {
+				playerID any
+				quoteIDs [3]int
+				age      time.Duration
+			}{
+				{"player-42", [3]int{1, 2, 3}, 3 * time.Hour},
+				{"player-42", [3]int{4, 5, 6}, 2 * time.Hour},
+				{"player-42", [3]int{7, 8, 9}, time.Hour},
+				{"player-7", [3]int{10, 11, 12}, time.Hour},
+				{nil, [3]int{13, 14, 15}, time.Hour},
+			}
+			for _, g := range seed {
+				db.Exec( //nolint:errcheck // this is a test
+					"insert into quote_game(id, quote1_id, quote2_id, quote3_id, player_id, created_at) values (?,?,?,?,?,?)",
+					uuid.New(),
+					g.quoteIDs[0],
+					g.quoteIDs[1],
+					g.quoteIDs[2],
+					g.playerID,
+					time.Now().Add(-g.age),
+				)
+			}
+
+			res, err := NewQuoteGameRepo(&logger, db).GetRecentQuoteIDs(context.TODO(), tt.playerID, tt.games)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("returns the quote ids of the most recent games of the player", run(Test{
+		playerID:       "player-42",
+		games:          2,
+		expectedResult: []int{7, 8, 9, 4, 5, 6},
+	}))
+
+	t.Run("returns all games when the player has played fewer games", run(Test{
+		playerID:       "player-7",
+		games:          10,
+		expectedResult: []int{10, 11, 12},
+	}))
+
+	t.Run("returns nothing for an unknown player", run(Test{
+		playerID:       "player-1",
+		games:          10,
+		expectedResult: []int{},
+	}))
+}
+
+func TestQuoteGameRepo_GetQuoteGameStatus(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := database.Init(&logger, ":memory:")
+	defer db.Close()
+	repo := NewQuoteGameRepo(&logger, db)
+
+	createdAt := time.Now().Add(-time.Minute).UTC()
+	open, completed := uuid.New(), uuid.New()
+	db.Exec( //nolint:errcheck // this is a test
+		"insert into quote_game(id, quote1_id, quote2_id, quote3_id, created_at) values (?,1,2,3,?)", open, createdAt,
+	)
+	db.Exec( //nolint:errcheck // this is a test
+		"insert into quote_game(id, quote1_id, quote2_id, quote3_id, created_at, completed_at) values (?,4,5,6,?,?)", completed, createdAt, createdAt,
+	)
+
+	res, err := repo.GetQuoteGameStatus(context.TODO(), open)
+	require.NoError(t, err)
+	assert.Equal(t, open, res.ID)
+	assert.True(t, createdAt.Add(models.QuoteGameDuration).Equal(res.Deadline))
+	assert.False(t, res.Completed)
+
+	res, err = repo.GetQuoteGameStatus(context.TODO(), completed)
+	require.NoError(t, err)
+	assert.True(t, res.Completed)
+
+	_, err = repo.GetQuoteGameStatus(context.TODO(), uuid.New())
+	assert.Equal(t, models.ErrQuoteGameIdNotFound, err)
+}
+
 func TestQuoteGameRepo_ValidateIDAndAnswerIDs(t *testing.T) {
 	type Test struct {
 		id             uuid.UUID
 		answers        models.QuoteGameAnswerMap
-		prepareDB      func(*sql.DB)
+		prepareDB      func(*database.DB)
+		expectedMode   models.GameMode
 		expectedResult []int
 		expectedError  error
 	}
@@ -153,7 +218,7 @@ func TestQuoteGameRepo_ValidateIDAndAnswerIDs(t *testing.T) {
 				tt.prepareDB(db)
 			}
 
-			res, err := NewQuoteGameRepo(&logger, db).ValidateIDAndAnswerIDs(context.TODO(), tt.id, tt.answers)
+			mode, res, err := NewQuoteGameRepo(&logger, db).ValidateIDAndAnswerIDs(context.TODO(), tt.id, tt.answers)
 
 			assrt := assert.New(t) // we rename to prevent shadowing
 			if tt.expectedError != nil {
@@ -163,6 +228,7 @@ func TestQuoteGameRepo_ValidateIDAndAnswerIDs(t *testing.T) {
 			}
 
 			require.NoError(t, err)
+			assrt.Equal(tt.expectedMode, mode)
 			assrt.Equal(tt.expectedResult, res)
 		}
 	}
@@ -174,9 +240,37 @@ func TestQuoteGameRepo_ValidateIDAndAnswerIDs(t *testing.T) {
 			12: "Bob",
 			72: "Jan",
 		},
+		expectedMode:   models.GameModeMatch,
+		expectedResult: []int{12, 72, 33},
+	}))
+
+	t.Run("returns the mode of the game", run(Test{
+		id: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+		answers: models.QuoteGameAnswerMap{
+			33: "Max",
+			12: "Bob",
+			72: "Jan",
+		},
+		prepareDB: func(db *database.DB) {
+			db.Exec( //nolint:errcheck // this is a test
+				"update quote_game set mode=? where id=?",
+				models.GameModeMultipleChoice,
+				uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+			)
+		},
+		expectedMode:   models.GameModeMultipleChoice,
 		expectedResult: []int{12, 72, 33},
 	}))
 
+	t.Run("throws error if not every quote is answered", run(Test{
+		id: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+		answers: models.QuoteGameAnswerMap{
+			12: "Bob",
+			72: "Jan",
+		},
+		expectedError: models.ErrInvalidQuoteID,
+	}))
+
 	t.Run("throws error if the id doesn't exist", run(Test{
 		id: uuid.MustParse("03f17f15-eeee-eeee-eeee-039f2f18373e"),
 		answers: models.QuoteGameAnswerMap{
@@ -204,7 +298,7 @@ func TestQuoteGameRepo_ValidateIDAndAnswerIDs(t *testing.T) {
 			12: "Bob",
 			72: "Jan",
 		},
-		prepareDB: func(db *sql.DB) {
+		prepareDB: func(db *database.DB) {
 			db.Exec( //nolint:errcheck // this is a test
 				"update quote_game set created_at=? where id=?",
 				time.Now().Add(-10*time.Minute),
@@ -221,96 +315,556 @@ func TestQuoteGameRepo_ValidateIDAndAnswerIDs(t *testing.T) {
 			12: "Bob",
 			72: "Jan",
 		},
-		prepareDB: func(db *sql.DB) {
+		prepareDB: func(db *database.DB) {
 			db.Exec( //nolint:errcheck // this is a test
 				"update quote_game set completed_at=? where id=?",
 				time.Now(),
 				uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
 			)
 		},
-		expectedError: models.ErrQuoteGameIdNotFound,
+		expectedError: models.ErrQuoteGameCompleted,
 	}))
-}
-
-func TestQuoteGameRepo_ValidateAnswersAndCreateGameResult(t *testing.T) {
-	type Test struct {
-		id             uuid.UUID
-		quoteIDs       []int
-		quotes         map[int]*models.Quote
-		answers        models.QuoteGameAnswerMap
-		expectedResult *models.QuoteGameResult
-		expectedError  error
-	}
-
-	run := func(tt Test) func(t *testing.T) {
-		return func(t *testing.T) {
-			t.Helper()
 
-			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
-			// We get a new fresh inmem db for each test
-			db := database.Init(&logger, ":memory:")
-			defer db.Close()
-			// And seed it
+	t.Run("throws error if the game is the shared game of a room", run(Test{
+		id: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+		answers: models.QuoteGameAnswerMap{
+			33: "Max",
+			12: "Bob",
+			72: "Jan",
+		},
+		prepareDB: func(db *database.DB) {
 			db.Exec( //nolint:errcheck // this is a test
-				"insert into quote_game(id, quote1_id, quote2_id, quote3_id, created_at) values (?,?,?,?,?)",
+				"insert into room(code, quote_game_id, host_id, created_at) values (?,?,?,?)",
+				"ABC123",
 				uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
-				12,
-				72,
-				33,
+				"host",
 				time.Now(),
 			)
+		},
+		expectedError: models.ErrQuoteGameIdNotFound,
+	}))
+}
 
-			res, err := NewQuoteGameRepo(&logger, db).ValidateAnswersAndCreateGameResult(context.TODO(), tt.id, tt.quoteIDs, tt.quotes, tt.answers)
+func TestQuoteGameRepo_StoreQuoteGameResult(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := database.Init(&logger, ":memory:")
+	defer db.Close()
+
+	id := uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e")
+	db.Exec( //nolint:errcheck // this is a test
+		"insert into quote_game(id, quote1_id, quote2_id, quote3_id, created_at) values (?,?,?,?,?)",
+		id, 12, 72, 33, time.Now(),
+	)
+
+	result := &models.QuoteGameResult{
+		ID: id,
+		Answers: []*models.QuoteGameActualAnswer{
+			{Quote: models.Quote{Author: "Bob", Quote: "Hi", ID: 12}, Correct: true},
+			{Quote: models.Quote{Author: "Someone else", Quote: "Bye", ID: 72}, Correct: false},
+			{Quote: models.Quote{Author: "Max", Quote: "Hey", ID: 33}, Correct: true},
+		},
+	}
+	answers := models.QuoteGameAnswerMap{12: "Bob", 72: "Max", 33: "Max"}
+	err := NewQuoteGameRepo(&logger, db).StoreQuoteGameResult(context.TODO(), result, answers)
+	require.NoError(t, err)
+
+	// We want to check if the state is actually set in the db
+	var q1_correct, q2_correct, q3_correct sql.NullBool
+	var completed_at sql.NullTime
+	err = db.QueryRow("select quote1_correct, quote2_correct, quote3_correct, completed_at from quote_game where id = ?", id).
+		Scan(&q1_correct, &q2_correct, &q3_correct, &completed_at)
+	require.NoError(t, err)
+
+	require.True(t, q1_correct.Valid)
+	require.True(t, q2_correct.Valid)
+	require.True(t, q3_correct.Valid)
+	require.True(t, completed_at.Valid)
+	assert.True(t, q1_correct.Bool)
+	assert.False(t, q2_correct.Bool)
+	assert.True(t, q3_correct.Bool)
+
+	// The submitted answers are stored as well, so they can be compared to a repeated submission
+	submission, err := NewQuoteGameRepo(&logger, db).GetQuoteGameSubmission(context.TODO(), id)
+	require.NoError(t, err)
+	assert.Equal(t, &models.QuoteGameSubmission{
+		Mode:     models.GameModeMatch,
+		QuoteIDs: []int{12, 72, 33},
+		Answers:  answers,
+		Correct:  []bool{true, false, true},
+	}, submission)
+
+	err = NewQuoteGameRepo(&logger, db).StoreQuoteGameResult(context.TODO(), &models.QuoteGameResult{ID: id, Answers: result.Answers[:2]}, answers)
+	assert.ErrorContains(t, err, "number of answers should be 3. Given: 2")
+
+	// A completed game is not completed again, so the first answers stay
+	err = NewQuoteGameRepo(&logger, db).StoreQuoteGameResult(context.TODO(), result, models.QuoteGameAnswerMap{12: "Max", 72: "Bob", 33: "Max"})
+	assert.Equal(t, models.ErrQuoteGameCompleted, err)
+	submission, err = NewQuoteGameRepo(&logger, db).GetQuoteGameSubmission(context.TODO(), id)
+	require.NoError(t, err)
+	assert.Equal(t, answers, submission.Answers)
+}
 
-			assrt := assert.New(t) // we rename to prevent shadowing
-			req := require.New(t)
-			if tt.expectedError != nil {
-				req.ErrorContains(err, tt.expectedError.Error())
-				assrt.Equal(tt.expectedResult, res)
+func TestQuoteGameRepo_StoreQuoteGameResultConcurrently(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	// A database file, so the submissions don't share a connection cache like the in-memory database does
+	db := database.Init(&logger, "file:"+filepath.Join(t.TempDir(), "kabisa.db"))
+	defer db.Close()
+	repo := NewQuoteGameRepo(&logger, db)
+
+	id := uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e")
+	db.Exec( //nolint:errcheck // this is a test
+		"insert into quote_game(id, quote1_id, quote2_id, quote3_id, created_at) values (?,?,?,?,?)",
+		id, 12, 72, 33, time.Now(),
+	)
+
+	// Every submission is validated before any of them is stored, which is the worst case of parallel submissions
+	const submissions = 10
+	var validated, done sync.WaitGroup
+	validated.Add(submissions)
+	errs := make([]error, submissions)
+	for i := range submissions {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			answers := models.QuoteGameAnswerMap{12: "Bob", 72: "Jan", 33: fmt.Sprintf("Max %d", i)}
+			_, _, err := repo.ValidateIDAndAnswerIDs(context.TODO(), id, answers)
+			validated.Done()
+			if err != nil {
+				errs[i] = err
 				return
 			}
+			validated.Wait()
+
+			errs[i] = repo.StoreQuoteGameResult(context.TODO(), &models.QuoteGameResult{
+				ID: id,
+				Answers: []*models.QuoteGameActualAnswer{
+					{Quote: models.Quote{ID: 12}, Correct: true},
+					{Quote: models.Quote{ID: 72}, Correct: true},
+					{Quote: models.Quote{ID: 33}, Correct: i%2 == 0},
+				},
+			}, answers)
+		}()
+	}
+	done.Wait()
+
+	// Only one submission completes the game, the others are told it's completed already
+	winner := -1
+	for i, err := range errs {
+		if err == nil {
+			require.Equal(t, -1, winner, "submissions %d and %d both completed the game", winner, i)
+			winner = i
+			continue
+		}
+		assert.Equal(t, models.ErrQuoteGameCompleted, err)
+	}
+	require.NotEqual(t, -1, winner)
 
-			req.NoError(err)
-			assrt.Equal(tt.expectedResult, res)
+	// The stored submission is the one of the winner
+	submission, err := repo.GetQuoteGameSubmission(context.TODO(), id)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("Max %d", winner), submission.Answers[33])
+	assert.Equal(t, []bool{true, true, winner%2 == 0}, submission.Correct)
+}
 
-			// We want to check if the state is actually set in the db
-			var q1_correct, q2_correct, q3_correct sql.NullBool
-			var completed_at sql.NullTime
-			err = db.QueryRow("select quote1_correct, quote2_correct, quote3_correct, completed_at from quote_game where id = ?", tt.id).
-				Scan(&q1_correct, &q2_correct, &q3_correct, &completed_at)
-			req.NoError(err)
-
-			req.True(q1_correct.Valid)
-			req.True(q2_correct.Valid)
-			req.True(q3_correct.Valid)
-			req.True(completed_at.Valid)
-
-			assrt.Equal(tt.expectedResult.Answers[0].Correct, q1_correct.Bool)
-			assrt.Equal(tt.expectedResult.Answers[1].Correct, q2_correct.Bool)
-			assrt.Equal(tt.expectedResult.Answers[2].Correct, q3_correct.Bool)
-		}
+func TestQuoteGameRepo_GetQuoteGameSubmission(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := database.Init(&logger, ":memory:")
+	defer db.Close()
+	repo := NewQuoteGameRepo(&logger, db)
+
+	open := uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e")
+	legacy := uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373f")
+	db.Exec( //nolint:errcheck // this is a test
+		"insert into quote_game(id, quote1_id, quote2_id, quote3_id, created_at) values (?,?,?,?,?)",
+		open, 12, 72, 33, time.Now(),
+	)
+	// Games completed before the answers were stored only have their correctness
+	db.Exec( //nolint:errcheck // this is a test
+		"insert into quote_game(id, quote1_id, quote2_id, quote3_id, quote1_correct, quote2_correct, quote3_correct, created_at, completed_at) values (?,?,?,?,?,?,?,?,?)",
+		legacy, 12, 72, 33, true, true, true, time.Now(), time.Now(),
+	)
+
+	for _, id := range []uuid.UUID{open, legacy, uuid.New()} {
+		_, err := repo.GetQuoteGameSubmission(context.TODO(), id)
+		assert.Equal(t, models.ErrQuoteGameIdNotFound, err)
 	}
+}
 
-	t.Run("checks the answers and stores them in the database", run(Test{
-		id: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
-		answers: models.QuoteGameAnswerMap{
-			33: "Max",
-			12: "Bob",
-			72: "Jan",
-		},
-		quoteIDs: []int{12, 72, 33},
-		quotes: map[int]*models.Quote{
-			33: {Author: "Max", Quote: "Hey", ID: 33},
-			12: {Author: "Bob", Quote: "Hi", ID: 12},
-			72: {Author: "Someone else", Quote: "Bye", ID: 72},
-		},
-		expectedResult: &models.QuoteGameResult{
-			ID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
-			Answers: []*models.QuoteGameActualAnswer{
-				{Quote: models.Quote{Author: "Bob", Quote: "Hi", ID: 12}, Correct: true},
-				{Quote: models.Quote{Author: "Someone else", Quote: "Bye", ID: 72}, Correct: false},
-				{Quote: models.Quote{Author: "Max", Quote: "Hey", ID: 33}, Correct: true},
-			},
-		},
-	}))
+func TestQuoteGameRepo_DailyChallenge(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := database.Init(&logger, ":memory:")
+	defer db.Close()
+	repo := NewQuoteGameRepo(&logger, db)
+
+	_, err := repo.GetDailyChallenge(context.TODO(), "2025-02-01")
+	assert.Equal(t, models.ErrDailyChallengeNotFound, err)
+
+	res, err := repo.CreateDailyChallenge(context.TODO(), "2025-02-01", []int{905, 70, 451})
+	require.NoError(t, err)
+	assert.Equal(t, []int{905, 70, 451}, res)
+
+	// A challenge chosen later for the same day doesn't replace the first one
+	res, err = repo.CreateDailyChallenge(context.TODO(), "2025-02-01", []int{1, 2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, []int{905, 70, 451}, res)
+
+	res, err = repo.GetDailyChallenge(context.TODO(), "2025-02-01")
+	require.NoError(t, err)
+	assert.Equal(t, []int{905, 70, 451}, res)
+}
+
+func TestQuoteGameRepo_CreateDailyQuoteGame(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := database.Init(&logger, ":memory:")
+	defer db.Close()
+	repo := NewQuoteGameRepo(&logger, db)
+
+	newGame := func() *models.QuoteGame {
+		return models.NewQuoteGame(uuid.New(), []*models.Quote{
+			{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi"},
+			{ID: 905, Quote: "Try as much as you can to mention death.", Author: "Umar ibn Al-Khattāb (R.A)"},
+			{ID: 451, Quote: "We should not give up and we should not allow the problem to defeat us.", Author: "Abdul Kalam"},
+		})
+	}
+
+	game := newGame()
+	err := repo.CreateDailyQuoteGame(context.TODO(), game, "player-42", "2025-02-01")
+	require.NoError(t, err)
+	var dailyDate sql.NullString
+	require.NoError(t, db.QueryRow("select daily_date from quote_game where id = ?", game.ID).Scan(&dailyDate))
+	assert.Equal(t, "2025-02-01", dailyDate.String)
+
+	// Every player can play the challenge once a day, while normal games are unlimited
+	err = repo.CreateDailyQuoteGame(context.TODO(), newGame(), "player-42", "2025-02-01")
+	assert.Equal(t, models.ErrDailyChallengeAlreadyPlayed, err)
+	err = repo.CreateDailyQuoteGame(context.TODO(), newGame(), "player-42", "2025-02-02")
+	require.NoError(t, err)
+	err = repo.CreateDailyQuoteGame(context.TODO(), newGame(), "player-7", "2025-02-01")
+	require.NoError(t, err)
+	for range 2 {
+		err = repo.CreateQuoteGame(context.TODO(), newGame(), "player-42")
+		require.NoError(t, err)
+	}
+
+	err = repo.CreateDailyQuoteGame(context.TODO(), newGame(), "", "2025-02-01")
+	assert.Error(t, err)
+
+	// Only the daily conflict is ignored, a game with an id that is already taken returns the real error
+	err = repo.CreateQuoteGame(context.TODO(), game, "player-42")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, models.ErrDailyChallengeAlreadyPlayed)
+	err = repo.CreateDailyQuoteGame(context.TODO(), game, "player-42", "2025-02-03")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, models.ErrDailyChallengeAlreadyPlayed)
+}
+
+func TestQuoteGameRepo_GetDailyLeaderboard(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := database.Init(&logger, ":memory:")
+	defer db.Close()
+	repo := NewQuoteGameRepo(&logger, db)
+
+	// Seed the games of the day, one of another day, one that is not completed yet and a normal game
+	now := time.Now().UTC().Truncate(time.Second)
+	seed := []struct {
+		playerID  string
+		date      any
+		correct   [3]bool
+		completed any
+	}{
+		{"player-1", "2025-02-01", [3]bool{true, false, true}, now.Add(-time.Minute)},
+		{"player-2", "2025-02-01", [3]bool{true, true, true}, now},
+		{"player-3", "2025-02-01", [3]bool{true, true, false}, now.Add(-2 * time.Minute)},
+		{"player-4", "2025-02-01", [3]bool{false, false, false}, nil},
+		{"player-5", "2025-02-02", [3]bool{true, true, true}, now},
+		{"player-6", nil, [3]bool{true, true, true}, now},
+	}
+	gameIDs := map[string]uuid.UUID{}
+	for _, g := range seed {
+		gameIDs[g.playerID] = uuid.New()
+		_, err := db.Exec(
+			"insert into quote_game(id, quote1_id, quote2_id, quote3_id, player_id, daily_date, created_at, completed_at, quote1_correct, quote2_correct, quote3_correct) values (?,1,2,3,?,?,?,?,?,?,?)",
+			gameIDs[g.playerID], g.playerID, g.date, now.Add(-time.Hour), g.completed, g.correct[0], g.correct[1], g.correct[2],
+		)
+		require.NoError(t, err)
+	}
+
+	res, err := repo.GetDailyLeaderboard(context.TODO(), "2025-02-01", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.Total)
+	require.Len(t, res.Items, 3)
+	// The time is read back in the local zone, so only its instant is compared
+	assert.True(t, now.Equal(res.Items[0].CompletedAt))
+	res.Items[0].CompletedAt = now
+	assert.Equal(t, &models.DailyLeaderboardEntry{Rank: 1, PlayerID: "player-2", Score: 3, CompletedAt: now}, res.Items[0])
+	// With the same score, the player that completed the challenge first ranks higher
+	assert.Equal(t, "player-3", res.Items[1].PlayerID)
+	assert.Equal(t, 2, res.Items[1].Score)
+	assert.Equal(t, "player-1", res.Items[2].PlayerID)
+
+	// The rank continues on the next page
+	res, err = repo.GetDailyLeaderboard(context.TODO(), "2025-02-01", 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.Total)
+	require.Len(t, res.Items, 2)
+	assert.Equal(t, 2, res.Items[0].Rank)
+	assert.Equal(t, "player-1", res.Items[1].PlayerID)
+	assert.Equal(t, 3, res.Items[1].Rank)
+
+	res, err = repo.GetDailyLeaderboard(context.TODO(), "2025-01-01", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Total)
+	assert.Empty(t, res.Items)
+
+	// The costs of hints are deducted from the score, but the score doesn't go below zero
+	require.NoError(t, repo.CreateQuoteGameHint(context.TODO(), gameIDs["player-2"], &models.Hint{Type: models.HintRevealPair, QuoteID: 1, Author: "Bob", Cost: 2}))
+	require.NoError(t, repo.CreateQuoteGameHint(context.TODO(), gameIDs["player-3"], &models.Hint{Type: models.HintRevealPair, QuoteID: 1, Author: "Bob", Cost: 2}))
+	require.NoError(t, repo.CreateQuoteGameHint(context.TODO(), gameIDs["player-3"], &models.Hint{Type: models.HintRevealPair, QuoteID: 2, Author: "Jan", Cost: 2}))
+	res, err = repo.GetDailyLeaderboard(context.TODO(), "2025-02-01", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, res.Items, 3)
+	assert.Equal(t, []string{"player-1", "player-2", "player-3"}, []string{res.Items[0].PlayerID, res.Items[1].PlayerID, res.Items[2].PlayerID})
+	assert.Equal(t, []int{2, 1, 0}, []int{res.Items[0].Score, res.Items[1].Score, res.Items[2].Score})
+}
+
+func TestQuoteGameRepo_ExportQuoteGames(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := database.Init(&logger, ":memory:")
+	defer db.Close()
+	repo := NewQuoteGameRepo(&logger, db)
+
+	// The games are written in UTC, in the order of the ids
+	start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	completedAt := start.Add(time.Hour + 30*time.Second)
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	_, err := db.Exec(
+		"insert into quote_game(id, mode, quote1_id, quote2_id, quote3_id, player_id, daily_date, created_at, completed_at, quote1_correct, quote2_correct, quote3_correct) values (?,?,1,2,3,?,?,?,?,?,?,?)",
+		ids[1], models.GameModeMatch, "player-1", "2026-10-19", start.Add(time.Hour), completedAt, true, false, true,
+	)
+	require.NoError(t, err)
+	_, err = db.Exec(
+		"insert into quote_game(id, mode, quote1_id, quote2_id, quote3_id, created_at) values (?,?,4,5,6,?)",
+		ids[0], models.GameModeMultipleChoice, start,
+	)
+	require.NoError(t, err)
+	_, err = db.Exec(
+		"insert into quote_game(id, mode, quote1_id, quote2_id, quote3_id, created_at) values (?,?,7,8,9,?)",
+		ids[2], models.GameModeMatch, start.Add(time.Hour+time.Millisecond),
+	)
+	require.NoError(t, err)
+	// The game of a room is answered in the room, so it's not exported
+	room := models.NewQuoteGame(uuid.New(), []*models.Quote{{ID: 10}, {ID: 11}, {ID: 12}})
+	_, err = NewRoomRepo(&logger, db).CreateRoom(context.TODO(), "ABC234", room, "host")
+	require.NoError(t, err)
+
+	export := func(from, to time.Time) []*models.GameExport {
+		t.Helper()
+		var games []*models.GameExport
+		err := repo.ExportQuoteGames(context.TODO(), from, to, func(game *models.GameExport) error {
+			games = append(games, game)
+			return nil
+		})
+		require.NoError(t, err)
+		return games
+	}
+
+	// All games, oldest first
+	games := export(time.Time{}, time.Time{})
+	require.Len(t, games, 3)
+	assert.Equal(t, []uuid.UUID{ids[0], ids[1], ids[2]}, []uuid.UUID{games[0].ID, games[1].ID, games[2].ID})
+	assert.True(t, start.Equal(games[0].CreatedAt))
+	games[0].CreatedAt = start
+	assert.Equal(t, &models.GameExport{
+		ID:        ids[0],
+		Mode:      models.GameModeMultipleChoice,
+		QuoteIDs:  []int{4, 5, 6},
+		CreatedAt: start,
+	}, games[0])
+	assert.Equal(t, ids[1], games[1].ID)
+	assert.Equal(t, models.GameModeMatch, games[1].Mode)
+	assert.Equal(t, "player-1", games[1].PlayerID)
+	assert.Equal(t, "2026-10-19", games[1].DailyDate)
+	assert.Equal(t, []int{1, 2, 3}, games[1].QuoteIDs)
+	assert.Equal(t, []bool{true, false, true}, games[1].Correct)
+	require.NotNil(t, games[1].CompletedAt)
+	assert.True(t, completedAt.Equal(*games[1].CompletedAt))
+	assert.Equal(t, 30*time.Second, games[1].Duration())
+
+	// from is inclusive and to is exclusive, in whatever time zone the range is given. A fraction of a second counts as well
+	cest := time.FixedZone("CEST", 2*60*60)
+	games = export(start.Add(time.Hour).In(cest), start.Add(time.Hour+time.Millisecond).In(cest))
+	require.Len(t, games, 1)
+	assert.Equal(t, ids[1], games[0].ID)
+	games = export(start.Add(time.Minute), time.Time{})
+	require.Len(t, games, 2)
+	assert.Equal(t, ids[1], games[0].ID)
+	assert.Empty(t, export(time.Time{}, start))
+
+	// The error of fn stops the export
+	stop := errors.New("stop")
+	calls := 0
+	err = repo.ExportQuoteGames(context.TODO(), time.Time{}, time.Time{}, func(*models.GameExport) error {
+		calls++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestQuoteGameRepo_GetOpenQuoteGame(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := database.Init(&logger, ":memory:")
+	defer db.Close()
+	repo := NewQuoteGameRepo(&logger, db)
+
+	// Seed an open game, a completed game, an expired game and the game of a room
+	open, completed, expired, room := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+	for _, g := range []struct {
+		id        uuid.UUID
+		createdAt time.Time
+		completed any
+	}{
+		{open, now, nil},
+		{completed, now, now},
+		{expired, now.Add(-models.QuoteGameDuration - time.Minute), nil},
+		{room, now, nil},
+	} {
+		_, err := db.Exec("insert into quote_game(id, mode, quote1_id, quote2_id, quote3_id, created_at, completed_at) values (?,'match',4,5,6,?,?)", g.id, g.createdAt, g.completed)
+		require.NoError(t, err)
+	}
+	_, err := db.Exec("insert into room(code, quote_game_id, host_id, created_at) values ('ABC234', ?, 'host', ?)", room, now)
+	require.NoError(t, err)
+
+	mode, quoteIDs, err := repo.GetOpenQuoteGame(context.TODO(), open)
+	require.NoError(t, err)
+	assert.Equal(t, models.GameModeMatch, mode)
+	assert.Equal(t, []int{4, 5, 6}, quoteIDs)
+
+	for _, id := range []uuid.UUID{completed, expired, room, uuid.New()} {
+		_, _, err = repo.GetOpenQuoteGame(context.TODO(), id)
+		assert.ErrorIs(t, err, models.ErrQuoteGameIdNotFound)
+	}
+}
+
+func TestQuoteGameRepo_QuoteGameHints(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := database.Init(&logger, ":memory:")
+	defer db.Close()
+	repo := NewQuoteGameRepo(&logger, db)
+	id := uuid.New()
+
+	hints, err := repo.GetQuoteGameHints(context.TODO(), id)
+	require.NoError(t, err)
+	assert.Empty(t, hints)
+
+	eliminate := &models.Hint{Type: models.HintEliminateAuthor, QuoteID: 4, Author: "Jan", Cost: 1}
+	reveal := &models.Hint{Type: models.HintRevealPair, QuoteID: 4, Author: "Bob", Cost: 2}
+	require.NoError(t, repo.CreateQuoteGameHint(context.TODO(), id, eliminate))
+	require.NoError(t, repo.CreateQuoteGameHint(context.TODO(), id, reveal))
+	// A quote gets only one hint of every type
+	err = repo.CreateQuoteGameHint(context.TODO(), id, &models.Hint{Type: models.HintEliminateAuthor, QuoteID: 4, Author: "Max", Cost: 1})
+	assert.ErrorIs(t, err, models.ErrNoHintAvailable)
+
+	hints, err = repo.GetQuoteGameHints(context.TODO(), id)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Hint{eliminate, reveal}, hints)
+
+	// The hints of other games are not returned
+	hints, err = repo.GetQuoteGameHints(context.TODO(), uuid.New())
+	require.NoError(t, err)
+	assert.Empty(t, hints)
+}
+
+func TestQuoteGameRepo_GetQuoteGameKeys(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := database.Init(&logger, ":memory:")
+	defer db.Close()
+	repo := NewQuoteGameRepo(&logger, db)
+
+	_, err := repo.GetQuoteGameKeys(context.TODO(), uuid.New())
+	assert.ErrorIs(t, err, models.ErrQuoteGameIdNotFound)
+
+	// A game without keys returns none
+	game := models.NewQuoteGame(uuid.New(), []*models.Quote{{ID: 12}, {ID: 72}, {ID: 33}})
+	game.Keys = nil
+	require.NoError(t, repo.CreateQuoteGame(context.TODO(), game, ""))
+	keys, err := repo.GetQuoteGameKeys(context.TODO(), game.ID)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	// The keys are returned by the id of the quote, whatever the order of the quotes is
+	game = models.NewQuoteGame(uuid.New(), []*models.Quote{
+		{ID: 12, Quote: "Hi", Author: "Bob"},
+		{ID: 72, Quote: "Bye", Author: "Jan"},
+		{ID: 33, Quote: "Hey", Author: "Max"},
+	})
+	require.NoError(t, repo.CreateQuoteGame(context.TODO(), game, ""))
+	keys, err = repo.GetQuoteGameKeys(context.TODO(), game.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]*models.QuoteKey{
+		12: {Author: "Bob", Choices: []string{"Bob", "Jan", "Max"}},
+		72: {Author: "Jan", Choices: []string{"Bob", "Jan", "Max"}},
+		33: {Author: "Max", Choices: []string{"Bob", "Jan", "Max"}},
+	}, keys)
+
+	game = models.NewQuoteGame(uuid.New(), []*models.Quote{{ID: 12}, {ID: 72}, {ID: 33}})
+	game.Mode = models.GameModeFillInTheBlank
+	game.Keys = map[int]*models.QuoteKey{33: {Author: "Max", Blank: "Hey"}, 12: {Author: "Bob", Blank: "Hi"}, 72: {Author: "Jan", Blank: "Bye"}}
+	require.NoError(t, repo.CreateQuoteGame(context.TODO(), game, ""))
+	keys, err = repo.GetQuoteGameKeys(context.TODO(), game.ID)
+	require.NoError(t, err)
+	assert.Equal(t, game.Keys, keys)
+}
+
+// BenchmarkQuoteGameRepo_ParallelGames creates games from parallel goroutines on a database file, the way the api does for parallel requests.
+// Every game reads the recent quotes of the player first, and the status is read afterwards like a client polling it.
+// Run with: go test -run '^$' -bench ParallelGames ./repositories
+func BenchmarkQuoteGameRepo_ParallelGames(b *testing.B) {
+	configs := []struct {
+		name string
+		conf database.Config
+	}{
+		// How the database was opened before the pragmas could be configured
+		{name: "single connection", conf: database.Config{JournalMode: "DELETE", Synchronous: "FULL", BusyTimeout: 5 * time.Second}},
+		{name: "wal with read pool", conf: database.DefaultConfig()},
+	}
+
+	for _, c := range configs {
+		b.Run(c.name, func(b *testing.B) {
+			logger := zerolog.New(os.Stderr).Level(zerolog.WarnLevel)
+			db := database.InitWithConfig(&logger, "file:"+filepath.Join(b.TempDir(), "kabisa.db"), c.conf)
+			defer db.Close()
+			repo := NewQuoteGameRepo(&logger, db)
+			ctx := context.TODO()
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				playerID := uuid.NewString()
+				for pb.Next() {
+					_, err := repo.GetRecentQuoteIDs(ctx, playerID, 10)
+					if err != nil {
+						b.Error(err)
+						return
+					}
+					game := models.NewQuoteGame(uuid.New(), []*models.Quote{
+						{ID: 12, Quote: "Hi", Author: "Bob"},
+						{ID: 72, Quote: "Bye", Author: "Jan"},
+						{ID: 33, Quote: "Hey", Author: "Max"},
+					})
+					err = repo.CreateQuoteGame(ctx, game, playerID)
+					if err != nil {
+						b.Error(err)
+						return
+					}
+					_, err = repo.GetQuoteGameStatus(ctx, game.ID)
+					if err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "games/s")
+		})
+	}
 }
@@ -0,0 +1,271 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/database"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+
+	"github.com/stephenafamo/bob/dialect/sqlite"
+	"github.com/stephenafamo/bob/dialect/sqlite/im"
+	"github.com/stephenafamo/bob/dialect/sqlite/sm"
+	"github.com/stephenafamo/bob/dialect/sqlite/um"
+)
+
+type RoomRepo struct {
+	logger *zerolog.Logger
+	db     *database.DB
+}
+
+// NewRoomRepo returns a new RoomRepo, which stores the rooms in which several players answer the same quote game.
+func NewRoomRepo(logger *zerolog.Logger, db *database.DB) *RoomRepo {
+	return &RoomRepo{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// CreateRoom stores the game and a new room for it with the host as first participant, and returns the room.
+// The game belongs to the room and not to the host, so it's stored without a player.
+// ErrRoomCodeTaken is returned if another room already uses the code, in which case the game is not stored either.
+func (repo *RoomRepo) CreateRoom(ctx context.Context, code string, game *models.QuoteGame, hostID string) (*models.Room, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not start transaction")
+		return nil, errors.Join(errors.New("could not start transaction"), err)
+	}
+	defer tx.Rollback() //nolint:errcheck // the rollback is a no-op after a successful commit
+
+	err = insertQuoteGame(ctx, repo.logger, tx, game, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	queryString, args, err := sqlite.Insert(
+		im.Into("room", "code", "quote_game_id", "host_id", "created_at"),
+		im.Values(sqlite.Arg(code, game.ID, hostID, now)),
+		im.OnConflict("code").DoNothing(),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	res, err := tx.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not get affected rows")
+		return nil, errors.Join(errors.New("could not get affected rows"), err)
+	}
+	if affected == 0 {
+		return nil, models.ErrRoomCodeTaken
+	}
+
+	_, err = repo.insertParticipant(ctx, tx, code, hostID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not commit transaction")
+		return nil, errors.Join(errors.New("could not commit transaction"), err)
+	}
+
+	return repo.GetRoom(ctx, code)
+}
+
+// GetRoom returns the room with the given code, including the quotes of its game and its deadline.
+// ErrRoomNotFound is returned if the room doesn't exist.
+func (repo *RoomRepo) GetRoom(ctx context.Context, code string) (*models.Room, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("room").As("r"),
+		sm.InnerJoin("quote_game").As("g").OnEQ(sqlite.Quote("g", "id"), sqlite.Quote("r", "quote_game_id")),
+		sm.Columns(
+			sqlite.Quote("r", "code"),
+			sqlite.Quote("r", "host_id"),
+			sqlite.Quote("r", "quote_game_id"),
+			sqlite.Quote("g", "quote1_id"),
+			sqlite.Quote("g", "quote2_id"),
+			sqlite.Quote("g", "quote3_id"),
+			sqlite.Quote("g", "created_at"),
+		),
+		sm.Where(sqlite.Quote("r", "code").EQ(sqlite.Arg(code))),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	room := &models.Room{QuoteIDs: make([]int, 3)}
+	var createdAt time.Time
+	err = repo.db.Reader().QueryRowContext(ctx, queryString, args...).
+		Scan(&room.Code, &room.HostID, &room.GameID, &room.QuoteIDs[0], &room.QuoteIDs[1], &room.QuoteIDs[2], &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrRoomNotFound
+	}
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+
+	room.Deadline = createdAt.Add(models.QuoteGameDuration)
+	return room, nil
+}
+
+// JoinRoom adds the player to the participants of the room. Joining a room twice is a no-op, so joined is only true the first time.
+func (repo *RoomRepo) JoinRoom(ctx context.Context, code string, playerID string) (joined bool, err error) {
+	res, err := repo.insertParticipant(ctx, repo.db, code, playerID, time.Now().UTC())
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not get affected rows")
+		return false, errors.Join(errors.New("could not get affected rows"), err)
+	}
+	return affected > 0, nil
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (repo *RoomRepo) insertParticipant(ctx context.Context, db execer, code string, playerID string, joinedAt time.Time) (sql.Result, error) {
+	queryString, args, err := sqlite.Insert(
+		im.Into("room_participant", "room_code", "player_id", "joined_at"),
+		im.Values(sqlite.Arg(code, playerID, joinedAt)),
+		im.OnConflict("room_code", "player_id").DoNothing(),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	res, err := db.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	return res, nil
+}
+
+// SubmitRoomAnswer stores the answers of a participant and whether they were correct, in the order of the quotes of the room.
+// A participant can only answer once, so ErrRoomAlreadyAnswered is returned for a second answer.
+// ErrRoomNotJoined is returned if the player is not a participant of the room.
+func (repo *RoomRepo) SubmitRoomAnswer(ctx context.Context, code string, playerID string, result *models.QuoteGameResult, answers models.QuoteGameAnswerMap) error {
+	if len(result.Answers) != 3 {
+		return errors.New("a room answer should have exactly three answers")
+	}
+
+	// The check on submitted_at is part of the update, so two concurrent answers can't both be stored
+	queryString, args, err := sqlite.Update(
+		um.Table("room_participant"),
+		um.SetCol("quote1_correct").ToArg(result.Answers[0].Correct),
+		um.SetCol("quote2_correct").ToArg(result.Answers[1].Correct),
+		um.SetCol("quote3_correct").ToArg(result.Answers[2].Correct),
+		um.SetCol("quote1_answer").ToArg(answers[result.Answers[0].ID]),
+		um.SetCol("quote2_answer").ToArg(answers[result.Answers[1].ID]),
+		um.SetCol("quote3_answer").ToArg(answers[result.Answers[2].ID]),
+		um.SetCol("submitted_at").ToArg(time.Now().UTC()),
+		um.Where(sqlite.Quote("room_code").EQ(sqlite.Arg(code))),
+		um.Where(sqlite.Quote("player_id").EQ(sqlite.Arg(playerID))),
+		um.Where(sqlite.Quote("submitted_at").IsNull()),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return errors.Join(errors.New("could not build query"), err)
+	}
+
+	res, err := repo.db.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return errors.Join(errors.New("could not execute query"), err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not get affected rows")
+		return errors.Join(errors.New("could not get affected rows"), err)
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	// Nothing was updated, so the player either answered already or never joined
+	participants, err := repo.ListRoomParticipants(ctx, code)
+	if err != nil {
+		return err
+	}
+	for _, p := range participants {
+		if p.PlayerID == playerID {
+			return models.ErrRoomAlreadyAnswered
+		}
+	}
+	return models.ErrRoomNotJoined
+}
+
+// ListRoomParticipants returns the participants of a room. Participants that answered come first, ordered by score
+// and then by who answered first. Participants that didn't answer yet are ordered by who joined first.
+func (repo *RoomRepo) ListRoomParticipants(ctx context.Context, code string) ([]*models.RoomParticipant, error) {
+	queryString, args, err := sqlite.Select(
+		sm.From("room_participant"),
+		sm.Columns(
+			"player_id",
+			"joined_at",
+			sqlite.Raw("quote1_correct + quote2_correct + quote3_correct"),
+			"submitted_at",
+		),
+		sm.Where(sqlite.Quote("room_code").EQ(sqlite.Arg(code))),
+		sm.OrderBy(sqlite.Quote("submitted_at").IsNull()),
+		sm.OrderBy(sqlite.Raw("quote1_correct + quote2_correct + quote3_correct")).Desc(),
+		sm.OrderBy("submitted_at"),
+		sm.OrderBy("joined_at"),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	rows, err := repo.db.Reader().QueryContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	defer rows.Close()
+
+	participants := []*models.RoomParticipant{}
+	for rows.Next() {
+		p := &models.RoomParticipant{}
+		var score sql.NullInt64
+		var submittedAt sql.NullTime
+		err = rows.Scan(&p.PlayerID, &p.JoinedAt, &score, &submittedAt)
+		if err != nil {
+			repo.logger.Error().Err(err).Msg("could not scan row")
+			return nil, errors.Join(errors.New("could not scan row"), err)
+		}
+		if score.Valid {
+			s := int(score.Int64)
+			p.Score = &s
+		}
+		if submittedAt.Valid {
+			p.SubmittedAt = &submittedAt.Time
+		}
+		participants = append(participants, p)
+	}
+	if err = rows.Err(); err != nil {
+		repo.logger.Error().Err(err).Msg("could not iterate rows")
+		return nil, errors.Join(errors.New("could not iterate rows"), err)
+	}
+
+	return participants, nil
+}
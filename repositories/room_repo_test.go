@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/database"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoomRepo(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := database.Init(&logger, ":memory:")
+	defer db.Close()
+	repo := NewRoomRepo(&logger, db)
+
+	quotes := []*models.Quote{
+		{ID: 72, Quote: "a", Author: "Jan"},
+		{ID: 12, Quote: "b", Author: "Bob"},
+		{ID: 33, Quote: "c", Author: "Max"},
+	}
+	game := models.NewQuoteGame(uuid.New(), quotes)
+	room, err := repo.CreateRoom(context.TODO(), "ABC234", game, "host")
+	require.NoError(t, err)
+	assert.Equal(t, "ABC234", room.Code)
+	assert.Equal(t, "host", room.HostID)
+	assert.Equal(t, game.ID, room.GameID)
+	assert.Equal(t, []int{72, 12, 33}, room.QuoteIDs)
+	assert.WithinDuration(t, time.Now().Add(models.QuoteGameDuration), room.Deadline, time.Minute)
+
+	// The game is stored with the room, not as a game of the host
+	var playerID sql.NullString
+	require.NoError(t, db.QueryRow("select player_id from quote_game where id = ?", game.ID).Scan(&playerID))
+	assert.False(t, playerID.Valid)
+
+	// A code can only be used once, and the game of the room that couldn't be created is not stored
+	other := models.NewQuoteGame(uuid.New(), quotes)
+	_, err = repo.CreateRoom(context.TODO(), "ABC234", other, "another-host")
+	assert.Equal(t, models.ErrRoomCodeTaken, err)
+	err = db.QueryRow("select player_id from quote_game where id = ?", other.ID).Scan(&playerID)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	_, err = repo.GetRoom(context.TODO(), "ZZZ999")
+	assert.Equal(t, models.ErrRoomNotFound, err)
+
+	// Joining twice is fine
+	for _, join := range []struct {
+		playerID string
+		joined   bool
+	}{{"player-1", true}, {"player-2", true}, {"player-2", false}, {"host", false}} {
+		joined, err := repo.JoinRoom(context.TODO(), "ABC234", join.playerID)
+		require.NoError(t, err)
+		assert.Equal(t, join.joined, joined, join.playerID)
+	}
+
+	result := func(correct ...bool) *models.QuoteGameResult {
+		res := &models.QuoteGameResult{ID: game.ID}
+		for i, c := range correct {
+			res.Answers = append(res.Answers, &models.QuoteGameActualAnswer{Quote: *quotes[i], Correct: c})
+		}
+		return res
+	}
+	answers := models.QuoteGameAnswerMap{72: "Jan", 12: "Max", 33: "Bob"}
+	require.NoError(t, repo.SubmitRoomAnswer(context.TODO(), "ABC234", "player-1", result(true, false, false), answers))
+	require.NoError(t, repo.SubmitRoomAnswer(context.TODO(), "ABC234", "host", result(true, true, false), answers))
+
+	err = repo.SubmitRoomAnswer(context.TODO(), "ABC234", "player-1", result(true, true, true), answers)
+	assert.Equal(t, models.ErrRoomAlreadyAnswered, err)
+	err = repo.SubmitRoomAnswer(context.TODO(), "ABC234", "stranger", result(true, true, true), answers)
+	assert.Equal(t, models.ErrRoomNotJoined, err)
+
+	// The answers are stored in the order of the quotes of the room
+	stored := make([]string, 3)
+	require.NoError(t, db.QueryRow("select quote1_answer, quote2_answer, quote3_answer from room_participant where player_id = 'player-1'").
+		Scan(&stored[0], &stored[1], &stored[2]))
+	assert.Equal(t, []string{"Jan", "Max", "Bob"}, stored)
+
+	// The best score comes first and participants that didn't answer come last
+	participants, err := repo.ListRoomParticipants(context.TODO(), "ABC234")
+	require.NoError(t, err)
+	require.Len(t, participants, 3)
+
+	assert.Equal(t, "host", participants[0].PlayerID)
+	require.NotNil(t, participants[0].Score)
+	assert.Equal(t, 2, *participants[0].Score)
+	assert.NotNil(t, participants[0].SubmittedAt)
+
+	assert.Equal(t, "player-1", participants[1].PlayerID)
+	require.NotNil(t, participants[1].Score)
+	assert.Equal(t, 1, *participants[1].Score)
+
+	assert.Equal(t, "player-2", participants[2].PlayerID)
+	assert.Nil(t, participants[2].Score)
+	assert.Nil(t, participants[2].SubmittedAt)
+
+	// The times are written in UTC, like the times of the games
+	var createdAt, joinedAt, submittedAt string
+	require.NoError(t, db.QueryRow("select created_at || '' from room where code = 'ABC234'").Scan(&createdAt))
+	require.NoError(t, db.QueryRow("select joined_at || '', submitted_at || '' from room_participant where player_id = 'player-1'").Scan(&joinedAt, &submittedAt))
+	for _, ts := range []string{createdAt, joinedAt, submittedAt} {
+		assert.True(t, strings.HasSuffix(ts, "+00:00"), ts)
+	}
+}
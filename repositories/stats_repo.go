@@ -0,0 +1,230 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/pietdevries94/Kabisa/database"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/sqlite"
+	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
+	"github.com/stephenafamo/bob/dialect/sqlite/sm"
+)
+
+type StatsRepo struct {
+	logger *zerolog.Logger
+	db     *database.DB
+}
+
+// NewStatsRepo returns a new StatsRepo, which aggregates the answers of the completed quote games
+func NewStatsRepo(logger *zerolog.Logger, db *database.DB) *StatsRepo {
+	return &StatsRepo{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// unionAll combines select queries with UNION ALL, in parentheses so it can be selected from. The query builder puts every
+// combined query between parentheses, which sqlite doesn't accept, so the queries are combined here instead.
+type unionAll []bob.Expression
+
+func (queries unionAll) WriteSQL(ctx context.Context, w io.Writer, d bob.Dialect, start int) ([]any, error) {
+	return bob.ExpressSlice(ctx, w, d, start, queries, "(", "\nUNION ALL\n", ")")
+}
+
+// answers selects the answers of the completed games as rows of quote_id, correct and answer. A game stores its answers in
+// three positions, so every position is selected separately and combined. The mods of a position are given its column prefix,
+// like quote1_, and are applied to every position, so each of them can use the index of that position.
+func answers(positionMods func(prefix string) []bob.Mod[*dialect.SelectQuery]) unionAll {
+	queries := make(unionAll, 3)
+	for i := range queries {
+		prefix := fmt.Sprintf("quote%d_", i+1)
+		queries[i] = sqlite.Select(append([]bob.Mod[*dialect.SelectQuery]{
+			sm.From("quote_game"),
+			sm.Columns(
+				sqlite.Quote(prefix+"id").As("quote_id"),
+				sqlite.Quote(prefix+"correct").As("correct"),
+				sqlite.Quote(prefix+"answer").As("answer"),
+			),
+			sm.Where(sqlite.Quote("completed_at").IsNotNull()),
+		}, positionMods(prefix)...)...).Expression
+	}
+	return queries
+}
+
+// answersInMode only selects the answers of the games in the mode, or all answers when the mode is empty
+func answersInMode(mode models.GameMode) func(prefix string) []bob.Mod[*dialect.SelectQuery] {
+	return func(string) []bob.Mod[*dialect.SelectQuery] {
+		if mode == "" {
+			return nil
+		}
+		return []bob.Mod[*dialect.SelectQuery]{sm.Where(sqlite.Quote("mode").EQ(sqlite.Arg(mode)))}
+	}
+}
+
+// wrongAuthorAnswers only selects the wrong answers that were one of the authors the player could choose from, so a typed
+// answer that is no author is never selected. The author of the quote as it was played is selected as well, as author.
+// Games in GameModeFillInTheBlank have no choices and are left out, like the games created before the choices were stored.
+func wrongAuthorAnswers(prefix string) []bob.Mod[*dialect.SelectQuery] {
+	return []bob.Mod[*dialect.SelectQuery]{
+		sm.Columns(sqlite.Quote(prefix + "author").As("author")),
+		sm.Where(sqlite.Quote(prefix + "correct").EQ(sqlite.Arg(false))),
+		sm.Where(sqlite.Quote(prefix + "answer").NE(sqlite.Quote(prefix + "author"))),
+		sm.Where(sqlite.Quote("mode").NE(sqlite.Arg(models.GameModeFillInTheBlank))),
+		sm.Where(sqlite.Raw(fmt.Sprintf("EXISTS (SELECT 1 FROM json_each(%[1]schoices) WHERE json_each.value = %[1]sanswer)", prefix))),
+	}
+}
+
+// ListQuoteStats returns a page of the quotes that were answered in completed games, the least accurately answered quotes first.
+// Quotes with the same accuracy are ordered by their number of attempts, the most first.
+// The answers of rooms are not counted, their participants answer in the room and the game of the room is never completed.
+func (repo *StatsRepo) ListQuoteStats(ctx context.Context, filter models.QuoteStatsFilter) (*models.Page[*models.QuoteStats], error) {
+	answerQuery := answers(answersInMode(filter.Mode))
+	attempts := sqlite.Raw("COUNT(*)")
+	correct := sqlite.Raw("SUM(answer.correct)")
+	grouped := []bob.Mod[*dialect.SelectQuery]{
+		sm.From(answerQuery).As("answer"),
+		sm.GroupBy(sqlite.Quote("answer", "quote_id")),
+		sm.Having(sqlite.Raw("COUNT(*) >= ?", filter.MinAttempts)),
+	}
+
+	page := &models.Page[*models.QuoteStats]{
+		Items:  []*models.QuoteStats{},
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+	countQueryString, countArgs, err := sqlite.Select(
+		sm.From(sqlite.Select(append(grouped, sm.Columns(sqlite.Quote("answer", "quote_id")))...)).As("counted"),
+		sm.Columns(sqlite.F("count", "*")),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+	err = repo.db.Reader().QueryRowContext(ctx, countQueryString, countArgs...).Scan(&page.Total)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+
+	queryString, args, err := sqlite.Select(append(grouped,
+		// Quotes that are not in the local catalogue (anymore) are still counted
+		sm.LeftJoin("quote").OnEQ(sqlite.Quote("quote", "id"), sqlite.Quote("answer", "quote_id")),
+		sm.Columns(
+			sqlite.Quote("answer", "quote_id"),
+			sqlite.F("coalesce", sqlite.F("max", sqlite.Quote("quote", "quote")), sqlite.S("")),
+			sqlite.F("coalesce", sqlite.F("max", sqlite.Quote("quote", "author")), sqlite.S("")),
+			attempts,
+			correct,
+		),
+		sm.OrderBy(sqlite.Raw("CAST(SUM(answer.correct) AS REAL) / COUNT(*)")),
+		sm.OrderBy(attempts).Desc(),
+		sm.OrderBy(sqlite.Quote("answer", "quote_id")),
+		sm.Limit(filter.Limit),
+		sm.Offset(filter.Offset),
+	)...).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	rows, err := repo.db.Reader().QueryContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		stats := &models.QuoteStats{}
+		err = rows.Scan(&stats.QuoteID, &stats.Quote, &stats.Author, &stats.Attempts, &stats.Correct)
+		if err != nil {
+			repo.logger.Error().Err(err).Msg("could not scan row")
+			return nil, errors.Join(errors.New("could not scan row"), err)
+		}
+		page.Items = append(page.Items, stats)
+	}
+	if err = rows.Err(); err != nil {
+		repo.logger.Error().Err(err).Msg("could not iterate rows")
+		return nil, errors.Join(errors.New("could not iterate rows"), err)
+	}
+
+	return page, nil
+}
+
+// ListAuthorConfusions returns a page of the authors that players guessed wrong for the quotes of another author, the most
+// frequent confusion first. Only the authors the player could choose from count, see wrongAuthorAnswers. The authors are the
+// ones of the games as they were played, so an edit of a quote doesn't move its confusions to another author.
+// The answers of rooms are not counted, like in ListQuoteStats.
+func (repo *StatsRepo) ListAuthorConfusions(ctx context.Context, filter models.AuthorConfusionFilter) (*models.Page[*models.AuthorConfusion], error) {
+	answerQuery := answers(wrongAuthorAnswers)
+	grouped := []bob.Mod[*dialect.SelectQuery]{
+		sm.From(answerQuery).As("answer"),
+		sm.GroupBy(sqlite.Quote("answer", "author")),
+		sm.GroupBy(sqlite.Quote("answer", "answer")),
+	}
+	if filter.Author != "" {
+		grouped = append(grouped, sm.Where(sqlite.Quote("answer", "author").EQ(sqlite.Arg(filter.Author))))
+	}
+
+	page := &models.Page[*models.AuthorConfusion]{
+		Items:  []*models.AuthorConfusion{},
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+	countQueryString, countArgs, err := sqlite.Select(
+		sm.From(sqlite.Select(append(grouped, sm.Columns(sqlite.Quote("answer", "author")))...)).As("counted"),
+		sm.Columns(sqlite.F("count", "*")),
+	).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+	err = repo.db.Reader().QueryRowContext(ctx, countQueryString, countArgs...).Scan(&page.Total)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+
+	count := sqlite.Raw("COUNT(*)")
+	queryString, args, err := sqlite.Select(append(grouped,
+		sm.Columns(sqlite.Quote("answer", "author"), sqlite.Quote("answer", "answer"), count),
+		sm.OrderBy(count).Desc(),
+		sm.OrderBy(sqlite.Quote("answer", "author")),
+		sm.OrderBy(sqlite.Quote("answer", "answer")),
+		sm.Limit(filter.Limit),
+		sm.Offset(filter.Offset),
+	)...).Build(ctx)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not build query")
+		return nil, errors.Join(errors.New("could not build query"), err)
+	}
+
+	rows, err := repo.db.Reader().QueryContext(ctx, queryString, args...)
+	if err != nil {
+		repo.logger.Error().Err(err).Msg("could not execute query")
+		return nil, errors.Join(errors.New("could not execute query"), err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		confusion := &models.AuthorConfusion{}
+		err = rows.Scan(&confusion.Author, &confusion.GuessedAuthor, &confusion.Count)
+		if err != nil {
+			repo.logger.Error().Err(err).Msg("could not scan row")
+			return nil, errors.Join(errors.New("could not scan row"), err)
+		}
+		page.Items = append(page.Items, confusion)
+	}
+	if err = rows.Err(); err != nil {
+		repo.logger.Error().Err(err).Msg("could not iterate rows")
+		return nil, errors.Join(errors.New("could not iterate rows"), err)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,265 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/database"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/sqlite"
+	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
+	"github.com/stephenafamo/bob/dialect/sqlite/sm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// answeredGame is a game to seed, with its answers and the keys it was created with
+type answeredGame struct {
+	mode      models.GameMode
+	quoteIDs  [3]int
+	correct   [3]any
+	answers   [3]any
+	authors   [3]any
+	choices   [3]any
+	completed any
+}
+
+func seedGames(t *testing.T, db *database.DB, games []answeredGame) {
+	t.Helper()
+
+	now := time.Now()
+	for _, g := range games {
+		_, err := db.Exec(
+			"insert into quote_game(id, mode, quote1_id, quote2_id, quote3_id, created_at, completed_at, quote1_correct, quote2_correct, quote3_correct, quote1_answer, quote2_answer, quote3_answer, quote1_author, quote2_author, quote3_author, quote1_choices, quote2_choices, quote3_choices) values (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)",
+			uuid.New(), g.mode, g.quoteIDs[0], g.quoteIDs[1], g.quoteIDs[2], now, g.completed, g.correct[0], g.correct[1], g.correct[2], g.answers[0], g.answers[1], g.answers[2],
+			g.authors[0], g.authors[1], g.authors[2], g.choices[0], g.choices[1], g.choices[2],
+		)
+		require.NoError(t, err)
+	}
+}
+
+// seedAnsweredGames adds games to the quote catalogue of seedQuoteCatalogue. Quote 999 is not in the catalogue
+func seedAnsweredGames(t *testing.T, db *database.DB) {
+	t.Helper()
+
+	now := time.Now()
+	matched := `["Abdul Kalam","C. S. Lewis","Rumi"]`
+	seedGames(t, db, []answeredGame{
+		{
+			models.GameModeMatch,
+			[3]int{70, 414, 451},
+			[3]any{true, false, false},
+			[3]any{"Rumi", "Rumi", "C. S. Lewis"},
+			[3]any{"Rumi", "C. S. Lewis", "Abdul Kalam"},
+			[3]any{matched, matched, matched},
+			now,
+		},
+		{
+			models.GameModeMultipleChoice,
+			[3]int{414, 70, 172},
+			[3]any{false, true, false},
+			[3]any{"Rumi", "Rumi", "Abdul Kalam"},
+			[3]any{"C. S. Lewis", "Rumi", "Rumi"},
+			[3]any{`["C. S. Lewis","Rumi"]`, `["Abdul Kalam","Rumi"]`, `["Abdul Kalam","Rumi"]`},
+			now,
+		},
+		{
+			models.GameModeFillInTheBlank,
+			[3]int{414, 451, 999},
+			[3]any{false, true, true},
+			[3]any{"heart", "problem", "word"},
+			[3]any{"C. S. Lewis", "Abdul Kalam", "Nobody"},
+			[3]any{nil, nil, nil},
+			now,
+		},
+		// A game that is not completed yet
+		{
+			models.GameModeMatch,
+			[3]int{70, 414, 451},
+			[3]any{nil, nil, nil},
+			[3]any{nil, nil, nil},
+			[3]any{"Rumi", "C. S. Lewis", "Abdul Kalam"},
+			[3]any{matched, matched, matched},
+			nil,
+		},
+		// A game that was completed before the answers were stored
+		{models.GameModeMatch, [3]int{451, 70, 172}, [3]any{false, false, true}, [3]any{nil, nil, nil}, [3]any{nil, nil, nil}, [3]any{nil, nil, nil}, now},
+	})
+}
+
+func TestStatsRepo_ListQuoteStats(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := seedQuoteCatalogue(t, &logger)
+	defer db.Close()
+	seedAnsweredGames(t, db)
+
+	lewis := &models.QuoteStats{QuoteID: 414, Quote: "When We Lose One Blessing, Another Is Often Most Unexpectedly Given In Its Place.", Author: "C. S. Lewis", Attempts: 3, Correct: 0}
+	kalam := &models.QuoteStats{QuoteID: 451, Quote: "We should not give up and we should not allow the problem to defeat us.", Author: "Abdul Kalam", Attempts: 3, Correct: 1}
+	heart := &models.QuoteStats{QuoteID: 172, Quote: "The only lasting beauty is the beauty of the heart.", Author: "Rumi", Attempts: 2, Correct: 1}
+	pain := &models.QuoteStats{QuoteID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi", Attempts: 3, Correct: 2}
+	unknown := &models.QuoteStats{QuoteID: 999, Attempts: 1, Correct: 1}
+
+	type Test struct {
+		filter       models.QuoteStatsFilter
+		expectedPage *models.Page[*models.QuoteStats]
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			page, err := NewStatsRepo(&logger, db).ListQuoteStats(context.TODO(), tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPage, page)
+		}
+	}
+
+	t.Run("lists the least accurately answered quotes first", run(Test{
+		filter:       models.QuoteStatsFilter{MinAttempts: 1, Limit: 10},
+		expectedPage: &models.Page[*models.QuoteStats]{Items: []*models.QuoteStats{lewis, kalam, heart, pain, unknown}, Total: 5, Limit: 10},
+	}))
+
+	t.Run("pages the quotes", run(Test{
+		filter:       models.QuoteStatsFilter{MinAttempts: 1, Limit: 2, Offset: 1},
+		expectedPage: &models.Page[*models.QuoteStats]{Items: []*models.QuoteStats{kalam, heart}, Total: 5, Limit: 2, Offset: 1},
+	}))
+
+	t.Run("leaves out the quotes with fewer attempts", run(Test{
+		filter:       models.QuoteStatsFilter{MinAttempts: 3, Limit: 10},
+		expectedPage: &models.Page[*models.QuoteStats]{Items: []*models.QuoteStats{lewis, kalam, pain}, Total: 3, Limit: 10},
+	}))
+
+	// With the same accuracy, the quote with the most attempts comes first
+	t.Run("only counts the games of the mode", run(Test{
+		filter: models.QuoteStatsFilter{Mode: models.GameModeMatch, MinAttempts: 1, Limit: 10},
+		expectedPage: &models.Page[*models.QuoteStats]{Items: []*models.QuoteStats{
+			{QuoteID: 451, Quote: kalam.Quote, Author: kalam.Author, Attempts: 2, Correct: 0},
+			{QuoteID: 414, Quote: lewis.Quote, Author: lewis.Author, Attempts: 1, Correct: 0},
+			{QuoteID: 70, Quote: pain.Quote, Author: pain.Author, Attempts: 2, Correct: 1},
+			{QuoteID: 172, Quote: heart.Quote, Author: heart.Author, Attempts: 1, Correct: 1},
+		}, Total: 4, Limit: 10},
+	}))
+
+	t.Run("lists nothing without games in the mode", run(Test{
+		filter:       models.QuoteStatsFilter{Mode: "unknown", MinAttempts: 1, Limit: 10},
+		expectedPage: &models.Page[*models.QuoteStats]{Items: []*models.QuoteStats{}, Total: 0, Limit: 10},
+	}))
+}
+
+func TestStatsRepo_ListAuthorConfusions(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := seedQuoteCatalogue(t, &logger)
+	defer db.Close()
+	seedAnsweredGames(t, db)
+	now := time.Now()
+	renamed := `["A. P. J. Abdul Kalam","C. S. Lewis","Rumi"]`
+	seedGames(t, db, []answeredGame{
+		// The author of quote 451 was renamed after this game, its confusion stays with the author as it was played
+		{
+			models.GameModeMatch,
+			[3]int{70, 414, 451},
+			[3]any{true, true, false},
+			[3]any{"Rumi", "C. S. Lewis", "Rumi"},
+			[3]any{"Rumi", "C. S. Lewis", "A. P. J. Abdul Kalam"},
+			[3]any{renamed, renamed, renamed},
+			now,
+		},
+		// Answers that were not one of the choices are wrong, but never listed
+		{
+			models.GameModeMultipleChoice,
+			[3]int{414, 70, 172},
+			[3]any{false, false, false},
+			[3]any{"Visit my site", "Rumi ", "C. S. Lewis"},
+			[3]any{"C. S. Lewis", "Rumi", "Rumi"},
+			[3]any{`["C. S. Lewis","Rumi"]`, `["Abdul Kalam","Rumi"]`, `["Abdul Kalam","Rumi"]`},
+			now,
+		},
+		// A game that was completed before the choices were stored
+		{models.GameModeMatch, [3]int{451, 70, 172}, [3]any{false, false, true}, [3]any{"Rumi", "Abdul Kalam", "Rumi"}, [3]any{nil, nil, nil}, [3]any{nil, nil, nil}, now},
+	})
+
+	type Test struct {
+		filter       models.AuthorConfusionFilter
+		expectedPage *models.Page[*models.AuthorConfusion]
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			page, err := NewStatsRepo(&logger, db).ListAuthorConfusions(context.TODO(), tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPage, page)
+		}
+	}
+
+	// The wrong words of fill in the blank games and the games without stored answers or choices are not counted
+	t.Run("lists the most frequent confusions first", run(Test{
+		filter: models.AuthorConfusionFilter{Limit: 10},
+		expectedPage: &models.Page[*models.AuthorConfusion]{Items: []*models.AuthorConfusion{
+			{Author: "C. S. Lewis", GuessedAuthor: "Rumi", Count: 2},
+			{Author: "A. P. J. Abdul Kalam", GuessedAuthor: "Rumi", Count: 1},
+			{Author: "Abdul Kalam", GuessedAuthor: "C. S. Lewis", Count: 1},
+			{Author: "Rumi", GuessedAuthor: "Abdul Kalam", Count: 1},
+		}, Total: 4, Limit: 10},
+	}))
+
+	t.Run("pages the confusions", run(Test{
+		filter: models.AuthorConfusionFilter{Limit: 1, Offset: 3},
+		expectedPage: &models.Page[*models.AuthorConfusion]{Items: []*models.AuthorConfusion{
+			{Author: "Rumi", GuessedAuthor: "Abdul Kalam", Count: 1},
+		}, Total: 4, Limit: 1, Offset: 3},
+	}))
+
+	t.Run("only lists the confusions of the author", run(Test{
+		filter: models.AuthorConfusionFilter{Author: "Rumi", Limit: 10},
+		expectedPage: &models.Page[*models.AuthorConfusion]{Items: []*models.AuthorConfusion{
+			{Author: "Rumi", GuessedAuthor: "Abdul Kalam", Count: 1},
+		}, Total: 1, Limit: 10},
+	}))
+
+	t.Run("lists nothing for an author without confusions", run(Test{
+		filter:       models.AuthorConfusionFilter{Author: "Nobody", Limit: 10},
+		expectedPage: &models.Page[*models.AuthorConfusion]{Items: []*models.AuthorConfusion{}, Total: 0, Limit: 10},
+	}))
+}
+
+// The statistics read every completed game, so every position should be read from its own index instead of the table
+func TestStatsRepo_AnswersUseIndexes(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	db := database.Init(&logger, ":memory:")
+	defer db.Close()
+
+	for name, positionMods := range map[string]func(prefix string) []bob.Mod[*dialect.SelectQuery]{
+		"all answers":          answersInMode(""),
+		"answers in a mode":    answersInMode(models.GameModeMatch),
+		"wrong author answers": wrongAuthorAnswers,
+	} {
+		queryString, args, err := sqlite.Select(
+			sm.From(answers(positionMods)).As("answer"),
+			sm.Columns("quote_id"),
+		).Build(context.TODO())
+		require.NoError(t, err)
+
+		rows, err := db.Query("EXPLAIN QUERY PLAN "+queryString, args...)
+		require.NoError(t, err)
+		var plan []string
+		for rows.Next() {
+			var id, parent, unused int
+			var detail string
+			require.NoError(t, rows.Scan(&id, &parent, &unused, &detail))
+			plan = append(plan, detail)
+		}
+		require.NoError(t, rows.Err())
+		rows.Close()
+
+		for i := 1; i <= 3; i++ {
+			assert.Regexp(t, fmt.Sprintf("(SCAN|SEARCH) quote_game USING COVERING INDEX quote_game_quote%d_answer_stats", i), strings.Join(plan, "\n"), name)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+)
+
+// apiKeyPrefix makes the API keys of this application recognizable, for example for secret scanners
+const apiKeyPrefix = "kq_"
+
+// JWTConfig contains the keys used to validate JWTs. An algorithm is only accepted when its key is set
+type JWTConfig struct {
+	HS256Secret    []byte
+	RS256PublicKey *rsa.PublicKey
+	// Issuer is the expected iss claim. When empty, the issuer is not checked
+	Issuer string
+}
+
+// jwtClaims are the claims we read from a JWT. The subject identifies the caller, the role defaults to player
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Role models.Role `json:"role"`
+}
+
+type AuthService struct {
+	logger     *zerolog.Logger
+	apiKeyRepo apiKeyRepo
+	// staticApiKeys contains the callers of the API keys from the configuration, mapped by the hash of the key
+	staticApiKeys map[string]*models.Caller
+	jwtConfig     JWTConfig
+}
+
+// NewAuthService returns a new AuthService. staticApiKeys maps API keys from the configuration to their caller,
+// these keys are accepted next to the keys stored in the database.
+func NewAuthService(logger *zerolog.Logger, apiKeyRepo apiKeyRepo, staticApiKeys map[string]*models.Caller, jwtConfig JWTConfig) *AuthService {
+	hashedApiKeys := make(map[string]*models.Caller, len(staticApiKeys))
+	for key, caller := range staticApiKeys {
+		hashedApiKeys[hashApiKey(key)] = caller
+	}
+
+	return &AuthService{
+		logger:        logger,
+		apiKeyRepo:    apiKeyRepo,
+		staticApiKeys: hashedApiKeys,
+		jwtConfig:     jwtConfig,
+	}
+}
+
+// AuthenticateApiKey returns the caller the API key belongs to. Static keys from the configuration are checked first, then the database.
+// ErrInvalidCredentials is returned if the key is unknown or revoked.
+func (service *AuthService) AuthenticateApiKey(ctx context.Context, key string) (*models.Caller, error) {
+	keyHash := hashApiKey(key)
+	if caller, ok := service.staticApiKeys[keyHash]; ok {
+		return caller, nil
+	}
+
+	caller, err := service.apiKeyRepo.GetCallerByKeyHash(ctx, keyHash)
+	if err == models.ErrApiKeyNotFound {
+		return nil, models.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	return caller, nil
+}
+
+// AuthenticateJWT validates the signature and claims of the JWT and returns the caller it identifies.
+// Tokens need an expiry and a subject. ErrInvalidCredentials is returned for every invalid token.
+func (service *AuthService) AuthenticateJWT(_ context.Context, token string) (*models.Caller, error) {
+	validMethods := []string{}
+	if len(service.jwtConfig.HS256Secret) > 0 {
+		validMethods = append(validMethods, jwt.SigningMethodHS256.Alg())
+	}
+	if service.jwtConfig.RS256PublicKey != nil {
+		validMethods = append(validMethods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(validMethods) == 0 {
+		return nil, models.ErrInvalidCredentials
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(validMethods),
+		jwt.WithExpirationRequired(),
+	}
+	if service.jwtConfig.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(service.jwtConfig.Issuer))
+	}
+
+	claims := &jwtClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, service.jwtKey, opts...)
+	if err != nil {
+		service.logger.Debug().Err(err).Msg("received invalid jwt")
+		return nil, models.ErrInvalidCredentials
+	}
+
+	if claims.Subject == "" {
+		return nil, models.ErrInvalidCredentials
+	}
+	role := claims.Role
+	if role == "" {
+		role = models.RolePlayer
+	}
+	if !role.Valid() {
+		return nil, models.ErrInvalidCredentials
+	}
+
+	return &models.Caller{
+		ID:   claims.Subject,
+		Role: role,
+	}, nil
+}
+
+// jwtKey returns the key matching the algorithm of the token. The algorithm is already checked against the valid methods by the parser
+func (service *AuthService) jwtKey(token *jwt.Token) (any, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return service.jwtConfig.HS256Secret, nil
+	case jwt.SigningMethodRS256.Alg():
+		return service.jwtConfig.RS256PublicKey, nil
+	}
+	return nil, errors.New("unsupported signing method")
+}
+
+// CreateApiKey generates a new random API key for the subject and stores its hash. The key itself is returned only once
+func (service *AuthService) CreateApiKey(ctx context.Context, subject string, role models.Role) (*models.ApiKey, string, error) {
+	random := make([]byte, 32)
+	_, err := rand.Read(random)
+	if err != nil {
+		return nil, "", errors.Join(errors.New("could not generate api key"), err)
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	apiKey, err := service.apiKeyRepo.CreateApiKey(ctx, hashApiKey(key), subject, role)
+	if err != nil {
+		return nil, "", err
+	}
+	service.logger.Info().Str("id", apiKey.ID.String()).Str("subject", subject).Str("role", string(role)).Msg("api key created")
+	return apiKey, key, nil
+}
+
+// RevokeApiKey revokes an API key from the database. Static keys can only be removed from the configuration
+func (service *AuthService) RevokeApiKey(ctx context.Context, id uuid.UUID) error {
+	err := service.apiKeyRepo.RevokeApiKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	service.logger.Info().Str("id", id.String()).Msg("api key revoked")
+	return nil
+}
+
+// hashApiKey hashes the key with SHA-256. API keys are long and random, so a slow password hash isn't needed
+func hashApiKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
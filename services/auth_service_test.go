@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthService_AuthenticateApiKey(t *testing.T) {
+	type Test struct {
+		key            string
+		mockedCaller   *models.Caller
+		mockedError    error
+		expectedResult *models.Caller
+		expectedError  error
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedApiKeyRepo := new(MockedApiKeyRepo)
+			mockedApiKeyRepo.On("GetCallerByKeyHash", hashApiKey(tt.key)).
+				Once().
+				Return(tt.mockedCaller, tt.mockedError)
+
+			staticApiKeys := map[string]*models.Caller{
+				"static-admin-key": {ID: "admin", Role: models.RoleAdmin},
+			}
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			res, err := NewAuthService(&logger, mockedApiKeyRepo, staticApiKeys, JWTConfig{}).AuthenticateApiKey(context.TODO(), tt.key)
+
+			if tt.expectedError != nil {
+				require.ErrorContains(t, err, tt.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("accepts static keys from the configuration", run(Test{
+		key:            "static-admin-key",
+		expectedResult: &models.Caller{ID: "admin", Role: models.RoleAdmin},
+	}))
+
+	t.Run("accepts keys from the database", run(Test{
+		key:            "kq_a-random-key",
+		mockedCaller:   &models.Caller{ID: "player-42", Role: models.RolePlayer},
+		expectedResult: &models.Caller{ID: "player-42", Role: models.RolePlayer},
+	}))
+
+	t.Run("rejects unknown keys", run(Test{
+		key:           "kq_an-unknown-key",
+		mockedError:   models.ErrApiKeyNotFound,
+		expectedError: models.ErrInvalidCredentials,
+	}))
+
+	t.Run("passes trough an error from apiKeyRepo", run(Test{
+		key:           "kq_a-random-key",
+		mockedError:   errors.New("this is an error"),
+		expectedError: errors.New("this is an error"),
+	}))
+}
+
+func TestAuthService_AuthenticateJWT(t *testing.T) {
+	secret := []byte("a-secret-of-at-least-32-bytes-long")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	type Test struct {
+		jwtConfig      JWTConfig
+		method         jwt.SigningMethod
+		key            any
+		claims         jwt.MapClaims
+		expectedResult *models.Caller
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			token, err := jwt.NewWithClaims(tt.method, tt.claims).SignedString(tt.key)
+			require.NoError(t, err)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			res, err := NewAuthService(&logger, nil, nil, tt.jwtConfig).AuthenticateJWT(context.TODO(), token)
+
+			if tt.expectedResult == nil {
+				require.ErrorIs(t, err, models.ErrInvalidCredentials)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	expiry := time.Now().Add(time.Hour).Unix()
+
+	t.Run("accepts a HS256 token and defaults to the player role", run(Test{
+		jwtConfig:      JWTConfig{HS256Secret: secret},
+		method:         jwt.SigningMethodHS256,
+		key:            secret,
+		claims:         jwt.MapClaims{"sub": "player-42", "exp": expiry},
+		expectedResult: &models.Caller{ID: "player-42", Role: models.RolePlayer},
+	}))
+
+	t.Run("accepts a RS256 token with the admin role", run(Test{
+		jwtConfig:      JWTConfig{RS256PublicKey: &rsaKey.PublicKey},
+		method:         jwt.SigningMethodRS256,
+		key:            rsaKey,
+		claims:         jwt.MapClaims{"sub": "admin", "role": "admin", "exp": expiry},
+		expectedResult: &models.Caller{ID: "admin", Role: models.RoleAdmin},
+	}))
+
+	t.Run("checks the issuer when configured", run(Test{
+		jwtConfig:      JWTConfig{HS256Secret: secret, Issuer: "https://auth.example.com"},
+		method:         jwt.SigningMethodHS256,
+		key:            secret,
+		claims:         jwt.MapClaims{"sub": "player-42", "exp": expiry, "iss": "https://auth.example.com"},
+		expectedResult: &models.Caller{ID: "player-42", Role: models.RolePlayer},
+	}))
+
+	t.Run("rejects a token of another issuer", run(Test{
+		jwtConfig: JWTConfig{HS256Secret: secret, Issuer: "https://auth.example.com"},
+		method:    jwt.SigningMethodHS256,
+		key:       secret,
+		claims:    jwt.MapClaims{"sub": "player-42", "exp": expiry, "iss": "https://evil.example.com"},
+	}))
+
+	t.Run("rejects a token signed with another secret", run(Test{
+		jwtConfig: JWTConfig{HS256Secret: secret},
+		method:    jwt.SigningMethodHS256,
+		key:       []byte("another-secret-of-at-least-32-bytes"),
+		claims:    jwt.MapClaims{"sub": "player-42", "exp": expiry},
+	}))
+
+	t.Run("rejects an algorithm that is not configured", run(Test{
+		jwtConfig: JWTConfig{RS256PublicKey: &rsaKey.PublicKey},
+		method:    jwt.SigningMethodHS256,
+		key:       secret,
+		claims:    jwt.MapClaims{"sub": "player-42", "exp": expiry},
+	}))
+
+	t.Run("rejects all tokens when nothing is configured", run(Test{
+		method: jwt.SigningMethodHS256,
+		key:    secret,
+		claims: jwt.MapClaims{"sub": "player-42", "exp": expiry},
+	}))
+
+	t.Run("rejects an expired token", run(Test{
+		jwtConfig: JWTConfig{HS256Secret: secret},
+		method:    jwt.SigningMethodHS256,
+		key:       secret,
+		claims:    jwt.MapClaims{"sub": "player-42", "exp": time.Now().Add(-time.Minute).Unix()},
+	}))
+
+	t.Run("rejects a token without expiry", run(Test{
+		jwtConfig: JWTConfig{HS256Secret: secret},
+		method:    jwt.SigningMethodHS256,
+		key:       secret,
+		claims:    jwt.MapClaims{"sub": "player-42"},
+	}))
+
+	t.Run("rejects a token without subject", run(Test{
+		jwtConfig: JWTConfig{HS256Secret: secret},
+		method:    jwt.SigningMethodHS256,
+		key:       secret,
+		claims:    jwt.MapClaims{"exp": expiry},
+	}))
+
+	t.Run("rejects an unknown role", run(Test{
+		jwtConfig: JWTConfig{HS256Secret: secret},
+		method:    jwt.SigningMethodHS256,
+		key:       secret,
+		claims:    jwt.MapClaims{"sub": "player-42", "role": "superuser", "exp": expiry},
+	}))
+}
+
+func TestAuthService_CreateApiKey(t *testing.T) {
+	mockedApiKeyRepo := new(MockedApiKeyRepo)
+	mockedApiKeyRepo.On("CreateApiKey", mock.Anything, "player-42", models.RolePlayer).
+		Once().
+		Return(&models.ApiKey{ID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"), Subject: "player-42", Role: models.RolePlayer}, nil)
+
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	apiKey, key, err := NewAuthService(&logger, mockedApiKeyRepo, nil, JWTConfig{}).CreateApiKey(context.TODO(), "player-42", models.RolePlayer)
+	require.NoError(t, err)
+	assert.Equal(t, "player-42", apiKey.Subject)
+	assert.True(t, strings.HasPrefix(key, apiKeyPrefix))
+
+	// Only the hash of the returned key may be stored
+	mockedApiKeyRepo.AssertCalled(t, "CreateApiKey", hashApiKey(key), "player-42", models.RolePlayer)
+}
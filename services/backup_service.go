@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+)
+
+// The file name of a backup is the time of the backup between backupPrefix and backupExtension.
+// The time is formatted so the names sort from old to new
+const (
+	backupPrefix     = "kabisa-"
+	backupExtension  = ".db"
+	backupTimeFormat = "20060102-150405.000000000"
+)
+
+type BackupService struct {
+	logger     *zerolog.Logger
+	backupRepo backupRepo
+	// dir is the directory the backups are written to. Without it, backups are disabled
+	dir string
+	// retention is the number of backups kept in dir. With 0, every backup is kept
+	retention int
+
+	// mu lets backups wait for each other, so the retention is only applied to complete backups
+	mu sync.Mutex
+}
+
+// NewBackupService returns a new BackupService, which writes backups to dir and keeps the newest ones up to the retention
+func NewBackupService(logger *zerolog.Logger, backupRepo backupRepo, dir string, retention int) *BackupService {
+	return &BackupService{
+		logger:     logger,
+		backupRepo: backupRepo,
+		dir:        dir,
+		retention:  retention,
+	}
+}
+
+// Backup writes a snapshot of the database to a new file in the backup directory and removes the oldest backups beyond the retention.
+// ErrBackupsDisabled is returned when there is no backup directory.
+func (service *BackupService) Backup(ctx context.Context) (*models.Backup, error) {
+	if service.dir == "" {
+		return nil, models.ErrBackupsDisabled
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	err := os.MkdirAll(service.dir, 0o750)
+	if err != nil {
+		return nil, errors.Join(errors.New("could not create backup directory"), err)
+	}
+	createdAt := time.Now().UTC()
+	name := backupPrefix + createdAt.Format(backupTimeFormat) + backupExtension
+	backup, err := service.write(ctx, filepath.Join(service.dir, name), createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	service.prune()
+	return backup, nil
+}
+
+// BackupTo writes a snapshot of the database to a new file at path. An existing file is never overwritten.
+// The file is not part of the backup directory, so the retention doesn't apply to it.
+func (service *BackupService) BackupTo(ctx context.Context, path string) (*models.Backup, error) {
+	return service.write(ctx, path, time.Now())
+}
+
+// write writes the snapshot to path, which is created at the given time
+func (service *BackupService) write(ctx context.Context, path string, createdAt time.Time) (*models.Backup, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("%s exists already", path)
+	}
+
+	// The snapshot gets its name when it's complete, so an interrupted backup doesn't look like a backup
+	tmp := path + ".tmp"
+	err := service.backupRepo.VacuumInto(ctx, tmp)
+	if err != nil {
+		os.Remove(tmp) //nolint:errcheck // the snapshot may not exist at all
+		return nil, err
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return nil, errors.Join(errors.New("could not rename backup"), err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Join(errors.New("could not read backup"), err)
+	}
+	service.logger.Info().Str("path", path).Int64("size", info.Size()).Msg("created backup")
+	return &models.Backup{
+		Name:      filepath.Base(path),
+		Size:      info.Size(),
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// ListBackups returns the backups in the backup directory, newest first. Other files in the directory are ignored.
+// ErrBackupsDisabled is returned when there is no backup directory.
+func (service *BackupService) ListBackups(_ context.Context) ([]*models.Backup, error) {
+	if service.dir == "" {
+		return nil, models.ErrBackupsDisabled
+	}
+
+	entries, err := os.ReadDir(service.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		// The directory is created by the first backup
+		return []*models.Backup{}, nil
+	}
+	if err != nil {
+		return nil, errors.Join(errors.New("could not read backup directory"), err)
+	}
+
+	backups := []*models.Backup{}
+	for _, entry := range entries {
+		createdAt, ok := parseBackupName(entry.Name())
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// The backup was removed in the meantime
+			continue
+		}
+		backups = append(backups, &models.Backup{
+			Name:      entry.Name(),
+			Size:      info.Size(),
+			CreatedAt: createdAt,
+		})
+	}
+	slices.SortFunc(backups, func(a, b *models.Backup) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return backups, nil
+}
+
+// prune removes the oldest backups beyond the retention. The new backup is complete already, so failures are only logged
+func (service *BackupService) prune() {
+	if service.retention <= 0 {
+		return
+	}
+
+	backups, err := service.ListBackups(context.Background())
+	if err != nil {
+		service.logger.Error().Err(err).Msg("could not list backups to remove")
+		return
+	}
+	for _, backup := range backups[min(service.retention, len(backups)):] {
+		err = os.Remove(filepath.Join(service.dir, backup.Name))
+		if err != nil {
+			service.logger.Error().Err(err).Str("name", backup.Name).Msg("could not remove old backup")
+			continue
+		}
+		service.logger.Info().Str("name", backup.Name).Msg("removed old backup")
+	}
+}
+
+// parseBackupName returns the time of the backup with the given file name. False is returned for files that are not a backup
+func parseBackupName(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, backupPrefix) || !strings.HasSuffix(name, backupExtension) {
+		return time.Time{}, false
+	}
+	createdAt, err := time.Parse(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), backupExtension))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return createdAt, true
+}
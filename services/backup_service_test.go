@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockedBackupRepo returns a repo that writes a fake snapshot, like VACUUM INTO would
+func newMockedBackupRepo(t *testing.T) *MockedBackupRepo {
+	t.Helper()
+
+	mockedBackupRepo := new(MockedBackupRepo)
+	mockedBackupRepo.On("VacuumInto", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		require.NoError(t, os.WriteFile(args.String(0), []byte("snapshot"), 0o600))
+	})
+	return mockedBackupRepo
+}
+
+func TestBackupService_Backup(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	dir := filepath.Join(t.TempDir(), "backups")
+	service := NewBackupService(&logger, newMockedBackupRepo(t), dir, 2)
+
+	// The directory doesn't exist until the first backup
+	backups, err := service.ListBackups(context.TODO())
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+
+	// Files of others in the directory are left alone
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("keep me"), 0o600))
+
+	var created []*models.Backup
+	for range 3 {
+		backup, err := service.Backup(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, int64(len("snapshot")), backup.Size)
+		created = append(created, backup)
+	}
+
+	// Only the newest backups within the retention are kept
+	backups, err = service.ListBackups(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Backup{created[2], created[1]}, backups)
+	assert.NoFileExists(t, filepath.Join(dir, created[0].Name))
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+}
+
+func TestBackupService_BackupWithoutRetention(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	service := NewBackupService(&logger, newMockedBackupRepo(t), t.TempDir(), 0)
+
+	for range 3 {
+		_, err := service.Backup(context.TODO())
+		require.NoError(t, err)
+	}
+	backups, err := service.ListBackups(context.TODO())
+	require.NoError(t, err)
+	assert.Len(t, backups, 3)
+}
+
+func TestBackupService_BackupFails(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	dir := t.TempDir()
+	errDatabase := errors.New("database is gone")
+
+	// The snapshot was written partially, before it failed
+	mockedBackupRepo := new(MockedBackupRepo)
+	mockedBackupRepo.On("VacuumInto", mock.Anything).Return(errDatabase).Run(func(args mock.Arguments) {
+		require.NoError(t, os.WriteFile(args.String(0), []byte("snaps"), 0o600))
+	})
+
+	service := NewBackupService(&logger, mockedBackupRepo, dir, 2)
+	backup, err := service.Backup(context.TODO())
+	require.ErrorIs(t, err, errDatabase)
+	assert.Nil(t, backup)
+
+	// Nothing is left behind that looks like a backup
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestBackupService_BackupTo(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	path := filepath.Join(t.TempDir(), "snapshot.db")
+	service := NewBackupService(&logger, newMockedBackupRepo(t), "", 2)
+
+	backup, err := service.BackupTo(context.TODO(), path)
+	require.NoError(t, err)
+	assert.Equal(t, "snapshot.db", backup.Name)
+	assert.FileExists(t, path)
+
+	// An existing file is never overwritten
+	_, err = service.BackupTo(context.TODO(), path)
+	assert.ErrorContains(t, err, "exists already")
+}
+
+func TestBackupService_Disabled(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	mockedBackupRepo := new(MockedBackupRepo)
+	service := NewBackupService(&logger, mockedBackupRepo, "", 2)
+
+	_, err := service.Backup(context.TODO())
+	assert.Equal(t, models.ErrBackupsDisabled, err)
+	_, err = service.ListBackups(context.TODO())
+	assert.Equal(t, models.ErrBackupsDisabled, err)
+	mockedBackupRepo.AssertNotCalled(t, "VacuumInto", mock.Anything)
+}
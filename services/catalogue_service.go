@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+)
+
+// dailyQuoteSeed is hashed together with the date to choose the quote of the day. Changing it changes the choice for all days that aren't stored yet
+const dailyQuoteSeed = "kabisa-quote-of-the-day"
+
+type CatalogueService struct {
+	logger        *zerolog.Logger
+	dummyJsonRepo dummyJsonRepo
+	quoteRepo     quoteRepo
+}
+
+func NewCatalogueService(logger *zerolog.Logger, dummyJsonRepo dummyJsonRepo, quoteRepo quoteRepo) *CatalogueService {
+	return &CatalogueService{
+		logger:        logger,
+		dummyJsonRepo: dummyJsonRepo,
+		quoteRepo:     quoteRepo,
+	}
+}
+
+// SyncCatalogue retrieves all quotes from dummyjson and replaces the local catalogue with them.
+// The local catalogue is used for browsing and searching, which the dummyjson api doesn't support.
+func (service *CatalogueService) SyncCatalogue(ctx context.Context) error {
+	quotes, err := service.dummyJsonRepo.GetAllQuotes(ctx)
+	if err != nil {
+		return err
+	}
+	// An empty response would wipe the catalogue, which is never what we want
+	if len(quotes) == 0 {
+		return errors.New("dummyJsonRepo returned no quotes and no error")
+	}
+
+	err = service.quoteRepo.ReplaceQuotes(ctx, quotes)
+	if err != nil {
+		return err
+	}
+
+	service.logger.Info().Int("quotes", len(quotes)).Msg("synced quote catalogue")
+	return nil
+}
+
+// ListQuotes returns a page of quotes from the local catalogue that match the filter
+func (service *CatalogueService) ListQuotes(ctx context.Context, filter models.QuoteFilter) (*models.Page[*models.Quote], error) {
+	return service.quoteRepo.ListQuotes(ctx, filter)
+}
+
+// GetQuote returns a single quote from the local catalogue
+func (service *CatalogueService) GetQuote(ctx context.Context, id int) (*models.Quote, error) {
+	return service.quoteRepo.GetQuote(ctx, id)
+}
+
+// ListAuthors returns a page of authors in the local catalogue, together with their number of quotes
+func (service *CatalogueService) ListAuthors(ctx context.Context, limit, offset int) (*models.Page[*models.AuthorQuoteCount], error) {
+	return service.quoteRepo.ListAuthors(ctx, limit, offset)
+}
+
+// GetDailyQuote returns the quote of the given day (in UTC). The quote is chosen deterministically from the catalogue using a seeded hash
+// of the date and stored on first use, so it stays the same for the whole day, even when the catalogue changes in the meantime.
+// Days in the future don't have a quote yet and return ErrDailyQuoteNotFound.
+func (service *CatalogueService) GetDailyQuote(ctx context.Context, day time.Time) (*models.DailyQuote, error) {
+	date := day.UTC().Format(time.DateOnly)
+	if date > time.Now().UTC().Format(time.DateOnly) {
+		return nil, models.ErrDailyQuoteNotFound
+	}
+
+	dailyQuote, err := service.quoteRepo.GetDailyQuote(ctx, date)
+	if err != models.ErrDailyQuoteNotFound {
+		return dailyQuote, err
+	}
+
+	// No quote has been chosen for this day yet, so we choose one
+	ids, err := service.quoteRepo.GetQuoteIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, models.ErrCatalogueEmpty
+	}
+
+	quote, err := service.quoteRepo.GetQuote(ctx, ids[dailyQuoteIndex(date, len(ids))])
+	if err != nil {
+		return nil, err
+	}
+	return service.quoteRepo.CreateDailyQuote(ctx, date, quote)
+}
+
+// dailyQuoteIndex deterministically maps a date to an index in a list of the given length
+func dailyQuoteIndex(date string, length int) int {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(dailyQuoteSeed + date))
+	return int(h.Sum64() % uint64(length))
+}
+
+// CreateQuote adds a new quote to the local store, which can be used by the game like any other quote unless it's hidden
+func (service *CatalogueService) CreateQuote(ctx context.Context, edit models.QuoteEdit) (*models.CuratedQuote, error) {
+	quote, err := service.quoteRepo.CreateQuote(ctx, edit)
+	if err != nil {
+		return nil, err
+	}
+	service.logger.Info().Int("id", quote.ID).Bool("hidden", quote.Hidden).Msg("quote created by admin")
+	return quote, nil
+}
+
+// UpdateQuote edits a quote in the local store. Hidden quotes are no longer shown to players, but can be made visible again
+func (service *CatalogueService) UpdateQuote(ctx context.Context, id int, edit models.QuoteEdit) (*models.CuratedQuote, error) {
+	quote, err := service.quoteRepo.UpdateQuote(ctx, id, edit)
+	if err != nil {
+		return nil, err
+	}
+	service.logger.Info().Int("id", quote.ID).Bool("hidden", quote.Hidden).Msg("quote updated by admin")
+	return quote, nil
+}
+
+// DeleteQuote soft-deletes a quote, which blocks it permanently. Also when dummyjson returns it
+func (service *CatalogueService) DeleteQuote(ctx context.Context, id int) error {
+	err := service.quoteRepo.DeleteQuote(ctx, id)
+	if err != nil {
+		return err
+	}
+	service.logger.Info().Int("id", id).Msg("quote deleted by admin")
+	return nil
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogueService_SyncCatalogue(t *testing.T) {
+	type Test struct {
+		mockedJsonRepoQuotes []*models.Quote
+		mockedJsonRepoError  error
+		mockedReplaceError   error
+		expectReplace        bool
+		expectedError        error
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedDummyJsonRepo := new(MockedDummyJsonRepo)
+			mockedDummyJsonRepo.On("GetAllQuotes").
+				Once().
+				Return(tt.mockedJsonRepoQuotes, tt.mockedJsonRepoError)
+
+			mockedQuoteRepo := new(MockedQuoteRepo)
+			mockedQuoteRepo.On("ReplaceQuotes", tt.mockedJsonRepoQuotes).
+				Once().
+				Return(tt.mockedReplaceError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			err := NewCatalogueService(&logger, mockedDummyJsonRepo, mockedQuoteRepo).SyncCatalogue(context.TODO())
+
+			if tt.expectedError != nil {
+				require.ErrorContains(t, err, tt.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tt.expectReplace {
+				mockedQuoteRepo.AssertCalled(t, "ReplaceQuotes", tt.mockedJsonRepoQuotes)
+			} else {
+				mockedQuoteRepo.AssertNotCalled(t, "ReplaceQuotes", tt.mockedJsonRepoQuotes)
+			}
+		}
+	}
+
+	t.Run("replaces the catalogue with the quotes from dummyJsonRepo", run(Test{
+		mockedJsonRepoQuotes: []*models.Quote{
+			{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi"},
+		},
+		expectReplace: true,
+	}))
+
+	t.Run("passes trough an error from dummyJsonRepo", run(Test{
+		mockedJsonRepoError: errors.New("this is an error"),
+		expectedError:       errors.New("this is an error"),
+	}))
+
+	t.Run("does not wipe the catalogue when dummyJsonRepo returns no quotes", run(Test{
+		mockedJsonRepoQuotes: []*models.Quote{},
+		expectedError:        errors.New("dummyJsonRepo returned no quotes and no error"),
+	}))
+
+	t.Run("passes trough an error from quoteRepo", run(Test{
+		mockedJsonRepoQuotes: []*models.Quote{
+			{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi"},
+		},
+		mockedReplaceError: errors.New("this is an error"),
+		expectReplace:      true,
+		expectedError:      errors.New("this is an error"),
+	}))
+}
+
+func TestCatalogueService_GetDailyQuote(t *testing.T) {
+	type Test struct {
+		day                     time.Time
+		mockedStoredDailyQuote  *models.DailyQuote
+		mockedStoredError       error
+		mockedQuoteIDs          []int
+		expectedChosenQuote     *models.Quote
+		mockedCreatedDailyQuote *models.DailyQuote
+		expectedResult          *models.DailyQuote
+		expectedError           error
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			date := tt.day.UTC().Format(time.DateOnly)
+			mockedQuoteRepo := new(MockedQuoteRepo)
+			mockedQuoteRepo.On("GetDailyQuote", date).
+				Once().
+				Return(tt.mockedStoredDailyQuote, tt.mockedStoredError)
+			mockedQuoteRepo.On("GetQuoteIDs").
+				Once().
+				Return(tt.mockedQuoteIDs, nil)
+			if tt.expectedChosenQuote != nil {
+				mockedQuoteRepo.On("GetQuote", tt.expectedChosenQuote.ID).
+					Once().
+					Return(tt.expectedChosenQuote, nil)
+			}
+			mockedQuoteRepo.On("CreateDailyQuote", date, tt.expectedChosenQuote).
+				Once().
+				Return(tt.mockedCreatedDailyQuote, nil)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			res, err := NewCatalogueService(&logger, nil, mockedQuoteRepo).GetDailyQuote(context.TODO(), tt.day)
+
+			if tt.expectedError != nil {
+				require.ErrorContains(t, err, tt.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	quotes := []*models.Quote{
+		{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi"},
+		{ID: 451, Quote: "We should not give up and we should not allow the problem to defeat us.", Author: "Abdul Kalam"},
+		{ID: 905, Quote: "Try as much as you can to mention death.", Author: "Umar ibn Al-Khattāb (R.A)"},
+	}
+	day := time.Date(2025, 2, 1, 15, 4, 5, 0, time.UTC)
+	chosen := quotes[dailyQuoteIndex("2025-02-01", len(quotes))]
+
+	t.Run("returns the stored quote of the day", run(Test{
+		day:                    day,
+		mockedStoredDailyQuote: &models.DailyQuote{Date: "2025-02-01", Quote: *quotes[0]},
+		expectedResult:         &models.DailyQuote{Date: "2025-02-01", Quote: *quotes[0]},
+	}))
+
+	t.Run("chooses and stores a quote when none is stored yet", run(Test{
+		day:                     day,
+		mockedStoredError:       models.ErrDailyQuoteNotFound,
+		mockedQuoteIDs:          []int{70, 451, 905},
+		expectedChosenQuote:     chosen,
+		mockedCreatedDailyQuote: &models.DailyQuote{Date: "2025-02-01", Quote: *chosen},
+		expectedResult:          &models.DailyQuote{Date: "2025-02-01", Quote: *chosen},
+	}))
+
+	t.Run("uses the day in UTC", run(Test{
+		day:                    time.Date(2025, 2, 2, 0, 30, 0, 0, time.FixedZone("CET", 3600)),
+		mockedStoredDailyQuote: &models.DailyQuote{Date: "2025-02-01", Quote: *quotes[0]},
+		expectedResult:         &models.DailyQuote{Date: "2025-02-01", Quote: *quotes[0]},
+	}))
+
+	t.Run("returns an error when the catalogue is empty", run(Test{
+		day:               day,
+		mockedStoredError: models.ErrDailyQuoteNotFound,
+		mockedQuoteIDs:    []int{},
+		expectedError:     models.ErrCatalogueEmpty,
+	}))
+
+	t.Run("returns not found for days in the future", run(Test{
+		day:           time.Now().Add(48 * time.Hour),
+		expectedError: models.ErrDailyQuoteNotFound,
+	}))
+}
+
+func TestDailyQuoteIndex(t *testing.T) {
+	// The same date always results in the same index
+	assert.Equal(t, dailyQuoteIndex("2025-02-01", 1454), dailyQuoteIndex("2025-02-01", 1454))
+
+	// And the index is spread over the full range
+	seen := map[int]bool{}
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 365 {
+		index := dailyQuoteIndex(day.AddDate(0, 0, i).Format(time.DateOnly), 10)
+		assert.GreaterOrEqual(t, index, 0)
+		assert.Less(t, index, 10)
+		seen[index] = true
+	}
+	assert.Len(t, seen, 10)
+}
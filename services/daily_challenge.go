@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+)
+
+// dailyChallengeSeed is hashed together with the date to choose the quotes of the daily challenge. Changing it changes the choice for all days that aren't stored yet
+const dailyChallengeSeed = "kabisa-daily-challenge"
+
+// CreateDailyQuoteGame creates a quote game of the daily challenge for the player. Every player gets the same quotes on the same day (in UTC)
+// and can play the challenge only once per day, so a player is required.
+func (service *QuoteService) CreateDailyQuoteGame(ctx context.Context, playerID string) (*models.DailyQuoteGame, error) {
+	if playerID == "" {
+		return nil, models.ErrPlayerIDRequired
+	}
+
+	date := time.Now().UTC().Format(time.DateOnly)
+	quotes, err := service.getDailyChallengeQuotes(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+
+	// The daily challenge is always played in GameModeMatch
+	game := service.engines[models.GameModeMatch].NewGame(uuid.New(), quotes)
+	err = service.quoteGameRepo.CreateDailyQuoteGame(ctx, game, playerID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleDeadlineEvents(service.publisher, models.QuoteGameTopic(game.ID), time.Now().Add(models.QuoteGameDuration))
+	return &models.DailyQuoteGame{QuoteGame: *game, Date: date}, nil
+}
+
+// GetDailyLeaderboard returns a page of the results of the daily challenge of the given day (in UTC), best score first
+func (service *QuoteService) GetDailyLeaderboard(ctx context.Context, day time.Time, limit, offset int) (*models.Page[*models.DailyLeaderboardEntry], error) {
+	return service.quoteGameRepo.GetDailyLeaderboard(ctx, day.UTC().Format(time.DateOnly), limit, offset)
+}
+
+// getDailyChallengeQuotes returns the quotes of the daily challenge of the given day. The quotes are chosen on first use and stored,
+// so they stay the same for the whole day, even when the catalogue changes in the meantime.
+func (service *QuoteService) getDailyChallengeQuotes(ctx context.Context, date string) ([]*models.Quote, error) {
+	quoteIDs, err := service.quoteGameRepo.GetDailyChallenge(ctx, date)
+	if err == models.ErrDailyChallengeNotFound {
+		quoteIDs, err = service.chooseDailyChallenge(ctx, date)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := service.GetQuotes(ctx, quoteIDs)
+	if err != nil {
+		return nil, err
+	}
+	gameQuotes := make([]*models.Quote, len(quoteIDs))
+	for i, id := range quoteIDs {
+		gameQuotes[i] = quotes[id]
+	}
+	return gameQuotes, nil
+}
+
+// chooseDailyChallenge deterministically chooses quotes by distinct authors from the catalogue for the given day and stores them.
+// The catalogue is shuffled with a seeded hash of the date, after which the first quotes with distinct authors are taken.
+func (service *QuoteService) chooseDailyChallenge(ctx context.Context, date string) ([]int, error) {
+	ids, err := service.quoteRepo.GetQuoteIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, models.ErrCatalogueEmpty
+	}
+
+	// The ids are shared with the repo, so we shuffle a copy
+	ids = append([]int{}, ids...)
+	dailyChallengeRand(date).Shuffle(len(ids), func(i, j int) {
+		ids[i], ids[j] = ids[j], ids[i]
+	})
+
+	// We look at the shuffled quotes in batches, as we usually need only the first few
+	quoteIDs := make([]int, 0, quoteGameSize)
+	usedAuthors := map[string]bool{}
+	for start := 0; start < len(ids) && len(quoteIDs) < quoteGameSize; start += quoteSampleSize {
+		batch := ids[start:min(start+quoteSampleSize, len(ids))]
+		quotes, err := service.quoteRepo.GetQuotes(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range batch {
+			q, ok := quotes[id]
+			if !ok || usedAuthors[q.Author] {
+				continue
+			}
+			usedAuthors[q.Author] = true
+			quoteIDs = append(quoteIDs, id)
+			if len(quoteIDs) == quoteGameSize {
+				break
+			}
+		}
+	}
+	if len(quoteIDs) < quoteGameSize {
+		service.logger.Error().Str("date", date).Int("found", len(quoteIDs)).Msg("could not find enough quotes with distinct authors for the daily challenge")
+		return nil, models.ErrNotEnoughDistinctAuthors
+	}
+
+	// When another request chose the quotes in the meantime, we get those back, so everyone plays the same challenge
+	return service.quoteGameRepo.CreateDailyChallenge(ctx, date, quoteIDs)
+}
+
+// dailyChallengeRand returns a random source that is the same for every call with the same date
+func dailyChallengeRand(date string) *rand.Rand {
+	h := fnv.New128a()
+	_, _ = h.Write([]byte(dailyChallengeSeed + date))
+	sum := h.Sum(nil)
+	return rand.New(rand.NewPCG(binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:])))
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"maps"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteService_CreateDailyQuoteGame(t *testing.T) {
+	rumi := &models.Quote{ID: 70, Quote: "The cure for pain is in the pain.", Author: "Rumi"}
+	rumi2 := &models.Quote{ID: 172, Quote: "The only lasting beauty is the beauty of the heart.", Author: "Rumi"}
+	kalam := &models.Quote{ID: 451, Quote: "We should not give up and we should not allow the problem to defeat us.", Author: "Abdul Kalam"}
+	umar := &models.Quote{ID: 905, Quote: "Try as much as you can to mention death.", Author: "Umar ibn Al-Khattāb (R.A)"}
+	catalogue := map[int]*models.Quote{rumi.ID: rumi, rumi2.ID: rumi2, kalam.ID: kalam, umar.ID: umar}
+	date := time.Now().UTC().Format(time.DateOnly)
+
+	type Test struct {
+		playerID             string
+		mockedChallenge      []int
+		mockedCatalogueIDs   []int
+		expectedGameQuotes   []*models.Quote
+		mockedQuoteGameError error
+		expectedError        error
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedQuoteGameRepo := new(MockedQuoteGameRepo)
+			if tt.mockedChallenge != nil {
+				mockedQuoteGameRepo.On("GetDailyChallenge", date).Return(tt.mockedChallenge, nil)
+			} else {
+				mockedQuoteGameRepo.On("GetDailyChallenge", date).Return([]int(nil), models.ErrDailyChallengeNotFound)
+			}
+			// A new challenge has to consist of quotes by distinct authors from the catalogue
+			mockedQuoteGameRepo.On("CreateDailyChallenge", date, mock.MatchedBy(func(ids []int) bool {
+				authors := map[string]bool{}
+				for _, id := range ids {
+					authors[catalogue[id].Author] = true
+				}
+				return len(ids) == quoteGameSize && len(authors) == quoteGameSize
+			})).Return([]int{905, 70, 451}, nil)
+			mockedQuoteGameRepo.On("CreateDailyQuoteGame", mock.Anything, tt.playerID, date).Return(tt.mockedQuoteGameError)
+
+			mockedQuoteRepo := new(MockedQuoteRepo)
+			mockedQuoteRepo.On("GetQuoteIDs").Return(tt.mockedCatalogueIDs, nil)
+			mockedQuoteRepo.On("GetQuotes", mock.Anything).Return(maps.Clone(catalogue), nil)
+
+			mockedPublisher := new(MockedPublisher)
+			mockedPublisher.On("Schedule", mock.Anything, mock.Anything)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			res, err := NewQuoteService(&logger, nil, mockedQuoteGameRepo, mockedQuoteRepo, 10, mockedPublisher).CreateDailyQuoteGame(context.TODO(), tt.playerID)
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, res)
+				mockedPublisher.AssertNotCalled(t, "Schedule", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			game := models.NewQuoteGame(res.ID, tt.expectedGameQuotes)
+			assert.Equal(t, &models.DailyQuoteGame{QuoteGame: *game, Date: date}, res)
+			mockedPublisher.AssertNumberOfCalls(t, "Schedule", 2)
+			mockedQuoteGameRepo.AssertCalled(t, "CreateDailyQuoteGame", game, tt.playerID, date)
+		}
+	}
+
+	t.Run("uses the stored challenge of today", run(Test{
+		playerID:           "player-1",
+		mockedChallenge:    []int{905, 70, 451},
+		expectedGameQuotes: []*models.Quote{umar, rumi, kalam},
+	}))
+
+	// The stored challenge is returned by the repo, which can be the challenge chosen by a concurrent request
+	t.Run("chooses quotes by distinct authors when there's no challenge yet", run(Test{
+		playerID:           "player-1",
+		mockedCatalogueIDs: []int{70, 172, 451, 905},
+		expectedGameQuotes: []*models.Quote{umar, rumi, kalam},
+	}))
+
+	t.Run("returns an error when the catalogue has too few authors", run(Test{
+		playerID:           "player-1",
+		mockedCatalogueIDs: []int{70, 172, 451},
+		expectedError:      models.ErrNotEnoughDistinctAuthors,
+	}))
+
+	t.Run("returns an error when the catalogue is empty", run(Test{
+		playerID:           "player-1",
+		mockedCatalogueIDs: []int{},
+		expectedError:      models.ErrCatalogueEmpty,
+	}))
+
+	t.Run("returns an error when the player played today already", run(Test{
+		playerID:             "player-1",
+		mockedChallenge:      []int{905, 70, 451},
+		mockedQuoteGameError: models.ErrDailyChallengeAlreadyPlayed,
+		expectedError:        models.ErrDailyChallengeAlreadyPlayed,
+	}))
+
+	t.Run("requires a player", run(Test{
+		expectedError: models.ErrPlayerIDRequired,
+	}))
+}
+
+func TestQuoteService_GetDailyLeaderboard(t *testing.T) {
+	page := &models.Page[*models.DailyLeaderboardEntry]{Items: []*models.DailyLeaderboardEntry{{Rank: 1, PlayerID: "player-1", Score: 3}}, Total: 1, Limit: 10}
+	mockedQuoteGameRepo := new(MockedQuoteGameRepo)
+	mockedQuoteGameRepo.On("GetDailyLeaderboard", "2025-02-01", 10, 0).Return(page, nil)
+
+	// The day is converted to UTC, like the date of the challenge
+	day := time.Date(2025, 2, 2, 0, 30, 0, 0, time.FixedZone("CET", 3600))
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	res, err := NewQuoteService(&logger, nil, mockedQuoteGameRepo, nil, 10, nil).GetDailyLeaderboard(context.TODO(), day, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, page, res)
+}
+
+func TestDailyChallengeRand(t *testing.T) {
+	shuffle := func(date string) []int {
+		ids := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+		dailyChallengeRand(date).Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+		return ids
+	}
+
+	// The same day always gives the same order, another day gives another order
+	assert.Equal(t, shuffle("2025-02-01"), shuffle("2025-02-01"))
+	assert.False(t, slices.Equal(shuffle("2025-02-01"), shuffle("2025-02-02")))
+}
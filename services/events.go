@@ -0,0 +1,22 @@
+package services
+
+import (
+	"time"
+
+	"github.com/pietdevries94/Kabisa/models"
+)
+
+// scheduleDeadlineEvents warns the followers of a game or room shortly before the deadline and tells them when the deadline passed.
+// The atDeadline events are published at the deadline as well, right before the game expired event.
+func scheduleDeadlineEvents(publisher publisher, topic string, deadline time.Time, atDeadline ...models.EventType) {
+	publisher.Schedule(deadline.Add(-models.DeadlineWarning), []models.Event{
+		{Topic: topic, Type: models.EventDeadlineApproaching, Deadline: deadline},
+	})
+
+	events := []models.Event{}
+	for _, eventType := range atDeadline {
+		events = append(events, models.Event{Topic: topic, Type: eventType, Deadline: deadline})
+	}
+	events = append(events, models.Event{Topic: topic, Type: models.EventGameExpired, Deadline: deadline})
+	publisher.Schedule(deadline, events)
+}
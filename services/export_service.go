@@ -0,0 +1,138 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+)
+
+// gameExportHeader is the header row of a CSV export of games. The columns are named like the fields of the NDJSON export
+var gameExportHeader = []string{
+	"id", "mode", "playerId", "dailyDate",
+	"quote1Id", "quote2Id", "quote3Id",
+	"quote1Correct", "quote2Correct", "quote3Correct",
+	"createdAt", "completedAt", "durationMs",
+}
+
+type ExportService struct {
+	logger     *zerolog.Logger
+	exportRepo exportRepo
+}
+
+// NewExportService returns a new ExportService
+func NewExportService(logger *zerolog.Logger, exportRepo exportRepo) *ExportService {
+	return &ExportService{
+		logger:     logger,
+		exportRepo: exportRepo,
+	}
+}
+
+// gameExportRecord is a game in an NDJSON export. Values that a game doesn't have yet are null
+type gameExportRecord struct {
+	ID          uuid.UUID       `json:"id"`
+	Mode        models.GameMode `json:"mode"`
+	PlayerID    *string         `json:"playerId"`
+	DailyDate   *string         `json:"dailyDate"`
+	QuoteIDs    []int           `json:"quoteIds"`
+	Correct     []bool          `json:"correct"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	CompletedAt *time.Time      `json:"completedAt"`
+	DurationMs  *int64          `json:"durationMs"`
+}
+
+// ExportGames writes the games created from from up to to to w, oldest first. A zero from or to leaves that side of the range open.
+// The games are written while they are read, so the export is never held in memory completely.
+func (service *ExportService) ExportGames(ctx context.Context, w io.Writer, format models.ExportFormat, from, to time.Time) error {
+	var err error
+	switch format {
+	case models.ExportFormatCSV:
+		err = service.exportGamesCSV(ctx, w, from, to)
+	case models.ExportFormatNDJSON:
+		err = service.exportGamesNDJSON(ctx, w, from, to)
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+	if err != nil {
+		service.logger.Error().Err(err).Str("format", string(format)).Msg("could not export games")
+	}
+	return err
+}
+
+func (service *ExportService) exportGamesCSV(ctx context.Context, w io.Writer, from, to time.Time) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(gameExportHeader)
+	if err != nil {
+		return errors.Join(errors.New("could not write header"), err)
+	}
+
+	err = service.exportRepo.ExportQuoteGames(ctx, from, to, func(game *models.GameExport) error {
+		row := make([]string, 0, len(gameExportHeader))
+		row = append(row, game.ID.String(), string(game.Mode), game.PlayerID, game.DailyDate)
+		for _, id := range game.QuoteIDs {
+			row = append(row, strconv.Itoa(id))
+		}
+		for i := range game.QuoteIDs {
+			if game.Correct == nil {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, strconv.FormatBool(game.Correct[i]))
+		}
+		row = append(row, game.CreatedAt.UTC().Format(time.RFC3339Nano))
+		if game.CompletedAt == nil {
+			row = append(row, "", "")
+		} else {
+			row = append(row, game.CompletedAt.UTC().Format(time.RFC3339Nano), strconv.FormatInt(game.Duration().Milliseconds(), 10))
+		}
+		return writer.Write(row)
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (service *ExportService) exportGamesNDJSON(ctx context.Context, w io.Writer, from, to time.Time) error {
+	buffer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffer)
+
+	err := service.exportRepo.ExportQuoteGames(ctx, from, to, func(game *models.GameExport) error {
+		record := gameExportRecord{
+			ID:        game.ID,
+			Mode:      game.Mode,
+			QuoteIDs:  game.QuoteIDs,
+			Correct:   game.Correct,
+			CreatedAt: game.CreatedAt.UTC(),
+		}
+		if game.PlayerID != "" {
+			record.PlayerID = &game.PlayerID
+		}
+		if game.DailyDate != "" {
+			record.DailyDate = &game.DailyDate
+		}
+		if game.CompletedAt != nil {
+			completedAt := game.CompletedAt.UTC()
+			durationMs := game.Duration().Milliseconds()
+			record.CompletedAt = &completedAt
+			record.DurationMs = &durationMs
+		}
+		// Encode ends every object with a newline
+		return encoder.Encode(record)
+	})
+	if err != nil {
+		return err
+	}
+	return buffer.Flush()
+}
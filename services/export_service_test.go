@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportService_ExportGames(t *testing.T) {
+	createdAt := time.Date(2026, 10, 19, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	completedAt := createdAt.Add(12500 * time.Millisecond)
+	games := []*models.GameExport{
+		{
+			ID:          uuid.MustParse("0b4c6c8e-52d5-4a47-9d4b-3f8c0f0d1a01"),
+			Mode:        models.GameModeMatch,
+			PlayerID:    "player-1",
+			DailyDate:   "2026-10-19",
+			QuoteIDs:    []int{1, 2, 3},
+			Correct:     []bool{true, false, true},
+			CreatedAt:   createdAt,
+			CompletedAt: &completedAt,
+		},
+		{
+			ID:        uuid.MustParse("0b4c6c8e-52d5-4a47-9d4b-3f8c0f0d1a02"),
+			Mode:      models.GameModeMultipleChoice,
+			QuoteIDs:  []int{4, 5, 6},
+			CreatedAt: createdAt,
+		},
+	}
+	from := createdAt.Add(-time.Hour)
+
+	type Test struct {
+		format         models.ExportFormat
+		games          []*models.GameExport
+		repoErr        error
+		expectedOutput string
+		expectedError  string
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			mockedExportRepo := new(MockedExportRepo)
+			mockedExportRepo.On("ExportQuoteGames", from, time.Time{}).Return(tt.games, tt.repoErr)
+			service := NewExportService(&logger, mockedExportRepo)
+
+			var output strings.Builder
+			err := service.ExportGames(context.TODO(), &output, tt.format, from, time.Time{})
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedOutput, output.String())
+		}
+	}
+
+	t.Run("writes a CSV row per game", run(Test{
+		format: models.ExportFormatCSV,
+		games:  games,
+		expectedOutput: "id,mode,playerId,dailyDate,quote1Id,quote2Id,quote3Id,quote1Correct,quote2Correct,quote3Correct,createdAt,completedAt,durationMs\n" +
+			"0b4c6c8e-52d5-4a47-9d4b-3f8c0f0d1a01,match,player-1,2026-10-19,1,2,3,true,false,true,2026-10-19T12:00:00Z,2026-10-19T12:00:12.5Z,12500\n" +
+			"0b4c6c8e-52d5-4a47-9d4b-3f8c0f0d1a02,multiple_choice,,,4,5,6,,,,2026-10-19T12:00:00Z,,\n",
+	}))
+
+	t.Run("writes only the CSV header without games", run(Test{
+		format:         models.ExportFormatCSV,
+		games:          []*models.GameExport{},
+		expectedOutput: "id,mode,playerId,dailyDate,quote1Id,quote2Id,quote3Id,quote1Correct,quote2Correct,quote3Correct,createdAt,completedAt,durationMs\n",
+	}))
+
+	t.Run("writes a JSON object per line", run(Test{
+		format: models.ExportFormatNDJSON,
+		games:  games,
+		expectedOutput: `{"id":"0b4c6c8e-52d5-4a47-9d4b-3f8c0f0d1a01","mode":"match","playerId":"player-1","dailyDate":"2026-10-19","quoteIds":[1,2,3],"correct":[true,false,true],"createdAt":"2026-10-19T12:00:00Z","completedAt":"2026-10-19T12:00:12.5Z","durationMs":12500}` + "\n" +
+			`{"id":"0b4c6c8e-52d5-4a47-9d4b-3f8c0f0d1a02","mode":"multiple_choice","playerId":null,"dailyDate":null,"quoteIds":[4,5,6],"correct":null,"createdAt":"2026-10-19T12:00:00Z","completedAt":null,"durationMs":null}` + "\n",
+	}))
+
+	t.Run("writes nothing as NDJSON without games", run(Test{
+		format:         models.ExportFormatNDJSON,
+		games:          []*models.GameExport{},
+		expectedOutput: "",
+	}))
+
+	t.Run("returns the error of the repo", run(Test{
+		format:        models.ExportFormatNDJSON,
+		games:         games[:1],
+		repoErr:       errors.New("database is gone"),
+		expectedError: "database is gone",
+	}))
+
+	t.Run("rejects an unknown format", run(Test{
+		format:        "xml",
+		games:         games,
+		expectedError: `unknown export format "xml"`,
+	}))
+}
+
+// failingWriter fails every write, like the connection of a client that went away
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestExportService_ExportGamesStopsOnWriteError(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	games := make([]*models.GameExport, 10000)
+	for i := range games {
+		games[i] = &models.GameExport{ID: uuid.New(), Mode: models.GameModeMultipleChoice, QuoteIDs: []int{1, 2, 3}, CreatedAt: time.Now()}
+	}
+	mockedExportRepo := new(MockedExportRepo)
+	mockedExportRepo.On("ExportQuoteGames", time.Time{}, time.Time{}).Return(games, nil)
+	service := NewExportService(&logger, mockedExportRepo)
+
+	for _, format := range []models.ExportFormat{models.ExportFormatCSV, models.ExportFormatNDJSON} {
+		err := service.ExportGames(context.TODO(), failingWriter{}, format, time.Time{}, time.Time{})
+		require.ErrorContains(t, err, "connection reset", format)
+	}
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+)
+
+// blankPlaceholder replaces the key word of a quote in GameModeFillInTheBlank
+const blankPlaceholder = "_____"
+
+// blankWordPattern matches a single word of a quote. Apostrophes and hyphens within a word, like in "don't", are part of it
+var blankWordPattern = regexp.MustCompile(`[\p{L}\p{N}]+(?:['’-][\p{L}\p{N}]+)*`)
+
+// blankStopWords are words that are too common to be the key word of a quote
+var blankStopWords = map[string]bool{
+	"about": true, "after": true, "again": true, "because": true, "before": true, "being": true, "could": true,
+	"every": true, "other": true, "should": true, "their": true, "there": true, "these": true, "those": true,
+	"through": true, "which": true, "while": true, "would": true, "yourself": true, "yourselves": true,
+}
+
+// fillInTheBlankEngine removes the key word of every quote, which the player has to fill in. Answers are matched loosely,
+// see blankAnswerMatches. The removed words are stored with the game, so they stay the same when a quote is edited during the game.
+type fillInTheBlankEngine struct{}
+
+func (fillInTheBlankEngine) QuoteCount() int {
+	return quoteGameSize
+}
+
+func (fillInTheBlankEngine) NewGame(id uuid.UUID, quotes []*models.Quote) *models.QuoteGame {
+	game := &models.QuoteGame{
+		ID:      id,
+		Mode:    models.GameModeFillInTheBlank,
+		Quotes:  make([]*models.QuoteWithoutAuthor, len(quotes)),
+		Authors: []string{},
+		Keys:    make(map[int]*models.QuoteKey, len(quotes)),
+	}
+	for i, q := range quotes {
+		blanked, word := blankQuote(q.Quote)
+		game.Quotes[i] = &models.QuoteWithoutAuthor{
+			ID:    q.ID,
+			Quote: blanked,
+		}
+		game.Keys[q.ID] = &models.QuoteKey{Author: q.Author, Blank: word}
+	}
+
+	slices.SortFunc(game.Quotes, func(a *models.QuoteWithoutAuthor, b *models.QuoteWithoutAuthor) int {
+		return strings.Compare(a.Quote, b.Quote)
+	})
+	return game
+}
+
+// Result checks the answers against the stored words. A game without them falls back to the words of the quotes as they are now
+func (fillInTheBlankEngine) Result(id uuid.UUID, quoteIDs []int, quotes map[int]*models.Quote, keys map[int]*models.QuoteKey, answers models.QuoteGameAnswerMap) *models.QuoteGameResult {
+	result := &models.QuoteGameResult{
+		ID:      id,
+		Answers: make([]*models.QuoteGameActualAnswer, len(quoteIDs)),
+	}
+	for i, id := range quoteIDs {
+		quote := quotes[id]
+		_, expected := blankQuote(quote.Quote)
+		if key, ok := keys[id]; ok && key.Blank != "" {
+			expected = key.Blank
+		}
+		result.Answers[i] = &models.QuoteGameActualAnswer{
+			Quote:   *quote,
+			Correct: blankAnswerMatches(answers[id], expected),
+			Blank:   &models.BlankAnswer{Given: answers[id], Expected: expected},
+		}
+	}
+	return result
+}
+
+// blankQuote replaces the key word of the quote with blankPlaceholder and returns the result together with the removed word.
+// The key word is the longest word that is not a stop word. When there are several, the first one is used.
+// Every occurrence of the key word is replaced, regardless of its case, otherwise the quote would give the answer away.
+func blankQuote(quote string) (blanked string, word string) {
+	words := blankWordPattern.FindAllStringIndex(quote, -1)
+	var key []int
+	keyLength := 0
+	for _, loc := range words {
+		candidate := quote[loc[0]:loc[1]]
+		length := utf8.RuneCountInString(candidate)
+		if blankStopWords[strings.ToLower(candidate)] {
+			// A stop word is only used when the quote has nothing else
+			length = 0
+		}
+		if key == nil || length > keyLength {
+			key = loc
+			keyLength = length
+		}
+	}
+	if key == nil {
+		return quote, ""
+	}
+
+	word = quote[key[0]:key[1]]
+	var sb strings.Builder
+	last := 0
+	for _, loc := range words {
+		if strings.EqualFold(quote[loc[0]:loc[1]], word) {
+			sb.WriteString(quote[last:loc[0]])
+			sb.WriteString(blankPlaceholder)
+			last = loc[1]
+		}
+	}
+	sb.WriteString(quote[last:])
+	return sb.String(), word
+}
+
+// blankAnswerMatches compares the answer of a player to the removed word. Case and punctuation are ignored
+// and, depending on the length of the word, a typo or two are allowed.
+func blankAnswerMatches(given, expected string) bool {
+	given = normalizeBlankAnswer(given)
+	expected = normalizeBlankAnswer(expected)
+	if given == "" {
+		return false
+	}
+
+	allowedTypos := 0
+	switch length := utf8.RuneCountInString(expected); {
+	case length > 7:
+		allowedTypos = 2
+	case length > 3:
+		allowedTypos = 1
+	}
+	return levenshtein(given, expected) <= allowedTypos
+}
+
+// normalizeBlankAnswer lowercases the answer and removes everything that is not a letter, digit or space
+func normalizeBlankAnswer(answer string) string {
+	answer = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		case unicode.IsSpace(r):
+			return ' '
+		default:
+			return -1
+		}
+	}, answer)
+	return strings.Join(strings.Fields(answer), " ")
+}
+
+// levenshtein returns the edit distance between a and b: the number of runes that have to be inserted, deleted or substituted to turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// We only keep the previous and the current row of the matrix
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlankQuote(t *testing.T) {
+	type Test struct {
+		quote           string
+		expectedBlanked string
+		expectedWord    string
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			blanked, word := blankQuote(tt.quote)
+			assert.Equal(t, tt.expectedBlanked, blanked)
+			assert.Equal(t, tt.expectedWord, word)
+		}
+	}
+
+	t.Run("removes the longest word and keeps the punctuation", run(Test{
+		quote:           "Talk is cheap. Show me the code.",
+		expectedBlanked: "Talk is _____. Show me the code.",
+		expectedWord:    "cheap",
+	}))
+
+	t.Run("removes the first of the longest words", run(Test{
+		quote:           "The cure for pain is in the pain.",
+		expectedBlanked: "The _____ for pain is in the pain.",
+		expectedWord:    "cure",
+	}))
+
+	t.Run("removes every occurrence of a repeated word", run(Test{
+		quote:           "Imagination rules. Without imagination, nothing.",
+		expectedBlanked: "_____ rules. Without _____, nothing.",
+		expectedWord:    "Imagination",
+	}))
+
+	t.Run("only removes whole words", run(Test{
+		quote:           "Learn to earn, then learn more.",
+		expectedBlanked: "_____ to earn, then _____ more.",
+		expectedWord:    "Learn",
+	}))
+
+	t.Run("skips stop words", run(Test{
+		quote:           "Because life matters.",
+		expectedBlanked: "Because life _____.",
+		expectedWord:    "matters",
+	}))
+
+	t.Run("keeps apostrophes within a word", run(Test{
+		quote:           "Don't panic, wouldn't.",
+		expectedBlanked: "Don't panic, _____.",
+		expectedWord:    "wouldn't",
+	}))
+
+	t.Run("uses a stop word when there is nothing else", run(Test{
+		quote:           "Because!",
+		expectedBlanked: "_____!",
+		expectedWord:    "Because",
+	}))
+
+	t.Run("leaves a quote without words alone", run(Test{
+		quote:           "...",
+		expectedBlanked: "...",
+	}))
+}
+
+func TestBlankAnswerMatches(t *testing.T) {
+	type Test struct {
+		given    string
+		expected string
+		matches  bool
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+			assert.Equal(t, tt.matches, blankAnswerMatches(tt.given, tt.expected))
+		}
+	}
+
+	t.Run("matches the exact word", run(Test{given: "cheap", expected: "cheap", matches: true}))
+	t.Run("ignores case and punctuation", run(Test{given: " Dont! ", expected: "don't", matches: true}))
+	t.Run("allows a typo in a medium word", run(Test{given: "cheep", expected: "cheap", matches: true}))
+	t.Run("counts swapped letters as two typos", run(Test{given: "chaep", expected: "cheap", matches: false}))
+	t.Run("allows two typos in a long word", run(Test{given: "imagnaton", expected: "Imagination", matches: true}))
+	t.Run("rejects three typos in a long word", run(Test{given: "imagnaton", expected: "Imaginations", matches: false}))
+	t.Run("requires a short word to be exact", run(Test{given: "cat", expected: "car", matches: false}))
+	t.Run("rejects an empty answer", run(Test{given: "?!", expected: "a", matches: false}))
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("", ""))
+	assert.Equal(t, 3, levenshtein("", "abc"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 1, levenshtein("café", "cafe"))
+}
+
+func TestFillInTheBlankEngine(t *testing.T) {
+	quotes := []*models.Quote{
+		{ID: 1, Quote: "Talk is cheap.", Author: "Linus Torvalds"},
+		{ID: 2, Quote: "Imagination is more important than knowledge.", Author: "Albert Einstein"},
+		{ID: 3, Quote: "Stay hungry.", Author: "Steve Jobs"},
+	}
+	engine := fillInTheBlankEngine{}
+	require.Equal(t, quoteGameSize, engine.QuoteCount())
+
+	id := uuid.New()
+	game := engine.NewGame(id, quotes)
+	assert.Equal(t, &models.QuoteGame{
+		ID:   id,
+		Mode: models.GameModeFillInTheBlank,
+		Quotes: []*models.QuoteWithoutAuthor{
+			{ID: 3, Quote: "Stay _____."},
+			{ID: 1, Quote: "Talk is _____."},
+			{ID: 2, Quote: "_____ is more important than knowledge."},
+		},
+		Authors: []string{},
+		Keys: map[int]*models.QuoteKey{
+			1: {Author: "Linus Torvalds", Blank: "cheap"},
+			2: {Author: "Albert Einstein", Blank: "Imagination"},
+			3: {Author: "Steve Jobs", Blank: "hungry"},
+		},
+	}, game)
+
+	result := engine.Result(id, []int{3, 1, 2}, map[int]*models.Quote{1: quotes[0], 2: quotes[1], 3: quotes[2]}, game.Keys, models.QuoteGameAnswerMap{
+		1: "cheep",
+		2: "Imagination",
+		3: "angry",
+	})
+	assert.Equal(t, &models.QuoteGameResult{
+		ID: id,
+		Answers: []*models.QuoteGameActualAnswer{
+			{Quote: *quotes[2], Correct: false, Blank: &models.BlankAnswer{Given: "angry", Expected: "hungry"}},
+			{Quote: *quotes[0], Correct: true, Blank: &models.BlankAnswer{Given: "cheep", Expected: "cheap"}},
+			{Quote: *quotes[1], Correct: true, Blank: &models.BlankAnswer{Given: "Imagination", Expected: "Imagination"}},
+		},
+	}, result)
+
+	// A quote that is edited during the game is checked against the word that was removed when the game was created
+	edited := &models.Quote{ID: 1, Quote: "Talk is inexpensive.", Author: "Linus Torvalds"}
+	result = engine.Result(id, []int{1}, map[int]*models.Quote{1: edited}, game.Keys, models.QuoteGameAnswerMap{1: "cheap"})
+	assert.Equal(t, &models.BlankAnswer{Given: "cheap", Expected: "cheap"}, result.Answers[0].Blank)
+	assert.True(t, result.Answers[0].Correct)
+
+	// A game without stored words uses the quote as it is now
+	result = engine.Result(id, []int{1}, map[int]*models.Quote{1: edited}, nil, models.QuoteGameAnswerMap{1: "cheap"})
+	assert.Equal(t, &models.BlankAnswer{Given: "cheap", Expected: "inexpensive"}, result.Answers[0].Blank)
+	assert.False(t, result.Answers[0].Correct)
+}
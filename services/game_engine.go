@@ -0,0 +1,104 @@
+package services
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+)
+
+// multipleChoiceOptions is the number of candidate authors shown with every quote in GameModeMultipleChoice
+const multipleChoiceOptions = 4
+
+// gameEngine implements the rules of a game mode. The QuoteService draws the quotes and stores the games,
+// the engine decides what the player is shown and how the answers are scored.
+type gameEngine interface {
+	// QuoteCount returns the number of quotes by distinct authors that a new game is built from
+	QuoteCount() int
+	// NewGame builds a new game from QuoteCount quotes by distinct authors. The game asks for quoteGameSize of them
+	NewGame(id uuid.UUID, quotes []*models.Quote) *models.QuoteGame
+	// Result scores the answers to the quotes of a game. The keys are the ones NewGame stored with the game, which can be missing
+	// for games created before they were stored. The answers of the result are in the order of quoteIDs
+	Result(id uuid.UUID, quoteIDs []int, quotes map[int]*models.Quote, keys map[int]*models.QuoteKey, answers models.QuoteGameAnswerMap) *models.QuoteGameResult
+}
+
+// newGameEngines returns the engines of all game modes
+func newGameEngines() map[models.GameMode]gameEngine {
+	return map[models.GameMode]gameEngine{
+		models.GameModeMatch:          matchEngine{},
+		models.GameModeMultipleChoice: multipleChoiceEngine{options: multipleChoiceOptions},
+		models.GameModeFillInTheBlank: fillInTheBlankEngine{},
+	}
+}
+
+// matchEngine shows the quotes and their authors separately, and the player has to match them
+type matchEngine struct{}
+
+func (matchEngine) QuoteCount() int {
+	return quoteGameSize
+}
+
+func (matchEngine) NewGame(id uuid.UUID, quotes []*models.Quote) *models.QuoteGame {
+	return models.NewQuoteGame(id, quotes)
+}
+
+func (matchEngine) Result(id uuid.UUID, quoteIDs []int, quotes map[int]*models.Quote, _ map[int]*models.QuoteKey, answers models.QuoteGameAnswerMap) *models.QuoteGameResult {
+	return models.NewQuoteGameResult(id, quoteIDs, quotes, answers)
+}
+
+// multipleChoiceEngine shows every quote with a number of candidate authors, of which one is right.
+// The other candidates are the authors of the other quotes the game is built from, so they are real authors from the catalogue.
+type multipleChoiceEngine struct {
+	options int
+}
+
+func (engine multipleChoiceEngine) QuoteCount() int {
+	// The authors of the quotes that are not asked only serve as distractors
+	return quoteGameSize + engine.options - 1
+}
+
+func (engine multipleChoiceEngine) NewGame(id uuid.UUID, quotes []*models.Quote) *models.QuoteGame {
+	asked := min(quoteGameSize, len(quotes))
+	game := &models.QuoteGame{
+		ID:      id,
+		Mode:    models.GameModeMultipleChoice,
+		Quotes:  make([]*models.QuoteWithoutAuthor, asked),
+		Authors: []string{},
+		Keys:    make(map[int]*models.QuoteKey, asked),
+	}
+
+	for i, q := range quotes[:asked] {
+		// The distractors are drawn from the authors of all other quotes, which are all distinct
+		distractors := make([]string, 0, len(quotes)-1)
+		for _, other := range quotes {
+			if other.ID != q.ID {
+				distractors = append(distractors, other.Author)
+			}
+		}
+		rand.Shuffle(len(distractors), func(i, j int) {
+			distractors[i], distractors[j] = distractors[j], distractors[i]
+		})
+
+		// The choices are sorted, so the position doesn't give away the right author
+		choices := append(distractors[:min(engine.options-1, len(distractors))], q.Author)
+		slices.Sort(choices)
+		game.Quotes[i] = &models.QuoteWithoutAuthor{
+			ID:      q.ID,
+			Quote:   q.Quote,
+			Choices: choices,
+		}
+		game.Keys[q.ID] = &models.QuoteKey{Author: q.Author, Choices: choices}
+	}
+
+	slices.SortFunc(game.Quotes, func(a *models.QuoteWithoutAuthor, b *models.QuoteWithoutAuthor) int {
+		return strings.Compare(a.Quote, b.Quote)
+	})
+	return game
+}
+
+// Result scores every question on its own. An author that was not one of the choices is simply wrong
+func (multipleChoiceEngine) Result(id uuid.UUID, quoteIDs []int, quotes map[int]*models.Quote, _ map[int]*models.QuoteKey, answers models.QuoteGameAnswerMap) *models.QuoteGameResult {
+	return models.NewQuoteGameResult(id, quoteIDs, quotes, answers)
+}
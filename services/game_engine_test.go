@@ -0,0 +1,62 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultipleChoiceEngine_NewGame(t *testing.T) {
+	quotes := []*models.Quote{
+		{ID: 1, Quote: "c", Author: "Anne"},
+		{ID: 2, Quote: "a", Author: "Bob"},
+		{ID: 3, Quote: "b", Author: "Carl"},
+		{ID: 4, Quote: "d", Author: "Dirk"},
+		{ID: 5, Quote: "e", Author: "Eve"},
+		{ID: 6, Quote: "f", Author: "Fred"},
+	}
+	engine := multipleChoiceEngine{options: multipleChoiceOptions}
+	require.Equal(t, len(quotes), engine.QuoteCount())
+
+	id := uuid.New()
+	game := engine.NewGame(id, quotes)
+	assert.Equal(t, id, game.ID)
+	assert.Equal(t, models.GameModeMultipleChoice, game.Mode)
+	assert.Empty(t, game.Authors)
+
+	// Only the first quotes are asked, sorted by their text
+	require.Len(t, game.Quotes, quoteGameSize)
+	assert.Equal(t, []int{2, 3, 1}, []int{game.Quotes[0].ID, game.Quotes[1].ID, game.Quotes[2].ID})
+
+	authors := map[int]string{}
+	for _, q := range quotes {
+		authors[q.ID] = q.Author
+	}
+	for _, q := range game.Quotes {
+		// Every quote has sorted, distinct choices, of which one is the right author
+		assert.Len(t, q.Choices, multipleChoiceOptions)
+		assert.True(t, slices.IsSorted(q.Choices))
+		assert.Len(t, slices.Compact(slices.Clone(q.Choices)), multipleChoiceOptions)
+		assert.Contains(t, q.Choices, authors[q.ID])
+		// The choices are stored with the game, so the confusions between authors only count the authors the player could choose
+		assert.Equal(t, &models.QuoteKey{Author: authors[q.ID], Choices: q.Choices}, game.Keys[q.ID])
+	}
+	assert.Len(t, game.Keys, quoteGameSize)
+}
+
+func TestMatchEngine_NewGame(t *testing.T) {
+	quotes := []*models.Quote{
+		{ID: 1, Quote: "c", Author: "Anne"},
+		{ID: 2, Quote: "a", Author: "Bob"},
+		{ID: 3, Quote: "b", Author: "Carl"},
+	}
+	engine := matchEngine{}
+	require.Equal(t, len(quotes), engine.QuoteCount())
+
+	id := uuid.New()
+	assert.Equal(t, models.NewQuoteGame(id, quotes), engine.NewGame(id, quotes))
+}
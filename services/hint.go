@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+)
+
+// RequestHint gives the player of a game in GameModeMatch a hint of the given type and records it on the game, so its cost is deducted
+// from the score when the game is answered. When quoteID is 0, the hint is about a random quote that can still get a hint of this type.
+//
+// A quote that is revealed gets no more hints, and a quote gets only one author eliminated, as a second one would reveal the quote as well.
+func (service *QuoteService) RequestHint(ctx context.Context, id uuid.UUID, hintType models.HintType, quoteID int) (*models.Hint, error) {
+	cost, ok := models.HintCosts[hintType]
+	if !ok {
+		return nil, models.ErrInvalidHintType
+	}
+
+	mode, quoteIDs, err := service.quoteGameRepo.GetOpenQuoteGame(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if mode != models.GameModeMatch {
+		return nil, models.ErrWrongGameMode
+	}
+	if quoteID != 0 && !slices.Contains(quoteIDs, quoteID) {
+		return nil, models.ErrInvalidQuoteID
+	}
+
+	used, err := service.quoteGameRepo.GetQuoteGameHints(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	candidates := []int{}
+	for _, candidate := range quoteIDs {
+		if (quoteID == 0 || candidate == quoteID) && hintAvailable(used, candidate, hintType) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, models.ErrNoHintAvailable
+	}
+
+	quotes, err := service.GetQuotes(ctx, quoteIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	hint := &models.Hint{
+		Type:    hintType,
+		QuoteID: candidates[rand.IntN(len(candidates))],
+		Cost:    cost,
+	}
+	switch hintType {
+	case models.HintRevealPair:
+		hint.Author = quotes[hint.QuoteID].Author
+	case models.HintEliminateAuthor:
+		// Any author of the other quotes of the game is a wrong author
+		wrongAuthors := []string{}
+		for _, other := range quoteIDs {
+			if other != hint.QuoteID {
+				wrongAuthors = append(wrongAuthors, quotes[other].Author)
+			}
+		}
+		hint.Author = wrongAuthors[rand.IntN(len(wrongAuthors))]
+	}
+
+	err = service.quoteGameRepo.CreateQuoteGameHint(ctx, id, hint)
+	if err != nil {
+		return nil, err
+	}
+	return hint, nil
+}
+
+// hintAvailable returns whether the quote can still get a hint of the given type. A revealed quote gets no hints at all
+func hintAvailable(used []*models.Hint, quoteID int, hintType models.HintType) bool {
+	for _, h := range used {
+		if h.QuoteID == quoteID && (h.Type == hintType || h.Type == models.HintRevealPair) {
+			return false
+		}
+	}
+	return true
+}
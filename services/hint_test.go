@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"maps"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteService_RequestHint(t *testing.T) {
+	id := uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e")
+	quotes := map[int]*models.Quote{
+		54: {ID: 54, Author: "George", Quote: "Hello!"},
+		43: {ID: 43, Author: "William", Quote: "Hi!"},
+		2:  {ID: 2, Author: "Bob", Quote: "Bye!"},
+	}
+
+	type Test struct {
+		hintType         models.HintType
+		quoteID          int
+		mockedMode       models.GameMode
+		mockedGameError  error
+		mockedHints      []*models.Hint
+		mockedHintError  error
+		expectedQuoteIDs []int
+		expectedAuthors  []string
+		expectedCost     int
+		expectedError    error
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mode := tt.mockedMode
+			if mode == "" {
+				mode = models.GameModeMatch
+			}
+			mockedQuoteGameRepo := new(MockedQuoteGameRepo)
+			mockedQuoteGameRepo.On("GetOpenQuoteGame", id).Return(mode, []int{54, 43, 2}, tt.mockedGameError)
+			mockedQuoteGameRepo.On("GetQuoteGameHints", id).Return(tt.mockedHints, nil)
+			mockedQuoteGameRepo.On("CreateQuoteGameHint", id, mock.Anything).Return(tt.mockedHintError)
+
+			mockedQuoteRepo := new(MockedQuoteRepo)
+			mockedQuoteRepo.On("GetQuotes", []int{54, 43, 2}).Return(maps.Clone(quotes), nil)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			res, err := NewQuoteService(&logger, nil, mockedQuoteGameRepo, mockedQuoteRepo, 10, nil).RequestHint(context.TODO(), id, tt.hintType, tt.quoteID)
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, res)
+				return
+			}
+			require.NoError(t, err)
+			// The quote and author are chosen at random from the candidates
+			assert.Equal(t, tt.hintType, res.Type)
+			assert.Contains(t, tt.expectedQuoteIDs, res.QuoteID)
+			assert.Contains(t, tt.expectedAuthors, res.Author)
+			assert.Equal(t, tt.expectedCost, res.Cost)
+			if res.Type == models.HintRevealPair {
+				assert.Equal(t, quotes[res.QuoteID].Author, res.Author)
+			} else {
+				assert.NotEqual(t, quotes[res.QuoteID].Author, res.Author)
+			}
+			mockedQuoteGameRepo.AssertCalled(t, "CreateQuoteGameHint", id, res)
+		}
+	}
+
+	t.Run("reveals the author of the given quote", run(Test{
+		hintType:         models.HintRevealPair,
+		quoteID:          43,
+		expectedQuoteIDs: []int{43},
+		expectedAuthors:  []string{"William"},
+		expectedCost:     2,
+	}))
+
+	t.Run("eliminates another author of the game", run(Test{
+		hintType:         models.HintEliminateAuthor,
+		quoteID:          43,
+		expectedQuoteIDs: []int{43},
+		expectedAuthors:  []string{"George", "Bob"},
+		expectedCost:     1,
+	}))
+
+	t.Run("chooses a quote that didn't get a hint of the type yet", run(Test{
+		hintType: models.HintEliminateAuthor,
+		mockedHints: []*models.Hint{
+			{Type: models.HintEliminateAuthor, QuoteID: 54, Author: "Bob", Cost: 1},
+			{Type: models.HintRevealPair, QuoteID: 2, Author: "Bob", Cost: 2},
+		},
+		expectedQuoteIDs: []int{43},
+		expectedAuthors:  []string{"George", "Bob"},
+		expectedCost:     1,
+	}))
+
+	t.Run("gives no hints about a revealed quote", run(Test{
+		hintType:      models.HintEliminateAuthor,
+		quoteID:       2,
+		mockedHints:   []*models.Hint{{Type: models.HintRevealPair, QuoteID: 2, Author: "Bob", Cost: 2}},
+		expectedError: models.ErrNoHintAvailable,
+	}))
+
+	t.Run("returns an error when every quote is revealed", run(Test{
+		hintType: models.HintRevealPair,
+		mockedHints: []*models.Hint{
+			{Type: models.HintRevealPair, QuoteID: 54, Author: "George", Cost: 2},
+			{Type: models.HintRevealPair, QuoteID: 43, Author: "William", Cost: 2},
+			{Type: models.HintRevealPair, QuoteID: 2, Author: "Bob", Cost: 2},
+		},
+		expectedError: models.ErrNoHintAvailable,
+	}))
+
+	t.Run("returns an error when the hint is requested twice at the same time", run(Test{
+		hintType:        models.HintRevealPair,
+		mockedHintError: models.ErrNoHintAvailable,
+		expectedError:   models.ErrNoHintAvailable,
+	}))
+
+	t.Run("rejects a quote that is not part of the game", run(Test{
+		hintType:      models.HintRevealPair,
+		quoteID:       99,
+		expectedError: models.ErrInvalidQuoteID,
+	}))
+
+	t.Run("rejects an unknown hint type", run(Test{
+		hintType:      "free_points",
+		expectedError: models.ErrInvalidHintType,
+	}))
+
+	t.Run("rejects a game in another mode", run(Test{
+		hintType:      models.HintRevealPair,
+		mockedMode:    models.GameModeMultipleChoice,
+		expectedError: models.ErrWrongGameMode,
+	}))
+
+	t.Run("passes through that the game is not found", run(Test{
+		hintType:        models.HintRevealPair,
+		mockedGameError: models.ErrQuoteGameIdNotFound,
+		expectedError:   models.ErrQuoteGameIdNotFound,
+	}))
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+)
+
+// idempotencyLockTimeout is how long a key stays claimed by a request without a response. When the request never finishes,
+// for example because the server stopped, the key can be used again after this time
+const idempotencyLockTimeout = time.Minute
+
+// idempotencyCleanupInterval is how often expired keys are removed, so the table doesn't grow with every key ever used
+const idempotencyCleanupInterval = time.Hour
+
+type IdempotencyService struct {
+	logger          *zerolog.Logger
+	idempotencyRepo idempotencyRepo
+	// ttl is how long the response of a request is replayed for retries
+	ttl time.Duration
+
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+// NewIdempotencyService returns a new IdempotencyService. The responses of requests are replayed for the duration of the ttl
+func NewIdempotencyService(logger *zerolog.Logger, idempotencyRepo idempotencyRepo, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		logger:          logger,
+		idempotencyRepo: idempotencyRepo,
+		ttl:             ttl,
+		lastCleanup:     time.Now(),
+	}
+}
+
+// Begin claims the idempotency key within the scope for the request with the given fingerprint. When the key is new, nil is returned
+// and the request should be handled, followed by Complete or Release. When the key was used for the same request before, its response is returned.
+//
+// ErrIdempotencyKeyReused is returned when the key was used for another request, and ErrIdempotencyKeyInProgress when the first request is still being handled.
+func (service *IdempotencyService) Begin(ctx context.Context, scope, key, fingerprint string) (*models.IdempotentResponse, error) {
+	service.cleanup(ctx)
+
+	err := service.idempotencyRepo.CreateIdempotencyKey(ctx, scope, key, fingerprint, time.Now().Add(idempotencyLockTimeout))
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, models.ErrIdempotencyKeyExists) {
+		return nil, err
+	}
+
+	stored, err := service.idempotencyRepo.GetIdempotencyKey(ctx, scope, key)
+	if errors.Is(err, models.ErrIdempotencyKeyNotFound) {
+		// The key expired right after we tried to claim it, a retry will claim it
+		return nil, models.ErrIdempotencyKeyInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stored.Fingerprint != fingerprint {
+		return nil, models.ErrIdempotencyKeyReused
+	}
+	if stored.Response == nil {
+		return nil, models.ErrIdempotencyKeyInProgress
+	}
+	return stored.Response, nil
+}
+
+// Complete stores the response of the request the key was claimed for with Begin, so it's replayed for retries during the ttl
+func (service *IdempotencyService) Complete(ctx context.Context, scope, key string, response *models.IdempotentResponse) error {
+	return service.idempotencyRepo.SaveIdempotentResponse(ctx, scope, key, response, time.Now().Add(service.ttl))
+}
+
+// Release gives up the key claimed with Begin without storing a response, so a retry is handled like a new request.
+// This is used when the request failed in a way that a retry might fix.
+func (service *IdempotencyService) Release(ctx context.Context, scope, key string) error {
+	return service.idempotencyRepo.DeleteIdempotencyKey(ctx, scope, key)
+}
+
+// cleanup removes the expired keys, at most once per idempotencyCleanupInterval. Expired keys are ignored anyway,
+// so a failed cleanup is only logged
+func (service *IdempotencyService) cleanup(ctx context.Context) {
+	service.mu.Lock()
+	if time.Since(service.lastCleanup) < idempotencyCleanupInterval {
+		service.mu.Unlock()
+		return
+	}
+	service.lastCleanup = time.Now()
+	service.mu.Unlock()
+
+	deleted, err := service.idempotencyRepo.DeleteExpiredIdempotencyKeys(ctx)
+	if err != nil {
+		service.logger.Error().Err(err).Msg("could not remove expired idempotency keys")
+		return
+	}
+	service.logger.Debug().Int64("deleted", deleted).Msg("removed expired idempotency keys")
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	errDatabase := errors.New("database is gone")
+	response := &models.IdempotentResponse{StatusCode: 200, ContentType: "application/json", Body: []byte(`{}`)}
+
+	type Test struct {
+		mockedCreateError error
+		mockedKey         *models.IdempotencyKey
+		mockedGetError    error
+		expectedResponse  *models.IdempotentResponse
+		expectedError     error
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedIdempotencyRepo := new(MockedIdempotencyRepo)
+			mockedIdempotencyRepo.On("CreateIdempotencyKey", "caller:player-42", "key-1", "a-fingerprint", mock.Anything).Return(tt.mockedCreateError)
+			mockedIdempotencyRepo.On("GetIdempotencyKey", "caller:player-42", "key-1").Return(tt.mockedKey, tt.mockedGetError)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			res, err := NewIdempotencyService(&logger, mockedIdempotencyRepo, time.Hour).Begin(context.TODO(), "caller:player-42", "key-1", "a-fingerprint")
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, res)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResponse, res)
+		}
+	}
+
+	t.Run("claims a new key", run(Test{}))
+
+	t.Run("returns the response of the same request", run(Test{
+		mockedCreateError: models.ErrIdempotencyKeyExists,
+		mockedKey:         &models.IdempotencyKey{Fingerprint: "a-fingerprint", Response: response},
+		expectedResponse:  response,
+	}))
+
+	t.Run("rejects a key used for another request", run(Test{
+		mockedCreateError: models.ErrIdempotencyKeyExists,
+		mockedKey:         &models.IdempotencyKey{Fingerprint: "another-fingerprint", Response: response},
+		expectedError:     models.ErrIdempotencyKeyReused,
+	}))
+
+	t.Run("rejects a key used for another request that is still being handled", run(Test{
+		mockedCreateError: models.ErrIdempotencyKeyExists,
+		mockedKey:         &models.IdempotencyKey{Fingerprint: "another-fingerprint"},
+		expectedError:     models.ErrIdempotencyKeyReused,
+	}))
+
+	t.Run("rejects a key of a request that is still being handled", run(Test{
+		mockedCreateError: models.ErrIdempotencyKeyExists,
+		mockedKey:         &models.IdempotencyKey{Fingerprint: "a-fingerprint"},
+		expectedError:     models.ErrIdempotencyKeyInProgress,
+	}))
+
+	t.Run("asks for a retry when the key expired in the meantime", run(Test{
+		mockedCreateError: models.ErrIdempotencyKeyExists,
+		mockedKey:         (*models.IdempotencyKey)(nil),
+		mockedGetError:    models.ErrIdempotencyKeyNotFound,
+		expectedError:     models.ErrIdempotencyKeyInProgress,
+	}))
+
+	t.Run("passes through errors of the repository", run(Test{
+		mockedCreateError: errDatabase,
+		expectedError:     errDatabase,
+	}))
+}
+
+func TestIdempotencyService_Complete(t *testing.T) {
+	response := &models.IdempotentResponse{StatusCode: 200, ContentType: "application/json", Body: []byte(`{}`)}
+	mockedIdempotencyRepo := new(MockedIdempotencyRepo)
+	mockedIdempotencyRepo.On("SaveIdempotentResponse", "anonymous", "key-1", response, mock.Anything).Return(nil)
+
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	err := NewIdempotencyService(&logger, mockedIdempotencyRepo, time.Hour).Complete(context.TODO(), "anonymous", "key-1", response)
+	require.NoError(t, err)
+
+	// The response is kept for the ttl
+	expiresAt := mockedIdempotencyRepo.Calls[0].Arguments.Get(3).(time.Time)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+}
+
+func TestIdempotencyService_Cleanup(t *testing.T) {
+	mockedIdempotencyRepo := new(MockedIdempotencyRepo)
+	mockedIdempotencyRepo.On("CreateIdempotencyKey", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockedIdempotencyRepo.On("DeleteExpiredIdempotencyKeys").Return(int64(3), nil)
+
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	service := NewIdempotencyService(&logger, mockedIdempotencyRepo, time.Hour)
+
+	// Right after the start, there is nothing to clean up yet
+	_, err := service.Begin(context.TODO(), "anonymous", "key-1", "a-fingerprint")
+	require.NoError(t, err)
+	mockedIdempotencyRepo.AssertNotCalled(t, "DeleteExpiredIdempotencyKeys")
+
+	service.lastCleanup = time.Now().Add(-idempotencyCleanupInterval)
+	_, err = service.Begin(context.TODO(), "anonymous", "key-2", "a-fingerprint")
+	require.NoError(t, err)
+	_, err = service.Begin(context.TODO(), "anonymous", "key-3", "a-fingerprint")
+	require.NoError(t, err)
+	mockedIdempotencyRepo.AssertNumberOfCalls(t, "DeleteExpiredIdempotencyKeys", 1)
+}
@@ -3,59 +3,350 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pietdevries94/Kabisa/models"
 	"github.com/rs/zerolog"
 )
 
+const (
+	// quoteGameSize is the number of quotes (and thus authors) in a single quote game
+	quoteGameSize = 3
+	// quoteSampleSize is the number of random quotes requested at once when looking for distinct authors. This is the maximum dummyjson allows
+	quoteSampleSize = 10
+	// quoteDrawAttempts is the number of samples we draw before giving up on finding enough distinct authors
+	quoteDrawAttempts = 5
+)
+
 type QuoteService struct {
 	logger        *zerolog.Logger
 	dummyJsonRepo dummyJsonRepo
 	quoteGameRepo quoteGameRepo
+	quoteRepo     quoteRepo
+	// recentGamesExcluded is the number of recent games of a player of which the quotes are avoided in new games
+	recentGamesExcluded int
+	publisher           publisher
+	engines             map[models.GameMode]gameEngine
 }
 
-func NewQuoteService(logger *zerolog.Logger, dummyJsonRepo dummyJsonRepo, quoteGameRepo quoteGameRepo) *QuoteService {
+func NewQuoteService(logger *zerolog.Logger, dummyJsonRepo dummyJsonRepo, quoteGameRepo quoteGameRepo, quoteRepo quoteRepo, recentGamesExcluded int, publisher publisher) *QuoteService {
 	return &QuoteService{
-		logger:        logger,
-		dummyJsonRepo: dummyJsonRepo,
-		quoteGameRepo: quoteGameRepo,
+		logger:              logger,
+		dummyJsonRepo:       dummyJsonRepo,
+		quoteGameRepo:       quoteGameRepo,
+		quoteRepo:           quoteRepo,
+		recentGamesExcluded: recentGamesExcluded,
+		publisher:           publisher,
+		engines:             newGameEngines(),
 	}
 }
 
-// GetRandomQuote returns a single ransom quote
-func (service *QuoteService) GetRandomQuote(ctx context.Context) (*models.Quote, error) {
-	res, err := service.dummyJsonRepo.GetRandomQuotes(ctx, 1)
+// GetRandomQuote returns a single ransom quote, which is never a quote blocked by an admin. The quote is in the given language
+// when the catalogue has quotes in it, otherwise it's in English.
+func (service *QuoteService) GetRandomQuote(ctx context.Context, language models.Language) (*models.Quote, error) {
+	blockedQuoteIDs, err := service.getBlockedQuoteIDs(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if len(res) == 0 {
-		return nil, errors.New("dummyJsonRepo returned no quotes and no error")
+
+	// When dummyjson is used directly, the drawn quote can be blocked, so we draw again
+	for range quoteDrawAttempts {
+		res, err := service.drawRandomQuotes(ctx, 1, blockedQuoteIDs, language)
+		if err != nil {
+			return nil, err
+		}
+		if len(res) > 0 {
+			return res[0], nil
+		}
+		// The local store never returns blocked quotes, so an empty draw means there are no quotes in the language yet
+		language = models.LanguageEnglish
 	}
-	return res[0], nil
+	return nil, errors.New("quote source returned no quotes that are not blocked")
 }
 
-// CreateQuoteGame gets 3 random quotes, seperates the quotes from the authors, stores the game info and returns them to the user for them to match together
-func (service *QuoteService) CreateQuoteGame(ctx context.Context) (*models.QuoteGame, error) {
-	quotes, err := service.dummyJsonRepo.GetRandomQuotes(ctx, 3)
+// CreateQuoteGame gets random quotes by distinct authors, builds a game in the given mode from them, stores the game info and returns it to the user.
+// In GameModeMatch, the quotes are seperated from the authors for the user to match them together. In GameModeFillInTheBlank, a key word
+// of every quote is removed for the user to fill in.
+// The playerID is optional. When given, quotes the player has seen in their recent games are avoided where possible.
+// The quotes are in the given language. When the catalogue doesn't have enough quotes in it yet, the game is played in English.
+func (service *QuoteService) CreateQuoteGame(ctx context.Context, playerID string, mode models.GameMode, language models.Language) (*models.QuoteGame, error) {
+	game, err := service.DrawQuoteGame(ctx, playerID, mode, language)
 	if err != nil {
 		return nil, err
 	}
-	return service.quoteGameRepo.CreateQuoteGame(ctx, quotes)
+	err = service.quoteGameRepo.CreateQuoteGame(ctx, game, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleDeadlineEvents(service.publisher, models.QuoteGameTopic(game.ID), time.Now().Add(models.QuoteGameDuration))
+	return game, nil
 }
 
-// SubmitAnswerToQuoteGame receives the answers a user has given to a quote game. The function validates if the game exists and the quote ids are correct.
-// After that the quotes will be retrieved and the result of the game determined and stored in the db. The result of the game is returned.
+// DrawQuoteGame builds a new game in the same way as CreateQuoteGame, but doesn't store it. It's used for games that are stored
+// with something else, like the game of a room. The playerID is only used to avoid the quotes of their recent games.
+func (service *QuoteService) DrawQuoteGame(ctx context.Context, playerID string, mode models.GameMode, language models.Language) (*models.QuoteGame, error) {
+	engine, ok := service.engines[mode]
+	if !ok {
+		return nil, models.ErrInvalidGameMode
+	}
+
+	excludedQuoteIDs := map[int]bool{}
+	if playerID != "" && service.recentGamesExcluded > 0 {
+		recentQuoteIDs, err := service.quoteGameRepo.GetRecentQuoteIDs(ctx, playerID, service.recentGamesExcluded)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range recentQuoteIDs {
+			excludedQuoteIDs[id] = true
+		}
+	}
+
+	blockedQuoteIDs, err := service.getBlockedQuoteIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := service.getQuotesWithDistinctAuthors(ctx, engine.QuoteCount(), excludedQuoteIDs, blockedQuoteIDs, language)
+	if err == models.ErrNotEnoughDistinctAuthors && language != models.LanguageEnglish {
+		quotes, err = service.getQuotesWithDistinctAuthors(ctx, engine.QuoteCount(), excludedQuoteIDs, blockedQuoteIDs, models.LanguageEnglish)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return engine.NewGame(uuid.New(), quotes), nil
+}
+
+// getQuotesWithDistinctAuthors draws samples of random quotes until it has found the given amount of quotes that all have a different author.
+// Two quotes by the same author would make the matching game ambiguous, so we rather draw again than return those.
+//
+// Quotes with an id in excludedQuoteIDs are only used as a fallback, when the source can't supply enough other quotes within quoteDrawAttempts.
+// This way a player that has seen (nearly) the whole pool can still play. If even then there are not enough distinct authors,
+// ErrNotEnoughDistinctAuthors is returned. Quotes with an id in blockedQuoteIDs are never used.
+func (service *QuoteService) getQuotesWithDistinctAuthors(ctx context.Context, amount int, excludedQuoteIDs, blockedQuoteIDs map[int]bool, language models.Language) ([]*models.Quote, error) {
+	quotes := make([]*models.Quote, 0, amount)
+	usedAuthors := map[string]bool{}
+	// excludedQuotes holds the excluded quotes we came across, in case we need to fall back to them
+	excludedQuotes := []*models.Quote{}
+	seenExcludedQuoteIDs := map[int]bool{}
+
+	for range quoteDrawAttempts {
+		sample, err := service.drawRandomQuotes(ctx, quoteSampleSize, blockedQuoteIDs, language)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, q := range sample {
+			if excludedQuoteIDs[q.ID] {
+				if !seenExcludedQuoteIDs[q.ID] {
+					seenExcludedQuoteIDs[q.ID] = true
+					excludedQuotes = append(excludedQuotes, q)
+				}
+				continue
+			}
+			if usedAuthors[q.Author] {
+				continue
+			}
+			usedAuthors[q.Author] = true
+			quotes = append(quotes, q)
+
+			if len(quotes) == amount {
+				return quotes, nil
+			}
+		}
+	}
+
+	// We couldn't find enough new quotes, so we fall back to the quotes the player has seen before
+	for _, q := range excludedQuotes {
+		if usedAuthors[q.Author] {
+			continue
+		}
+		usedAuthors[q.Author] = true
+		quotes = append(quotes, q)
+
+		if len(quotes) == amount {
+			service.logger.Warn().Int("amount", amount).Msg("not enough unseen quotes available, falling back to recently seen quotes")
+			return quotes, nil
+		}
+	}
+
+	service.logger.Error().Int("amount", amount).Int("found", len(quotes)).Str("language", string(language)).Msg("could not find enough quotes with distinct authors")
+	return nil, models.ErrNotEnoughDistinctAuthors
+}
+
+// drawRandomQuotes draws a sample of random quotes in the given language from the local store. As long as the store has no English quotes,
+// for example because the first catalogue sync hasn't finished yet, English samples are drawn from dummyjson directly. For other languages
+// the sample is empty when the store has no quotes in it. Blocked quotes are removed from the sample, so it can contain fewer quotes than requested.
+func (service *QuoteService) drawRandomQuotes(ctx context.Context, amount int, blockedQuoteIDs map[int]bool, language models.Language) ([]*models.Quote, error) {
+	sample, err := service.quoteRepo.GetRandomQuotes(ctx, amount, language)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sample) == 0 && language == models.LanguageEnglish {
+		sample, err = service.dummyJsonRepo.GetRandomQuotes(ctx, amount)
+		if err != nil {
+			return nil, err
+		}
+		if len(sample) == 0 {
+			return nil, errors.New("dummyJsonRepo returned no quotes and no error")
+		}
+	}
+
+	quotes := make([]*models.Quote, 0, len(sample))
+	for _, q := range sample {
+		if !blockedQuoteIDs[q.ID] {
+			quotes = append(quotes, q)
+		}
+	}
+	return quotes, nil
+}
+
+// getBlockedQuoteIDs returns the ids of the quotes that are hidden or deleted by an admin as a set
+func (service *QuoteService) getBlockedQuoteIDs(ctx context.Context) (map[int]bool, error) {
+	ids, err := service.quoteRepo.GetBlockedQuoteIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blockedQuoteIDs := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		blockedQuoteIDs[id] = true
+	}
+	return blockedQuoteIDs, nil
+}
+
+// SubmitAnswerToQuoteGame receives the authors a user has given to the quotes of a game in GameModeMatch or GameModeMultipleChoice.
+// The function validates if the game exists and the quote ids are correct. After that the quotes will be retrieved and the result
+// of the game determined by the rules of its mode and stored in the db. The result of the game is returned.
 func (service *QuoteService) SubmitAnswerToQuoteGame(ctx context.Context, id uuid.UUID, answers models.QuoteGameAnswerMap) (*models.QuoteGameResult, error) {
-	quoteIDs, err := service.quoteGameRepo.ValidateIDAndAnswerIDs(ctx, id, answers)
+	return service.submitAnswers(ctx, id, answers, models.GameModeMatch, models.GameModeMultipleChoice)
+}
+
+// SubmitBlanksToQuoteGame receives the words a user has filled in for the blanks of a game in GameModeFillInTheBlank.
+// Like SubmitAnswerToQuoteGame, the result of the game is determined, stored and returned.
+func (service *QuoteService) SubmitBlanksToQuoteGame(ctx context.Context, id uuid.UUID, answers models.QuoteGameAnswerMap) (*models.QuoteGameResult, error) {
+	return service.submitAnswers(ctx, id, answers, models.GameModeFillInTheBlank)
+}
+
+// submitAnswers determines and stores the result of a game. The kind of answer depends on the mode, so only games
+// in one of the given modes are accepted. Games in other modes return ErrWrongGameMode.
+//
+// A game that is answered already returns its stored result when the answers are the same, for example because the client
+// didn't get the response of the first submission. Other answers return ErrQuoteGameAlreadyAnswered.
+func (service *QuoteService) submitAnswers(ctx context.Context, id uuid.UUID, answers models.QuoteGameAnswerMap, modes ...models.GameMode) (*models.QuoteGameResult, error) {
+	mode, quoteIDs, err := service.quoteGameRepo.ValidateIDAndAnswerIDs(ctx, id, answers)
+	if errors.Is(err, models.ErrQuoteGameCompleted) {
+		return service.storedResult(ctx, id, answers, modes...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(modes, mode) {
+		return nil, models.ErrWrongGameMode
+	}
+
+	result, err := service.result(ctx, id, mode, quoteIDs, answers)
+	if err != nil {
+		return nil, err
+	}
+	err = service.quoteGameRepo.StoreQuoteGameResult(ctx, result, answers)
+	if errors.Is(err, models.ErrQuoteGameCompleted) {
+		// Another submission completed the game while we were scoring it. Only that one counts, so it's handled like a repeated submission
+		return service.storedResult(ctx, id, answers, modes...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The game is over, so its deadline doesn't matter anymore
+	topic := models.QuoteGameTopic(id)
+	service.publisher.Cancel(topic)
+	score := result.Score()
+	service.publisher.Publish(models.Event{Topic: topic, Type: models.EventResultsAvailable, Score: &score})
+	return result, nil
+}
+
+// storedResult returns the result of a completed game, when the answers are the same as the ones that completed it.
+// The correctness is taken from the database, so the result stays the same when a quote is edited afterwards.
+func (service *QuoteService) storedResult(ctx context.Context, id uuid.UUID, answers models.QuoteGameAnswerMap, modes ...models.GameMode) (*models.QuoteGameResult, error) {
+	submission, err := service.quoteGameRepo.GetQuoteGameSubmission(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(modes, submission.Mode) {
+		return nil, models.ErrWrongGameMode
+	}
+	if !maps.Equal(submission.Answers, answers) {
+		return nil, models.ErrQuoteGameAlreadyAnswered
+	}
+
+	result, err := service.result(ctx, id, submission.Mode, submission.QuoteIDs, submission.Answers)
 	if err != nil {
 		return nil, err
 	}
+	for i, correct := range submission.Correct {
+		result.Answers[i].Correct = correct
+	}
+	return result, nil
+}
+
+// result scores the answers to a game with the engine of its mode. The answers of the result are in the order of quoteIDs
+func (service *QuoteService) result(ctx context.Context, id uuid.UUID, mode models.GameMode, quoteIDs []int, answers models.QuoteGameAnswerMap) (*models.QuoteGameResult, error) {
+	engine, ok := service.engines[mode]
+	if !ok {
+		return nil, fmt.Errorf("game %s has unknown mode %q", id, mode)
+	}
+
+	quotes, err := service.GetQuotes(ctx, quoteIDs)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := service.quoteGameRepo.GetQuoteGameKeys(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := engine.Result(id, quoteIDs, quotes, keys, answers)
+	// The costs of the hints used during the game are deducted from the score
+	result.Hints, err = service.quoteGameRepo.GetQuoteGameHints(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetQuoteGameStatus returns the deadline of the game and whether it's answered already
+func (service *QuoteService) GetQuoteGameStatus(ctx context.Context, id uuid.UUID) (*models.QuoteGameStatus, error) {
+	return service.quoteGameRepo.GetQuoteGameStatus(ctx, id)
+}
 
-	quotes, err := service.dummyJsonRepo.GetQuotes(ctx, quoteIDs)
+// GetQuotes retrieves the quotes with the given ids from the local store. Quotes that are not in the store, for example
+// because the game was created before the first catalogue sync, are retrieved from dummyjson.
+func (service *QuoteService) GetQuotes(ctx context.Context, ids []int) (map[int]*models.Quote, error) {
+	quotes, err := service.quoteRepo.GetQuotes(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
 
-	return service.quoteGameRepo.ValidateAnswersAndCreateGameResult(ctx, id, quoteIDs, quotes, answers)
+	missingIDs := []int{}
+	for _, id := range ids {
+		if _, ok := quotes[id]; !ok {
+			missingIDs = append(missingIDs, id)
+		}
+	}
+	if len(missingIDs) == 0 {
+		return quotes, nil
+	}
+
+	missingQuotes, err := service.dummyJsonRepo.GetQuotes(ctx, missingIDs)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(quotes, missingQuotes)
+	return quotes, nil
 }
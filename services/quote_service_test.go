@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"maps"
 	"os"
 	"testing"
 
@@ -10,11 +11,16 @@ import (
 	"github.com/pietdevries94/Kabisa/models"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
 func TestQuoteService_GetRandomQuote(t *testing.T) {
 	type Test struct {
+		language            models.Language
+		mockedLocalQuotes   []*models.Quote
+		mockedEnglishQuotes []*models.Quote
+		mockedBlockedIDs    []int
 		mockedJsonRepoQuote []*models.Quote
 		mockedJsonRepoError error
 		expectedResult      *models.Quote
@@ -26,12 +32,27 @@ func TestQuoteService_GetRandomQuote(t *testing.T) {
 
 			mockedDummyJsonRepo := new(MockedDummyJsonRepo)
 			mockedDummyJsonRepo.On("GetRandomQuotes", 1).
-				Once().
 				Return(tt.mockedJsonRepoQuote, tt.mockedJsonRepoError)
 
+			// By default the local store is empty, so dummyjson is used
+			mockedQuoteRepo := new(MockedQuoteRepo)
+			mockedQuoteRepo.On("GetBlockedQuoteIDs").
+				Once().
+				Return(append([]int{}, tt.mockedBlockedIDs...), nil)
+			language := tt.language
+			if language == "" {
+				language = models.LanguageEnglish
+			}
+			mockedQuoteRepo.On("GetRandomQuotes", 1, language).
+				Return(append([]*models.Quote{}, tt.mockedLocalQuotes...), nil)
+			if language != models.LanguageEnglish {
+				mockedQuoteRepo.On("GetRandomQuotes", 1, models.LanguageEnglish).
+					Return(append([]*models.Quote{}, tt.mockedEnglishQuotes...), nil)
+			}
+
 			// We inject the mocked repo into the service and expect the same quote back
 			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
-			res, err := NewQuoteService(&logger, mockedDummyJsonRepo, nil).GetRandomQuote(context.TODO())
+			res, err := NewQuoteService(&logger, mockedDummyJsonRepo, nil, mockedQuoteRepo, 10, nil).GetRandomQuote(context.TODO(), language)
 
 			if tt.expectedError != nil {
 				require.ErrorContains(t, err, tt.expectedError.Error())
@@ -71,108 +92,179 @@ func TestQuoteService_GetRandomQuote(t *testing.T) {
 			expectedError: errors.New("dummyJsonRepo returned no quotes and no error"),
 		},
 	))
+
+	t.Run("returns a quote from the local store when it's filled", run(Test{
+		mockedLocalQuotes: []*models.Quote{
+			{ID: 1000000, Quote: "A quote added by an admin.", Author: "An admin"},
+		},
+		expectedResult: &models.Quote{ID: 1000000, Quote: "A quote added by an admin.", Author: "An admin"},
+	}))
+
+	t.Run("returns a quote in the requested language", run(Test{
+		language: models.LanguageDutch,
+		mockedLocalQuotes: []*models.Quote{
+			{ID: 1000001, Quote: "Wie het kleine niet eert, is het grote niet weerd.", Author: "Onbekend", Language: models.LanguageDutch},
+		},
+		expectedResult: &models.Quote{ID: 1000001, Quote: "Wie het kleine niet eert, is het grote niet weerd.", Author: "Onbekend", Language: models.LanguageDutch},
+	}))
+
+	t.Run("falls back to English when there are no quotes in the requested language", run(Test{
+		language: models.LanguageDutch,
+		mockedEnglishQuotes: []*models.Quote{
+			{ID: 663, Quote: "Never Mistake Motion For Action.", Author: "Ernest Hemingway", Language: models.LanguageEnglish},
+		},
+		expectedResult: &models.Quote{ID: 663, Quote: "Never Mistake Motion For Action.", Author: "Ernest Hemingway", Language: models.LanguageEnglish},
+	}))
+
+	t.Run("falls back to dummyJsonRepo when there are no quotes in the requested language, nor in English", run(Test{
+		language: models.LanguageDutch,
+		mockedJsonRepoQuote: []*models.Quote{
+			{ID: 663, Quote: "Never Mistake Motion For Action.", Author: "Ernest Hemingway", Language: models.LanguageEnglish},
+		},
+		expectedResult: &models.Quote{ID: 663, Quote: "Never Mistake Motion For Action.", Author: "Ernest Hemingway", Language: models.LanguageEnglish},
+	}))
+
+	t.Run("never returns a blocked quote from dummyJsonRepo", run(Test{
+		mockedBlockedIDs: []int{663},
+		mockedJsonRepoQuote: []*models.Quote{
+			{ID: 663, Quote: "Never Mistake Motion For Action.", Author: "Ernest Hemingway"},
+		},
+		expectedError: errors.New("quote source returned no quotes that are not blocked"),
+	}))
 }
 
 func TestQuoteService_CreateQuoteGame(t *testing.T) {
+	// The mocked quotes are based on actual responses from the underlying api
+	rumi := &models.Quote{
+		ID:     70,
+		Quote:  "The cure for pain is in the pain.",
+		Author: "Rumi",
+	}
+	rumi2 := &models.Quote{
+		ID:     172,
+		Quote:  "The only lasting beauty is the beauty of the heart.",
+		Author: "Rumi",
+	}
+	umar := &models.Quote{
+		ID:     905,
+		Quote:  "Try as much as you can to mention death. For if you were having hard times in your life, then it would give you more hope and would ease things for you. And if you were having abundant affluence of living in luxury, then it would make it less luxurious.",
+		Author: "Umar ibn Al-Khattāb (R.A)",
+	}
+	kalam := &models.Quote{
+		ID:     451,
+		Quote:  "We should not give up and we should not allow the problem to defeat us.",
+		Author: "Abdul Kalam",
+	}
+	erasmus := &models.Quote{ID: 1000001, Quote: "Voorkomen is beter dan genezen.", Author: "Erasmus", Language: models.LanguageDutch}
+	cats := &models.Quote{ID: 1000002, Quote: "Wie wat vindt, heeft wat verloren.", Author: "Jacob Cats", Language: models.LanguageDutch}
+	vondel := &models.Quote{ID: 1000003, Quote: "Wie kan de zon het schijnen beletten?", Author: "Vondel", Language: models.LanguageDutch}
+
 	type Test struct {
-		mockedJsonRepoQuote  []*models.Quote
-		mockedJsonRepoError  error
-		mockedQuoteGame      *models.QuoteGame
-		mockedQuoteGameError error
-		expectedResult       *models.QuoteGame
-		expectedError        error
+		playerID              string
+		mode                  models.GameMode
+		language              models.Language
+		mockedRecentQuoteIDs  []int
+		mockedBlockedIDs      []int
+		mockedLocalSamples    [][]*models.Quote
+		mockedEnglishSamples  [][]*models.Quote
+		mockedJsonRepoSamples [][]*models.Quote
+		mockedJsonRepoError   error
+		expectedGameQuotes    []*models.Quote
+		mockedQuoteGameError  error
+		expectedError         error
 	}
 	run := func(tt Test) func(t *testing.T) {
 		return func(t *testing.T) {
 			t.Helper()
 
+			// Every draw from the dummyJsonRepo returns the next sample
 			mockedDummyJsonRepo := new(MockedDummyJsonRepo)
-			mockedDummyJsonRepo.On("GetRandomQuotes", 3).
-				Once().
-				Return(tt.mockedJsonRepoQuote, tt.mockedJsonRepoError)
+			for _, sample := range tt.mockedJsonRepoSamples {
+				mockedDummyJsonRepo.On("GetRandomQuotes", quoteSampleSize).
+					Once().
+					Return(sample, nil)
+			}
+			if tt.mockedJsonRepoError != nil {
+				mockedDummyJsonRepo.On("GetRandomQuotes", quoteSampleSize).
+					Once().
+					Return([]*models.Quote(nil), tt.mockedJsonRepoError)
+			}
 
+			// The game is built by the service, so the stored game has to be the game of the expected quotes
 			mockedQuoteGameRepo := new(MockedQuoteGameRepo)
-			mockedQuoteGameRepo.On("CreateQuoteGame", tt.mockedJsonRepoQuote).
+			mockedQuoteGameRepo.On("GetRecentQuoteIDs", tt.playerID, 10).
 				Once().
-				Return(tt.mockedQuoteGame, tt.mockedQuoteGameError)
+				Return(tt.mockedRecentQuoteIDs, nil)
+			mockedQuoteGameRepo.On("CreateQuoteGame", mock.MatchedBy(func(game *models.QuoteGame) bool {
+				return assert.ObjectsAreEqual(models.NewQuoteGame(game.ID, tt.expectedGameQuotes), game)
+			}), tt.playerID).
+				Once().
+				Return(tt.mockedQuoteGameError)
 
-			// We inject the mocked repos into the service and expect the same quote back
+			// Every draw from the local store returns the next sample in the language. When there are no more samples, the store is empty
+			language := tt.language
+			if language == "" {
+				language = models.LanguageEnglish
+			}
+			mockedQuoteRepo := new(MockedQuoteRepo)
+			mockedQuoteRepo.On("GetBlockedQuoteIDs").
+				Once().
+				Return(append([]int{}, tt.mockedBlockedIDs...), nil)
+			samples := map[models.Language][][]*models.Quote{language: tt.mockedLocalSamples}
+			if language != models.LanguageEnglish {
+				samples[models.LanguageEnglish] = tt.mockedEnglishSamples
+			}
+			for sampleLanguage, languageSamples := range samples {
+				for _, sample := range languageSamples {
+					mockedQuoteRepo.On("GetRandomQuotes", quoteSampleSize, sampleLanguage).
+						Once().
+						Return(append([]*models.Quote{}, sample...), nil)
+				}
+				mockedQuoteRepo.On("GetRandomQuotes", quoteSampleSize, sampleLanguage).
+					Return([]*models.Quote{}, nil)
+			}
+
+			mockedPublisher := new(MockedPublisher)
+			mockedPublisher.On("Schedule", mock.Anything, mock.Anything)
+
+			// We inject the mocked repos into the service and expect the game of the expected quotes back
+			mode := tt.mode
+			if mode == "" {
+				mode = models.GameModeMatch
+			}
 			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
-			res, err := NewQuoteService(&logger, mockedDummyJsonRepo, mockedQuoteGameRepo).CreateQuoteGame(context.TODO())
+			res, err := NewQuoteService(&logger, mockedDummyJsonRepo, mockedQuoteGameRepo, mockedQuoteRepo, 10, mockedPublisher).CreateQuoteGame(context.TODO(), tt.playerID, mode, language)
 
 			if tt.expectedError != nil {
 				require.ErrorContains(t, err, tt.expectedError.Error())
+				assert.Nil(t, res)
+				mockedPublisher.AssertNotCalled(t, "Schedule", mock.Anything, mock.Anything)
 			} else {
 				require.NoError(t, err)
+				assert.NoError(t, uuid.Validate(res.ID.String()))
+				assert.Equal(t, models.NewQuoteGame(res.ID, tt.expectedGameQuotes), res)
+				// The deadline events of the new game are scheduled
+				mockedPublisher.AssertNumberOfCalls(t, "Schedule", 2)
+				topic := models.QuoteGameTopic(res.ID)
+				mockedPublisher.AssertCalled(t, "Schedule", mock.Anything, mock.MatchedBy(func(events []models.Event) bool {
+					return len(events) == 1 && events[0].Topic == topic && events[0].Type == models.EventGameExpired
+				}))
 			}
 
-			assert.Equal(t, tt.expectedResult, res)
+			mockedDummyJsonRepo.AssertExpectations(t)
+			if tt.expectedGameQuotes == nil {
+				mockedQuoteGameRepo.AssertNotCalled(t, "CreateQuoteGame", mock.Anything, tt.playerID)
+			}
+			// The recent games are only looked up when a player is given
+			if tt.playerID == "" {
+				mockedQuoteGameRepo.AssertNotCalled(t, "GetRecentQuoteIDs", tt.playerID, 10)
+			}
 		}
 	}
 
 	t.Run("returns quote from dummyJsonRepo", run(Test{
-		// The mocked quote is based on an actual response from the underlying api
-		mockedJsonRepoQuote: []*models.Quote{
-			{
-				ID:     70,
-				Quote:  "The cure for pain is in the pain.",
-				Author: "Rumi",
-			},
-			{
-				ID:     905,
-				Quote:  "Try as much as you can to mention death. For if you were having hard times in your life, then it would give you more hope and would ease things for you. And if you were having abundant affluence of living in luxury, then it would make it less luxurious.",
-				Author: "Umar ibn Al-Khattāb (R.A)",
-			},
-			{
-				ID:     451,
-				Quote:  "We should not give up and we should not allow the problem to defeat us.",
-				Author: "Abdul Kalam",
-			},
-		},
-		mockedQuoteGame: &models.QuoteGame{
-			ID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
-			Quotes: []*models.QuoteWithoutAuthor{
-				{
-					ID:    70,
-					Quote: "The cure for pain is in the pain.",
-				},
-				{
-					ID:    905,
-					Quote: "Try as much as you can to mention death. For if you were having hard times in your life, then it would give you more hope and would ease things for you. And if you were having abundant affluence of living in luxury, then it would make it less luxurious.",
-				},
-				{
-					ID:    451,
-					Quote: "We should not give up and we should not allow the problem to defeat us.",
-				},
-			},
-			Authors: []string{
-				"Abdul Kalam",
-				"Rumi",
-				"Umar ibn Al-Khattāb (R.A)",
-			},
-		},
-		expectedResult: &models.QuoteGame{
-			ID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
-			Quotes: []*models.QuoteWithoutAuthor{
-				{
-					ID:    70,
-					Quote: "The cure for pain is in the pain.",
-				},
-				{
-					ID:    905,
-					Quote: "Try as much as you can to mention death. For if you were having hard times in your life, then it would give you more hope and would ease things for you. And if you were having abundant affluence of living in luxury, then it would make it less luxurious.",
-				},
-				{
-					ID:    451,
-					Quote: "We should not give up and we should not allow the problem to defeat us.",
-				},
-			},
-			Authors: []string{
-				"Abdul Kalam",
-				"Rumi",
-				"Umar ibn Al-Khattāb (R.A)",
-			},
-		},
+		mockedJsonRepoSamples: [][]*models.Quote{{rumi, umar, kalam}},
+		expectedGameQuotes:    []*models.Quote{rumi, umar, kalam},
 	}))
 
 	t.Run("passes trough an error from dummyJsonRepo", run(
@@ -184,41 +276,111 @@ func TestQuoteService_CreateQuoteGame(t *testing.T) {
 
 	t.Run("passes trough an error from quoteGameRepo", run(
 		Test{
-			mockedJsonRepoQuote: []*models.Quote{
-				{
-					ID:     70,
-					Quote:  "The cure for pain is in the pain.",
-					Author: "Rumi",
-				},
-				{
-					ID:     905,
-					Quote:  "Try as much as you can to mention death. For if you were having hard times in your life, then it would give you more hope and would ease things for you. And if you were having abundant affluence of living in luxury, then it would make it less luxurious.",
-					Author: "Umar ibn Al-Khattāb (R.A)",
-				},
-				{
-					ID:     451,
-					Quote:  "We should not give up and we should not allow the problem to defeat us.",
-					Author: "Abdul Kalam",
-				},
-			},
-			mockedQuoteGameError: errors.New("this is an error"),
-			expectedError:        errors.New("this is an error"),
+			mockedJsonRepoSamples: [][]*models.Quote{{rumi, umar, kalam}},
+			expectedGameQuotes:    []*models.Quote{rumi, umar, kalam},
+			mockedQuoteGameError:  errors.New("this is an error"),
+			expectedError:         errors.New("this is an error"),
 		},
 	))
+
+	t.Run("draws again when a sample contains quotes by the same author", run(Test{
+		mockedJsonRepoSamples: [][]*models.Quote{
+			{rumi, rumi2, kalam},
+			{rumi, kalam, umar},
+		},
+		expectedGameQuotes: []*models.Quote{rumi, kalam, umar},
+	}))
+
+	t.Run("returns an error when the source can't supply enough distinct authors", run(Test{
+		mockedJsonRepoSamples: [][]*models.Quote{
+			{rumi, rumi2},
+			{rumi2, kalam},
+			{rumi},
+			{kalam, rumi},
+			{rumi2},
+		},
+		expectedError: models.ErrNotEnoughDistinctAuthors,
+	}))
+
+	t.Run("avoids quotes from the recent games of the player", run(Test{
+		playerID:             "player-42",
+		mockedRecentQuoteIDs: []int{70, 12, 33},
+		mockedJsonRepoSamples: [][]*models.Quote{
+			{rumi, kalam},
+			{umar, rumi2},
+		},
+		expectedGameQuotes: []*models.Quote{kalam, umar, rumi2},
+	}))
+
+	t.Run("draws from the local store when it's filled", run(Test{
+		mockedLocalSamples: [][]*models.Quote{{umar, rumi, kalam}},
+		expectedGameQuotes: []*models.Quote{umar, rumi, kalam},
+	}))
+
+	t.Run("draws from the quotes in the requested language", run(Test{
+		language:           models.LanguageDutch,
+		mockedLocalSamples: [][]*models.Quote{{erasmus, cats, vondel}},
+		expectedGameQuotes: []*models.Quote{erasmus, cats, vondel},
+	}))
+
+	t.Run("falls back to English when there are not enough distinct authors in the requested language", run(Test{
+		language:             models.LanguageDutch,
+		mockedLocalSamples:   [][]*models.Quote{{erasmus, cats}},
+		mockedEnglishSamples: [][]*models.Quote{{umar, rumi, kalam}},
+		expectedGameQuotes:   []*models.Quote{umar, rumi, kalam},
+	}))
+
+	t.Run("falls back to dummyJsonRepo when there are no quotes in the requested language, nor in English", run(Test{
+		language:              models.LanguageDutch,
+		mockedJsonRepoSamples: [][]*models.Quote{{rumi, umar, kalam}},
+		expectedGameQuotes:    []*models.Quote{rumi, umar, kalam},
+	}))
+
+	t.Run("never uses blocked quotes from dummyJsonRepo", run(Test{
+		mockedBlockedIDs: []int{70},
+		mockedJsonRepoSamples: [][]*models.Quote{
+			{rumi, kalam, umar},
+			{rumi2},
+		},
+		expectedGameQuotes: []*models.Quote{kalam, umar, rumi2},
+	}))
+
+	t.Run("falls back to recently seen quotes when the pool is exhausted", run(Test{
+		playerID:             "player-42",
+		mockedRecentQuoteIDs: []int{70, 905},
+		mockedJsonRepoSamples: [][]*models.Quote{
+			{rumi, kalam},
+			{umar, rumi},
+			{kalam},
+			{umar},
+			{rumi, kalam, umar},
+		},
+		expectedGameQuotes: []*models.Quote{kalam, rumi, umar},
+	}))
+
+	t.Run("returns an error for an unknown mode", run(Test{
+		mode:          "unknown",
+		expectedError: models.ErrInvalidGameMode,
+	}))
 }
 
 func TestQuoteService_SubmitAnswerToQuoteGame(t *testing.T) {
 	type Test struct {
-		id                                       uuid.UUID
-		answers                                  models.QuoteGameAnswerMap
-		mockedValidateIDAndAnswerIDsResult       []int
-		mockedValidateIDAndAnswerIDsError        error
-		mockedGetQuotesResult                    map[int]*models.Quote
-		mockedGetQuotesError                     error
-		mockedValidateAnswersAndCreateGameResult *models.QuoteGameResult
-		mockedValidateAnswersAndCreateGameError  error
-		expectedResult                           *models.QuoteGameResult
-		expectedError                            error
+		id                                 uuid.UUID
+		answers                            models.QuoteGameAnswerMap
+		blanks                             bool
+		mockedValidateIDAndAnswerIDsMode   models.GameMode
+		mockedValidateIDAndAnswerIDsResult []int
+		mockedValidateIDAndAnswerIDsError  error
+		mockedLocalQuotesResult            map[int]*models.Quote
+		expectedMissingIDs                 []int
+		mockedGetQuotesResult              map[int]*models.Quote
+		mockedGetQuotesError               error
+		mockedHints                        []*models.Hint
+		mockedKeys                         map[int]*models.QuoteKey
+		mockedStoreQuoteGameResultError    error
+		expectedResult                     *models.QuoteGameResult
+		expectedError                      error
 	}
 	run := func(tt Test) func(t *testing.T) {
 		return func(t *testing.T) {
@@ -227,30 +389,69 @@ func TestQuoteService_SubmitAnswerToQuoteGame(t *testing.T) {
 			mockedQuoteGameRepo := new(MockedQuoteGameRepo)
 			mockedDummyJsonRepo := new(MockedDummyJsonRepo)
 
+			mode := tt.mockedValidateIDAndAnswerIDsMode
+			if mode == "" {
+				mode = models.GameModeMatch
+			}
 			mockedQuoteGameRepo.On("ValidateIDAndAnswerIDs", tt.id, tt.answers).
 				Once().
-				Return(tt.mockedValidateIDAndAnswerIDsResult, tt.mockedValidateIDAndAnswerIDsError)
+				Return(mode, tt.mockedValidateIDAndAnswerIDsResult, tt.mockedValidateIDAndAnswerIDsError)
+
+			// Quotes are looked up in the local store first, only the missing ones are retrieved from dummyjson
+			localQuotes := map[int]*models.Quote{}
+			maps.Copy(localQuotes, tt.mockedLocalQuotesResult)
+			mockedQuoteRepo := new(MockedQuoteRepo)
+			mockedQuoteRepo.On("GetQuotes", tt.mockedValidateIDAndAnswerIDsResult).
+				Once().
+				Return(localQuotes, nil)
 
-			mockedDummyJsonRepo.On("GetQuotes", tt.mockedValidateIDAndAnswerIDsResult).
+			missingIDs := tt.expectedMissingIDs
+			if missingIDs == nil {
+				missingIDs = tt.mockedValidateIDAndAnswerIDsResult
+			}
+			mockedDummyJsonRepo.On("GetQuotes", missingIDs).
 				Once().
 				Return(tt.mockedGetQuotesResult, tt.mockedGetQuotesError)
 
-			mockedQuoteGameRepo.On("ValidateAnswersAndCreateGameResult", tt.id, tt.mockedValidateIDAndAnswerIDsResult, tt.mockedGetQuotesResult, tt.answers).
+			// The game result is determined using the quotes from both sources and the used hints, and then stored
+			mockedQuoteGameRepo.On("GetQuoteGameHints", tt.id).
 				Once().
-				Return(tt.mockedValidateAnswersAndCreateGameResult, tt.mockedValidateAnswersAndCreateGameError)
+				Return(tt.mockedHints, nil)
+			mockedQuoteGameRepo.On("GetQuoteGameKeys", tt.id).
+				Once().
+				Return(tt.mockedKeys, nil)
+			mockedQuoteGameRepo.On("StoreQuoteGameResult", mock.Anything, mock.Anything).
+				Once().
+				Return(tt.mockedStoreQuoteGameResultError)
+
+			mockedPublisher := new(MockedPublisher)
+			mockedPublisher.On("Cancel", models.QuoteGameTopic(tt.id))
+			mockedPublisher.On("Publish", mock.Anything)
 
 			// We inject the mocked repos into the service and expect the same quote back
 			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
-			res, err := NewQuoteService(&logger, mockedDummyJsonRepo, mockedQuoteGameRepo).
-				SubmitAnswerToQuoteGame(context.TODO(), tt.id, tt.answers)
+			service := NewQuoteService(&logger, mockedDummyJsonRepo, mockedQuoteGameRepo, mockedQuoteRepo, 10, mockedPublisher)
+			submit := service.SubmitAnswerToQuoteGame
+			if tt.blanks {
+				submit = service.SubmitBlanksToQuoteGame
+			}
+			res, err := submit(context.TODO(), tt.id, tt.answers)
 
 			if tt.expectedError != nil {
 				require.ErrorContains(t, err, tt.expectedError.Error())
+				mockedPublisher.AssertNotCalled(t, "Publish", mock.Anything)
 			} else {
 				require.NoError(t, err)
+				// The game is over, so the deadline events are cancelled and the result is announced
+				score := res.Score()
+				mockedPublisher.AssertCalled(t, "Cancel", models.QuoteGameTopic(tt.id))
+				mockedPublisher.AssertCalled(t, "Publish", models.Event{Topic: models.QuoteGameTopic(tt.id), Type: models.EventResultsAvailable, Score: &score})
 			}
 
 			assert.Equal(t, tt.expectedResult, res)
+			if tt.expectedResult != nil {
+				mockedQuoteGameRepo.AssertCalled(t, "StoreQuoteGameResult", tt.expectedResult, tt.answers)
+			}
 		}
 	}
 
@@ -267,7 +468,7 @@ func TestQuoteService_SubmitAnswerToQuoteGame(t *testing.T) {
 			43: {ID: 43, Author: "William", Quote: "Hi!"},
 			2:  {ID: 2, Author: "Bob", Quote: "Bye!"},
 		},
-		mockedValidateAnswersAndCreateGameResult: &models.QuoteGameResult{
+		expectedResult: &models.QuoteGameResult{
 			ID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
 			Answers: []*models.QuoteGameActualAnswer{
 				{Quote: models.Quote{ID: 54, Author: "George", Quote: "Hello!"}, Correct: false},
@@ -275,17 +476,141 @@ func TestQuoteService_SubmitAnswerToQuoteGame(t *testing.T) {
 				{Quote: models.Quote{ID: 2, Author: "Bob", Quote: "Bye!"}, Correct: true},
 			},
 		},
+	}))
+
+	t.Run("only retrieves the quotes missing from the local store from dummyjson", run(Test{
+		id: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+		answers: models.QuoteGameAnswerMap{
+			54:      "A name",
+			1000000: "An admin",
+			2:       "Bob",
+		},
+		mockedValidateIDAndAnswerIDsResult: []int{54, 1000000, 2},
+		mockedLocalQuotesResult: map[int]*models.Quote{
+			54:      {ID: 54, Author: "George", Quote: "Hello!"},
+			1000000: {ID: 1000000, Author: "An admin", Quote: "Hi!"},
+		},
+		expectedMissingIDs: []int{2},
+		mockedGetQuotesResult: map[int]*models.Quote{
+			2: {ID: 2, Author: "Bob", Quote: "Bye!"},
+		},
 		expectedResult: &models.QuoteGameResult{
 			ID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
 			Answers: []*models.QuoteGameActualAnswer{
 				{Quote: models.Quote{ID: 54, Author: "George", Quote: "Hello!"}, Correct: false},
-				{Quote: models.Quote{ID: 43, Author: "William", Quote: "Hi!"}, Correct: false},
+				{Quote: models.Quote{ID: 1000000, Author: "An admin", Quote: "Hi!"}, Correct: true},
 				{Quote: models.Quote{ID: 2, Author: "Bob", Quote: "Bye!"}, Correct: true},
 			},
 		},
 	}))
 
-	t.Run("returns the error when ValidateAnswersAndCreateGameResult fails", run(Test{
+	t.Run("scores a multiple choice game per question", run(Test{
+		id: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+		answers: models.QuoteGameAnswerMap{
+			54: "George",
+			43: "William",
+			2:  "George",
+		},
+		mockedValidateIDAndAnswerIDsMode:   models.GameModeMultipleChoice,
+		mockedValidateIDAndAnswerIDsResult: []int{54, 43, 2},
+		mockedGetQuotesResult: map[int]*models.Quote{
+			54: {ID: 54, Author: "George", Quote: "Hello!"},
+			43: {ID: 43, Author: "William", Quote: "Hi!"},
+			2:  {ID: 2, Author: "Bob", Quote: "Bye!"},
+		},
+		expectedResult: &models.QuoteGameResult{
+			ID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+			Answers: []*models.QuoteGameActualAnswer{
+				{Quote: models.Quote{ID: 54, Author: "George", Quote: "Hello!"}, Correct: true},
+				{Quote: models.Quote{ID: 43, Author: "William", Quote: "Hi!"}, Correct: true},
+				{Quote: models.Quote{ID: 2, Author: "Bob", Quote: "Bye!"}, Correct: false},
+			},
+		},
+	}))
+
+	t.Run("scores the blanks of a fill in the blank game", run(Test{
+		id: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+		answers: models.QuoteGameAnswerMap{
+			54: "hello",
+			43: "Nothing",
+		},
+		blanks:                             true,
+		mockedValidateIDAndAnswerIDsMode:   models.GameModeFillInTheBlank,
+		mockedValidateIDAndAnswerIDsResult: []int{54, 43},
+		mockedGetQuotesResult: map[int]*models.Quote{
+			54: {ID: 54, Author: "George", Quote: "Hello!"},
+			43: {ID: 43, Author: "William", Quote: "Hi!"},
+		},
+		expectedResult: &models.QuoteGameResult{
+			ID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+			Answers: []*models.QuoteGameActualAnswer{
+				{Quote: models.Quote{ID: 54, Author: "George", Quote: "Hello!"}, Correct: true, Blank: &models.BlankAnswer{Given: "hello", Expected: "Hello"}},
+				{Quote: models.Quote{ID: 43, Author: "William", Quote: "Hi!"}, Correct: false, Blank: &models.BlankAnswer{Given: "Nothing", Expected: "Hi"}},
+			},
+		},
+	}))
+
+	t.Run("scores the blanks against the words that were removed when the game was created", run(Test{
+		id:                                 uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+		answers:                            models.QuoteGameAnswerMap{54: "hello"},
+		blanks:                             true,
+		mockedValidateIDAndAnswerIDsMode:   models.GameModeFillInTheBlank,
+		mockedValidateIDAndAnswerIDsResult: []int{54},
+		// The quote was edited by an admin during the game
+		mockedGetQuotesResult: map[int]*models.Quote{
+			54: {ID: 54, Author: "George", Quote: "Greetings!"},
+		},
+		mockedKeys: map[int]*models.QuoteKey{54: {Blank: "Hello"}},
+		expectedResult: &models.QuoteGameResult{
+			ID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+			Answers: []*models.QuoteGameActualAnswer{
+				{Quote: models.Quote{ID: 54, Author: "George", Quote: "Greetings!"}, Correct: true, Blank: &models.BlankAnswer{Given: "hello", Expected: "Hello"}},
+			},
+		},
+	}))
+
+	t.Run("rejects blanks for a game in another mode", run(Test{
+		id:                                 uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+		answers:                            models.QuoteGameAnswerMap{54: "hello"},
+		blanks:                             true,
+		mockedValidateIDAndAnswerIDsResult: []int{54},
+		expectedError:                      models.ErrWrongGameMode,
+	}))
+
+	t.Run("rejects authors for a fill in the blank game", run(Test{
+		id:                                 uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+		answers:                            models.QuoteGameAnswerMap{54: "George"},
+		mockedValidateIDAndAnswerIDsMode:   models.GameModeFillInTheBlank,
+		mockedValidateIDAndAnswerIDsResult: []int{54},
+		expectedError:                      models.ErrWrongGameMode,
+	}))
+
+	t.Run("deducts the costs of the used hints from the score", run(Test{
+		id: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+		answers: models.QuoteGameAnswerMap{
+			54: "George",
+			43: "William",
+			2:  "George",
+		},
+		mockedValidateIDAndAnswerIDsResult: []int{54, 43, 2},
+		mockedGetQuotesResult: map[int]*models.Quote{
+			54: {ID: 54, Author: "George", Quote: "Hello!"},
+			43: {ID: 43, Author: "William", Quote: "Hi!"},
+			2:  {ID: 2, Author: "Bob", Quote: "Bye!"},
+		},
+		mockedHints: []*models.Hint{{Type: models.HintEliminateAuthor, QuoteID: 2, Author: "William", Cost: 1}},
+		expectedResult: &models.QuoteGameResult{
+			ID: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
+			Answers: []*models.QuoteGameActualAnswer{
+				{Quote: models.Quote{ID: 54, Author: "George", Quote: "Hello!"}, Correct: true},
+				{Quote: models.Quote{ID: 43, Author: "William", Quote: "Hi!"}, Correct: true},
+				{Quote: models.Quote{ID: 2, Author: "Bob", Quote: "Bye!"}, Correct: false},
+			},
+			Hints: []*models.Hint{{Type: models.HintEliminateAuthor, QuoteID: 2, Author: "William", Cost: 1}},
+		},
+	}))
+
+	t.Run("returns the error when StoreQuoteGameResult fails", run(Test{
 		id: uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e"),
 		answers: models.QuoteGameAnswerMap{
 			54: "A name",
@@ -298,8 +623,8 @@ func TestQuoteService_SubmitAnswerToQuoteGame(t *testing.T) {
 			43: {ID: 43, Author: "William", Quote: "Hi!"},
 			2:  {ID: 2, Author: "Bob", Quote: "Bye!"},
 		},
-		mockedValidateAnswersAndCreateGameError: errors.New("a brand new error"),
-		expectedError:                           errors.New("a brand new error"),
+		mockedStoreQuoteGameResultError: errors.New("a brand new error"),
+		expectedError:                   errors.New("a brand new error"),
 	}))
 
 	t.Run("returns the error when GetQuotes fails", run(Test{
@@ -325,3 +650,169 @@ func TestQuoteService_SubmitAnswerToQuoteGame(t *testing.T) {
 		expectedError:                     errors.New("a brand new error"),
 	}))
 }
+
+func TestQuoteService_SubmitAnswerToCompletedQuoteGame(t *testing.T) {
+	id := uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e")
+	submission := &models.QuoteGameSubmission{
+		Mode:     models.GameModeMatch,
+		QuoteIDs: []int{54, 43, 2},
+		Answers:  models.QuoteGameAnswerMap{54: "George", 43: "Bob", 2: "William"},
+		Correct:  []bool{true, false, false},
+	}
+
+	type Test struct {
+		answers          models.QuoteGameAnswerMap
+		blanks           bool
+		mockedSubmission *models.QuoteGameSubmission
+		mockedError      error
+		expectedResult   *models.QuoteGameResult
+		expectedError    error
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedQuoteGameRepo := new(MockedQuoteGameRepo)
+			mockedQuoteGameRepo.On("ValidateIDAndAnswerIDs", id, tt.answers).Return(models.GameMode(""), ([]int)(nil), models.ErrQuoteGameCompleted)
+			mockedQuoteGameRepo.On("GetQuoteGameSubmission", id).Return(tt.mockedSubmission, tt.mockedError)
+			mockedQuoteGameRepo.On("GetQuoteGameHints", id).Return([]*models.Hint{{Type: models.HintRevealPair, QuoteID: 54, Author: "George", Cost: 2}}, nil)
+			mockedQuoteGameRepo.On("GetQuoteGameKeys", id).Return(map[int]*models.QuoteKey(nil), nil)
+
+			// The quote of 43 was edited after the game, which doesn't change the stored result
+			mockedQuoteRepo := new(MockedQuoteRepo)
+			mockedQuoteRepo.On("GetQuotes", []int{54, 43, 2}).Return(map[int]*models.Quote{
+				54: {ID: 54, Author: "George", Quote: "Hello!"},
+				43: {ID: 43, Author: "Bob", Quote: "Hi!"},
+				2:  {ID: 2, Author: "Bob", Quote: "Bye!"},
+			}, nil)
+			mockedPublisher := new(MockedPublisher)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			service := NewQuoteService(&logger, nil, mockedQuoteGameRepo, mockedQuoteRepo, 10, mockedPublisher)
+			submit := service.SubmitAnswerToQuoteGame
+			if tt.blanks {
+				submit = service.SubmitBlanksToQuoteGame
+			}
+			res, err := submit(context.TODO(), id, tt.answers)
+
+			// The game was over already, so nothing is stored or announced again
+			mockedQuoteGameRepo.AssertNotCalled(t, "StoreQuoteGameResult", mock.Anything, mock.Anything)
+			mockedPublisher.AssertNotCalled(t, "Publish", mock.Anything)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, res)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("returns the stored result for the same answers", run(Test{
+		answers:          models.QuoteGameAnswerMap{2: "William", 54: "George", 43: "Bob"},
+		mockedSubmission: submission,
+		expectedResult: &models.QuoteGameResult{
+			ID: id,
+			Answers: []*models.QuoteGameActualAnswer{
+				{Quote: models.Quote{ID: 54, Author: "George", Quote: "Hello!"}, Correct: true},
+				{Quote: models.Quote{ID: 43, Author: "Bob", Quote: "Hi!"}, Correct: false},
+				{Quote: models.Quote{ID: 2, Author: "Bob", Quote: "Bye!"}, Correct: false},
+			},
+			Hints: []*models.Hint{{Type: models.HintRevealPair, QuoteID: 54, Author: "George", Cost: 2}},
+		},
+	}))
+
+	t.Run("rejects other answers", run(Test{
+		answers:          models.QuoteGameAnswerMap{54: "George", 43: "William", 2: "Bob"},
+		mockedSubmission: submission,
+		expectedError:    models.ErrQuoteGameAlreadyAnswered,
+	}))
+
+	t.Run("rejects answers for other quotes", run(Test{
+		answers:          models.QuoteGameAnswerMap{54: "George", 43: "Bob"},
+		mockedSubmission: submission,
+		expectedError:    models.ErrQuoteGameAlreadyAnswered,
+	}))
+
+	t.Run("rejects the same answers to the endpoint of another mode", run(Test{
+		answers:          submission.Answers,
+		blanks:           true,
+		mockedSubmission: submission,
+		expectedError:    models.ErrWrongGameMode,
+	}))
+
+	t.Run("passes through that the answers are unknown", run(Test{
+		answers:          submission.Answers,
+		mockedSubmission: (*models.QuoteGameSubmission)(nil),
+		mockedError:      models.ErrQuoteGameIdNotFound,
+		expectedError:    models.ErrQuoteGameIdNotFound,
+	}))
+}
+
+func TestQuoteService_SubmitAnswerToQuoteGameCompletedMeanwhile(t *testing.T) {
+	id := uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e")
+	// The submission that completed the game while ours was scored
+	submission := &models.QuoteGameSubmission{
+		Mode:     models.GameModeMatch,
+		QuoteIDs: []int{54, 43, 2},
+		Answers:  models.QuoteGameAnswerMap{54: "George", 43: "Bob", 2: "William"},
+		Correct:  []bool{true, true, false},
+	}
+
+	type Test struct {
+		answers        models.QuoteGameAnswerMap
+		expectedResult *models.QuoteGameResult
+		expectedError  error
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedQuoteGameRepo := new(MockedQuoteGameRepo)
+			mockedQuoteGameRepo.On("ValidateIDAndAnswerIDs", id, tt.answers).Return(models.GameModeMatch, []int{54, 43, 2}, nil)
+			mockedQuoteGameRepo.On("StoreQuoteGameResult", mock.Anything, tt.answers).Return(models.ErrQuoteGameCompleted)
+			mockedQuoteGameRepo.On("GetQuoteGameSubmission", id).Return(submission, nil)
+			mockedQuoteGameRepo.On("GetQuoteGameHints", id).Return([]*models.Hint(nil), nil)
+			mockedQuoteGameRepo.On("GetQuoteGameKeys", id).Return(map[int]*models.QuoteKey(nil), nil)
+
+			mockedQuoteRepo := new(MockedQuoteRepo)
+			mockedQuoteRepo.On("GetQuotes", []int{54, 43, 2}).Return(map[int]*models.Quote{
+				54: {ID: 54, Author: "George", Quote: "Hello!"},
+				43: {ID: 43, Author: "Bob", Quote: "Hi!"},
+				2:  {ID: 2, Author: "Bob", Quote: "Bye!"},
+			}, nil)
+			mockedPublisher := new(MockedPublisher)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			res, err := NewQuoteService(&logger, nil, mockedQuoteGameRepo, mockedQuoteRepo, 10, mockedPublisher).SubmitAnswerToQuoteGame(context.TODO(), id, tt.answers)
+
+			// Only the submission that completed the game announces its result
+			mockedPublisher.AssertNotCalled(t, "Cancel", mock.Anything)
+			mockedPublisher.AssertNotCalled(t, "Publish", mock.Anything)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, res)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+		}
+	}
+
+	t.Run("returns the result of the other submission for the same answers", run(Test{
+		answers: models.QuoteGameAnswerMap{54: "George", 43: "Bob", 2: "William"},
+		expectedResult: &models.QuoteGameResult{
+			ID: id,
+			Answers: []*models.QuoteGameActualAnswer{
+				{Quote: models.Quote{ID: 54, Author: "George", Quote: "Hello!"}, Correct: true},
+				{Quote: models.Quote{ID: 43, Author: "Bob", Quote: "Hi!"}, Correct: true},
+				{Quote: models.Quote{ID: 2, Author: "Bob", Quote: "Bye!"}, Correct: false},
+			},
+		},
+	}))
+
+	t.Run("rejects other answers", run(Test{
+		answers:       models.QuoteGameAnswerMap{54: "Bob", 43: "George", 2: "William"},
+		expectedError: models.ErrQuoteGameAlreadyAnswered,
+	}))
+}
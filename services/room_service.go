@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// roomCodeAlphabet leaves out characters that are easily confused, like 0 and O or 1 and I
+	roomCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	// roomCodeLength gives about a billion codes, while still being easy to share
+	roomCodeLength = 6
+	// roomCodeAttempts is the number of codes that are tried before giving up on creating a room
+	roomCodeAttempts = 5
+)
+
+type RoomService struct {
+	logger           *zerolog.Logger
+	roomRepo         roomRepo
+	quoteGameService quoteGameService
+	publisher        publisher
+}
+
+// NewRoomService returns a new RoomService, which lets several players answer the same quote game.
+// The games themselves are drawn by the quoteGameService, so rooms get the same quotes as solo games.
+// Everything that happens in a room is published on the topic of the room.
+func NewRoomService(logger *zerolog.Logger, roomRepo roomRepo, quoteGameService quoteGameService, publisher publisher) *RoomService {
+	return &RoomService{
+		logger:           logger,
+		roomRepo:         roomRepo,
+		quoteGameService: quoteGameService,
+		publisher:        publisher,
+	}
+}
+
+// CreateRoom creates a new quote game in a room with a random code. The host is the first participant of the room.
+// The game is stored together with the room, so it isn't a solo game of the host and is never stored without a room.
+func (service *RoomService) CreateRoom(ctx context.Context, hostID string) (*models.RoomGame, error) {
+	if hostID == "" {
+		return nil, models.ErrPlayerIDRequired
+	}
+
+	// Rooms are always played in GameModeMatch, JoinRoom rebuilds the game of the room by those rules.
+	// The participants don't necessarily share a language, so the quotes are in English
+	game, err := service.quoteGameService.DrawQuoteGame(ctx, hostID, models.GameModeMatch, models.LanguageEnglish)
+	if err != nil {
+		return nil, err
+	}
+
+	for range roomCodeAttempts {
+		code, err := newRoomCode()
+		if err != nil {
+			return nil, err
+		}
+
+		room, err := service.roomRepo.CreateRoom(ctx, code, game, hostID)
+		if err == models.ErrRoomCodeTaken {
+			service.logger.Debug().Str("code", code).Msg("room code is already taken, trying another")
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		service.logger.Info().Str("code", room.Code).Str("host", hostID).Msg("room created")
+		scheduleDeadlineEvents(service.publisher, models.RoomTopic(room.Code), room.Deadline, models.EventResultsAvailable)
+		return &models.RoomGame{Room: *room, Game: game}, nil
+	}
+	return nil, errors.New("could not find a free room code")
+}
+
+// JoinRoom adds the player to the room and returns the game of the room. A room can be joined until its deadline.
+func (service *RoomService) JoinRoom(ctx context.Context, code string, playerID string) (*models.RoomGame, error) {
+	if playerID == "" {
+		return nil, models.ErrPlayerIDRequired
+	}
+
+	room, err := service.getOpenRoom(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	joined, err := service.roomRepo.JoinRoom(ctx, room.Code, playerID)
+	if err != nil {
+		return nil, err
+	}
+	if joined {
+		service.publisher.Publish(models.Event{Topic: models.RoomTopic(room.Code), Type: models.EventPlayerJoined, PlayerID: playerID})
+	}
+
+	quotes, err := service.quoteGameService.GetQuotes(ctx, room.QuoteIDs)
+	if err != nil {
+		return nil, err
+	}
+	gameQuotes := make([]*models.Quote, len(room.QuoteIDs))
+	for i, id := range room.QuoteIDs {
+		gameQuotes[i] = quotes[id]
+	}
+
+	return &models.RoomGame{Room: *room, Game: models.NewQuoteGame(room.GameID, gameQuotes)}, nil
+}
+
+// SubmitRoomAnswer stores the answer of a participant and returns the result. Every participant can answer once, before the deadline.
+func (service *RoomService) SubmitRoomAnswer(ctx context.Context, code string, playerID string, answers models.QuoteGameAnswerMap) (*models.QuoteGameResult, error) {
+	if playerID == "" {
+		return nil, models.ErrPlayerIDRequired
+	}
+
+	room, err := service.getOpenRoom(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	// There has to be exactly one answer for every quote of the room
+	if len(answers) != len(room.QuoteIDs) {
+		return nil, models.ErrInvalidQuoteID
+	}
+	for _, id := range room.QuoteIDs {
+		if _, ok := answers[id]; !ok {
+			return nil, models.ErrInvalidQuoteID
+		}
+	}
+
+	quotes, err := service.quoteGameService.GetQuotes(ctx, room.QuoteIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := models.NewQuoteGameResult(room.GameID, room.QuoteIDs, quotes, answers)
+	err = service.roomRepo.SubmitRoomAnswer(ctx, room.Code, playerID, result, answers)
+	if err != nil {
+		return nil, err
+	}
+
+	topic := models.RoomTopic(room.Code)
+	score := result.Score()
+	service.publisher.Publish(models.Event{Topic: topic, Type: models.EventAnswerSubmitted, PlayerID: playerID, Score: &score})
+
+	// When everyone who joined has answered, the result is complete until someone else joins
+	participants, err := service.roomRepo.ListRoomParticipants(ctx, room.Code)
+	if err != nil {
+		// The answer is stored already, only the event is missed
+		service.logger.Error().Err(err).Str("code", room.Code).Msg("could not check if all participants answered")
+		return result, nil
+	}
+	for _, p := range participants {
+		if p.SubmittedAt == nil {
+			return result, nil
+		}
+	}
+	service.publisher.Publish(models.Event{Topic: topic, Type: models.EventResultsAvailable, Deadline: room.Deadline})
+	return result, nil
+}
+
+// GetRoomResult returns the room with the scores of all participants. The result can be requested before and after the deadline.
+func (service *RoomService) GetRoomResult(ctx context.Context, code string) (*models.RoomResult, error) {
+	room, err := service.roomRepo.GetRoom(ctx, models.NormalizeRoomCode(code))
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := service.roomRepo.ListRoomParticipants(ctx, room.Code)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RoomResult{Room: *room, Participants: participants}, nil
+}
+
+// getOpenRoom returns the room if its deadline has not passed yet. Closed rooms are treated as not found, like expired solo games
+func (service *RoomService) getOpenRoom(ctx context.Context, code string) (*models.Room, error) {
+	room, err := service.roomRepo.GetRoom(ctx, models.NormalizeRoomCode(code))
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(room.Deadline) {
+		return nil, models.ErrRoomNotFound
+	}
+	return room, nil
+}
+
+func newRoomCode() (string, error) {
+	code := make([]byte, roomCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(roomCodeAlphabet))))
+		if err != nil {
+			return "", errors.Join(errors.New("could not generate room code"), err)
+		}
+		code[i] = roomCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	roomGameID = uuid.MustParse("03f17f15-5d0a-49ea-aa05-039f2f18373e")
+	roomQuotes = map[int]*models.Quote{
+		72: {ID: 72, Quote: "a", Author: "Jan"},
+		12: {ID: 12, Quote: "b", Author: "Bob"},
+		33: {ID: 33, Quote: "c", Author: "Max"},
+	}
+)
+
+// roomKeys returns the keys of the game of the room. Every quote can be matched to all authors
+func roomKeys() map[int]*models.QuoteKey {
+	choices := []string{"Bob", "Jan", "Max"}
+	return map[int]*models.QuoteKey{
+		72: {Author: "Jan", Choices: choices},
+		12: {Author: "Bob", Choices: choices},
+		33: {Author: "Max", Choices: choices},
+	}
+}
+
+func openRoom() *models.Room {
+	return &models.Room{
+		Code:     "ABC234",
+		HostID:   "host",
+		GameID:   roomGameID,
+		QuoteIDs: []int{72, 12, 33},
+		Deadline: time.Now().Add(time.Minute),
+	}
+}
+
+func closedRoom() *models.Room {
+	room := openRoom()
+	room.Deadline = time.Now().Add(-time.Minute)
+	return room
+}
+
+func TestRoomService_CreateRoom(t *testing.T) {
+	type Test struct {
+		hostID           string
+		mockedGameError  error
+		mockedRoomErrors []error
+		expectedError    error
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			game := &models.QuoteGame{ID: roomGameID}
+			mockedQuoteGameService := new(MockedQuoteGameService)
+			mockedQuoteGameService.On("DrawQuoteGame", tt.hostID, models.GameModeMatch, models.LanguageEnglish).Return(game, tt.mockedGameError)
+
+			mockedRoomRepo := new(MockedRoomRepo)
+			for _, err := range tt.mockedRoomErrors {
+				var room *models.Room
+				if err == nil {
+					room = openRoom()
+				}
+				mockedRoomRepo.On("CreateRoom", mock.MatchedBy(func(code string) bool {
+					return len(code) == roomCodeLength && strings.Trim(code, roomCodeAlphabet) == ""
+				}), game, tt.hostID).Once().Return(room, err)
+			}
+
+			mockedPublisher := new(MockedPublisher)
+			mockedPublisher.On("Schedule", mock.Anything, mock.Anything)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			res, err := NewRoomService(&logger, mockedRoomRepo, mockedQuoteGameService, mockedPublisher).CreateRoom(context.TODO(), tt.hostID)
+
+			if tt.expectedError != nil {
+				require.ErrorContains(t, err, tt.expectedError.Error())
+				assert.Nil(t, res)
+				mockedPublisher.AssertNotCalled(t, "Schedule", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "ABC234", res.Code)
+				assert.Same(t, game, res.Game)
+				// At the deadline, the followers of the room hear that the results are final
+				mockedPublisher.AssertCalled(t, "Schedule", res.Deadline, []models.Event{
+					{Topic: "room:ABC234", Type: models.EventResultsAvailable, Deadline: res.Deadline},
+					{Topic: "room:ABC234", Type: models.EventGameExpired, Deadline: res.Deadline},
+				})
+				mockedPublisher.AssertCalled(t, "Schedule", res.Deadline.Add(-models.DeadlineWarning), []models.Event{
+					{Topic: "room:ABC234", Type: models.EventDeadlineApproaching, Deadline: res.Deadline},
+				})
+			}
+			mockedRoomRepo.AssertExpectations(t)
+		}
+	}
+
+	t.Run("creates a room with the game of the host", run(Test{
+		hostID:           "host",
+		mockedRoomErrors: []error{nil},
+	}))
+
+	t.Run("tries another code when the code is taken", run(Test{
+		hostID:           "host",
+		mockedRoomErrors: []error{models.ErrRoomCodeTaken, models.ErrRoomCodeTaken, nil},
+	}))
+
+	t.Run("gives up when no free code is found", run(Test{
+		hostID:           "host",
+		mockedRoomErrors: []error{models.ErrRoomCodeTaken, models.ErrRoomCodeTaken, models.ErrRoomCodeTaken, models.ErrRoomCodeTaken, models.ErrRoomCodeTaken},
+		expectedError:    errors.New("could not find a free room code"),
+	}))
+
+	t.Run("requires a host", run(Test{
+		expectedError: models.ErrPlayerIDRequired,
+	}))
+
+	t.Run("passes through an error from creating the game", run(Test{
+		hostID:          "host",
+		mockedGameError: errors.New("this is an error"),
+		expectedError:   errors.New("this is an error"),
+	}))
+}
+
+func TestRoomService_JoinRoom(t *testing.T) {
+	type Test struct {
+		code            string
+		playerID        string
+		mockedRoom      *models.Room
+		mockedRoomError error
+		mockedJoined    bool
+		expectedGame    *models.QuoteGame
+		expectedError   error
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedRoomRepo := new(MockedRoomRepo)
+			mockedRoomRepo.On("GetRoom", "ABC234").Return(tt.mockedRoom, tt.mockedRoomError)
+			mockedRoomRepo.On("JoinRoom", "ABC234", tt.playerID).Return(tt.mockedJoined, nil)
+
+			mockedQuoteGameService := new(MockedQuoteGameService)
+			mockedQuoteGameService.On("GetQuotes", []int{72, 12, 33}).Return(roomQuotes, nil)
+
+			mockedPublisher := new(MockedPublisher)
+			mockedPublisher.On("Publish", mock.Anything)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			res, err := NewRoomService(&logger, mockedRoomRepo, mockedQuoteGameService, mockedPublisher).JoinRoom(context.TODO(), tt.code, tt.playerID)
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, res)
+				mockedRoomRepo.AssertNotCalled(t, "JoinRoom", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ABC234", res.Code)
+			assert.Equal(t, tt.expectedGame, res.Game)
+			mockedRoomRepo.AssertCalled(t, "JoinRoom", "ABC234", tt.playerID)
+			// Only players that weren't in the room yet are announced
+			if tt.mockedJoined {
+				mockedPublisher.AssertCalled(t, "Publish", models.Event{Topic: "room:ABC234", Type: models.EventPlayerJoined, PlayerID: tt.playerID})
+			} else {
+				mockedPublisher.AssertNotCalled(t, "Publish", mock.Anything)
+			}
+		}
+	}
+
+	t.Run("joins the room and returns the same game as the host", run(Test{
+		code:         "abc234",
+		playerID:     "player-1",
+		mockedRoom:   openRoom(),
+		mockedJoined: true,
+		expectedGame: &models.QuoteGame{
+			ID:   roomGameID,
+			Mode: models.GameModeMatch,
+			Quotes: []*models.QuoteWithoutAuthor{
+				{ID: 72, Quote: "a"},
+				{ID: 12, Quote: "b"},
+				{ID: 33, Quote: "c"},
+			},
+			Authors: []string{"Bob", "Jan", "Max"},
+			Keys:    roomKeys(),
+		},
+	}))
+
+	t.Run("joins again without announcing the player", run(Test{
+		code:       "ABC234",
+		playerID:   "host",
+		mockedRoom: openRoom(),
+		expectedGame: &models.QuoteGame{
+			ID:   roomGameID,
+			Mode: models.GameModeMatch,
+			Quotes: []*models.QuoteWithoutAuthor{
+				{ID: 72, Quote: "a"},
+				{ID: 12, Quote: "b"},
+				{ID: 33, Quote: "c"},
+			},
+			Authors: []string{"Bob", "Jan", "Max"},
+			Keys:    roomKeys(),
+		},
+	}))
+
+	t.Run("can't join after the deadline", run(Test{
+		code:          "ABC234",
+		playerID:      "player-1",
+		mockedRoom:    closedRoom(),
+		expectedError: models.ErrRoomNotFound,
+	}))
+
+	t.Run("passes through an unknown room", run(Test{
+		code:            "ABC234",
+		playerID:        "player-1",
+		mockedRoomError: models.ErrRoomNotFound,
+		expectedError:   models.ErrRoomNotFound,
+	}))
+
+	t.Run("requires a player", run(Test{
+		code:          "ABC234",
+		mockedRoom:    openRoom(),
+		expectedError: models.ErrPlayerIDRequired,
+	}))
+}
+
+func TestRoomService_SubmitRoomAnswer(t *testing.T) {
+	type Test struct {
+		mockedRoom         *models.Room
+		mockedSubmitError  error
+		mockedParticipants []*models.RoomParticipant
+		answers            models.QuoteGameAnswerMap
+		expectedScore      int
+		expectedError      error
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			mockedRoomRepo := new(MockedRoomRepo)
+			mockedRoomRepo.On("GetRoom", "ABC234").Return(tt.mockedRoom, nil)
+			mockedRoomRepo.On("SubmitRoomAnswer", "ABC234", "player-1", mock.Anything, tt.answers).Return(tt.mockedSubmitError)
+			mockedRoomRepo.On("ListRoomParticipants", "ABC234").Return(tt.mockedParticipants, nil)
+
+			mockedQuoteGameService := new(MockedQuoteGameService)
+			mockedQuoteGameService.On("GetQuotes", []int{72, 12, 33}).Return(roomQuotes, nil)
+
+			mockedPublisher := new(MockedPublisher)
+			mockedPublisher.On("Publish", mock.Anything)
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			res, err := NewRoomService(&logger, mockedRoomRepo, mockedQuoteGameService, mockedPublisher).
+				SubmitRoomAnswer(context.TODO(), "ABC234", "player-1", tt.answers)
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, res)
+				mockedPublisher.AssertNotCalled(t, "Publish", mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			mockedPublisher.AssertCalled(t, "Publish", models.Event{Topic: "room:ABC234", Type: models.EventAnswerSubmitted, PlayerID: "player-1", Score: &tt.expectedScore})
+			allAnswered := true
+			for _, p := range tt.mockedParticipants {
+				allAnswered = allAnswered && p.SubmittedAt != nil
+			}
+			if allAnswered {
+				mockedPublisher.AssertCalled(t, "Publish", models.Event{Topic: "room:ABC234", Type: models.EventResultsAvailable, Deadline: tt.mockedRoom.Deadline})
+			} else {
+				mockedPublisher.AssertNumberOfCalls(t, "Publish", 1)
+			}
+			assert.Equal(t, roomGameID, res.ID)
+			assert.Equal(t, tt.expectedScore, res.Score())
+			// The answers are stored in the order of the quotes of the room
+			require.Len(t, res.Answers, 3)
+			assert.Equal(t, 72, res.Answers[0].ID)
+			mockedRoomRepo.AssertCalled(t, "SubmitRoomAnswer", "ABC234", "player-1", res, tt.answers)
+		}
+	}
+
+	submittedAt := time.Now()
+	t.Run("grades and stores the answer", run(Test{
+		mockedRoom:         openRoom(),
+		mockedParticipants: []*models.RoomParticipant{{PlayerID: "host"}, {PlayerID: "player-1", SubmittedAt: &submittedAt}},
+		answers:            models.QuoteGameAnswerMap{72: "Jan", 12: "Max", 33: "Bob"},
+		expectedScore:      1,
+	}))
+
+	t.Run("announces the results when everyone answered", run(Test{
+		mockedRoom:         openRoom(),
+		mockedParticipants: []*models.RoomParticipant{{PlayerID: "host", SubmittedAt: &submittedAt}, {PlayerID: "player-1", SubmittedAt: &submittedAt}},
+		answers:            models.QuoteGameAnswerMap{72: "Jan", 12: "Bob", 33: "Max"},
+		expectedScore:      3,
+	}))
+
+	t.Run("can't answer after the deadline", run(Test{
+		mockedRoom:    closedRoom(),
+		answers:       models.QuoteGameAnswerMap{72: "Jan", 12: "Bob", 33: "Max"},
+		expectedError: models.ErrRoomNotFound,
+	}))
+
+	t.Run("requires an answer for every quote", run(Test{
+		mockedRoom:    openRoom(),
+		answers:       models.QuoteGameAnswerMap{72: "Jan", 12: "Bob"},
+		expectedError: models.ErrInvalidQuoteID,
+	}))
+
+	t.Run("rejects quotes of another game", run(Test{
+		mockedRoom:    openRoom(),
+		answers:       models.QuoteGameAnswerMap{72: "Jan", 12: "Bob", 34: "Max"},
+		expectedError: models.ErrInvalidQuoteID,
+	}))
+
+	t.Run("passes through a second answer", run(Test{
+		mockedRoom:        openRoom(),
+		mockedSubmitError: models.ErrRoomAlreadyAnswered,
+		answers:           models.QuoteGameAnswerMap{72: "Jan", 12: "Bob", 33: "Max"},
+		expectedError:     models.ErrRoomAlreadyAnswered,
+	}))
+}
+
+func TestRoomService_GetRoomResult(t *testing.T) {
+	score := 2
+	participants := []*models.RoomParticipant{{PlayerID: "host", Score: &score}, {PlayerID: "player-1"}}
+
+	mockedRoomRepo := new(MockedRoomRepo)
+	mockedRoomRepo.On("GetRoom", "ABC234").Return(closedRoom(), nil)
+	mockedRoomRepo.On("ListRoomParticipants", "ABC234").Return(participants, nil)
+
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	res, err := NewRoomService(&logger, mockedRoomRepo, nil, nil).GetRoomResult(context.TODO(), " abc234 ")
+
+	// The result is still available after the deadline
+	require.NoError(t, err)
+	assert.Equal(t, "ABC234", res.Code)
+	assert.Equal(t, participants, res.Participants)
+}
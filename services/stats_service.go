@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+)
+
+type StatsService struct {
+	logger    *zerolog.Logger
+	statsRepo statsRepo
+}
+
+// NewStatsService returns a new StatsService, which reports how the quotes are answered in the quote games
+func NewStatsService(logger *zerolog.Logger, statsRepo statsRepo) *StatsService {
+	return &StatsService{
+		logger:    logger,
+		statsRepo: statsRepo,
+	}
+}
+
+// ListQuoteStats returns a page of the answered quotes, the hardest quotes first. A quote needs at least one attempt to be listed,
+// so a lower MinAttempts is raised to one.
+func (service *StatsService) ListQuoteStats(ctx context.Context, filter models.QuoteStatsFilter) (*models.Page[*models.QuoteStats], error) {
+	filter.MinAttempts = max(filter.MinAttempts, 1)
+	return service.statsRepo.ListQuoteStats(ctx, filter)
+}
+
+// ListAuthorConfusions returns a page of the authors that are guessed for the quotes of another author, the most frequent first
+func (service *StatsService) ListAuthorConfusions(ctx context.Context, filter models.AuthorConfusionFilter) (*models.Page[*models.AuthorConfusion], error) {
+	return service.statsRepo.ListAuthorConfusions(ctx, filter)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/pietdevries94/Kabisa/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsService_ListQuoteStats(t *testing.T) {
+	page := &models.Page[*models.QuoteStats]{
+		Items: []*models.QuoteStats{{QuoteID: 414, Quote: "A quote", Author: "C. S. Lewis", Attempts: 3}},
+		Total: 1,
+		Limit: 20,
+	}
+
+	type Test struct {
+		filter         models.QuoteStatsFilter
+		expectedFilter models.QuoteStatsFilter
+	}
+	run := func(tt Test) func(t *testing.T) {
+		return func(t *testing.T) {
+			t.Helper()
+
+			logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+			mockedStatsRepo := new(MockedStatsRepo)
+			mockedStatsRepo.On("ListQuoteStats", tt.expectedFilter).Once().Return(page, nil)
+			service := NewStatsService(&logger, mockedStatsRepo)
+
+			res, err := service.ListQuoteStats(context.TODO(), tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, page, res)
+			mockedStatsRepo.AssertExpectations(t)
+		}
+	}
+
+	t.Run("passes the filter on", run(Test{
+		filter:         models.QuoteStatsFilter{Mode: models.GameModeMatch, MinAttempts: 5, Limit: 20, Offset: 40},
+		expectedFilter: models.QuoteStatsFilter{Mode: models.GameModeMatch, MinAttempts: 5, Limit: 20, Offset: 40},
+	}))
+
+	t.Run("only lists quotes with attempts", run(Test{
+		filter:         models.QuoteStatsFilter{Limit: 20},
+		expectedFilter: models.QuoteStatsFilter{MinAttempts: 1, Limit: 20},
+	}))
+}
+
+func TestStatsService_ListAuthorConfusions(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.DebugLevel)
+	page := &models.Page[*models.AuthorConfusion]{
+		Items: []*models.AuthorConfusion{{Author: "C. S. Lewis", GuessedAuthor: "Rumi", Count: 2}},
+		Total: 1,
+		Limit: 20,
+	}
+	filter := models.AuthorConfusionFilter{Author: "C. S. Lewis", Limit: 20}
+	mockedStatsRepo := new(MockedStatsRepo)
+	mockedStatsRepo.On("ListAuthorConfusions", filter).Once().Return(page, nil)
+	service := NewStatsService(&logger, mockedStatsRepo)
+
+	res, err := service.ListAuthorConfusions(context.TODO(), filter)
+	require.NoError(t, err)
+	assert.Equal(t, page, res)
+}
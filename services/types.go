@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pietdevries94/Kabisa/models"
@@ -10,10 +11,84 @@ import (
 type dummyJsonRepo interface {
 	GetRandomQuotes(ctx context.Context, amount int) ([]*models.Quote, error)
 	GetQuotes(ctx context.Context, ids []int) (map[int]*models.Quote, error)
+	GetAllQuotes(ctx context.Context) ([]*models.Quote, error)
 }
 
 type quoteGameRepo interface {
-	CreateQuoteGame(ctx context.Context, quotes []*models.Quote) (*models.QuoteGame, error)
-	ValidateIDAndAnswerIDs(ctx context.Context, id uuid.UUID, answers models.QuoteGameAnswerMap) (quoteIDs []int, err error)
-	ValidateAnswersAndCreateGameResult(ctx context.Context, id uuid.UUID, quoteIDs []int, quotes map[int]*models.Quote, answers models.QuoteGameAnswerMap) (*models.QuoteGameResult, error)
+	CreateQuoteGame(ctx context.Context, game *models.QuoteGame, playerID string) error
+	GetRecentQuoteIDs(ctx context.Context, playerID string, games int) ([]int, error)
+	GetQuoteGameStatus(ctx context.Context, id uuid.UUID) (*models.QuoteGameStatus, error)
+	ValidateIDAndAnswerIDs(ctx context.Context, id uuid.UUID, answers models.QuoteGameAnswerMap) (mode models.GameMode, quoteIDs []int, err error)
+	StoreQuoteGameResult(ctx context.Context, result *models.QuoteGameResult, answers models.QuoteGameAnswerMap) error
+	GetQuoteGameSubmission(ctx context.Context, id uuid.UUID) (*models.QuoteGameSubmission, error)
+	GetOpenQuoteGame(ctx context.Context, id uuid.UUID) (mode models.GameMode, quoteIDs []int, err error)
+	CreateQuoteGameHint(ctx context.Context, id uuid.UUID, hint *models.Hint) error
+	GetQuoteGameHints(ctx context.Context, id uuid.UUID) ([]*models.Hint, error)
+	GetQuoteGameKeys(ctx context.Context, id uuid.UUID) (map[int]*models.QuoteKey, error)
+	GetDailyChallenge(ctx context.Context, date string) ([]int, error)
+	CreateDailyChallenge(ctx context.Context, date string, quoteIDs []int) ([]int, error)
+	CreateDailyQuoteGame(ctx context.Context, game *models.QuoteGame, playerID string, date string) error
+	GetDailyLeaderboard(ctx context.Context, date string, limit, offset int) (*models.Page[*models.DailyLeaderboardEntry], error)
+}
+
+type quoteRepo interface {
+	ReplaceQuotes(ctx context.Context, quotes []*models.Quote) error
+	ListQuotes(ctx context.Context, filter models.QuoteFilter) (*models.Page[*models.Quote], error)
+	GetQuote(ctx context.Context, id int) (*models.Quote, error)
+	ListAuthors(ctx context.Context, limit, offset int) (*models.Page[*models.AuthorQuoteCount], error)
+	GetQuoteIDs(ctx context.Context) ([]int, error)
+	GetDailyQuote(ctx context.Context, date string) (*models.DailyQuote, error)
+	CreateDailyQuote(ctx context.Context, date string, quote *models.Quote) (*models.DailyQuote, error)
+	GetRandomQuotes(ctx context.Context, amount int, language models.Language) ([]*models.Quote, error)
+	GetQuotes(ctx context.Context, ids []int) (map[int]*models.Quote, error)
+	GetBlockedQuoteIDs(ctx context.Context) ([]int, error)
+	CreateQuote(ctx context.Context, edit models.QuoteEdit) (*models.CuratedQuote, error)
+	UpdateQuote(ctx context.Context, id int, edit models.QuoteEdit) (*models.CuratedQuote, error)
+	DeleteQuote(ctx context.Context, id int) error
+}
+
+type apiKeyRepo interface {
+	CreateApiKey(ctx context.Context, keyHash string, subject string, role models.Role) (*models.ApiKey, error)
+	GetCallerByKeyHash(ctx context.Context, keyHash string) (*models.Caller, error)
+	RevokeApiKey(ctx context.Context, id uuid.UUID) error
+}
+
+type roomRepo interface {
+	CreateRoom(ctx context.Context, code string, game *models.QuoteGame, hostID string) (*models.Room, error)
+	GetRoom(ctx context.Context, code string) (*models.Room, error)
+	JoinRoom(ctx context.Context, code string, playerID string) (joined bool, err error)
+	SubmitRoomAnswer(ctx context.Context, code string, playerID string, result *models.QuoteGameResult, answers models.QuoteGameAnswerMap) error
+	ListRoomParticipants(ctx context.Context, code string) ([]*models.RoomParticipant, error)
+}
+
+type idempotencyRepo interface {
+	CreateIdempotencyKey(ctx context.Context, scope, key, fingerprint string, expiresAt time.Time) error
+	GetIdempotencyKey(ctx context.Context, scope, key string) (*models.IdempotencyKey, error)
+	SaveIdempotentResponse(ctx context.Context, scope, key string, response *models.IdempotentResponse, expiresAt time.Time) error
+	DeleteIdempotencyKey(ctx context.Context, scope, key string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+}
+
+type backupRepo interface {
+	VacuumInto(ctx context.Context, path string) error
+}
+
+type exportRepo interface {
+	ExportQuoteGames(ctx context.Context, from, to time.Time, fn func(*models.GameExport) error) error
+}
+
+type statsRepo interface {
+	ListQuoteStats(ctx context.Context, filter models.QuoteStatsFilter) (*models.Page[*models.QuoteStats], error)
+	ListAuthorConfusions(ctx context.Context, filter models.AuthorConfusionFilter) (*models.Page[*models.AuthorConfusion], error)
+}
+
+type quoteGameService interface {
+	DrawQuoteGame(ctx context.Context, playerID string, mode models.GameMode, language models.Language) (*models.QuoteGame, error)
+	GetQuotes(ctx context.Context, ids []int) (map[int]*models.Quote, error)
+}
+
+type publisher interface {
+	Publish(event models.Event)
+	Schedule(at time.Time, events []models.Event)
+	Cancel(topic string)
 }
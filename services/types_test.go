@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pietdevries94/Kabisa/models"
@@ -22,21 +23,295 @@ func (m *MockedDummyJsonRepo) GetQuotes(_ context.Context, ids []int) (map[int]*
 	return args.Get(0).(map[int]*models.Quote), args.Error(1)
 }
 
+func (m *MockedDummyJsonRepo) GetAllQuotes(_ context.Context) ([]*models.Quote, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.Quote), args.Error(1)
+}
+
 type MockedQuoteGameRepo struct {
 	mock.Mock
 }
 
-func (m *MockedQuoteGameRepo) CreateQuoteGame(_ context.Context, quotes []*models.Quote) (*models.QuoteGame, error) {
-	args := m.Called(quotes)
-	return args.Get(0).(*models.QuoteGame), args.Error(1)
+func (m *MockedQuoteGameRepo) CreateQuoteGame(_ context.Context, game *models.QuoteGame, playerID string) error {
+	args := m.Called(game, playerID)
+	return args.Error(0)
 }
 
-func (m *MockedQuoteGameRepo) ValidateIDAndAnswerIDs(_ context.Context, id uuid.UUID, answers models.QuoteGameAnswerMap) (quoteIDs []int, err error) {
+func (m *MockedQuoteGameRepo) GetRecentQuoteIDs(_ context.Context, playerID string, games int) ([]int, error) {
+	args := m.Called(playerID, games)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockedQuoteGameRepo) GetQuoteGameStatus(_ context.Context, id uuid.UUID) (*models.QuoteGameStatus, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.QuoteGameStatus), args.Error(1)
+}
+
+func (m *MockedQuoteGameRepo) ValidateIDAndAnswerIDs(_ context.Context, id uuid.UUID, answers models.QuoteGameAnswerMap) (mode models.GameMode, quoteIDs []int, err error) {
 	args := m.Called(id, answers)
+	return args.Get(0).(models.GameMode), args.Get(1).([]int), args.Error(2)
+}
+
+func (m *MockedQuoteGameRepo) StoreQuoteGameResult(_ context.Context, result *models.QuoteGameResult, answers models.QuoteGameAnswerMap) error {
+	args := m.Called(result, answers)
+	return args.Error(0)
+}
+
+func (m *MockedQuoteGameRepo) GetQuoteGameSubmission(_ context.Context, id uuid.UUID) (*models.QuoteGameSubmission, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.QuoteGameSubmission), args.Error(1)
+}
+
+func (m *MockedQuoteGameRepo) GetOpenQuoteGame(_ context.Context, id uuid.UUID) (models.GameMode, []int, error) {
+	args := m.Called(id)
+	return args.Get(0).(models.GameMode), args.Get(1).([]int), args.Error(2)
+}
+
+func (m *MockedQuoteGameRepo) CreateQuoteGameHint(_ context.Context, id uuid.UUID, hint *models.Hint) error {
+	args := m.Called(id, hint)
+	return args.Error(0)
+}
+
+func (m *MockedQuoteGameRepo) GetQuoteGameHints(_ context.Context, id uuid.UUID) ([]*models.Hint, error) {
+	args := m.Called(id)
+	return args.Get(0).([]*models.Hint), args.Error(1)
+}
+
+func (m *MockedQuoteGameRepo) GetQuoteGameKeys(_ context.Context, id uuid.UUID) (map[int]*models.QuoteKey, error) {
+	args := m.Called(id)
+	return args.Get(0).(map[int]*models.QuoteKey), args.Error(1)
+}
+
+func (m *MockedQuoteGameRepo) GetDailyChallenge(_ context.Context, date string) ([]int, error) {
+	args := m.Called(date)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockedQuoteGameRepo) CreateDailyChallenge(_ context.Context, date string, quoteIDs []int) ([]int, error) {
+	args := m.Called(date, quoteIDs)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockedQuoteGameRepo) CreateDailyQuoteGame(_ context.Context, game *models.QuoteGame, playerID string, date string) error {
+	args := m.Called(game, playerID, date)
+	return args.Error(0)
+}
+
+func (m *MockedQuoteGameRepo) GetDailyLeaderboard(_ context.Context, date string, limit, offset int) (*models.Page[*models.DailyLeaderboardEntry], error) {
+	args := m.Called(date, limit, offset)
+	return args.Get(0).(*models.Page[*models.DailyLeaderboardEntry]), args.Error(1)
+}
+
+type MockedQuoteRepo struct {
+	mock.Mock
+}
+
+func (m *MockedQuoteRepo) ReplaceQuotes(_ context.Context, quotes []*models.Quote) error {
+	args := m.Called(quotes)
+	return args.Error(0)
+}
+
+func (m *MockedQuoteRepo) ListQuotes(_ context.Context, filter models.QuoteFilter) (*models.Page[*models.Quote], error) {
+	args := m.Called(filter)
+	return args.Get(0).(*models.Page[*models.Quote]), args.Error(1)
+}
+
+func (m *MockedQuoteRepo) GetQuote(_ context.Context, id int) (*models.Quote, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Quote), args.Error(1)
+}
+
+func (m *MockedQuoteRepo) ListAuthors(_ context.Context, limit, offset int) (*models.Page[*models.AuthorQuoteCount], error) {
+	args := m.Called(limit, offset)
+	return args.Get(0).(*models.Page[*models.AuthorQuoteCount]), args.Error(1)
+}
+
+func (m *MockedQuoteRepo) GetQuoteIDs(_ context.Context) ([]int, error) {
+	args := m.Called()
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockedQuoteRepo) GetDailyQuote(_ context.Context, date string) (*models.DailyQuote, error) {
+	args := m.Called(date)
+	return args.Get(0).(*models.DailyQuote), args.Error(1)
+}
+
+func (m *MockedQuoteRepo) CreateDailyQuote(_ context.Context, date string, quote *models.Quote) (*models.DailyQuote, error) {
+	args := m.Called(date, quote)
+	return args.Get(0).(*models.DailyQuote), args.Error(1)
+}
+
+func (m *MockedQuoteRepo) GetRandomQuotes(_ context.Context, amount int, language models.Language) ([]*models.Quote, error) {
+	args := m.Called(amount, language)
+	return args.Get(0).([]*models.Quote), args.Error(1)
+}
+
+func (m *MockedQuoteRepo) GetQuotes(_ context.Context, ids []int) (map[int]*models.Quote, error) {
+	args := m.Called(ids)
+	return args.Get(0).(map[int]*models.Quote), args.Error(1)
+}
+
+func (m *MockedQuoteRepo) GetBlockedQuoteIDs(_ context.Context) ([]int, error) {
+	args := m.Called()
 	return args.Get(0).([]int), args.Error(1)
 }
 
-func (m *MockedQuoteGameRepo) ValidateAnswersAndCreateGameResult(_ context.Context, id uuid.UUID, quoteIDs []int, quotes map[int]*models.Quote, answers models.QuoteGameAnswerMap) (*models.QuoteGameResult, error) {
-	args := m.Called(id, quoteIDs, quotes, answers)
-	return args.Get(0).(*models.QuoteGameResult), args.Error(1)
+func (m *MockedQuoteRepo) CreateQuote(_ context.Context, edit models.QuoteEdit) (*models.CuratedQuote, error) {
+	args := m.Called(edit)
+	return args.Get(0).(*models.CuratedQuote), args.Error(1)
+}
+
+func (m *MockedQuoteRepo) UpdateQuote(_ context.Context, id int, edit models.QuoteEdit) (*models.CuratedQuote, error) {
+	args := m.Called(id, edit)
+	return args.Get(0).(*models.CuratedQuote), args.Error(1)
+}
+
+func (m *MockedQuoteRepo) DeleteQuote(_ context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type MockedApiKeyRepo struct {
+	mock.Mock
+}
+
+func (m *MockedApiKeyRepo) CreateApiKey(_ context.Context, keyHash string, subject string, role models.Role) (*models.ApiKey, error) {
+	args := m.Called(keyHash, subject, role)
+	return args.Get(0).(*models.ApiKey), args.Error(1)
+}
+
+func (m *MockedApiKeyRepo) GetCallerByKeyHash(_ context.Context, keyHash string) (*models.Caller, error) {
+	args := m.Called(keyHash)
+	return args.Get(0).(*models.Caller), args.Error(1)
+}
+
+func (m *MockedApiKeyRepo) RevokeApiKey(_ context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type MockedRoomRepo struct {
+	mock.Mock
+}
+
+func (m *MockedRoomRepo) CreateRoom(_ context.Context, code string, game *models.QuoteGame, hostID string) (*models.Room, error) {
+	args := m.Called(code, game, hostID)
+	return args.Get(0).(*models.Room), args.Error(1)
+}
+
+func (m *MockedRoomRepo) GetRoom(_ context.Context, code string) (*models.Room, error) {
+	args := m.Called(code)
+	return args.Get(0).(*models.Room), args.Error(1)
+}
+
+func (m *MockedRoomRepo) JoinRoom(_ context.Context, code string, playerID string) (bool, error) {
+	args := m.Called(code, playerID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockedRoomRepo) SubmitRoomAnswer(_ context.Context, code string, playerID string, result *models.QuoteGameResult, answers models.QuoteGameAnswerMap) error {
+	args := m.Called(code, playerID, result, answers)
+	return args.Error(0)
+}
+
+func (m *MockedRoomRepo) ListRoomParticipants(_ context.Context, code string) ([]*models.RoomParticipant, error) {
+	args := m.Called(code)
+	return args.Get(0).([]*models.RoomParticipant), args.Error(1)
+}
+
+type MockedIdempotencyRepo struct {
+	mock.Mock
+}
+
+func (m *MockedIdempotencyRepo) CreateIdempotencyKey(_ context.Context, scope, key, fingerprint string, expiresAt time.Time) error {
+	args := m.Called(scope, key, fingerprint, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockedIdempotencyRepo) GetIdempotencyKey(_ context.Context, scope, key string) (*models.IdempotencyKey, error) {
+	args := m.Called(scope, key)
+	return args.Get(0).(*models.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockedIdempotencyRepo) SaveIdempotentResponse(_ context.Context, scope, key string, response *models.IdempotentResponse, expiresAt time.Time) error {
+	args := m.Called(scope, key, response, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockedIdempotencyRepo) DeleteIdempotencyKey(_ context.Context, scope, key string) error {
+	args := m.Called(scope, key)
+	return args.Error(0)
+}
+
+func (m *MockedIdempotencyRepo) DeleteExpiredIdempotencyKeys(_ context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockedBackupRepo struct {
+	mock.Mock
+}
+
+func (m *MockedBackupRepo) VacuumInto(_ context.Context, path string) error {
+	args := m.Called(path)
+	return args.Error(0)
+}
+
+type MockedExportRepo struct {
+	mock.Mock
+}
+
+// ExportQuoteGames calls fn with the games the mock returns, until fn returns an error
+func (m *MockedExportRepo) ExportQuoteGames(_ context.Context, from, to time.Time, fn func(*models.GameExport) error) error {
+	args := m.Called(from, to)
+	for _, game := range args.Get(0).([]*models.GameExport) {
+		if err := fn(game); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+type MockedStatsRepo struct {
+	mock.Mock
+}
+
+func (m *MockedStatsRepo) ListQuoteStats(_ context.Context, filter models.QuoteStatsFilter) (*models.Page[*models.QuoteStats], error) {
+	args := m.Called(filter)
+	return args.Get(0).(*models.Page[*models.QuoteStats]), args.Error(1)
+}
+
+func (m *MockedStatsRepo) ListAuthorConfusions(_ context.Context, filter models.AuthorConfusionFilter) (*models.Page[*models.AuthorConfusion], error) {
+	args := m.Called(filter)
+	return args.Get(0).(*models.Page[*models.AuthorConfusion]), args.Error(1)
+}
+
+type MockedQuoteGameService struct {
+	mock.Mock
+}
+
+func (m *MockedQuoteGameService) DrawQuoteGame(_ context.Context, playerID string, mode models.GameMode, language models.Language) (*models.QuoteGame, error) {
+	args := m.Called(playerID, mode, language)
+	return args.Get(0).(*models.QuoteGame), args.Error(1)
+}
+
+func (m *MockedQuoteGameService) GetQuotes(_ context.Context, ids []int) (map[int]*models.Quote, error) {
+	args := m.Called(ids)
+	return args.Get(0).(map[int]*models.Quote), args.Error(1)
+}
+
+type MockedPublisher struct {
+	mock.Mock
+}
+
+func (m *MockedPublisher) Publish(event models.Event) {
+	m.Called(event)
+}
+
+func (m *MockedPublisher) Schedule(at time.Time, events []models.Event) {
+	m.Called(at, events)
+}
+
+func (m *MockedPublisher) Cancel(topic string) {
+	m.Called(topic)
 }